@@ -0,0 +1,4 @@
+package constant
+
+// AuthRealm is sent with the basic auth challenge of the HTTP endpoints
+const AuthRealm = "Book Library"
@@ -5,4 +5,6 @@ const (
 	UserIDGinCtxKey = "UserIDGinCtxKey"
 	ClaimsGinCtxKey = "ClaimsGinCtxKey"
 	RoleGinCtxKey   = "RoleGinCtxKey"
+	// BranchIDGinCtxKey holds the branch of a librarian scoped to one branch
+	BranchIDGinCtxKey = "BranchIDGinCtxKey"
 )
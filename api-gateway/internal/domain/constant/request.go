@@ -0,0 +1,4 @@
+package constant
+
+// RequestIDHeader correlates the logs of a request in the gateway and the services, it is generated when missing
+const RequestIDHeader = "X-Request-ID"
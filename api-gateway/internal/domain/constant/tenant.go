@@ -0,0 +1,7 @@
+package constant
+
+const (
+	// TenantHeader selects the tenant of an anonymous request like the login, the token tenant wins otherwise
+	TenantHeader     = "X-Tenant-ID"
+	TenantQueryParam = "tenant"
+)
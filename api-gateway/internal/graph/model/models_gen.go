@@ -6,12 +6,26 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
 type Book struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
-	Stock int    `json:"stock"`
+	ID         string         `json:"id"`
+	Title      string         `json:"title"`
+	Stock      int            `json:"stock"`
+	CategoryID *string        `json:"categoryID"`
+	Tags       []string       `json:"tags"`
+	Isbn       *string        `json:"isbn"`
+	Author     *string        `json:"author"`
+	CoverURL   *string        `json:"coverURL"`
+	Branches   []*BranchStock `json:"branches"`
+}
+
+type BookLoanCount struct {
+	BookID string `json:"bookID"`
+	Book   *Book  `json:"book"`
+	Loans  int    `json:"loans"`
 }
 
 type BookPaged struct {
@@ -22,44 +36,225 @@ type BookPaged struct {
 	LastPage  int     `json:"lastPage"`
 }
 
+type BookStockHistoryRequest struct {
+	BookID   string               `json:"bookID"`
+	Page     *int                 `json:"page"`
+	Limit    *int                 `json:"limit"`
+	Reason   *StockMovementReason `json:"reason"`
+	BranchID *string              `json:"branchID"`
+}
+
+type BookStockReconciliation struct {
+	BookID      string `json:"bookID"`
+	Stock       int    `json:"stock"`
+	LedgerStock int    `json:"ledgerStock"`
+	Consistent  bool   `json:"consistent"`
+}
+
+type Branch struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Code    string `json:"code"`
+	Address string `json:"address"`
+	Default bool   `json:"default"`
+}
+
+type BranchPaged struct {
+	Branches    []*Branch `json:"branches"`
+	Page        int       `json:"page"`
+	Limit       int       `json:"limit"`
+	TotalBranch int       `json:"totalBranch"`
+	LastPage    int       `json:"lastPage"`
+}
+
+type BranchStock struct {
+	BranchID string `json:"branchID"`
+	Stock    int    `json:"stock"`
+}
+
+type BrowseCategoryRequest struct {
+	ParentID *string `json:"parentID"`
+}
+
+type Category struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Code        string   `json:"code"`
+	ParentID    *string  `json:"parentID"`
+	AncestorIDs []string `json:"ancestorIDs"`
+	LoanDays    int      `json:"loanDays"`
+}
+
+type CategoryBrowse struct {
+	Parent   *Category        `json:"parent"`
+	Children []*CategoryCount `json:"children"`
+}
+
+type CategoryCount struct {
+	Category   *Category `json:"category"`
+	BookCount  int       `json:"bookCount"`
+	ChildCount int       `json:"childCount"`
+}
+
+type CategoryPaged struct {
+	Categories    []*Category `json:"categories"`
+	Page          int         `json:"page"`
+	Limit         int         `json:"limit"`
+	TotalCategory int         `json:"totalCategory"`
+	LastPage      int         `json:"lastPage"`
+}
+
+type CirculationSummary struct {
+	Loans           int     `json:"loans"`
+	Returns         int     `json:"returns"`
+	AverageLoanDays float64 `json:"averageLoanDays"`
+	Overdue         int     `json:"overdue"`
+	OverdueRate     float64 `json:"overdueRate"`
+	ActiveMembers   int     `json:"activeMembers"`
+}
+
 type DeleteBook struct {
+	ID    string `json:"id"`
+	Force *bool  `json:"force"`
+}
+
+type DeleteCategory struct {
 	ID string `json:"id"`
 }
 
 type DeleteUser struct {
 	Email string `json:"email"`
+	Force *bool  `json:"force"`
+}
+
+type DeleteWebhook struct {
+	ID string `json:"id"`
 }
 
 type FetchBookFilter struct {
+	Page       *int     `json:"page"`
+	Limit      *int     `json:"limit"`
+	Title      *string  `json:"title"`
+	CategoryID *string  `json:"categoryID"`
+	Tags       []string `json:"tags"`
+	BranchID   *string  `json:"branchID"`
+}
+
+type FetchBranchFilter struct {
 	Page  *int    `json:"page"`
 	Limit *int    `json:"limit"`
-	Title *string `json:"title"`
+	Name  *string `json:"name"`
+}
+
+type FetchCategoryFilter struct {
+	Page     *int    `json:"page"`
+	Limit    *int    `json:"limit"`
+	ParentID *string `json:"parentID"`
+	Name     *string `json:"name"`
 }
 
 type FetchLendingRequest struct {
+	Page     *int    `json:"page"`
+	Limit    *int    `json:"limit"`
+	UserID   *string `json:"userID"`
+	Status   *string `json:"status"`
+	BranchID *string `json:"branchID"`
+}
+
+type FetchNotificationRequest struct {
 	Page   *int    `json:"page"`
 	Limit  *int    `json:"limit"`
 	UserID *string `json:"userID"`
 	Status *string `json:"status"`
 }
 
+type FetchTenantFilter struct {
+	Page  *int `json:"page"`
+	Limit *int `json:"limit"`
+}
+
+type FetchTransferFilter struct {
+	Page     *int            `json:"page"`
+	Limit    *int            `json:"limit"`
+	BookID   *string         `json:"bookID"`
+	BranchID *string         `json:"branchID"`
+	Status   *TransferStatus `json:"status"`
+}
+
 type FetchUserFilter struct {
-	Page  *int    `json:"page"`
-	Limit *int    `json:"limit"`
-	Email *string `json:"email"`
-	Role  *Role   `json:"role"`
+	Page     *int    `json:"page"`
+	Limit    *int    `json:"limit"`
+	Email    *string `json:"email"`
+	Role     *Role   `json:"role"`
+	BranchID *string `json:"branchID"`
 }
 
-type FinishLendingRequest struct {
+type FetchWebhookDeadLetterRequest struct {
+	Page      *int    `json:"page"`
+	Limit     *int    `json:"limit"`
+	WebhookID *string `json:"webhookID"`
+}
+
+type FetchWebhookDeliveryRequest struct {
+	Page      *int    `json:"page"`
+	Limit     *int    `json:"limit"`
+	WebhookID *string `json:"webhookID"`
+	EventType *string `json:"eventType"`
+	Status    *string `json:"status"`
+}
+
+type FetchWebhookRequest struct {
+	Page  *int `json:"page"`
+	Limit *int `json:"limit"`
+}
+
+type FindImportJob struct {
 	ID string `json:"id"`
 }
 
+type FinishLendingRequest struct {
+	ID       string  `json:"id"`
+	BranchID *string `json:"branchID"`
+}
+
+type ImportBooks struct {
+	File     graphql.Upload `json:"file"`
+	Format   *ImportFormat  `json:"format"`
+	DryRun   *bool          `json:"dryRun"`
+	BranchID *string        `json:"branchID"`
+}
+
+type ImportJob struct {
+	ID        string            `json:"id"`
+	Format    ImportFormat      `json:"format"`
+	DryRun    bool              `json:"dryRun"`
+	Status    ImportStatus      `json:"status"`
+	FileName  string            `json:"fileName"`
+	Processed int               `json:"processed"`
+	Created   int               `json:"created"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	Errors    []*ImportRowError `json:"errors"`
+	Message   *string           `json:"message"`
+	CreatedAt string            `json:"createdAt"`
+	UpdatedAt string            `json:"updatedAt"`
+	BranchID  string            `json:"branchID"`
+}
+
+type ImportRowError struct {
+	Row     int     `json:"row"`
+	Message string  `json:"message"`
+	BookID  *string `json:"bookID"`
+}
+
 type Lending struct {
-	ID         string `json:"id"`
-	BookID     string `json:"bookID"`
-	UserID     string `json:"userID"`
-	Status     string `json:"status"`
-	ReturnDate string `json:"returnDate"`
+	ID             string  `json:"id"`
+	BookID         string  `json:"bookID"`
+	UserID         string  `json:"userID"`
+	Status         string  `json:"status"`
+	ReturnDate     string  `json:"returnDate"`
+	BranchID       *string `json:"branchID"`
+	ReturnBranchID *string `json:"returnBranchID"`
 }
 
 type LendingPaged struct {
@@ -71,8 +266,17 @@ type LendingPaged struct {
 }
 
 type Login struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	Tenant   *string `json:"tenant"`
+}
+
+type MemberActivity struct {
+	UserID  string `json:"userID"`
+	User    *User  `json:"user"`
+	Loans   int    `json:"loans"`
+	Active  int    `json:"active"`
+	Overdue int    `json:"overdue"`
 }
 
 type MyLendingRequest struct {
@@ -81,43 +285,257 @@ type MyLendingRequest struct {
 	Status *string `json:"status"`
 }
 
+type MyNotificationRequest struct {
+	Page   *int    `json:"page"`
+	Limit  *int    `json:"limit"`
+	Status *string `json:"status"`
+}
+
 type NewBook struct {
-	Title string `json:"title"`
-	Stock int    `json:"stock"`
+	Title      string   `json:"title"`
+	Stock      int      `json:"stock"`
+	CategoryID *string  `json:"categoryID"`
+	Tags       []string `json:"tags"`
+	Isbn       *string  `json:"isbn"`
+	Author     *string  `json:"author"`
+}
+
+type NewBranch struct {
+	Name    string  `json:"name"`
+	Code    string  `json:"code"`
+	Address *string `json:"address"`
+}
+
+type NewCategory struct {
+	Name     string  `json:"name"`
+	Code     *string `json:"code"`
+	ParentID *string `json:"parentID"`
+	LoanDays *int    `json:"loanDays"`
 }
 
 type NewLending struct {
-	BookID string `json:"bookID"`
+	BookID   string  `json:"bookID"`
+	BranchID *string `json:"branchID"`
+}
+
+type NewTenant struct {
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	AdminEmail    string `json:"adminEmail"`
+	AdminPassword string `json:"adminPassword"`
+}
+
+type NewTransfer struct {
+	BookID       string  `json:"bookID"`
+	FromBranchID string  `json:"fromBranchID"`
+	ToBranchID   *string `json:"toBranchID"`
+	Quantity     int     `json:"quantity"`
 }
 
 type NewUser struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	BranchID *string `json:"branchID"`
+}
+
+type NewWebhook struct {
+	URL        string   `json:"url"`
+	Secret     *string  `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+type Notification struct {
+	ID        string  `json:"id"`
+	UserID    string  `json:"userID"`
+	Kind      string  `json:"kind"`
+	Channel   string  `json:"channel"`
+	Recipient string  `json:"recipient"`
+	Subject   string  `json:"subject"`
+	Body      string  `json:"body"`
+	Status    string  `json:"status"`
+	Attempts  int     `json:"attempts"`
+	LastError *string `json:"lastError"`
+	CreatedAt string  `json:"createdAt"`
+	SentAt    *string `json:"sentAt"`
+}
+
+type NotificationPaged struct {
+	Notifications     []*Notification `json:"notifications"`
+	Page              int             `json:"page"`
+	Limit             int             `json:"limit"`
+	TotalNotification int             `json:"totalNotification"`
+	LastPage          int             `json:"lastPage"`
+}
+
+type NotificationPreference struct {
+	UserID     string   `json:"userID"`
+	Email      string   `json:"email"`
+	Phone      *string  `json:"phone"`
+	WebhookURL *string  `json:"webhookURL"`
+	Channels   []string `json:"channels"`
+}
+
+type PeriodCount struct {
+	Period  string `json:"period"`
+	Loans   int    `json:"loans"`
+	Returns int    `json:"returns"`
+}
+
+type ReconcileBookStock struct {
+	BookID *string `json:"bookID"`
+}
+
+type RedeliverWebhookDelivery struct {
+	ID string `json:"id"`
 }
 
 type RenewLendingRequest struct {
 	ID string `json:"id"`
 }
 
+type ReportRequest struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	BranchID *string       `json:"branchID"`
+	Period   *ReportPeriod `json:"period"`
+	Limit    *int          `json:"limit"`
+}
+
+type RetryNotificationRequest struct {
+	ID string `json:"id"`
+}
+
+type SendTestWebhookEvent struct {
+	WebhookID string `json:"webhookID"`
+}
+
+type StockMovement struct {
+	ID         string              `json:"id"`
+	BookID     string              `json:"bookID"`
+	Delta      int                 `json:"delta"`
+	Stock      int                 `json:"stock"`
+	Reason     StockMovementReason `json:"reason"`
+	ActorID    string              `json:"actorID"`
+	LendingID  string              `json:"lendingID"`
+	CreatedAt  string              `json:"createdAt"`
+	BranchID   string              `json:"branchID"`
+	TransferID string              `json:"transferID"`
+}
+
+type StockMovementPaged struct {
+	Movements     []*StockMovement `json:"movements"`
+	Page          int              `json:"page"`
+	Limit         int              `json:"limit"`
+	TotalMovement int              `json:"totalMovement"`
+	LastPage      int              `json:"lastPage"`
+}
+
+type Tenant struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type TenantPaged struct {
+	Tenants     []*Tenant `json:"tenants"`
+	Page        int       `json:"page"`
+	Limit       int       `json:"limit"`
+	TotalTenant int       `json:"totalTenant"`
+	LastPage    int       `json:"lastPage"`
+}
+
+type Transfer struct {
+	ID           string         `json:"id"`
+	BookID       string         `json:"bookID"`
+	FromBranchID string         `json:"fromBranchID"`
+	ToBranchID   string         `json:"toBranchID"`
+	Quantity     int            `json:"quantity"`
+	Status       TransferStatus `json:"status"`
+	RequestedBy  string         `json:"requestedBy"`
+	ShippedBy    string         `json:"shippedBy"`
+	ReceivedBy   string         `json:"receivedBy"`
+	CreatedAt    string         `json:"createdAt"`
+	UpdatedAt    string         `json:"updatedAt"`
+}
+
+type TransferPaged struct {
+	Transfers     []*Transfer `json:"transfers"`
+	Page          int         `json:"page"`
+	Limit         int         `json:"limit"`
+	TotalTransfer int         `json:"totalTransfer"`
+	LastPage      int         `json:"lastPage"`
+}
+
 type UpdateBook struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
+	ID     string  `json:"id"`
+	Title  string  `json:"title"`
+	Isbn   *string `json:"isbn"`
+	Author *string `json:"author"`
+}
+
+type UpdateBookClassification struct {
+	ID         string   `json:"id"`
+	CategoryID *string  `json:"categoryID"`
+	Tags       []string `json:"tags"`
 }
 
 type UpdateBookStock struct {
-	ID          string `json:"id"`
-	StockChange int    `json:"stockChange"`
+	ID          string               `json:"id"`
+	StockChange int                  `json:"stockChange"`
+	Reason      *StockMovementReason `json:"reason"`
+	BranchID    *string              `json:"branchID"`
+}
+
+type UpdateBranch struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Code    string  `json:"code"`
+	Address *string `json:"address"`
+}
+
+type UpdateCategory struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Code     *string `json:"code"`
+	ParentID *string `json:"parentID"`
+	LoanDays *int    `json:"loanDays"`
+}
+
+type UpdateNotificationPreference struct {
+	Phone      *string  `json:"phone"`
+	WebhookURL *string  `json:"webhookURL"`
+	Channels   []string `json:"channels"`
+}
+
+type UpdateTransferStatus struct {
+	ID     string         `json:"id"`
+	Status TransferStatus `json:"status"`
 }
 
 type UpdateUser struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
+	ID       string  `json:"id"`
+	Email    string  `json:"email"`
+	BranchID *string `json:"branchID"`
+}
+
+type UpdateWebhook struct {
+	ID           string   `json:"id"`
+	URL          string   `json:"url"`
+	EventTypes   []string `json:"eventTypes"`
+	Active       bool     `json:"active"`
+	RotateSecret *bool    `json:"rotateSecret"`
+}
+
+type UploadBookCover struct {
+	BookID string         `json:"bookID"`
+	File   graphql.Upload `json:"file"`
 }
 
 type User struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
-	Role  Role   `json:"role"`
+	ID       string  `json:"id"`
+	Email    string  `json:"email"`
+	Role     Role    `json:"role"`
+	BranchID *string `json:"branchID"`
 }
 
 type UserPaged struct {
@@ -128,15 +546,241 @@ type UserPaged struct {
 	LastPage  int     `json:"lastPage"`
 }
 
+type Webhook struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	Secret     *string  `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+	Active     bool     `json:"active"`
+	CreatedAt  string   `json:"createdAt"`
+	UpdatedAt  string   `json:"updatedAt"`
+}
+
+type WebhookAttempt struct {
+	AttemptedAt string  `json:"attemptedAt"`
+	StatusCode  *int    `json:"statusCode"`
+	Error       *string `json:"error"`
+	DurationMs  int     `json:"durationMs"`
+}
+
+type WebhookDelivery struct {
+	ID            string            `json:"id"`
+	WebhookID     string            `json:"webhookID"`
+	EventID       string            `json:"eventID"`
+	EventType     string            `json:"eventType"`
+	Payload       string            `json:"payload"`
+	Status        string            `json:"status"`
+	Attempts      int               `json:"attempts"`
+	Log           []*WebhookAttempt `json:"log"`
+	LastError     *string           `json:"lastError"`
+	NextAttemptAt *string           `json:"nextAttemptAt"`
+	DeliveredAt   *string           `json:"deliveredAt"`
+	CreatedAt     string            `json:"createdAt"`
+}
+
+type WebhookDeliveryPaged struct {
+	Deliveries    []*WebhookDelivery `json:"deliveries"`
+	Page          int                `json:"page"`
+	Limit         int                `json:"limit"`
+	TotalDelivery int                `json:"totalDelivery"`
+	LastPage      int                `json:"lastPage"`
+}
+
+type WebhookPaged struct {
+	Webhooks     []*Webhook `json:"webhooks"`
+	Page         int        `json:"page"`
+	Limit        int        `json:"limit"`
+	TotalWebhook int        `json:"totalWebhook"`
+	LastPage     int        `json:"lastPage"`
+}
+
+type CoverSize string
+
+const (
+	CoverSizeSmall    CoverSize = "small"
+	CoverSizeMedium   CoverSize = "medium"
+	CoverSizeLarge    CoverSize = "large"
+	CoverSizeOriginal CoverSize = "original"
+)
+
+var AllCoverSize = []CoverSize{
+	CoverSizeSmall,
+	CoverSizeMedium,
+	CoverSizeLarge,
+	CoverSizeOriginal,
+}
+
+func (e CoverSize) IsValid() bool {
+	switch e {
+	case CoverSizeSmall, CoverSizeMedium, CoverSizeLarge, CoverSizeOriginal:
+		return true
+	}
+	return false
+}
+
+func (e CoverSize) String() string {
+	return string(e)
+}
+
+func (e *CoverSize) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CoverSize(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CoverSize", str)
+	}
+	return nil
+}
+
+func (e CoverSize) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImportFormat string
+
+const (
+	ImportFormatCsv     ImportFormat = "csv"
+	ImportFormatMarc21  ImportFormat = "marc21"
+	ImportFormatMarcxml ImportFormat = "marcxml"
+)
+
+var AllImportFormat = []ImportFormat{
+	ImportFormatCsv,
+	ImportFormatMarc21,
+	ImportFormatMarcxml,
+}
+
+func (e ImportFormat) IsValid() bool {
+	switch e {
+	case ImportFormatCsv, ImportFormatMarc21, ImportFormatMarcxml:
+		return true
+	}
+	return false
+}
+
+func (e ImportFormat) String() string {
+	return string(e)
+}
+
+func (e *ImportFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportFormat", str)
+	}
+	return nil
+}
+
+func (e ImportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusPending   ImportStatus = "pending"
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+var AllImportStatus = []ImportStatus{
+	ImportStatusPending,
+	ImportStatusRunning,
+	ImportStatusCompleted,
+	ImportStatusFailed,
+}
+
+func (e ImportStatus) IsValid() bool {
+	switch e {
+	case ImportStatusPending, ImportStatusRunning, ImportStatusCompleted, ImportStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ImportStatus) String() string {
+	return string(e)
+}
+
+func (e *ImportStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportStatus", str)
+	}
+	return nil
+}
+
+func (e ImportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReportPeriod string
+
+const (
+	ReportPeriodDay   ReportPeriod = "day"
+	ReportPeriodWeek  ReportPeriod = "week"
+	ReportPeriodMonth ReportPeriod = "month"
+)
+
+var AllReportPeriod = []ReportPeriod{
+	ReportPeriodDay,
+	ReportPeriodWeek,
+	ReportPeriodMonth,
+}
+
+func (e ReportPeriod) IsValid() bool {
+	switch e {
+	case ReportPeriodDay, ReportPeriodWeek, ReportPeriodMonth:
+		return true
+	}
+	return false
+}
+
+func (e ReportPeriod) String() string {
+	return string(e)
+}
+
+func (e *ReportPeriod) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportPeriod(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportPeriod", str)
+	}
+	return nil
+}
+
+func (e ReportPeriod) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type Role string
 
 const (
-	RoleAdmin     Role = "admin"
-	RoleLibrarian Role = "librarian"
-	RoleMember    Role = "member"
+	RoleSuperAdmin Role = "super_admin"
+	RoleAdmin      Role = "admin"
+	RoleLibrarian  Role = "librarian"
+	RoleMember     Role = "member"
 )
 
 var AllRole = []Role{
+	RoleSuperAdmin,
 	RoleAdmin,
 	RoleLibrarian,
 	RoleMember,
@@ -144,7 +788,7 @@ var AllRole = []Role{
 
 func (e Role) IsValid() bool {
 	switch e {
-	case RoleAdmin, RoleLibrarian, RoleMember:
+	case RoleSuperAdmin, RoleAdmin, RoleLibrarian, RoleMember:
 		return true
 	}
 	return false
@@ -170,3 +814,97 @@ func (e *Role) UnmarshalGQL(v interface{}) error {
 func (e Role) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type StockMovementReason string
+
+const (
+	StockMovementReasonPurchase   StockMovementReason = "purchase"
+	StockMovementReasonLoss       StockMovementReason = "loss"
+	StockMovementReasonLending    StockMovementReason = "lending"
+	StockMovementReasonReturn     StockMovementReason = "return"
+	StockMovementReasonCorrection StockMovementReason = "correction"
+	StockMovementReasonTransfer   StockMovementReason = "transfer"
+)
+
+var AllStockMovementReason = []StockMovementReason{
+	StockMovementReasonPurchase,
+	StockMovementReasonLoss,
+	StockMovementReasonLending,
+	StockMovementReasonReturn,
+	StockMovementReasonCorrection,
+	StockMovementReasonTransfer,
+}
+
+func (e StockMovementReason) IsValid() bool {
+	switch e {
+	case StockMovementReasonPurchase, StockMovementReasonLoss, StockMovementReasonLending, StockMovementReasonReturn, StockMovementReasonCorrection, StockMovementReasonTransfer:
+		return true
+	}
+	return false
+}
+
+func (e StockMovementReason) String() string {
+	return string(e)
+}
+
+func (e *StockMovementReason) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StockMovementReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StockMovementReason", str)
+	}
+	return nil
+}
+
+func (e StockMovementReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TransferStatus string
+
+const (
+	TransferStatusRequested TransferStatus = "requested"
+	TransferStatusInTransit TransferStatus = "in_transit"
+	TransferStatusReceived  TransferStatus = "received"
+	TransferStatusCanceled  TransferStatus = "canceled"
+)
+
+var AllTransferStatus = []TransferStatus{
+	TransferStatusRequested,
+	TransferStatusInTransit,
+	TransferStatusReceived,
+	TransferStatusCanceled,
+}
+
+func (e TransferStatus) IsValid() bool {
+	switch e {
+	case TransferStatusRequested, TransferStatusInTransit, TransferStatusReceived, TransferStatusCanceled:
+		return true
+	}
+	return false
+}
+
+func (e TransferStatus) String() string {
+	return string(e)
+}
+
+func (e *TransferStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TransferStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TransferStatus", str)
+	}
+	return nil
+}
+
+func (e TransferStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,6 @@
+package model
+
+// Reports resolves each report of the request only when it is selected
+type Reports struct {
+	Request ReportRequest
+}
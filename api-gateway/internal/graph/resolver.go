@@ -7,7 +7,9 @@ import (
 //go:generate go run github.com/99designs/gqlgen
 
 type Resolver struct {
-	UserGRPCService    *grpcClient.UserGRPCService
-	BookGRPCService    *grpcClient.BookGRPCService
-	LendingGRPCService *grpcClient.LendingGRPCService
+	UserGRPCService         *grpcClient.UserGRPCService
+	BookGRPCService         *grpcClient.BookGRPCService
+	LendingGRPCService      *grpcClient.LendingGRPCService
+	NotificationGRPCService *grpcClient.NotificationGRPCService
+	WebhookGRPCService      *grpcClient.WebhookGRPCService
 }
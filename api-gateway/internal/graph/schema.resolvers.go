@@ -6,11 +6,48 @@ package graph
 import (
 	"context"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"api-gateway/internal/graph/generated"
 	"api-gateway/internal/graph/model"
+	grpcClient "api-gateway/internal/grpc"
 )
 
+func (r *bookResolver) CoverURL(ctx context.Context, obj *model.Book, size *model.CoverSize) (*string, error) {
+	return grpcClient.CoverURL(ctx, obj, size), nil
+}
+
+func (r *bookLoanCountResolver) Book(ctx context.Context, obj *model.BookLoanCount) (*model.Book, error) {
+	book, err := r.BookGRPCService.FindByID(ctx, obj.BookID)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	return book, err
+}
+
+func (r *memberActivityResolver) User(ctx context.Context, obj *model.MemberActivity) (*model.User, error) {
+	user, err := r.UserGRPCService.FindByID(ctx, obj.UserID)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *mutationResolver) CreateTenant(ctx context.Context, input model.NewTenant) (*model.Tenant, error) {
+	return r.UserGRPCService.CreateTenant(ctx, input)
+}
+
+func (r *mutationResolver) FetchTenant(ctx context.Context, input model.FetchTenantFilter) (*model.TenantPaged, error) {
+	return r.UserGRPCService.FetchTenant(ctx, input)
+}
+
 func (r *mutationResolver) RegisterLibrarian(ctx context.Context, input model.NewUser) (*model.User, error) {
+	if input.BranchID != nil {
+		if _, err := r.BookGRPCService.FindBranchByID(ctx, *input.BranchID); err != nil {
+			return nil, err
+		}
+	}
 	return r.UserGRPCService.CreateUser(ctx, input, model.RoleLibrarian)
 }
 
@@ -27,6 +64,11 @@ func (r *mutationResolver) FetchUser(ctx context.Context, input model.FetchUserF
 }
 
 func (r *mutationResolver) UpdateUser(ctx context.Context, input model.UpdateUser) (*model.User, error) {
+	if input.BranchID != nil {
+		if _, err := r.BookGRPCService.FindBranchByID(ctx, *input.BranchID); err != nil {
+			return nil, err
+		}
+	}
 	return r.UserGRPCService.UpdateUser(ctx, input)
 }
 
@@ -50,14 +92,82 @@ func (r *mutationResolver) UpdateBook(ctx context.Context, input model.UpdateBoo
 	return r.BookGRPCService.UpdateBook(ctx, input)
 }
 
+func (r *mutationResolver) UpdateBookClassification(ctx context.Context, input model.UpdateBookClassification) (*model.Book, error) {
+	return r.BookGRPCService.UpdateBookClassification(ctx, input)
+}
+
 func (r *mutationResolver) UpdateBookStock(ctx context.Context, input model.UpdateBookStock) (*model.Book, error) {
 	return r.BookGRPCService.UpdateBookStock(ctx, input)
 }
 
+func (r *mutationResolver) UploadBookCover(ctx context.Context, input model.UploadBookCover) (*model.Book, error) {
+	return r.BookGRPCService.UploadBookCover(ctx, input)
+}
+
 func (r *mutationResolver) DeleteBook(ctx context.Context, input model.DeleteBook) (*model.Book, error) {
 	return nil, r.BookGRPCService.DeleteBook(ctx, input)
 }
 
+func (r *mutationResolver) BookStockHistory(ctx context.Context, input model.BookStockHistoryRequest) (*model.StockMovementPaged, error) {
+	return r.BookGRPCService.BookStockHistory(ctx, input)
+}
+
+func (r *mutationResolver) ReconcileBookStock(ctx context.Context, input *model.ReconcileBookStock) ([]*model.BookStockReconciliation, error) {
+	return r.BookGRPCService.ReconcileBookStock(ctx, input)
+}
+
+func (r *mutationResolver) CreateCategory(ctx context.Context, input model.NewCategory) (*model.Category, error) {
+	return r.BookGRPCService.CreateCategory(ctx, input)
+}
+
+func (r *mutationResolver) FetchCategory(ctx context.Context, input model.FetchCategoryFilter) (*model.CategoryPaged, error) {
+	return r.BookGRPCService.FetchCategory(ctx, input)
+}
+
+func (r *mutationResolver) BrowseCategory(ctx context.Context, input *model.BrowseCategoryRequest) (*model.CategoryBrowse, error) {
+	return r.BookGRPCService.BrowseCategory(ctx, input)
+}
+
+func (r *mutationResolver) UpdateCategory(ctx context.Context, input model.UpdateCategory) (*model.Category, error) {
+	return r.BookGRPCService.UpdateCategory(ctx, input)
+}
+
+func (r *mutationResolver) DeleteCategory(ctx context.Context, input model.DeleteCategory) (*model.Category, error) {
+	return nil, r.BookGRPCService.DeleteCategory(ctx, input)
+}
+
+func (r *mutationResolver) ImportBooks(ctx context.Context, input model.ImportBooks) (*model.ImportJob, error) {
+	return r.BookGRPCService.ImportBooks(ctx, input)
+}
+
+func (r *mutationResolver) FindImportJob(ctx context.Context, input model.FindImportJob) (*model.ImportJob, error) {
+	return r.BookGRPCService.FindImportJob(ctx, input)
+}
+
+func (r *mutationResolver) CreateBranch(ctx context.Context, input model.NewBranch) (*model.Branch, error) {
+	return r.BookGRPCService.CreateBranch(ctx, input)
+}
+
+func (r *mutationResolver) UpdateBranch(ctx context.Context, input model.UpdateBranch) (*model.Branch, error) {
+	return r.BookGRPCService.UpdateBranch(ctx, input)
+}
+
+func (r *mutationResolver) FetchBranch(ctx context.Context, input model.FetchBranchFilter) (*model.BranchPaged, error) {
+	return r.BookGRPCService.FetchBranch(ctx, input)
+}
+
+func (r *mutationResolver) CreateTransfer(ctx context.Context, input model.NewTransfer) (*model.Transfer, error) {
+	return r.BookGRPCService.CreateTransfer(ctx, input)
+}
+
+func (r *mutationResolver) UpdateTransferStatus(ctx context.Context, input model.UpdateTransferStatus) (*model.Transfer, error) {
+	return r.BookGRPCService.UpdateTransferStatus(ctx, input)
+}
+
+func (r *mutationResolver) FetchTransfer(ctx context.Context, input model.FetchTransferFilter) (*model.TransferPaged, error) {
+	return r.BookGRPCService.FetchTransfer(ctx, input)
+}
+
 func (r *mutationResolver) LendBook(ctx context.Context, input model.NewLending) (*model.Lending, error) {
 	return r.LendingGRPCService.LendBook(ctx, input)
 }
@@ -78,7 +188,112 @@ func (r *mutationResolver) FetchLending(ctx context.Context, input *model.FetchL
 	return r.LendingGRPCService.FetchLending(ctx, input)
 }
 
+func (r *mutationResolver) MyNotificationPreference(ctx context.Context) (*model.NotificationPreference, error) {
+	return r.NotificationGRPCService.MyNotificationPreference(ctx)
+}
+
+func (r *mutationResolver) UpdateMyNotificationPreference(ctx context.Context, input model.UpdateNotificationPreference) (*model.NotificationPreference, error) {
+	return r.NotificationGRPCService.UpdateMyNotificationPreference(ctx, input)
+}
+
+func (r *mutationResolver) MyNotification(ctx context.Context, input *model.MyNotificationRequest) (*model.NotificationPaged, error) {
+	return r.NotificationGRPCService.MyNotification(ctx, input)
+}
+
+func (r *mutationResolver) FetchNotification(ctx context.Context, input *model.FetchNotificationRequest) (*model.NotificationPaged, error) {
+	return r.NotificationGRPCService.FetchNotification(ctx, input)
+}
+
+func (r *mutationResolver) RetryNotification(ctx context.Context, input model.RetryNotificationRequest) (*model.Notification, error) {
+	return r.NotificationGRPCService.RetryNotification(ctx, input)
+}
+
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.Webhook, error) {
+	return r.WebhookGRPCService.CreateWebhook(ctx, input)
+}
+
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, input model.UpdateWebhook) (*model.Webhook, error) {
+	return r.WebhookGRPCService.UpdateWebhook(ctx, input)
+}
+
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, input model.DeleteWebhook) (*model.Webhook, error) {
+	return r.WebhookGRPCService.DeleteWebhook(ctx, input)
+}
+
+func (r *mutationResolver) FetchWebhook(ctx context.Context, input *model.FetchWebhookRequest) (*model.WebhookPaged, error) {
+	return r.WebhookGRPCService.FetchWebhook(ctx, input)
+}
+
+func (r *mutationResolver) FetchWebhookDelivery(ctx context.Context, input *model.FetchWebhookDeliveryRequest) (*model.WebhookDeliveryPaged, error) {
+	return r.WebhookGRPCService.FetchWebhookDelivery(ctx, input)
+}
+
+func (r *mutationResolver) FetchWebhookDeadLetter(ctx context.Context, input *model.FetchWebhookDeadLetterRequest) (*model.WebhookDeliveryPaged, error) {
+	return r.WebhookGRPCService.FetchWebhookDeadLetter(ctx, input)
+}
+
+func (r *mutationResolver) RedeliverWebhookDelivery(ctx context.Context, input model.RedeliverWebhookDelivery) (*model.WebhookDelivery, error) {
+	return r.WebhookGRPCService.RedeliverWebhookDelivery(ctx, input)
+}
+
+func (r *mutationResolver) SendTestWebhookEvent(ctx context.Context, input model.SendTestWebhookEvent) (*model.WebhookDelivery, error) {
+	return r.WebhookGRPCService.SendTestWebhookEvent(ctx, input)
+}
+
+func (r *queryResolver) Reports(ctx context.Context, input model.ReportRequest) (*model.Reports, error) {
+	if err := grpcClient.CheckReportRequest(ctx, input); err != nil {
+		return nil, err
+	}
+	return &model.Reports{Request: input}, nil
+}
+
+func (r *reportsResolver) LoansPerPeriod(ctx context.Context, obj *model.Reports) ([]*model.PeriodCount, error) {
+	return r.LendingGRPCService.LoansPerPeriod(ctx, obj.Request)
+}
+
+func (r *reportsResolver) TopBorrowedBooks(ctx context.Context, obj *model.Reports) ([]*model.BookLoanCount, error) {
+	return r.LendingGRPCService.TopBorrowedBooks(ctx, obj.Request)
+}
+
+func (r *reportsResolver) NeverBorrowedBooks(ctx context.Context, obj *model.Reports) ([]*model.Book, error) {
+	borrowedBookIDs, err := r.LendingGRPCService.BorrowedBookIDs(ctx, obj.Request)
+	if err != nil {
+		return nil, err
+	}
+	return r.BookGRPCService.NeverBorrowedBooks(ctx, borrowedBookIDs, obj.Request)
+}
+
+func (r *reportsResolver) Summary(ctx context.Context, obj *model.Reports) (*model.CirculationSummary, error) {
+	return r.LendingGRPCService.CirculationSummary(ctx, obj.Request)
+}
+
+func (r *reportsResolver) ActiveMembers(ctx context.Context, obj *model.Reports) ([]*model.MemberActivity, error) {
+	return r.LendingGRPCService.ActiveMembers(ctx, obj.Request)
+}
+
+// Book returns generated.BookResolver implementation.
+func (r *Resolver) Book() generated.BookResolver { return &bookResolver{r} }
+
+// BookLoanCount returns generated.BookLoanCountResolver implementation.
+func (r *Resolver) BookLoanCount() generated.BookLoanCountResolver { return &bookLoanCountResolver{r} }
+
+// MemberActivity returns generated.MemberActivityResolver implementation.
+func (r *Resolver) MemberActivity() generated.MemberActivityResolver {
+	return &memberActivityResolver{r}
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+// Reports returns generated.ReportsResolver implementation.
+func (r *Resolver) Reports() generated.ReportsResolver { return &reportsResolver{r} }
+
+type bookResolver struct{ *Resolver }
+type bookLoanCountResolver struct{ *Resolver }
+type memberActivityResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type reportsResolver struct{ *Resolver }
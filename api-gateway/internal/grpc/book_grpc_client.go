@@ -2,8 +2,10 @@ package grpc
 
 import (
 	"context"
-	"log"
+	"errors"
+	"time"
 
+	"api-gateway/internal/domain/constant"
 	"api-gateway/internal/graph/model"
 	"api-gateway/pkg/grpc"
 	"api-gateway/pkg/proto"
@@ -22,11 +24,27 @@ func NewBookGRPCService(
 }
 
 func (c *BookGRPCService) CreateBook(ctx context.Context, input model.NewBook) (*model.Book, error) {
+	var categoryID string
+	if input.CategoryID != nil {
+		categoryID = *input.CategoryID
+	}
+
+	var isbn, author string
+	if input.Isbn != nil {
+		isbn = *input.Isbn
+	}
+	if input.Author != nil {
+		author = *input.Author
+	}
+
 	book, err := c.client.CreateBook(ctx, &proto.CreateBookRequest{
-		Title: input.Title,
+		Title:      input.Title,
+		CategoryId: categoryID,
+		Tags:       input.Tags,
+		Isbn:       isbn,
+		Author:     author,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -34,18 +52,16 @@ func (c *BookGRPCService) CreateBook(ctx context.Context, input model.NewBook) (
 		return nil, err
 	}
 
-	return &model.Book{
-		ID:    book.GetId(),
-		Title: book.GetTitle(),
-		Stock: int(book.GetStock()),
-	}, nil
+	return toModelBook(book), nil
 }
 
 func (c *BookGRPCService) FetchBook(ctx context.Context, filter model.FetchBookFilter) (*model.BookPaged, error) {
 	var (
-		limit int32 = 10
-		page  int32 = 1
-		title string
+		limit      int32 = 10
+		page       int32 = 1
+		title      string
+		categoryID string
+		branchID   string
 	)
 	if filter.Limit != nil {
 		limit = int32(*filter.Limit)
@@ -56,16 +72,24 @@ func (c *BookGRPCService) FetchBook(ctx context.Context, filter model.FetchBookF
 	if filter.Title != nil {
 		title = *filter.Title
 	}
+	if filter.CategoryID != nil {
+		categoryID = *filter.CategoryID
+	}
+	if filter.BranchID != nil {
+		branchID = *filter.BranchID
+	}
 
 	fetchBookResponse, err := c.client.FetchBook(ctx, &proto.FetchBookRequest{
 		Pagination: &proto.BookPaginationRequest{
 			Limit: limit,
 			Page:  page,
 		},
-		Title: title,
+		Title:      title,
+		CategoryId: categoryID,
+		Tags:       filter.Tags,
+		BranchId:   branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -75,11 +99,7 @@ func (c *BookGRPCService) FetchBook(ctx context.Context, filter model.FetchBookF
 
 	books := make([]*model.Book, 0)
 	for _, fetchedBook := range fetchBookResponse.Books {
-		books = append(books, &model.Book{
-			ID:    fetchedBook.GetId(),
-			Title: fetchedBook.GetTitle(),
-			Stock: int(fetchedBook.GetStock()),
-		})
+		books = append(books, toModelBook(fetchedBook))
 	}
 
 	return &model.BookPaged{
@@ -96,7 +116,6 @@ func (c *BookGRPCService) FindByID(ctx context.Context, id string) (*model.Book,
 		Id: id,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -104,11 +123,7 @@ func (c *BookGRPCService) FindByID(ctx context.Context, id string) (*model.Book,
 		return nil, err
 	}
 
-	return &model.Book{
-		ID:    book.GetId(),
-		Title: book.GetTitle(),
-		Stock: int(book.GetStock()),
-	}, nil
+	return toModelBook(book), nil
 }
 
 func (c *BookGRPCService) FindByTitle(ctx context.Context, title string) (*model.Book, error) {
@@ -116,7 +131,6 @@ func (c *BookGRPCService) FindByTitle(ctx context.Context, title string) (*model
 		Title: title,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -124,20 +138,25 @@ func (c *BookGRPCService) FindByTitle(ctx context.Context, title string) (*model
 		return nil, err
 	}
 
-	return &model.Book{
-		ID:    book.GetId(),
-		Title: book.GetTitle(),
-		Stock: int(book.GetStock()),
-	}, nil
+	return toModelBook(book), nil
 }
 
 func (c *BookGRPCService) UpdateBook(ctx context.Context, input model.UpdateBook) (*model.Book, error) {
+	var isbn, author string
+	if input.Isbn != nil {
+		isbn = *input.Isbn
+	}
+	if input.Author != nil {
+		author = *input.Author
+	}
+
 	book, err := c.client.UpdateBook(ctx, &proto.UpdateBookRequest{
-		Id:    input.ID,
-		Title: input.Title,
+		Id:     input.ID,
+		Title:  input.Title,
+		Isbn:   isbn,
+		Author: author,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -145,20 +164,50 @@ func (c *BookGRPCService) UpdateBook(ctx context.Context, input model.UpdateBook
 		return nil, err
 	}
 
-	return &model.Book{
-		ID:    book.GetId(),
-		Title: book.GetTitle(),
-		Stock: int(book.GetStock()),
-	}, nil
+	return toModelBook(book), nil
+}
+
+func (c *BookGRPCService) UpdateBookClassification(ctx context.Context, input model.UpdateBookClassification) (*model.Book, error) {
+	var categoryID string
+	if input.CategoryID != nil {
+		categoryID = *input.CategoryID
+	}
+
+	book, err := c.client.UpdateBookClassification(ctx, &proto.UpdateBookClassificationRequest{
+		Id:         input.ID,
+		CategoryId: categoryID,
+		Tags:       input.Tags,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelBook(book), nil
 }
 
 func (c *BookGRPCService) UpdateBookStock(ctx context.Context, input model.UpdateBookStock) (*model.Book, error) {
+	reason := model.StockMovementReasonCorrection
+	if input.Reason != nil {
+		reason = *input.Reason
+	}
+	actorID, _ := ctx.Value(constant.UserIDGinCtxKey).(string)
+	branchID, err := branchScope(ctx, input.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
 	book, err := c.client.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
 		Id:          input.ID,
 		StockChange: int32(input.StockChange),
+		Reason:      reason.String(),
+		ActorId:     actorID,
+		BranchId:    branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -166,19 +215,20 @@ func (c *BookGRPCService) UpdateBookStock(ctx context.Context, input model.Updat
 		return nil, err
 	}
 
-	return &model.Book{
-		ID:    book.GetId(),
-		Title: book.GetTitle(),
-		Stock: int(book.GetStock()),
-	}, nil
+	return toModelBook(book), nil
 }
 
 func (c *BookGRPCService) DeleteBook(ctx context.Context, input model.DeleteBook) error {
+	force := input.Force != nil && *input.Force
+	if role, _ := ctx.Value(constant.RoleGinCtxKey).(string); force && role != model.RoleAdmin.String() {
+		return errors.New("only admin can force delete a book")
+	}
+
 	_, err := c.client.DeleteBook(ctx, &proto.DeleteBookRequest{
-		Id: input.ID,
+		Id:    input.ID,
+		Force: force,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return err
 		}
@@ -188,3 +238,109 @@ func (c *BookGRPCService) DeleteBook(ctx context.Context, input model.DeleteBook
 
 	return nil
 }
+
+func (c *BookGRPCService) BookStockHistory(ctx context.Context, input model.BookStockHistoryRequest) (*model.StockMovementPaged, error) {
+	var (
+		limit    int32 = 10
+		page     int32 = 1
+		reason   string
+		branchID string
+	)
+	if input.Limit != nil {
+		limit = int32(*input.Limit)
+	}
+	if input.Page != nil {
+		page = int32(*input.Page)
+	}
+	if input.Reason != nil {
+		reason = input.Reason.String()
+	}
+	if input.BranchID != nil {
+		branchID = *input.BranchID
+	}
+
+	history, err := c.client.BookStockHistory(ctx, &proto.BookStockHistoryRequest{
+		Pagination: &proto.BookPaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+		BookId:   input.BookID,
+		Reason:   reason,
+		BranchId: branchID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	movements := make([]*model.StockMovement, 0)
+	for _, movement := range history.Movements {
+		movements = append(movements, &model.StockMovement{
+			ID:         movement.GetId(),
+			BookID:     movement.GetBookId(),
+			Delta:      int(movement.GetDelta()),
+			Stock:      int(movement.GetStock()),
+			Reason:     model.StockMovementReason(movement.GetReason()),
+			ActorID:    movement.GetActorId(),
+			LendingID:  movement.GetLendingId(),
+			CreatedAt:  movement.GetCreatedAt().AsTime().Format(time.RFC3339),
+			BranchID:   movement.GetBranchId(),
+			TransferID: movement.GetTransferId(),
+		})
+	}
+
+	return &model.StockMovementPaged{
+		Movements:     movements,
+		Page:          int(history.Pagination.GetPage()),
+		Limit:         int(history.Pagination.GetLimit()),
+		TotalMovement: int(history.Pagination.GetTotal()),
+		LastPage:      int(history.Pagination.GetLastPage()),
+	}, nil
+}
+
+func (c *BookGRPCService) ReconcileBookStock(ctx context.Context, input *model.ReconcileBookStock) ([]*model.BookStockReconciliation, error) {
+	var bookID string
+	if input != nil && input.BookID != nil {
+		bookID = *input.BookID
+	}
+
+	reconciled, err := c.client.ReconcileBookStock(ctx, &proto.ReconcileBookStockRequest{
+		BookId: bookID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	reconciliations := make([]*model.BookStockReconciliation, 0)
+	for _, reconciliation := range reconciled.Reconciliations {
+		reconciliations = append(reconciliations, &model.BookStockReconciliation{
+			BookID:      reconciliation.GetBookId(),
+			Stock:       int(reconciliation.GetStock()),
+			LedgerStock: int(reconciliation.GetLedgerStock()),
+			Consistent:  reconciliation.GetConsistent(),
+		})
+	}
+
+	return reconciliations, nil
+}
+
+func toModelBook(book *proto.Book) *model.Book {
+	return &model.Book{
+		ID:         book.GetId(),
+		Title:      book.GetTitle(),
+		Stock:      int(book.GetStock()),
+		CategoryID: optionalString(book.GetCategoryId()),
+		Tags:       book.GetTags(),
+		Isbn:       optionalString(book.GetIsbn()),
+		Author:     optionalString(book.GetAuthor()),
+		CoverURL:   bookCoverURL(book),
+		Branches:   toModelBranchStocks(book.GetBranches()),
+	}
+}
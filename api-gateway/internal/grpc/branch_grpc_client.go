@@ -0,0 +1,275 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/proto"
+)
+
+func (c *BookGRPCService) CreateBranch(ctx context.Context, input model.NewBranch) (*model.Branch, error) {
+	var address string
+	if input.Address != nil {
+		address = *input.Address
+	}
+
+	branch, err := c.client.CreateBranch(ctx, &proto.CreateBranchRequest{
+		Name:    input.Name,
+		Code:    input.Code,
+		Address: address,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelBranch(branch), nil
+}
+
+func (c *BookGRPCService) UpdateBranch(ctx context.Context, input model.UpdateBranch) (*model.Branch, error) {
+	var address string
+	if input.Address != nil {
+		address = *input.Address
+	}
+
+	branch, err := c.client.UpdateBranch(ctx, &proto.UpdateBranchRequest{
+		Id:      input.ID,
+		Name:    input.Name,
+		Code:    input.Code,
+		Address: address,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelBranch(branch), nil
+}
+
+func (c *BookGRPCService) FetchBranch(ctx context.Context, filter model.FetchBranchFilter) (*model.BranchPaged, error) {
+	var (
+		limit int32 = 10
+		page  int32 = 1
+		name  string
+	)
+	if filter.Limit != nil {
+		limit = int32(*filter.Limit)
+	}
+	if filter.Page != nil {
+		page = int32(*filter.Page)
+	}
+	if filter.Name != nil {
+		name = *filter.Name
+	}
+
+	fetchBranchResponse, err := c.client.FetchBranch(ctx, &proto.FetchBranchRequest{
+		Pagination: &proto.BookPaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+		Name: name,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	branches := make([]*model.Branch, 0)
+	for _, branch := range fetchBranchResponse.Branches {
+		branches = append(branches, toModelBranch(branch))
+	}
+
+	return &model.BranchPaged{
+		Branches:    branches,
+		Page:        int(fetchBranchResponse.Pagination.GetPage()),
+		Limit:       int(fetchBranchResponse.Pagination.GetLimit()),
+		TotalBranch: int(fetchBranchResponse.Pagination.GetTotal()),
+		LastPage:    int(fetchBranchResponse.Pagination.GetLastPage()),
+	}, nil
+}
+
+// FindBranchByID validates the branch of the requests handled by the other services
+func (c *BookGRPCService) FindBranchByID(ctx context.Context, id string) (*model.Branch, error) {
+	branch, err := c.client.FindBranchByID(ctx, &proto.FindBranchByIDRequest{
+		Id: id,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelBranch(branch), nil
+}
+
+// CreateTransfer requests copies for the branch of the librarian, from another branch
+func (c *BookGRPCService) CreateTransfer(ctx context.Context, input model.NewTransfer) (*model.Transfer, error) {
+	toBranchID, err := branchScope(ctx, input.ToBranchID)
+	if err != nil {
+		return nil, err
+	}
+	actorID, _ := ctx.Value(constant.UserIDGinCtxKey).(string)
+
+	transfer, err := c.client.CreateTransfer(ctx, &proto.CreateTransferRequest{
+		BookId:       input.BookID,
+		FromBranchId: input.FromBranchID,
+		ToBranchId:   toBranchID,
+		Quantity:     int32(input.Quantity),
+		ActorId:      actorID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelTransfer(transfer), nil
+}
+
+func (c *BookGRPCService) UpdateTransferStatus(ctx context.Context, input model.UpdateTransferStatus) (*model.Transfer, error) {
+	actorID, _ := ctx.Value(constant.UserIDGinCtxKey).(string)
+	actorBranchID, _ := ctx.Value(constant.BranchIDGinCtxKey).(string)
+
+	transfer, err := c.client.UpdateTransferStatus(ctx, &proto.UpdateTransferStatusRequest{
+		Id:            input.ID,
+		Status:        input.Status.String(),
+		ActorId:       actorID,
+		ActorBranchId: actorBranchID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelTransfer(transfer), nil
+}
+
+func (c *BookGRPCService) FetchTransfer(ctx context.Context, filter model.FetchTransferFilter) (*model.TransferPaged, error) {
+	var (
+		limit          int32 = 10
+		page           int32 = 1
+		bookID         string
+		transferStatus string
+	)
+	if filter.Limit != nil {
+		limit = int32(*filter.Limit)
+	}
+	if filter.Page != nil {
+		page = int32(*filter.Page)
+	}
+	if filter.BookID != nil {
+		bookID = *filter.BookID
+	}
+	if filter.Status != nil {
+		transferStatus = filter.Status.String()
+	}
+	branchID, err := branchScope(ctx, filter.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchTransferResponse, err := c.client.FetchTransfer(ctx, &proto.FetchTransferRequest{
+		Pagination: &proto.BookPaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+		BookId:   bookID,
+		BranchId: branchID,
+		Status:   transferStatus,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	transfers := make([]*model.Transfer, 0)
+	for _, transfer := range fetchTransferResponse.Transfers {
+		transfers = append(transfers, toModelTransfer(transfer))
+	}
+
+	return &model.TransferPaged{
+		Transfers:     transfers,
+		Page:          int(fetchTransferResponse.Pagination.GetPage()),
+		Limit:         int(fetchTransferResponse.Pagination.GetLimit()),
+		TotalTransfer: int(fetchTransferResponse.Pagination.GetTotal()),
+		LastPage:      int(fetchTransferResponse.Pagination.GetLastPage()),
+	}, nil
+}
+
+// branchScope returns the branch a request is limited to. A librarian scoped to a branch by the token
+// only acts on that branch, the other users choose any branch, or none.
+func branchScope(ctx context.Context, branchID *string) (string, error) {
+	scope, _ := ctx.Value(constant.BranchIDGinCtxKey).(string)
+	if scope == "" {
+		if branchID == nil {
+			return "", nil
+		}
+		return *branchID, nil
+	}
+
+	if branchID != nil && *branchID != "" && *branchID != scope {
+		return "", fmt.Errorf("librarian of branch %s cannot access branch %s", scope, *branchID)
+	}
+	return scope, nil
+}
+
+func toModelBranch(branch *proto.Branch) *model.Branch {
+	return &model.Branch{
+		ID:      branch.GetId(),
+		Name:    branch.GetName(),
+		Code:    branch.GetCode(),
+		Address: branch.GetAddress(),
+		Default: branch.GetDefault(),
+	}
+}
+
+func toModelBranchStocks(branchStocks []*proto.BranchStock) []*model.BranchStock {
+	modelBranchStocks := make([]*model.BranchStock, 0, len(branchStocks))
+	for _, branchStock := range branchStocks {
+		modelBranchStocks = append(modelBranchStocks, &model.BranchStock{
+			BranchID: branchStock.GetBranchId(),
+			Stock:    int(branchStock.GetStock()),
+		})
+	}
+
+	return modelBranchStocks
+}
+
+func toModelTransfer(transfer *proto.Transfer) *model.Transfer {
+	return &model.Transfer{
+		ID:           transfer.GetId(),
+		BookID:       transfer.GetBookId(),
+		FromBranchID: transfer.GetFromBranchId(),
+		ToBranchID:   transfer.GetToBranchId(),
+		Quantity:     int(transfer.GetQuantity()),
+		Status:       model.TransferStatus(transfer.GetStatus()),
+		RequestedBy:  transfer.GetRequestedBy(),
+		ShippedBy:    transfer.GetShippedBy(),
+		ReceivedBy:   transfer.GetReceivedBy(),
+		CreatedAt:    transfer.GetCreatedAt().AsTime().Format(time.RFC3339),
+		UpdatedAt:    transfer.GetUpdatedAt().AsTime().Format(time.RFC3339),
+	}
+}
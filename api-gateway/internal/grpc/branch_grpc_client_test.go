@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"api-gateway/internal/domain/constant"
+)
+
+func TestBranchScope(t *testing.T) {
+	branch, otherBranch, empty := "branch", "other-branch", ""
+
+	tests := []struct {
+		name     string
+		scope    string
+		branchID *string
+		want     string
+		wantErr  bool
+	}{
+		{name: "unscoped without branch", want: ""},
+		{name: "unscoped chooses any branch", branchID: &otherBranch, want: otherBranch},
+		{name: "scoped defaults to its branch", scope: branch, want: branch},
+		{name: "scoped with empty branch", scope: branch, branchID: &empty, want: branch},
+		{name: "scoped with its branch", scope: branch, branchID: &branch, want: branch},
+		{name: "scoped with other branch", scope: branch, branchID: &otherBranch, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.scope != "" {
+				ctx = context.WithValue(ctx, constant.BranchIDGinCtxKey, tt.scope)
+			}
+
+			got, err := branchScope(ctx, tt.branchID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("branchScope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("branchScope() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package grpc
+
+import (
+	"context"
+
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/proto"
+)
+
+func (c *BookGRPCService) CreateCategory(ctx context.Context, input model.NewCategory) (*model.Category, error) {
+	var (
+		code     string
+		parentID string
+		loanDays int32
+	)
+	if input.Code != nil {
+		code = *input.Code
+	}
+	if input.ParentID != nil {
+		parentID = *input.ParentID
+	}
+	if input.LoanDays != nil {
+		loanDays = int32(*input.LoanDays)
+	}
+
+	category, err := c.client.CreateCategory(ctx, &proto.CreateCategoryRequest{
+		Name:     input.Name,
+		Code:     code,
+		ParentId: parentID,
+		LoanDays: loanDays,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelCategory(category), nil
+}
+
+func (c *BookGRPCService) FetchCategory(ctx context.Context, filter model.FetchCategoryFilter) (*model.CategoryPaged, error) {
+	var (
+		limit    int32 = 10
+		page     int32 = 1
+		parentID string
+		name     string
+	)
+	if filter.Limit != nil {
+		limit = int32(*filter.Limit)
+	}
+	if filter.Page != nil {
+		page = int32(*filter.Page)
+	}
+	if filter.ParentID != nil {
+		parentID = *filter.ParentID
+	}
+	if filter.Name != nil {
+		name = *filter.Name
+	}
+
+	fetchCategoryResponse, err := c.client.FetchCategory(ctx, &proto.FetchCategoryRequest{
+		Pagination: &proto.BookPaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+		ParentId: parentID,
+		Name:     name,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	categories := make([]*model.Category, 0)
+	for _, category := range fetchCategoryResponse.Categories {
+		categories = append(categories, toModelCategory(category))
+	}
+
+	return &model.CategoryPaged{
+		Categories:    categories,
+		Page:          int(fetchCategoryResponse.Pagination.GetPage()),
+		Limit:         int(fetchCategoryResponse.Pagination.GetLimit()),
+		TotalCategory: int(fetchCategoryResponse.Pagination.GetTotal()),
+		LastPage:      int(fetchCategoryResponse.Pagination.GetLastPage()),
+	}, nil
+}
+
+func (c *BookGRPCService) BrowseCategory(ctx context.Context, input *model.BrowseCategoryRequest) (*model.CategoryBrowse, error) {
+	var parentID string
+	if input != nil && input.ParentID != nil {
+		parentID = *input.ParentID
+	}
+
+	browseCategoryResponse, err := c.client.BrowseCategory(ctx, &proto.BrowseCategoryRequest{
+		ParentId: parentID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	browse := &model.CategoryBrowse{
+		Children: make([]*model.CategoryCount, 0),
+	}
+	if parent := browseCategoryResponse.GetParent(); parent != nil {
+		browse.Parent = toModelCategory(parent)
+	}
+	for _, child := range browseCategoryResponse.Children {
+		browse.Children = append(browse.Children, &model.CategoryCount{
+			Category:   toModelCategory(child.GetCategory()),
+			BookCount:  int(child.GetBookCount()),
+			ChildCount: int(child.GetChildCount()),
+		})
+	}
+
+	return browse, nil
+}
+
+func (c *BookGRPCService) UpdateCategory(ctx context.Context, input model.UpdateCategory) (*model.Category, error) {
+	var (
+		code     string
+		parentID string
+		loanDays int32
+	)
+	if input.Code != nil {
+		code = *input.Code
+	}
+	if input.ParentID != nil {
+		parentID = *input.ParentID
+	}
+	if input.LoanDays != nil {
+		loanDays = int32(*input.LoanDays)
+	}
+
+	category, err := c.client.UpdateCategory(ctx, &proto.UpdateCategoryRequest{
+		Id:       input.ID,
+		Name:     input.Name,
+		Code:     code,
+		ParentId: parentID,
+		LoanDays: loanDays,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelCategory(category), nil
+}
+
+func (c *BookGRPCService) DeleteCategory(ctx context.Context, input model.DeleteCategory) error {
+	_, err := c.client.DeleteCategory(ctx, &proto.DeleteCategoryRequest{
+		Id: input.ID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func toModelCategory(category *proto.Category) *model.Category {
+	return &model.Category{
+		ID:          category.GetId(),
+		Name:        category.GetName(),
+		Code:        category.GetCode(),
+		ParentID:    optionalString(category.GetParentId()),
+		AncestorIDs: category.GetAncestorIds(),
+		LoanDays:    int(category.GetLoanDays()),
+	}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+)
+
+// chunkReader reads the chunks of a server stream as a single content
+type chunkReader struct {
+	recv  func() ([]byte, error)
+	chunk []byte
+	eof   bool
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+
+		chunk, err := r.recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				r.eof = true
+				continue
+			}
+			return 0, err
+		}
+		r.chunk = chunk
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/proto"
+	"api-gateway/pkg/tenant"
+)
+
+const (
+	coverPath      = "/covers"
+	coverChunkSize = 64 << 10
+)
+
+type BookCover struct {
+	ContentType string
+	Version     string
+	Content     io.Reader
+}
+
+func (c *BookGRPCService) UploadBookCover(ctx context.Context, input model.UploadBookCover) (*model.Book, error) {
+	stream, err := c.client.UploadBookCover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// a failed send is an io.EOF, the rejection is received by CloseAndRecv
+	err = stream.Send(&proto.UploadBookCoverRequest{BookId: input.BookID})
+	chunk := make([]byte, coverChunkSize)
+	for err == nil {
+		var n int
+		n, err = input.File.File.Read(chunk)
+		if n > 0 {
+			if sendErr := stream.Send(&proto.UploadBookCoverRequest{Chunk: chunk[:n]}); sendErr != nil {
+				// the rejection is received by CloseAndRecv
+				break
+			}
+		}
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	book, err := stream.CloseAndRecv()
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelBook(book), nil
+}
+
+// FetchBookCover returns the cover as it is streamed from book-service,
+// the first chunk is already received to know the content type and the version.
+func (c *BookGRPCService) FetchBookCover(ctx context.Context, bookID, size string) (*BookCover, error) {
+	stream, err := c.client.FetchBookCover(ctx, &proto.FetchBookCoverRequest{
+		BookId: bookID,
+		Size:   size,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return &BookCover{
+		ContentType: response.GetContentType(),
+		Version:     response.GetVersion(),
+		Content: &chunkReader{
+			recv: func() ([]byte, error) {
+				response, err := stream.Recv()
+				return response.GetChunk(), err
+			},
+			chunk: response.GetChunk(),
+			eof:   err != nil,
+		},
+	}, nil
+}
+
+// CoverURL adds the size to the versioned cover URL of the book, medium by default.
+// The tenant is added too, the cover is loaded by an image tag which cannot send the tenant header.
+func CoverURL(ctx context.Context, book *model.Book, size *model.CoverSize) *string {
+	if book.CoverURL == nil {
+		return nil
+	}
+
+	coverSize := model.CoverSizeMedium
+	if size != nil {
+		coverSize = *size
+	}
+
+	coverURL := fmt.Sprintf("%s&size=%s", *book.CoverURL, coverSize)
+	if tenantID := tenant.FromContext(ctx); tenantID != tenant.Default {
+		coverURL += fmt.Sprintf("&%s=%s", constant.TenantQueryParam, url.QueryEscape(tenantID))
+	}
+	return &coverURL
+}
+
+func bookCoverURL(book *proto.Book) *string {
+	if book.GetCoverVersion() == "" {
+		return nil
+	}
+
+	coverURL := fmt.Sprintf("%s/%s?v=%s", coverPath, url.PathEscape(book.GetId()), book.GetCoverVersion())
+	return &coverURL
+}
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/tenant"
+)
+
+func TestCoverURL(t *testing.T) {
+	coverURL := "/covers/1?v=abc"
+	small := model.CoverSizeSmall
+
+	tests := []struct {
+		name     string
+		tenantID string
+		size     *model.CoverSize
+		want     string
+	}{
+		{name: "medium by default", want: "/covers/1?v=abc&size=medium"},
+		{name: "default tenant", tenantID: tenant.Default, size: &small, want: "/covers/1?v=abc&size=small"},
+		{name: "other tenant", tenantID: "city library", size: &small, want: "/covers/1?v=abc&size=small&tenant=city+library"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.tenantID != "" {
+				ctx = tenant.NewContext(ctx, tt.tenantID)
+			}
+
+			got := CoverURL(ctx, &model.Book{CoverURL: &coverURL}, tt.size)
+			if got == nil || *got != tt.want {
+				t.Errorf("CoverURL() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := CoverURL(context.Background(), &model.Book{}, nil); got != nil {
+		t.Errorf("CoverURL() without cover = %q, want nil", *got)
+	}
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"api-gateway/pkg/proto"
+)
+
+type ExportBooksFilter struct {
+	Format     string
+	Title      string
+	CategoryID string
+	Tags       []string
+}
+
+// ExportBooks returns the exported file as it is streamed from book-service.
+// The first chunk is already received, so a rejected export is reported before anything is read.
+func (c *BookGRPCService) ExportBooks(ctx context.Context, filter ExportBooksFilter) (io.Reader, error) {
+	stream, err := c.client.ExportBooks(ctx, &proto.ExportBooksRequest{
+		Format:     filter.Format,
+		Title:      filter.Title,
+		CategoryId: filter.CategoryID,
+		Tags:       filter.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return &chunkReader{
+		recv: func() ([]byte, error) {
+			response, err := stream.Recv()
+			return response.GetChunk(), err
+		},
+		chunk: response.GetChunk(),
+		eof:   err != nil,
+	}, nil
+}
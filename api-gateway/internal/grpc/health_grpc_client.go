@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// BackendHealth is the health of a backend service, Error tells why a backend can't be reached
+type BackendHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (h BackendHealth) Serving() bool {
+	return h.Status == healthpb.HealthCheckResponse_SERVING.String()
+}
+
+type HealthGRPCService struct {
+	clients map[string]healthpb.HealthClient
+}
+
+// NewHealthGRPCService checks the backends given by their name
+func NewHealthGRPCService(
+	clients map[string]healthpb.HealthClient,
+) *HealthGRPCService {
+	return &HealthGRPCService{
+		clients: clients,
+	}
+}
+
+// Check asks every backend for its health at the same time, a backend which can't be reached is not serving
+func (c *HealthGRPCService) Check(ctx context.Context) map[string]BackendHealth {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		backends = make(map[string]BackendHealth, len(c.clients))
+	)
+	for name, client := range c.clients {
+		wg.Add(1)
+		go func(name string, client healthpb.HealthClient) {
+			defer wg.Done()
+
+			health := BackendHealth{Status: healthpb.HealthCheckResponse_NOT_SERVING.String()}
+			response, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+			if err != nil {
+				health.Error = status.Convert(err).Message()
+			} else {
+				health.Status = response.GetStatus().String()
+			}
+
+			mu.Lock()
+			backends[name] = health
+			mu.Unlock()
+		}(name, client)
+	}
+	wg.Wait()
+
+	return backends
+}
@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/detached"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/proto"
+)
+
+const (
+	maxImportFileSize = 32 << 20
+	importChunkSize   = 64 << 10
+	importTimeout     = 30 * time.Minute
+)
+
+// ImportBooks buffers the uploaded file, then streams it to book-service in the background,
+// so the returned job can be followed with FindImportJob while the file is processed.
+func (c *BookGRPCService) ImportBooks(ctx context.Context, input model.ImportBooks) (*model.ImportJob, error) {
+	format, err := importFormat(input)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(io.LimitReader(input.File.File, maxImportFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxImportFileSize {
+		return nil, fmt.Errorf("import file is larger than %d MB", maxImportFileSize>>20)
+	}
+
+	branchID, err := branchScope(ctx, input.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID, _ := ctx.Value(constant.UserIDGinCtxKey).(string)
+	job, err := c.client.CreateImportJob(ctx, &proto.CreateImportJobRequest{
+		Format:   format.String(),
+		DryRun:   input.DryRun != nil && *input.DryRun,
+		ActorId:  actorID,
+		FileName: input.File.Filename,
+		BranchId: branchID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	go c.streamImport(ctx, job.GetId(), content)
+
+	return toModelImportJob(job), nil
+}
+
+func (c *BookGRPCService) streamImport(ctx context.Context, jobID string, content []byte) {
+	// the upload request is already answered, so the stream only keeps the tenant and the logging fields of its context
+	ctx, cancel := context.WithTimeout(detached.Context(ctx), importTimeout)
+	defer cancel()
+
+	stream, err := c.client.ImportBooks(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("import_job_id", jobID).Msg("Error starting import job")
+		return
+	}
+
+	if err = stream.Send(&proto.ImportBooksRequest{JobId: jobID}); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("import_job_id", jobID).Msg("Error streaming import job")
+		return
+	}
+	for len(content) > 0 {
+		size := importChunkSize
+		if len(content) < size {
+			size = len(content)
+		}
+
+		if err = stream.Send(&proto.ImportBooksRequest{Chunk: content[:size]}); err != nil {
+			break
+		}
+		content = content[size:]
+	}
+
+	// CloseAndRecv also reports the error which interrupted the sending
+	job, err := stream.CloseAndRecv()
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("import_job_id", jobID).Msg("Error streaming import job")
+		return
+	}
+	logger.Ctx(ctx).Info().
+		Str("import_job_id", jobID).
+		Str("status", job.GetStatus()).
+		Int32("created", job.GetCreated()).
+		Int32("skipped", job.GetSkipped()).
+		Int32("failed", job.GetFailed()).
+		Msg("Import job streamed")
+}
+
+func (c *BookGRPCService) FindImportJob(ctx context.Context, input model.FindImportJob) (*model.ImportJob, error) {
+	job, err := c.client.FindImportJob(ctx, &proto.FindImportJobRequest{
+		Id: input.ID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelImportJob(job), nil
+}
+
+// importFormat falls back to the file extension when the format is not set
+func importFormat(input model.ImportBooks) (model.ImportFormat, error) {
+	if input.Format != nil {
+		return *input.Format, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(input.File.Filename)) {
+	case ".csv":
+		return model.ImportFormatCsv, nil
+	case ".mrc", ".marc":
+		return model.ImportFormatMarc21, nil
+	case ".xml":
+		return model.ImportFormatMarcxml, nil
+	}
+
+	return "", fmt.Errorf("import format of %s cannot be detected, please set the format", input.File.Filename)
+}
+
+func toModelImportJob(job *proto.ImportJob) *model.ImportJob {
+	rowErrors := make([]*model.ImportRowError, 0)
+	for _, rowErr := range job.GetErrors() {
+		rowErrors = append(rowErrors, &model.ImportRowError{
+			Row:     int(rowErr.GetRow()),
+			Message: rowErr.GetMessage(),
+			BookID:  optionalString(rowErr.GetBookId()),
+		})
+	}
+
+	return &model.ImportJob{
+		ID:        job.GetId(),
+		Format:    model.ImportFormat(job.GetFormat()),
+		DryRun:    job.GetDryRun(),
+		Status:    model.ImportStatus(job.GetStatus()),
+		FileName:  job.GetFileName(),
+		Processed: int(job.GetProcessed()),
+		Created:   int(job.GetCreated()),
+		Skipped:   int(job.GetSkipped()),
+		Failed:    int(job.GetFailed()),
+		Errors:    rowErrors,
+		Message:   optionalString(job.GetMessage()),
+		CreatedAt: job.GetCreatedAt().AsTime().Format(time.RFC3339),
+		UpdatedAt: job.GetUpdatedAt().AsTime().Format(time.RFC3339),
+		BranchID:  job.GetBranchId(),
+	}
+}
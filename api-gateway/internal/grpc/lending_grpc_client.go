@@ -4,11 +4,11 @@ import (
 	"context"
 	"errors"
 	"io"
-	"log"
 
 	"api-gateway/internal/domain/constant"
 	"api-gateway/internal/graph/model"
 	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/logger"
 	"api-gateway/pkg/proto"
 )
 
@@ -30,12 +30,17 @@ func (c *LendingGRPCService) LendBook(ctx context.Context, input model.NewLendin
 		return nil, errors.New("missing userID on authorization token")
 	}
 
+	var branchID string
+	if input.BranchID != nil {
+		branchID = *input.BranchID
+	}
+
 	stream, err := c.client.CreateLending(ctx, &proto.CreateLendingRequest{
-		BookId: input.BookID,
-		UserId: selfUserID,
+		BookId:   input.BookID,
+		UserId:   selfUserID,
+		BranchId: branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -51,7 +56,6 @@ func (c *LendingGRPCService) LendBook(ctx context.Context, input model.NewLendin
 			break
 		}
 		if err != nil {
-			log.Println(err)
 			if err := grpc.ParseErrorStatus(err); err != nil {
 				return nil, err
 			}
@@ -59,13 +63,7 @@ func (c *LendingGRPCService) LendBook(ctx context.Context, input model.NewLendin
 			return nil, err
 		}
 
-		lending = &model.Lending{
-			ID:         lendingStream.GetId(),
-			BookID:     lendingStream.GetBookId(),
-			UserID:     lendingStream.GetUserId(),
-			Status:     lendingStream.GetStatus(),
-			ReturnDate: lendingStream.GetReturnDate().String(),
-		}
+		lending = toModelLending(lendingStream)
 
 		switch constant.LendingStatus(lending.Status) {
 		case constant.LendingActive:
@@ -73,7 +71,7 @@ func (c *LendingGRPCService) LendBook(ctx context.Context, input model.NewLendin
 		case constant.LendingCanceled:
 			return lending, errors.New("failed to lend book")
 		default:
-			log.Printf("received lending stream: %+v", lending)
+			logger.Ctx(ctx).Debug().Str("lending_id", lending.ID).Str("status", lending.Status).Msg("Received lending stream")
 		}
 	}
 
@@ -85,7 +83,6 @@ func (c *LendingGRPCService) RenewLending(ctx context.Context, input model.Renew
 		Id: input.ID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -93,21 +90,21 @@ func (c *LendingGRPCService) RenewLending(ctx context.Context, input model.Renew
 		return nil, err
 	}
 
-	return &model.Lending{
-		ID:         lending.GetId(),
-		BookID:     lending.GetBookId(),
-		UserID:     lending.GetUserId(),
-		Status:     lending.GetStatus(),
-		ReturnDate: lending.GetReturnDate().String(),
-	}, nil
+	return toModelLending(lending), nil
 }
 
 func (c *LendingGRPCService) FinishLending(ctx context.Context, input model.FinishLendingRequest) (*model.Lending, error) {
+	// a scoped librarian receives the returned book at their own branch
+	branchID, err := branchScope(ctx, input.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
 	lending, err := c.client.FinishLending(ctx, &proto.FinishLendingRequest{
-		Id: input.ID,
+		Id:       input.ID,
+		BranchId: branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -115,13 +112,7 @@ func (c *LendingGRPCService) FinishLending(ctx context.Context, input model.Fini
 		return nil, err
 	}
 
-	return &model.Lending{
-		ID:         lending.GetId(),
-		BookID:     lending.GetBookId(),
-		UserID:     lending.GetUserId(),
-		Status:     lending.GetStatus(),
-		ReturnDate: lending.GetReturnDate().String(),
-	}, nil
+	return toModelLending(lending), nil
 }
 
 func (c *LendingGRPCService) MyLending(ctx context.Context, input *model.MyLendingRequest) (*model.LendingPaged, error) {
@@ -137,10 +128,22 @@ func (c *LendingGRPCService) MyLending(ctx context.Context, input *model.MyLendi
 		Status: input.Status,
 	}
 
-	return c.FetchLending(ctx, &fetchInput)
+	return c.fetchLending(ctx, &fetchInput, "")
 }
 
 func (c *LendingGRPCService) FetchLending(ctx context.Context, input *model.FetchLendingRequest) (*model.LendingPaged, error) {
+	if input == nil {
+		input = &model.FetchLendingRequest{}
+	}
+	branchID, err := branchScope(ctx, input.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetchLending(ctx, input, branchID)
+}
+
+func (c *LendingGRPCService) fetchLending(ctx context.Context, input *model.FetchLendingRequest, branchID string) (*model.LendingPaged, error) {
 	var (
 		limit  int32 = 10
 		page   int32 = 1
@@ -165,11 +168,11 @@ func (c *LendingGRPCService) FetchLending(ctx context.Context, input *model.Fetc
 			Limit: limit,
 			Page:  page,
 		},
-		UserId: userID,
-		Status: status,
+		UserId:   userID,
+		Status:   status,
+		BranchId: branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -179,13 +182,7 @@ func (c *LendingGRPCService) FetchLending(ctx context.Context, input *model.Fetc
 
 	lendings := make([]*model.Lending, 0)
 	for _, lending := range fetchedLending.Lendings {
-		lendings = append(lendings, &model.Lending{
-			ID:         lending.GetId(),
-			BookID:     lending.GetBookId(),
-			UserID:     lending.GetUserId(),
-			Status:     lending.GetStatus(),
-			ReturnDate: lending.GetReturnDate().String(),
-		})
+		lendings = append(lendings, toModelLending(lending))
 	}
 
 	return &model.LendingPaged{
@@ -196,3 +193,15 @@ func (c *LendingGRPCService) FetchLending(ctx context.Context, input *model.Fetc
 		LastPage:     int(fetchedLending.GetPagination().GetLastPage()),
 	}, nil
 }
+
+func toModelLending(lending *proto.Lending) *model.Lending {
+	return &model.Lending{
+		ID:             lending.GetId(),
+		BookID:         lending.GetBookId(),
+		UserID:         lending.GetUserId(),
+		Status:         lending.GetStatus(),
+		ReturnDate:     lending.GetReturnDate().String(),
+		BranchID:       optionalString(lending.GetBranchId()),
+		ReturnBranchID: optionalString(lending.GetReturnBranchId()),
+	}
+}
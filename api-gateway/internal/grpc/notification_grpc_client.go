@@ -0,0 +1,199 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/proto"
+)
+
+type NotificationGRPCService struct {
+	client proto.NotificationServiceClient
+}
+
+func NewNotificationGRPCService(
+	client proto.NotificationServiceClient,
+) *NotificationGRPCService {
+	return &NotificationGRPCService{
+		client: client,
+	}
+}
+
+func (c *NotificationGRPCService) MyNotificationPreference(ctx context.Context) (*model.NotificationPreference, error) {
+	selfUserID, exist := ctx.Value(constant.UserIDGinCtxKey).(string)
+	if !exist {
+		return nil, errors.New("missing userID on authorization token")
+	}
+
+	preference, err := c.client.FindPreference(ctx, &proto.FindPreferenceRequest{
+		UserId: selfUserID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelNotificationPreference(preference), nil
+}
+
+func (c *NotificationGRPCService) UpdateMyNotificationPreference(ctx context.Context, input model.UpdateNotificationPreference) (*model.NotificationPreference, error) {
+	selfUserID, exist := ctx.Value(constant.UserIDGinCtxKey).(string)
+	if !exist {
+		return nil, errors.New("missing userID on authorization token")
+	}
+
+	request := &proto.UpdatePreferenceRequest{
+		UserId:   selfUserID,
+		Channels: input.Channels,
+	}
+	if input.Phone != nil {
+		request.Phone = *input.Phone
+	}
+	if input.WebhookURL != nil {
+		request.WebhookUrl = *input.WebhookURL
+	}
+
+	preference, err := c.client.UpdatePreference(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelNotificationPreference(preference), nil
+}
+
+func (c *NotificationGRPCService) MyNotification(ctx context.Context, input *model.MyNotificationRequest) (*model.NotificationPaged, error) {
+	if input == nil {
+		input = &model.MyNotificationRequest{}
+	}
+	selfUserID, exist := ctx.Value(constant.UserIDGinCtxKey).(string)
+	if !exist {
+		return nil, errors.New("missing userID on authorization token")
+	}
+
+	return c.fetchNotification(ctx, &model.FetchNotificationRequest{
+		Page:   input.Page,
+		Limit:  input.Limit,
+		UserID: &selfUserID,
+		Status: input.Status,
+	})
+}
+
+func (c *NotificationGRPCService) FetchNotification(ctx context.Context, input *model.FetchNotificationRequest) (*model.NotificationPaged, error) {
+	if input == nil {
+		input = &model.FetchNotificationRequest{}
+	}
+
+	return c.fetchNotification(ctx, input)
+}
+
+func (c *NotificationGRPCService) fetchNotification(ctx context.Context, input *model.FetchNotificationRequest) (*model.NotificationPaged, error) {
+	var (
+		limit  int32 = 10
+		page   int32 = 1
+		userID string
+		status string
+	)
+	if input.Limit != nil {
+		limit = int32(*input.Limit)
+	}
+	if input.Page != nil {
+		page = int32(*input.Page)
+	}
+	if input.UserID != nil {
+		userID = *input.UserID
+	}
+	if input.Status != nil {
+		status = *input.Status
+	}
+
+	fetchedNotification, err := c.client.FetchNotification(ctx, &proto.FetchNotificationRequest{
+		Pagination: &proto.NotificationPaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+		UserId: userID,
+		Status: status,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	notifications := make([]*model.Notification, 0)
+	for _, notification := range fetchedNotification.Notifications {
+		notifications = append(notifications, toModelNotification(notification))
+	}
+
+	return &model.NotificationPaged{
+		Notifications:     notifications,
+		Page:              int(fetchedNotification.GetPagination().GetPage()),
+		Limit:             int(fetchedNotification.GetPagination().GetLimit()),
+		TotalNotification: int(fetchedNotification.GetPagination().GetTotal()),
+		LastPage:          int(fetchedNotification.GetPagination().GetLastPage()),
+	}, nil
+}
+
+func (c *NotificationGRPCService) RetryNotification(ctx context.Context, input model.RetryNotificationRequest) (*model.Notification, error) {
+	notification, err := c.client.RetryNotification(ctx, &proto.RetryNotificationRequest{
+		Id: input.ID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelNotification(notification), nil
+}
+
+func toModelNotificationPreference(preference *proto.Preference) *model.NotificationPreference {
+	channels := preference.GetChannels()
+	if channels == nil {
+		channels = make([]string, 0)
+	}
+
+	return &model.NotificationPreference{
+		UserID:     preference.GetUserId(),
+		Email:      preference.GetEmail(),
+		Phone:      optionalString(preference.GetPhone()),
+		WebhookURL: optionalString(preference.GetWebhookUrl()),
+		Channels:   channels,
+	}
+}
+
+func toModelNotification(notification *proto.Notification) *model.Notification {
+	modelNotification := &model.Notification{
+		ID:        notification.GetId(),
+		UserID:    notification.GetUserId(),
+		Kind:      notification.GetKind(),
+		Channel:   notification.GetChannel(),
+		Recipient: notification.GetRecipient(),
+		Subject:   notification.GetSubject(),
+		Body:      notification.GetBody(),
+		Status:    notification.GetStatus(),
+		Attempts:  int(notification.GetAttempts()),
+		LastError: optionalString(notification.GetLastError()),
+		CreatedAt: notification.GetCreatedAt().AsTime().Format(time.RFC3339),
+	}
+	if notification.SentAt != nil {
+		sentAt := notification.GetSentAt().AsTime().Format(time.RFC3339)
+		modelNotification.SentAt = &sentAt
+	}
+	return modelNotification
+}
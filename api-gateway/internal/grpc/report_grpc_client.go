@@ -0,0 +1,222 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/proto"
+)
+
+const (
+	reportDateLayout   = "2006-01-02"
+	defaultReportLimit = 10
+	maxReportLimit     = 100
+)
+
+// CheckReportRequest reports an invalid range or a branch out of the scope of the librarian before any report is run
+func CheckReportRequest(ctx context.Context, input model.ReportRequest) error {
+	_, err := toProtoReportRequest(ctx, input)
+	return err
+}
+
+func (c *LendingGRPCService) LoansPerPeriod(ctx context.Context, input model.ReportRequest) ([]*model.PeriodCount, error) {
+	request, err := toProtoReportRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.LoansPerPeriod(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	periods := make([]*model.PeriodCount, 0, len(response.Periods))
+	for _, period := range response.Periods {
+		periods = append(periods, &model.PeriodCount{
+			Period:  period.GetPeriod(),
+			Loans:   int(period.GetLoans()),
+			Returns: int(period.GetReturns()),
+		})
+	}
+
+	return periods, nil
+}
+
+func (c *LendingGRPCService) TopBorrowedBooks(ctx context.Context, input model.ReportRequest) ([]*model.BookLoanCount, error) {
+	request, err := toProtoReportRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.TopBorrowedBooks(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	books := make([]*model.BookLoanCount, 0, len(response.Books))
+	for _, book := range response.Books {
+		books = append(books, &model.BookLoanCount{
+			BookID: book.GetBookId(),
+			Loans:  int(book.GetLoans()),
+		})
+	}
+
+	return books, nil
+}
+
+// BorrowedBookIDs returns the books lent in the range, see BookGRPCService.NeverBorrowedBooks
+func (c *LendingGRPCService) BorrowedBookIDs(ctx context.Context, input model.ReportRequest) ([]string, error) {
+	request, err := toProtoReportRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.BorrowedBooks(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return response.GetBookIds(), nil
+}
+
+func (c *LendingGRPCService) CirculationSummary(ctx context.Context, input model.ReportRequest) (*model.CirculationSummary, error) {
+	request, err := toProtoReportRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.CirculationSummary(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return &model.CirculationSummary{
+		Loans:           int(response.GetLoans()),
+		Returns:         int(response.GetReturns()),
+		AverageLoanDays: response.GetAverageLoanDays(),
+		Overdue:         int(response.GetOverdue()),
+		OverdueRate:     response.GetOverdueRate(),
+		ActiveMembers:   int(response.GetActiveMembers()),
+	}, nil
+}
+
+func (c *LendingGRPCService) ActiveMembers(ctx context.Context, input model.ReportRequest) ([]*model.MemberActivity, error) {
+	request, err := toProtoReportRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.ActiveMembers(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	members := make([]*model.MemberActivity, 0, len(response.Members))
+	for _, member := range response.Members {
+		members = append(members, &model.MemberActivity{
+			UserID:  member.GetUserId(),
+			Loans:   int(member.GetLoans()),
+			Active:  int(member.GetActive()),
+			Overdue: int(member.GetOverdue()),
+		})
+	}
+
+	return members, nil
+}
+
+// NeverBorrowedBooks returns the first books of the catalog without the borrowed ones
+func (c *BookGRPCService) NeverBorrowedBooks(ctx context.Context, borrowedBookIDs []string, input model.ReportRequest) ([]*model.Book, error) {
+	request, err := toProtoReportRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultReportLimit
+	}
+
+	fetchBookResponse, err := c.client.FetchBook(ctx, &proto.FetchBookRequest{
+		Pagination: &proto.BookPaginationRequest{
+			Limit: limit,
+			Page:  1,
+		},
+		BranchId:   request.BranchId,
+		ExcludeIds: borrowedBookIDs,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	books := make([]*model.Book, 0, len(fetchBookResponse.Books))
+	for _, book := range fetchBookResponse.Books {
+		books = append(books, toModelBook(book))
+	}
+
+	return books, nil
+}
+
+// toProtoReportRequest converts the days of the report to a range ending at the midnight after the last day
+func toProtoReportRequest(ctx context.Context, input model.ReportRequest) (*proto.ReportRequest, error) {
+	from, err := time.Parse(reportDateLayout, input.From)
+	if err != nil {
+		return nil, &grpc.StatusError{Code: codes.InvalidArgument, Message: fmt.Sprintf("invalid report start date, want YYYY-MM-DD: %s", input.From)}
+	}
+	to, err := time.Parse(reportDateLayout, input.To)
+	if err != nil {
+		return nil, &grpc.StatusError{Code: codes.InvalidArgument, Message: fmt.Sprintf("invalid report end date, want YYYY-MM-DD: %s", input.To)}
+	}
+
+	// a scoped librarian only reports the lendings of their own branch
+	branchID, err := branchScope(ctx, input.BranchID)
+	if err != nil {
+		return nil, &grpc.StatusError{Code: codes.PermissionDenied, Message: err.Error()}
+	}
+
+	request := &proto.ReportRequest{
+		From:     timestamppb.New(from),
+		To:       timestamppb.New(to.AddDate(0, 0, 1)),
+		BranchId: branchID,
+	}
+	if input.Period != nil {
+		request.Period = input.Period.String()
+	}
+	if input.Limit != nil {
+		if *input.Limit < 0 || *input.Limit > maxReportLimit {
+			return nil, &grpc.StatusError{Code: codes.InvalidArgument, Message: fmt.Sprintf("report limit must be between 0 and %d", maxReportLimit)}
+		}
+		request.Limit = int32(*input.Limit)
+	}
+
+	return request, nil
+}
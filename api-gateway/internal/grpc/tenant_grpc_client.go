@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/proto"
+)
+
+func (c *UserGRPCService) CreateTenant(ctx context.Context, input model.NewTenant) (*model.Tenant, error) {
+	tenant, err := c.client.CreateTenant(ctx, &proto.CreateTenantRequest{
+		Code:          input.Code,
+		Name:          input.Name,
+		AdminEmail:    input.AdminEmail,
+		AdminPassword: input.AdminPassword,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelTenant(tenant), nil
+}
+
+func (c *UserGRPCService) FetchTenant(ctx context.Context, filter model.FetchTenantFilter) (*model.TenantPaged, error) {
+	var (
+		limit int32 = 10
+		page  int32 = 1
+	)
+	if filter.Limit != nil {
+		limit = int32(*filter.Limit)
+	}
+	if filter.Page != nil {
+		page = int32(*filter.Page)
+	}
+
+	fetchTenantResponse, err := c.client.FetchTenant(ctx, &proto.FetchTenantRequest{
+		Pagination: &proto.PaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	tenants := make([]*model.Tenant, 0)
+	for _, fetchedTenant := range fetchTenantResponse.Tenants {
+		tenants = append(tenants, toModelTenant(fetchedTenant))
+	}
+
+	return &model.TenantPaged{
+		Tenants:     tenants,
+		Page:        int(fetchTenantResponse.Pagination.GetPage()),
+		Limit:       int(fetchTenantResponse.Pagination.GetLimit()),
+		TotalTenant: int(fetchTenantResponse.Pagination.GetTotal()),
+		LastPage:    int(fetchTenantResponse.Pagination.GetLastPage()),
+	}, nil
+}
+
+func toModelTenant(tenant *proto.Tenant) *model.Tenant {
+	return &model.Tenant{
+		ID:   tenant.GetId(),
+		Code: tenant.GetCode(),
+		Name: tenant.GetName(),
+	}
+}
@@ -3,12 +3,12 @@ package grpc
 import (
 	"context"
 	"errors"
-	"log"
 
 	"api-gateway/internal/domain/constant"
 	"api-gateway/internal/graph/model"
 	"api-gateway/pkg/grpc"
 	"api-gateway/pkg/proto"
+	"api-gateway/pkg/tenant"
 )
 
 type UserGRPCService struct {
@@ -24,13 +24,18 @@ func NewUserGRPCService(
 }
 
 func (c *UserGRPCService) CreateUser(ctx context.Context, input model.NewUser, role model.Role) (*model.User, error) {
+	var branchID string
+	if input.BranchID != nil {
+		branchID = *input.BranchID
+	}
+
 	user, err := c.client.CreateUser(ctx, &proto.CreateUserRequest{
 		Email:    input.Email,
 		Password: input.Password,
 		Role:     string(role),
+		BranchId: branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -38,20 +43,19 @@ func (c *UserGRPCService) CreateUser(ctx context.Context, input model.NewUser, r
 		return nil, err
 	}
 
-	return &model.User{
-		ID:    user.GetId(),
-		Email: user.GetEmail(),
-		Role:  model.Role(user.GetRole()),
-	}, nil
+	return toModelUser(user), nil
 }
 
 func (c *UserGRPCService) Login(ctx context.Context, input model.Login) (string, error) {
+	if input.Tenant != nil && *input.Tenant != "" {
+		ctx = tenant.NewContext(ctx, *input.Tenant)
+	}
+
 	loginResponse, err := c.client.Login(ctx, &proto.LoginRequest{
 		Email:    input.Email,
 		Password: input.Password,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return "", err
 		}
@@ -64,10 +68,11 @@ func (c *UserGRPCService) Login(ctx context.Context, input model.Login) (string,
 
 func (c *UserGRPCService) FetchUser(ctx context.Context, filter model.FetchUserFilter) (*model.UserPaged, error) {
 	var (
-		limit int32 = 10
-		page  int32 = 1
-		email string
-		role  string
+		limit    int32 = 10
+		page     int32 = 1
+		email    string
+		role     string
+		branchID string
 	)
 	if filter.Limit != nil {
 		limit = int32(*filter.Limit)
@@ -81,17 +86,20 @@ func (c *UserGRPCService) FetchUser(ctx context.Context, filter model.FetchUserF
 	if filter.Role != nil {
 		role = string(*filter.Role)
 	}
+	if filter.BranchID != nil {
+		branchID = *filter.BranchID
+	}
 
 	fetchUserResponse, err := c.client.FetchUser(ctx, &proto.FetchUserRequest{
 		Pagination: &proto.PaginationRequest{
 			Limit: limit,
 			Page:  page,
 		},
-		Email: email,
-		Role:  role,
+		Email:    email,
+		Role:     role,
+		BranchId: branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -101,11 +109,7 @@ func (c *UserGRPCService) FetchUser(ctx context.Context, filter model.FetchUserF
 
 	users := make([]*model.User, 0)
 	for _, fetchedUser := range fetchUserResponse.Users {
-		users = append(users, &model.User{
-			ID:    fetchedUser.GetId(),
-			Email: fetchedUser.GetEmail(),
-			Role:  model.Role(fetchedUser.GetRole()),
-		})
+		users = append(users, toModelUser(fetchedUser))
 	}
 
 	return &model.UserPaged{
@@ -122,7 +126,6 @@ func (c *UserGRPCService) FindByID(ctx context.Context, id string) (*model.User,
 		Id: id,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -130,11 +133,7 @@ func (c *UserGRPCService) FindByID(ctx context.Context, id string) (*model.User,
 		return nil, err
 	}
 
-	return &model.User{
-		ID:    user.GetId(),
-		Email: user.GetEmail(),
-		Role:  model.Role(user.GetRole()),
-	}, nil
+	return toModelUser(user), nil
 }
 
 func (c *UserGRPCService) FindByEmail(ctx context.Context, email string) (*model.User, error) {
@@ -142,7 +141,6 @@ func (c *UserGRPCService) FindByEmail(ctx context.Context, email string) (*model
 		Email: email,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -150,20 +148,21 @@ func (c *UserGRPCService) FindByEmail(ctx context.Context, email string) (*model
 		return nil, err
 	}
 
-	return &model.User{
-		ID:    user.GetId(),
-		Email: user.GetEmail(),
-		Role:  model.Role(user.GetRole()),
-	}, nil
+	return toModelUser(user), nil
 }
 
 func (c *UserGRPCService) UpdateUser(ctx context.Context, input model.UpdateUser) (*model.User, error) {
+	var branchID string
+	if input.BranchID != nil {
+		branchID = *input.BranchID
+	}
+
 	user, err := c.client.UpdateUser(ctx, &proto.UpdateUserRequest{
-		Id:    input.ID,
-		Email: input.Email,
+		Id:       input.ID,
+		Email:    input.Email,
+		BranchId: branchID,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -171,11 +170,7 @@ func (c *UserGRPCService) UpdateUser(ctx context.Context, input model.UpdateUser
 		return nil, err
 	}
 
-	return &model.User{
-		ID:    user.GetId(),
-		Email: user.GetEmail(),
-		Role:  model.Role(user.GetRole()),
-	}, nil
+	return toModelUser(user), nil
 }
 
 func (c *UserGRPCService) UpdateSelf(ctx context.Context, input model.UpdateUser) (*model.User, error) {
@@ -190,7 +185,6 @@ func (c *UserGRPCService) UpdateSelf(ctx context.Context, input model.UpdateUser
 		Email:     input.Email,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return nil, err
 		}
@@ -198,19 +192,15 @@ func (c *UserGRPCService) UpdateSelf(ctx context.Context, input model.UpdateUser
 		return nil, err
 	}
 
-	return &model.User{
-		ID:    user.GetId(),
-		Email: user.GetEmail(),
-		Role:  model.Role(user.GetRole()),
-	}, nil
+	return toModelUser(user), nil
 }
 
 func (c *UserGRPCService) DeleteUser(ctx context.Context, input model.DeleteUser) error {
 	_, err := c.client.DeleteUser(ctx, &proto.DeleteUserRequest{
 		Email: input.Email,
+		Force: input.Force != nil && *input.Force,
 	})
 	if err != nil {
-		log.Println(err)
 		if err := grpc.ParseErrorStatus(err); err != nil {
 			return err
 		}
@@ -220,3 +210,12 @@ func (c *UserGRPCService) DeleteUser(ctx context.Context, input model.DeleteUser
 
 	return nil
 }
+
+func toModelUser(user *proto.User) *model.User {
+	return &model.User{
+		ID:       user.GetId(),
+		Email:    user.GetEmail(),
+		Role:     model.Role(user.GetRole()),
+		BranchID: optionalString(user.GetBranchId()),
+	}
+}
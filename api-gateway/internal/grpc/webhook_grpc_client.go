@@ -0,0 +1,290 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"api-gateway/internal/graph/model"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/proto"
+)
+
+// the statuses of the webhook deliveries, the failed deliveries are the dead letters
+const (
+	deliveryPending = "PENDING"
+	deliveryFailed  = "FAILED"
+)
+
+type WebhookGRPCService struct {
+	client proto.WebhookServiceClient
+}
+
+func NewWebhookGRPCService(
+	client proto.WebhookServiceClient,
+) *WebhookGRPCService {
+	return &WebhookGRPCService{
+		client: client,
+	}
+}
+
+func (c *WebhookGRPCService) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.Webhook, error) {
+	request := &proto.CreateWebhookRequest{
+		Url:        input.URL,
+		EventTypes: input.EventTypes,
+	}
+	if input.Secret != nil {
+		request.Secret = *input.Secret
+	}
+
+	webhook, err := c.client.CreateWebhook(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelWebhook(webhook), nil
+}
+
+func (c *WebhookGRPCService) UpdateWebhook(ctx context.Context, input model.UpdateWebhook) (*model.Webhook, error) {
+	request := &proto.UpdateWebhookRequest{
+		Id:         input.ID,
+		Url:        input.URL,
+		EventTypes: input.EventTypes,
+		Active:     input.Active,
+	}
+	if input.RotateSecret != nil {
+		request.RotateSecret = *input.RotateSecret
+	}
+
+	webhook, err := c.client.UpdateWebhook(ctx, request)
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelWebhook(webhook), nil
+}
+
+func (c *WebhookGRPCService) DeleteWebhook(ctx context.Context, input model.DeleteWebhook) (*model.Webhook, error) {
+	webhook, err := c.client.DeleteWebhook(ctx, &proto.DeleteWebhookRequest{
+		Id: input.ID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelWebhook(webhook), nil
+}
+
+func (c *WebhookGRPCService) FetchWebhook(ctx context.Context, input *model.FetchWebhookRequest) (*model.WebhookPaged, error) {
+	var (
+		limit int32 = 10
+		page  int32 = 1
+	)
+	if input != nil && input.Limit != nil {
+		limit = int32(*input.Limit)
+	}
+	if input != nil && input.Page != nil {
+		page = int32(*input.Page)
+	}
+
+	fetchedWebhook, err := c.client.FetchWebhook(ctx, &proto.FetchWebhookRequest{
+		Pagination: &proto.NotificationPaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	webhooks := make([]*model.Webhook, 0)
+	for _, webhook := range fetchedWebhook.Webhooks {
+		webhooks = append(webhooks, toModelWebhook(webhook))
+	}
+
+	return &model.WebhookPaged{
+		Webhooks:     webhooks,
+		Page:         int(fetchedWebhook.GetPagination().GetPage()),
+		Limit:        int(fetchedWebhook.GetPagination().GetLimit()),
+		TotalWebhook: int(fetchedWebhook.GetPagination().GetTotal()),
+		LastPage:     int(fetchedWebhook.GetPagination().GetLastPage()),
+	}, nil
+}
+
+func (c *WebhookGRPCService) FetchWebhookDelivery(ctx context.Context, input *model.FetchWebhookDeliveryRequest) (*model.WebhookDeliveryPaged, error) {
+	if input == nil {
+		input = &model.FetchWebhookDeliveryRequest{}
+	}
+
+	return c.fetchWebhookDelivery(ctx, input)
+}
+
+// FetchWebhookDeadLetter returns the deliveries out of attempts, they are only sent again by RedeliverWebhookDelivery
+func (c *WebhookGRPCService) FetchWebhookDeadLetter(ctx context.Context, input *model.FetchWebhookDeadLetterRequest) (*model.WebhookDeliveryPaged, error) {
+	if input == nil {
+		input = &model.FetchWebhookDeadLetterRequest{}
+	}
+	status := deliveryFailed
+
+	return c.fetchWebhookDelivery(ctx, &model.FetchWebhookDeliveryRequest{
+		Page:      input.Page,
+		Limit:     input.Limit,
+		WebhookID: input.WebhookID,
+		Status:    &status,
+	})
+}
+
+func (c *WebhookGRPCService) fetchWebhookDelivery(ctx context.Context, input *model.FetchWebhookDeliveryRequest) (*model.WebhookDeliveryPaged, error) {
+	var (
+		limit     int32 = 10
+		page      int32 = 1
+		webhookID string
+		eventType string
+		status    string
+	)
+	if input.Limit != nil {
+		limit = int32(*input.Limit)
+	}
+	if input.Page != nil {
+		page = int32(*input.Page)
+	}
+	if input.WebhookID != nil {
+		webhookID = *input.WebhookID
+	}
+	if input.EventType != nil {
+		eventType = *input.EventType
+	}
+	if input.Status != nil {
+		status = *input.Status
+	}
+
+	fetchedDelivery, err := c.client.FetchWebhookDelivery(ctx, &proto.FetchWebhookDeliveryRequest{
+		Pagination: &proto.NotificationPaginationRequest{
+			Limit: limit,
+			Page:  page,
+		},
+		WebhookId: webhookID,
+		EventType: eventType,
+		Status:    status,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	for _, delivery := range fetchedDelivery.Deliveries {
+		deliveries = append(deliveries, toModelWebhookDelivery(delivery))
+	}
+
+	return &model.WebhookDeliveryPaged{
+		Deliveries:    deliveries,
+		Page:          int(fetchedDelivery.GetPagination().GetPage()),
+		Limit:         int(fetchedDelivery.GetPagination().GetLimit()),
+		TotalDelivery: int(fetchedDelivery.GetPagination().GetTotal()),
+		LastPage:      int(fetchedDelivery.GetPagination().GetLastPage()),
+	}, nil
+}
+
+func (c *WebhookGRPCService) RedeliverWebhookDelivery(ctx context.Context, input model.RedeliverWebhookDelivery) (*model.WebhookDelivery, error) {
+	delivery, err := c.client.RedeliverWebhookDelivery(ctx, &proto.RedeliverWebhookDeliveryRequest{
+		Id: input.ID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelWebhookDelivery(delivery), nil
+}
+
+func (c *WebhookGRPCService) SendTestWebhookEvent(ctx context.Context, input model.SendTestWebhookEvent) (*model.WebhookDelivery, error) {
+	delivery, err := c.client.SendTestWebhookEvent(ctx, &proto.SendTestWebhookEventRequest{
+		WebhookId: input.WebhookID,
+	})
+	if err != nil {
+		if err := grpc.ParseErrorStatus(err); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return toModelWebhookDelivery(delivery), nil
+}
+
+func toModelWebhook(webhook *proto.Webhook) *model.Webhook {
+	eventTypes := webhook.GetEventTypes()
+	if eventTypes == nil {
+		eventTypes = make([]string, 0)
+	}
+
+	return &model.Webhook{
+		ID:         webhook.GetId(),
+		URL:        webhook.GetUrl(),
+		Secret:     optionalString(webhook.GetSecret()),
+		EventTypes: eventTypes,
+		Active:     webhook.GetActive(),
+		CreatedAt:  webhook.GetCreatedAt().AsTime().Format(time.RFC3339),
+		UpdatedAt:  webhook.GetUpdatedAt().AsTime().Format(time.RFC3339),
+	}
+}
+
+func toModelWebhookDelivery(delivery *proto.WebhookDelivery) *model.WebhookDelivery {
+	log := make([]*model.WebhookAttempt, 0, len(delivery.GetLog()))
+	for _, attempt := range delivery.GetLog() {
+		modelAttempt := &model.WebhookAttempt{
+			AttemptedAt: attempt.GetAttemptedAt().AsTime().Format(time.RFC3339),
+			Error:       optionalString(attempt.GetError()),
+			DurationMs:  int(attempt.GetDurationMs()),
+		}
+		if statusCode := int(attempt.GetStatusCode()); statusCode != 0 {
+			modelAttempt.StatusCode = &statusCode
+		}
+		log = append(log, modelAttempt)
+	}
+
+	modelDelivery := &model.WebhookDelivery{
+		ID:        delivery.GetId(),
+		WebhookID: delivery.GetWebhookId(),
+		EventID:   delivery.GetEventId(),
+		EventType: delivery.GetEventType(),
+		Payload:   delivery.GetPayload(),
+		Status:    delivery.GetStatus(),
+		Attempts:  int(delivery.GetAttempts()),
+		Log:       log,
+		LastError: optionalString(delivery.GetLastError()),
+		CreatedAt: delivery.GetCreatedAt().AsTime().Format(time.RFC3339),
+	}
+	// only a pending delivery has a next attempt
+	if delivery.GetStatus() == deliveryPending && delivery.NextAttemptAt != nil {
+		nextAttemptAt := delivery.GetNextAttemptAt().AsTime().Format(time.RFC3339)
+		modelDelivery.NextAttemptAt = &nextAttemptAt
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt := delivery.GetDeliveredAt().AsTime().Format(time.RFC3339)
+		modelDelivery.DeliveredAt = &deliveredAt
+	}
+	return modelDelivery
+}
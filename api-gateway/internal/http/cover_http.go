@@ -0,0 +1,54 @@
+package http_handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+
+	grpcClient "api-gateway/internal/grpc"
+
+	"api-gateway/pkg/logger"
+)
+
+const (
+	// a versioned cover URL never changes its content
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	coverCacheControl     = "public, max-age=300"
+)
+
+// CoverHandler serves the book cover, e.g. GET /covers/<book ID>?size=small&v=<version>
+func CoverHandler(bookGRPCService *grpcClient.BookGRPCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		size := c.DefaultQuery("size", "medium")
+		bookCover, err := bookGRPCService.FetchBookCover(c.Request.Context(), c.Param("id"), size)
+		if err != nil {
+			c.AbortWithStatusJSON(httpStatusFromGRPC(err),
+				map[string]interface{}{
+					"errors": status.Convert(err).Message(),
+				})
+			return
+		}
+
+		cacheControl := coverCacheControl
+		if c.Query("v") == bookCover.Version {
+			cacheControl = immutableCacheControl
+		}
+		etag := fmt.Sprintf(`"%s-%s"`, bookCover.Version, size)
+		c.Header("Cache-Control", cacheControl)
+		c.Header("ETag", etag)
+
+		if c.GetHeader("If-None-Match") == etag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+
+		c.Header("Content-Type", bookCover.ContentType)
+		c.Status(http.StatusOK)
+		if _, err = io.Copy(c.Writer, bookCover.Content); err != nil {
+			logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Error serving book cover")
+		}
+	}
+}
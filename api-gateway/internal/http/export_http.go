@@ -0,0 +1,85 @@
+package http_handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpcClient "api-gateway/internal/grpc"
+
+	"api-gateway/pkg/logger"
+)
+
+var exportContentTypes = map[string]struct {
+	contentType string
+	extension   string
+}{
+	"csv":     {contentType: "text/csv; charset=utf-8", extension: "csv"},
+	"jsonl":   {contentType: "application/x-ndjson", extension: "jsonl"},
+	"marcxml": {contentType: "application/marcxml+xml", extension: "xml"},
+}
+
+// ExportBooksHandler downloads the book catalog,
+// e.g. GET /export/books?format=csv&title=go&category_id=<id>&tags=programming,go
+func ExportBooksHandler(bookGRPCService *grpcClient.BookGRPCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", "csv")
+		content, ok := exportContentTypes[format]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				map[string]interface{}{
+					"errors": fmt.Sprintf("unsupported export format: %s", format),
+				})
+			return
+		}
+
+		var tags []string
+		if tagsQuery := c.Query("tags"); tagsQuery != "" {
+			tags = strings.Split(tagsQuery, ",")
+		}
+
+		reader, err := bookGRPCService.ExportBooks(c.Request.Context(), grpcClient.ExportBooksFilter{
+			Format:     format,
+			Title:      c.Query("title"),
+			CategoryID: c.Query("category_id"),
+			Tags:       tags,
+		})
+		if err != nil {
+			c.AbortWithStatusJSON(httpStatusFromGRPC(err),
+				map[string]interface{}{
+					"errors": status.Convert(err).Message(),
+				})
+			return
+		}
+
+		fileName := fmt.Sprintf("books-%s.%s", time.Now().Format("20060102-150405"), content.extension)
+		c.Header("Content-Type", content.contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+		c.Status(http.StatusOK)
+
+		// the status is already sent, an interrupted export can only be logged
+		if _, err = io.Copy(c.Writer, reader); err != nil {
+			logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Error exporting books")
+		}
+	}
+}
+
+func httpStatusFromGRPC(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
@@ -9,12 +9,17 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 
 	"api-gateway/internal/domain/constant"
 	"api-gateway/internal/graph"
 	"api-gateway/internal/graph/generated"
 	"api-gateway/internal/graph/model"
 	grpcClient "api-gateway/internal/grpc"
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/metrics"
+	"api-gateway/pkg/tracing"
 )
 
 func GraphPlaygroundHandler() gin.HandlerFunc {
@@ -28,14 +33,18 @@ func GraphQLHandler(
 	userGRPCService *grpcClient.UserGRPCService,
 	bookGRPCService *grpcClient.BookGRPCService,
 	lendingGRPCService *grpcClient.LendingGRPCService,
+	notificationGRPCService *grpcClient.NotificationGRPCService,
+	webhookGRPCService *grpcClient.WebhookGRPCService,
 ) gin.HandlerFunc {
 	h := handler.NewDefaultServer(
 		generated.NewExecutableSchema(
 			generated.Config{
 				Resolvers: &graph.Resolver{
-					UserGRPCService:    userGRPCService,
-					BookGRPCService:    bookGRPCService,
-					LendingGRPCService: lendingGRPCService,
+					UserGRPCService:         userGRPCService,
+					BookGRPCService:         bookGRPCService,
+					LendingGRPCService:      lendingGRPCService,
+					NotificationGRPCService: notificationGRPCService,
+					WebhookGRPCService:      webhookGRPCService,
 				},
 				Directives: generated.DirectiveRoot{
 					IsAuthenticated: isAuthenticatedDirectiveConfig(),
@@ -44,12 +53,37 @@ func GraphQLHandler(
 			},
 		),
 	)
+	h.Use(metrics.GraphQLExtension{})
+	h.Use(tracing.GraphQLExtension{})
+	h.SetErrorPresenter(errorPresenter)
 
 	return func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
 	}
 }
 
+// errorPresenter logs the errors and adds the request ID and user to their extensions, so a client can report them.
+// The errors of the services also get their gRPC code, so a client can retry an Unavailable one.
+func errorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = make(map[string]interface{})
+	}
+	var statusErr *grpc.StatusError
+	if errors.As(err, &statusErr) {
+		gqlErr.Extensions["code"] = statusErr.Code.String()
+	}
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		gqlErr.Extensions["request_id"] = requestID
+	}
+	if userID := logger.UserIDFromContext(ctx); userID != "" {
+		gqlErr.Extensions["user_id"] = userID
+	}
+
+	logger.Ctx(ctx).Warn().Err(err).Str("graphql_path", gqlErr.Path.String()).Msg("GraphQL error")
+	return gqlErr
+}
+
 func isAuthenticatedDirectiveConfig() func(
 	ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
 	return func(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
@@ -0,0 +1,36 @@
+package http_handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"api-gateway/pkg/grpc"
+	"api-gateway/pkg/logger"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	ctx := logger.NewRequestIDContext(context.Background(), "request-1")
+	ctx = logger.NewUserIDContext(ctx, "user-1")
+
+	gqlErr := errorPresenter(ctx, gqlerror.WrapPath(nil, errors.New("[NotFound] book with 1 ID is not found")))
+	if gqlErr.Message != "[NotFound] book with 1 ID is not found" {
+		t.Errorf("message = %s", gqlErr.Message)
+	}
+	if gqlErr.Extensions["request_id"] != "request-1" || gqlErr.Extensions["user_id"] != "user-1" {
+		t.Errorf("extensions = %v, want the request ID and user", gqlErr.Extensions)
+	}
+
+	gqlErr = errorPresenter(ctx, gqlerror.WrapPath(nil, grpc.ParseErrorStatus(status.Error(codes.Unavailable, "book-service is temporarily unavailable"))))
+	if gqlErr.Message != "[Unavailable] book-service is temporarily unavailable" || gqlErr.Extensions["code"] != "Unavailable" {
+		t.Errorf("unavailable error = %s %v, want its code in the extensions", gqlErr.Message, gqlErr.Extensions)
+	}
+
+	if gqlErr = errorPresenter(context.Background(), gqlerror.WrapPath(nil, errors.New("unauthorized"))); len(gqlErr.Extensions) != 0 {
+		t.Errorf("extensions of an anonymous request = %v, want none", gqlErr.Extensions)
+	}
+}
@@ -0,0 +1,45 @@
+package http_handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	grpcClient "api-gateway/internal/grpc"
+)
+
+const readinessTimeout = 2 * time.Second
+
+// LivenessHandler tells the gateway is running, it stays alive while a backend is down
+func LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"status": "ok",
+		})
+	}
+}
+
+// ReadinessHandler tells the gateway can serve the requests, that is when all the backends are serving
+func ReadinessHandler(healthGRPCService *grpcClient.HealthGRPCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+
+		backends := healthGRPCService.Check(ctx)
+
+		code, status := http.StatusOK, "ready"
+		for _, backend := range backends {
+			if !backend.Serving() {
+				code, status = http.StatusServiceUnavailable, "degraded"
+				break
+			}
+		}
+
+		c.JSON(code, map[string]interface{}{
+			"status":   status,
+			"backends": backends,
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package http_handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	grpcClient "api-gateway/internal/grpc"
+)
+
+type fakeHealthClient struct {
+	healthpb.HealthClient
+	status healthpb.HealthCheckResponse_ServingStatus
+	err    error
+}
+
+func (c *fakeHealthClient) Check(context.Context, *healthpb.HealthCheckRequest, ...grpc.CallOption) (*healthpb.HealthCheckResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &healthpb.HealthCheckResponse{Status: c.status}, nil
+}
+
+func TestReadinessHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serving := &fakeHealthClient{status: healthpb.HealthCheckResponse_SERVING}
+	tests := []struct {
+		name         string
+		clients      map[string]healthpb.HealthClient
+		wantCode     int
+		wantStatuses map[string]string
+	}{
+		{
+			name:         "all backends serving",
+			clients:      map[string]healthpb.HealthClient{"user-service": serving, "book-service": serving},
+			wantCode:     http.StatusOK,
+			wantStatuses: map[string]string{"user-service": "SERVING", "book-service": "SERVING"},
+		},
+		{
+			name: "backend without database or unreachable",
+			clients: map[string]healthpb.HealthClient{
+				"user-service":    serving,
+				"book-service":    &fakeHealthClient{status: healthpb.HealthCheckResponse_NOT_SERVING},
+				"lending-service": &fakeHealthClient{err: status.Error(codes.Unavailable, "connection refused")},
+			},
+			wantCode:     http.StatusServiceUnavailable,
+			wantStatuses: map[string]string{"user-service": "SERVING", "book-service": "NOT_SERVING", "lending-service": "NOT_SERVING"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/readyz", ReadinessHandler(grpcClient.NewHealthGRPCService(tt.clients)))
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if recorder.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", recorder.Code, tt.wantCode)
+			}
+
+			var body struct {
+				Backends map[string]grpcClient.BackendHealth `json:"backends"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range tt.wantStatuses {
+				if got := body.Backends[name].Status; got != want {
+					t.Errorf("%s status = %s, want %s", name, got, want)
+				}
+			}
+		})
+	}
+}
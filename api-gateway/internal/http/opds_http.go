@@ -0,0 +1,284 @@
+package http_handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/graph/model"
+	grpcClient "api-gateway/internal/grpc"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/opds"
+)
+
+const (
+	opdsPath         = "/opds"
+	opdsBooksPath    = opdsPath + "/books"
+	opdsCategoryPath = opdsPath + "/categories"
+	opdsSearchPath   = opdsPath + "/opensearch.xml"
+	opdsPageSize     = 20
+	opdsIDPrefix     = "urn:book-lib:"
+)
+
+// OPDSRootHandler serves the navigation feed which e-reader apps open first
+func OPDSRootHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now().UTC().Format(time.RFC3339)
+
+		feed := opds.NewFeed(opdsIDPrefix+"root", "Book Library", now)
+		feed.Links = append(opdsCommonLinks(),
+			opds.Link{Rel: opds.RelSelf, Href: opdsPath, Type: opds.NavigationType},
+		)
+		feed.Entries = []opds.Entry{
+			{
+				ID:      opdsIDPrefix + "books",
+				Title:   "All books",
+				Updated: now,
+				Content: &opds.Content{Type: "text", Value: "The whole book catalog, newest first"},
+				Links:   []opds.Link{{Rel: opds.RelSubsection, Href: opdsBooksPath, Type: opds.AcquisitionType}},
+			},
+			{
+				ID:      opdsIDPrefix + "categories",
+				Title:   "Categories",
+				Updated: now,
+				Content: &opds.Content{Type: "text", Value: "Browse the books by category"},
+				Links:   []opds.Link{{Rel: opds.RelSubsection, Href: opdsCategoryPath, Type: opds.NavigationType}},
+			},
+		}
+
+		writeOPDS(c, opds.NavigationType, feed)
+	}
+}
+
+// OPDSBooksHandler serves the acquisition feed, searched with q and filtered with category_id
+func OPDSBooksHandler(bookGRPCService *grpcClient.BookGRPCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit := opdsPageSize
+
+		filter := model.FetchBookFilter{
+			Page:  &page,
+			Limit: &limit,
+		}
+		query := url.Values{}
+		if q := c.Query("q"); q != "" {
+			// the search terms are not a pattern
+			title := regexp.QuoteMeta(q)
+			filter.Title = &title
+			query.Set("q", q)
+		}
+		if categoryID := c.Query("category_id"); categoryID != "" {
+			filter.CategoryID = &categoryID
+			query.Set("category_id", categoryID)
+		}
+
+		books, err := bookGRPCService.FetchBook(c.Request.Context(), filter)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadGateway,
+				map[string]interface{}{
+					"errors": err.Error(),
+				})
+			return
+		}
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		title := "All books"
+		if q := query.Get("q"); q != "" {
+			title = fmt.Sprintf("Search: %s", q)
+		}
+
+		feedID := opdsIDPrefix + "books"
+		if len(query) > 0 {
+			feedID += "?" + query.Encode()
+		}
+		feed := opds.NewFeed(feedID, title, now)
+		feed.Links = append(opdsCommonLinks(),
+			opds.Link{Rel: opds.RelSelf, Href: opdsPageHref(query, page), Type: opds.AcquisitionType},
+			opds.Link{Rel: opds.RelUp, Href: opdsPath, Type: opds.NavigationType},
+		)
+		lastPage := int(math.Max(1, float64(books.LastPage)))
+		feed.Links = append(feed.Links, opds.Link{Rel: opds.RelFirst, Href: opdsPageHref(query, 1), Type: opds.AcquisitionType})
+		if page > 1 {
+			feed.Links = append(feed.Links, opds.Link{Rel: opds.RelPrevious, Href: opdsPageHref(query, page-1), Type: opds.AcquisitionType})
+		}
+		if page < lastPage {
+			feed.Links = append(feed.Links, opds.Link{Rel: opds.RelNext, Href: opdsPageHref(query, page+1), Type: opds.AcquisitionType})
+		}
+		feed.Links = append(feed.Links, opds.Link{Rel: opds.RelLast, Href: opdsPageHref(query, lastPage), Type: opds.AcquisitionType})
+
+		feed.TotalResults = books.TotalBook
+		feed.ItemsPerPage = limit
+		feed.StartIndex = (page-1)*limit + 1
+		for _, book := range books.Books {
+			feed.Entries = append(feed.Entries, opdsBookEntry(c.Request.Context(), book, now))
+		}
+
+		writeOPDS(c, opds.AcquisitionType, feed)
+	}
+}
+
+// OPDSBookHandler serves the complete entry of a book
+func OPDSBookHandler(bookGRPCService *grpcClient.BookGRPCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		book, err := bookGRPCService.FindByID(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				map[string]interface{}{
+					"errors": err.Error(),
+				})
+			return
+		}
+
+		entry := opdsBookEntry(c.Request.Context(), book, time.Now().UTC().Format(time.RFC3339))
+		writeOPDS(c, opds.EntryType, opds.NewCompleteEntry(entry))
+	}
+}
+
+// OPDSCategoriesHandler serves the navigation feed of the children of parent_id, or of the root categories
+func OPDSCategoriesHandler(bookGRPCService *grpcClient.BookGRPCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := &model.BrowseCategoryRequest{}
+		if parentID := c.Query("parent_id"); parentID != "" {
+			request.ParentID = &parentID
+		}
+
+		browse, err := bookGRPCService.BrowseCategory(c.Request.Context(), request)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadGateway,
+				map[string]interface{}{
+					"errors": err.Error(),
+				})
+			return
+		}
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		self, up, title := opdsCategoryPath, opdsPath, "Categories"
+		if parent := browse.Parent; parent != nil {
+			self = opdsCategoryPath + "?parent_id=" + url.QueryEscape(parent.ID)
+			up = opdsCategoryPath
+			if parent.ParentID != nil {
+				up = opdsCategoryPath + "?parent_id=" + url.QueryEscape(*parent.ParentID)
+			}
+			title = parent.Name
+		}
+
+		feed := opds.NewFeed(opdsIDPrefix+"categories:"+c.Query("parent_id"), title, now)
+		feed.Links = append(opdsCommonLinks(),
+			opds.Link{Rel: opds.RelSelf, Href: self, Type: opds.NavigationType},
+			opds.Link{Rel: opds.RelUp, Href: up, Type: opds.NavigationType},
+		)
+		for _, child := range browse.Children {
+			category := child.Category
+			entry := opds.Entry{
+				ID:      opdsIDPrefix + "category:" + category.ID,
+				Title:   category.Name,
+				Updated: now,
+				Content: &opds.Content{Type: "text", Value: fmt.Sprintf("%d book(s)", child.BookCount)},
+				Links: []opds.Link{{
+					Rel:  opds.RelSubsection,
+					Href: opdsBooksPath + "?category_id=" + url.QueryEscape(category.ID),
+					Type: opds.AcquisitionType,
+				}},
+			}
+			if child.ChildCount > 0 {
+				entry.Links = append(entry.Links, opds.Link{
+					Rel:   opds.RelAlternate,
+					Href:  opdsCategoryPath + "?parent_id=" + url.QueryEscape(category.ID),
+					Type:  opds.NavigationType,
+					Title: fmt.Sprintf("%d subcategories", child.ChildCount),
+				})
+			}
+			feed.Entries = append(feed.Entries, entry)
+		}
+
+		writeOPDS(c, opds.NavigationType, feed)
+	}
+}
+
+// OPDSSearchDescriptionHandler serves the OpenSearch description referred by the search link of every feed
+func OPDSSearchDescriptionHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		description := opds.NewOpenSearchDescription("Book Library", "Search the book catalog by title",
+			opds.OpenSearchURL{Type: opds.AcquisitionType, Template: opdsBooksPath + "?q={searchTerms}"},
+		)
+
+		writeOPDS(c, opds.OpenSearchType, description)
+	}
+}
+
+func opdsBookEntry(ctx context.Context, book *model.Book, updated string) opds.Entry {
+	entry := opds.Entry{
+		ID:      opdsIDPrefix + "book:" + book.ID,
+		Title:   book.Title,
+		Updated: updated,
+		Content: &opds.Content{Type: "text", Value: fmt.Sprintf("Available copies: %d", book.Stock)},
+	}
+	if book.Author != nil {
+		entry.Authors = []opds.Author{{Name: *book.Author}}
+	}
+	if book.Isbn != nil {
+		entry.Identifier = "urn:isbn:" + *book.Isbn
+	}
+	for _, tag := range book.Tags {
+		entry.Categories = append(entry.Categories, opds.Category{Term: tag, Label: tag})
+	}
+
+	// a physical book is borrowed at the library, so the borrow link only tells the availability
+	availability := opds.StatusAvailable
+	if book.Stock <= 0 {
+		availability = opds.StatusUnavailable
+	}
+	entry.Links = []opds.Link{
+		{
+			Rel:          opds.RelBorrow,
+			Href:         opdsBooksPath + "/" + url.PathEscape(book.ID),
+			Type:         opds.EntryType,
+			Availability: &opds.Availability{Status: availability},
+			Copies:       &opds.Copies{Available: book.Stock},
+		},
+	}
+	if book.CoverURL != nil {
+		large, small := model.CoverSizeLarge, model.CoverSizeSmall
+		entry.Links = append(entry.Links,
+			opds.Link{Rel: opds.RelImage, Href: *grpcClient.CoverURL(ctx, book, &large), Type: "image/jpeg"},
+			opds.Link{Rel: opds.RelThumbnail, Href: *grpcClient.CoverURL(ctx, book, &small), Type: "image/jpeg"},
+		)
+	}
+
+	return entry
+}
+
+func opdsCommonLinks() []opds.Link {
+	return []opds.Link{
+		{Rel: opds.RelStart, Href: opdsPath, Type: opds.NavigationType},
+		{Rel: opds.RelSearch, Href: opdsSearchPath, Type: opds.OpenSearchType},
+	}
+}
+
+func opdsPageHref(query url.Values, page int) string {
+	pageQuery := url.Values{}
+	for key, values := range query {
+		pageQuery[key] = values
+	}
+	pageQuery.Set("page", strconv.Itoa(page))
+
+	return opdsBooksPath + "?" + pageQuery.Encode()
+}
+
+func writeOPDS(c *gin.Context, contentType string, document interface{}) {
+	c.Header("Content-Type", contentType+";charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := opds.Write(c.Writer, document); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Error writing OPDS document")
+	}
+}
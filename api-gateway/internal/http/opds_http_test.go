@@ -0,0 +1,134 @@
+package http_handler
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	grpcClient "api-gateway/internal/grpc"
+	"api-gateway/pkg/proto"
+)
+
+type fakeBookServiceClient struct {
+	proto.BookServiceClient
+	total   int32
+	request *proto.FetchBookRequest
+}
+
+func (c *fakeBookServiceClient) FetchBook(_ context.Context, request *proto.FetchBookRequest, _ ...grpc.CallOption) (*proto.FetchBookResponse, error) {
+	c.request = request
+	limit := request.Pagination.Limit
+
+	return &proto.FetchBookResponse{
+		Pagination: &proto.BookPaginationResponse{
+			Limit:    limit,
+			Page:     request.Pagination.Page,
+			LastPage: (c.total + limit - 1) / limit,
+			Total:    c.total,
+		},
+		Books: []*proto.Book{
+			{Id: "1", Title: "Available", Stock: 2, Isbn: "9780134190440", Author: "Donovan, Alan"},
+			{Id: "2", Title: "Borrowed", Stock: 0},
+		},
+	}, nil
+}
+
+type testFeed struct {
+	Links []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	TotalResults int `xml:"totalResults"`
+	StartIndex   int `xml:"startIndex"`
+	Entries      []struct {
+		Identifier string `xml:"identifier"`
+		Links      []struct {
+			Availability struct {
+				Status string `xml:"status,attr"`
+			} `xml:"availability"`
+			Copies struct {
+				Available int `xml:"available,attr"`
+			} `xml:"copies"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+func TestOPDSBooksHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		target    string
+		wantTitle string
+		wantLinks map[string]string
+		wantStart int
+	}{
+		{
+			name:      "first page",
+			target:    "/opds/books",
+			wantLinks: map[string]string{"next": "/opds/books?page=2", "last": "/opds/books?page=3"},
+			wantStart: 1,
+		},
+		{
+			name:      "search keeps the query in pagination links",
+			target:    "/opds/books?q=go+(2nd)&page=2",
+			wantTitle: `go \(2nd\)`,
+			wantLinks: map[string]string{
+				"previous": "/opds/books?page=1&q=go+%282nd%29",
+				"next":     "/opds/books?page=3&q=go+%282nd%29",
+			},
+			wantStart: 21,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeBookServiceClient{total: 45}
+			router := gin.New()
+			router.GET("/opds/books", OPDSBooksHandler(grpcClient.NewBookGRPCService(client)))
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+			}
+			if client.request.Title != tt.wantTitle {
+				t.Errorf("title filter = %q, want %q", client.request.Title, tt.wantTitle)
+			}
+
+			var feed testFeed
+			if err := xml.Unmarshal(recorder.Body.Bytes(), &feed); err != nil {
+				t.Fatalf("xml.Unmarshal() error = %v", err)
+			}
+			links := make(map[string]string)
+			for _, link := range feed.Links {
+				links[link.Rel] = link.Href
+			}
+			for rel, href := range tt.wantLinks {
+				if links[rel] != href {
+					t.Errorf("%s link = %q, want %q", rel, links[rel], href)
+				}
+			}
+			if feed.TotalResults != 45 || feed.StartIndex != tt.wantStart {
+				t.Errorf("totalResults, startIndex = %d, %d, want 45, %d", feed.TotalResults, feed.StartIndex, tt.wantStart)
+			}
+
+			if len(feed.Entries) != 2 {
+				t.Fatalf("entries = %d, want 2", len(feed.Entries))
+			}
+			if got := feed.Entries[0].Identifier; got != "urn:isbn:9780134190440" {
+				t.Errorf("identifier = %q, want urn:isbn:9780134190440", got)
+			}
+			for i, want := range []string{"available", "unavailable"} {
+				if got := feed.Entries[i].Links[0].Availability.Status; got != want {
+					t.Errorf("entry %d availability = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package http_handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+
+	"api-gateway/internal/graph/model"
+	grpcClient "api-gateway/internal/grpc"
+)
+
+type reportServices struct {
+	lending *grpcClient.LendingGRPCService
+	book    *grpcClient.BookGRPCService
+	user    *grpcClient.UserGRPCService
+}
+
+// reportWriters write each report with its header, the rows are written only when the whole report is received
+var reportWriters = map[string]func(ctx context.Context, services reportServices, input model.ReportRequest, w *csv.Writer) error{
+	"loans-per-period": func(ctx context.Context, services reportServices, input model.ReportRequest, w *csv.Writer) error {
+		periods, err := services.lending.LoansPerPeriod(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		_ = w.Write([]string{"period", "loans", "returns"})
+		for _, period := range periods {
+			_ = w.Write([]string{period.Period, strconv.Itoa(period.Loans), strconv.Itoa(period.Returns)})
+		}
+		return nil
+	},
+	"top-borrowed-books": func(ctx context.Context, services reportServices, input model.ReportRequest, w *csv.Writer) error {
+		books, err := services.lending.TopBorrowedBooks(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		_ = w.Write([]string{"book_id", "title", "loans"})
+		for _, book := range books {
+			// a deleted book is kept without its title
+			var title string
+			if found, err := services.book.FindByID(ctx, book.BookID); err == nil {
+				title = found.Title
+			}
+			_ = w.Write([]string{book.BookID, title, strconv.Itoa(book.Loans)})
+		}
+		return nil
+	},
+	"never-borrowed-books": func(ctx context.Context, services reportServices, input model.ReportRequest, w *csv.Writer) error {
+		borrowedBookIDs, err := services.lending.BorrowedBookIDs(ctx, input)
+		if err != nil {
+			return err
+		}
+		books, err := services.book.NeverBorrowedBooks(ctx, borrowedBookIDs, input)
+		if err != nil {
+			return err
+		}
+
+		_ = w.Write([]string{"book_id", "title", "stock"})
+		for _, book := range books {
+			_ = w.Write([]string{book.ID, book.Title, strconv.Itoa(book.Stock)})
+		}
+		return nil
+	},
+	"summary": func(ctx context.Context, services reportServices, input model.ReportRequest, w *csv.Writer) error {
+		summary, err := services.lending.CirculationSummary(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		_ = w.Write([]string{"loans", "returns", "average_loan_days", "overdue", "overdue_rate", "active_members"})
+		_ = w.Write([]string{
+			strconv.Itoa(summary.Loans),
+			strconv.Itoa(summary.Returns),
+			strconv.FormatFloat(summary.AverageLoanDays, 'f', 2, 64),
+			strconv.Itoa(summary.Overdue),
+			strconv.FormatFloat(summary.OverdueRate, 'f', 4, 64),
+			strconv.Itoa(summary.ActiveMembers),
+		})
+		return nil
+	},
+	"active-members": func(ctx context.Context, services reportServices, input model.ReportRequest, w *csv.Writer) error {
+		members, err := services.lending.ActiveMembers(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		_ = w.Write([]string{"user_id", "email", "loans", "active", "overdue"})
+		for _, member := range members {
+			// a deleted member is kept without their email
+			var email string
+			if found, err := services.user.FindByID(ctx, member.UserID); err == nil {
+				email = found.Email
+			}
+			_ = w.Write([]string{member.UserID, email, strconv.Itoa(member.Loans), strconv.Itoa(member.Active), strconv.Itoa(member.Overdue)})
+		}
+		return nil
+	},
+}
+
+// ReportHandler downloads a circulation report as CSV, the reports are the ones of the GraphQL reports query,
+// e.g. GET /reports/loans-per-period?from=2021-02-01&to=2021-02-28&period=week&branch_id=<id>
+func ReportHandler(
+	lendingGRPCService *grpcClient.LendingGRPCService,
+	bookGRPCService *grpcClient.BookGRPCService,
+	userGRPCService *grpcClient.UserGRPCService,
+) gin.HandlerFunc {
+	services := reportServices{lending: lendingGRPCService, book: bookGRPCService, user: userGRPCService}
+
+	return func(c *gin.Context) {
+		report := c.Param("report")
+		writeReport, ok := reportWriters[report]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				map[string]interface{}{
+					"errors": fmt.Sprintf("unknown report: %s", report),
+				})
+			return
+		}
+
+		input := model.ReportRequest{From: c.Query("from"), To: c.Query("to")}
+		if branchID := c.Query("branch_id"); branchID != "" {
+			input.BranchID = &branchID
+		}
+		if period := model.ReportPeriod(c.Query("period")); period != "" {
+			input.Period = &period
+		}
+		if limitQuery := c.Query("limit"); limitQuery != "" {
+			limit, err := strconv.Atoi(limitQuery)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest,
+					map[string]interface{}{
+						"errors": fmt.Sprintf("invalid report limit: %s", limitQuery),
+					})
+				return
+			}
+			input.Limit = &limit
+		}
+
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := writeReport(c.Request.Context(), services, input, w); err != nil {
+			c.AbortWithStatusJSON(httpStatusFromGRPC(err),
+				map[string]interface{}{
+					"errors": status.Convert(err).Message(),
+				})
+			return
+		}
+		w.Flush()
+
+		fileName := fmt.Sprintf("%s-%s-%s.csv", report, input.From, input.To)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	}
+}
@@ -0,0 +1,157 @@
+package http_handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpcClient "api-gateway/internal/grpc"
+	"api-gateway/pkg/proto"
+)
+
+type fakeReportLendingServiceClient struct {
+	proto.LendingServiceClient
+	request *proto.ReportRequest
+}
+
+func (c *fakeReportLendingServiceClient) LoansPerPeriod(_ context.Context, request *proto.ReportRequest, _ ...grpc.CallOption) (*proto.LoansPerPeriodResponse, error) {
+	c.request = request
+	if request.Period == "year" {
+		return nil, status.Error(codes.InvalidArgument, "invalid report period: year")
+	}
+
+	return &proto.LoansPerPeriodResponse{Periods: []*proto.PeriodCount{
+		{Period: "2021-W05", Loans: 3, Returns: 2},
+		{Period: "2021-W06", Loans: 1},
+	}}, nil
+}
+
+func (c *fakeReportLendingServiceClient) TopBorrowedBooks(_ context.Context, request *proto.ReportRequest, _ ...grpc.CallOption) (*proto.TopBorrowedBooksResponse, error) {
+	c.request = request
+	return &proto.TopBorrowedBooksResponse{Books: []*proto.BookLoanCount{
+		{BookId: "1", Loans: 3},
+		{BookId: "deleted", Loans: 1},
+	}}, nil
+}
+
+func (c *fakeReportLendingServiceClient) BorrowedBooks(_ context.Context, request *proto.ReportRequest, _ ...grpc.CallOption) (*proto.BorrowedBooksResponse, error) {
+	c.request = request
+	return &proto.BorrowedBooksResponse{BookIds: []string{"3", "4"}}, nil
+}
+
+type fakeReportBookServiceClient struct {
+	fakeBookServiceClient
+}
+
+func (c *fakeReportBookServiceClient) FindByID(_ context.Context, request *proto.FindBookByIDRequest, _ ...grpc.CallOption) (*proto.Book, error) {
+	if request.Id == "deleted" {
+		return nil, status.Error(codes.NotFound, "book is not found")
+	}
+	return &proto.Book{Id: request.Id, Title: "The Go Programming Language"}, nil
+}
+
+func TestReportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		target         string
+		wantStatus     int
+		wantBody       string
+		wantExcludeIDs []string
+	}{
+		{
+			name:       "loans per week",
+			target:     "/reports/loans-per-period?from=2021-02-01&to=2021-02-14&period=week",
+			wantStatus: http.StatusOK,
+			wantBody:   "period,loans,returns\n2021-W05,3,2\n2021-W06,1,0\n",
+		},
+		{
+			name:       "top borrowed books with their titles",
+			target:     "/reports/top-borrowed-books?from=2021-02-01&to=2021-02-14&limit=2",
+			wantStatus: http.StatusOK,
+			wantBody:   "book_id,title,loans\n1,The Go Programming Language,3\ndeleted,,1\n",
+		},
+		{
+			name:           "never borrowed books",
+			target:         "/reports/never-borrowed-books?from=2021-02-01&to=2021-02-14",
+			wantStatus:     http.StatusOK,
+			wantBody:       "book_id,title,stock\n1,Available,2\n2,Borrowed,0\n",
+			wantExcludeIDs: []string{"3", "4"},
+		},
+		{
+			name:       "unknown report",
+			target:     "/reports/fines?from=2021-02-01&to=2021-02-14",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid date",
+			target:     "/reports/summary?from=01/02/2021&to=2021-02-14",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			target:     "/reports/active-members?from=2021-02-01&to=2021-02-14&limit=ten",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "rejected by lending-service",
+			target:     "/reports/loans-per-period?from=2021-02-01&to=2021-02-14&period=year",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lendingServiceClient := &fakeReportLendingServiceClient{}
+			bookServiceClient := &fakeReportBookServiceClient{}
+			server := gin.New()
+			server.GET("/reports/:report", ReportHandler(
+				grpcClient.NewLendingGRPCService(lendingServiceClient),
+				grpcClient.NewBookGRPCService(bookServiceClient),
+				nil,
+			))
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := recorder.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+
+			// the last day of the report is included
+			request := lendingServiceClient.request
+			if !request.From.AsTime().Equal(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)) ||
+				!request.To.AsTime().Equal(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("range = %v - %v, want 2021-02-01 - 2021-02-15", request.From.AsTime(), request.To.AsTime())
+			}
+			if tt.wantExcludeIDs != nil && !equalStrings(bookServiceClient.request.ExcludeIds, tt.wantExcludeIDs) {
+				t.Errorf("excluded books = %v, want %v", bookServiceClient.request.ExcludeIds, tt.wantExcludeIDs)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
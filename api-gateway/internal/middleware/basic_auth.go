@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/internal/graph/model"
+	grpcClient "api-gateway/internal/grpc"
+)
+
+// GinBasicAuth logs in with HTTP basic credentials, for clients which cannot send a bearer token like e-reader apps.
+// The token replaces the credentials, so it must be used before GinJWT.
+func GinBasicAuth(userGRPCService *grpcClient.UserGRPCService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		email, password, ok := ctx.Request.BasicAuth()
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		token, err := userGRPCService.Login(ctx.Request.Context(), model.Login{
+			Email:    email,
+			Password: password,
+		})
+		if err != nil {
+			ctx.Header("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, constant.AuthRealm))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized,
+				map[string]interface{}{
+					"errors": err.Error(),
+				})
+			return
+		}
+
+		ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		ctx.Next()
+	}
+}
+
+// GinIsAuthenticated asks for basic credentials when there is no valid token, it must be used after GinJWT
+func GinIsAuthenticated() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Context().Value(constant.ClaimsGinCtxKey) == nil {
+			ctx.Header("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, constant.AuthRealm))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized,
+				map[string]interface{}{
+					"errors": "unauthorized",
+				})
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -8,10 +8,13 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"api-gateway/internal/domain/constant"
+	"api-gateway/internal/graph/model"
 	"api-gateway/pkg/jwt"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/tenant"
 )
 
-func GinJWT() gin.HandlerFunc {
+func GinJWT(jwtService jwt.Service) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -20,7 +23,7 @@ func GinJWT() gin.HandlerFunc {
 		}
 
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-		token, err := jwt.New().ValidateToken(tokenString)
+		token, err := jwtService.ValidateToken(tokenString)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized,
 				map[string]interface{}{
@@ -40,12 +43,26 @@ func GinJWT() gin.HandlerFunc {
 		id, _ := claims["id"].(string)
 		role, _ := claims["role"].(string)
 		email, _ := claims["email"].(string)
+		branchID, _ := claims["branch_id"].(string)
+		tenantID, _ := claims["tenant_id"].(string)
 
 		requestCtx := ctx.Request.Context()
 		requestCtx = context.WithValue(requestCtx, constant.ClaimsGinCtxKey, claims)
 		requestCtx = context.WithValue(requestCtx, constant.UserIDGinCtxKey, id)
+		requestCtx = logger.NewUserIDContext(requestCtx, id)
 		requestCtx = context.WithValue(requestCtx, constant.RoleGinCtxKey, role)
 		requestCtx = context.WithValue(requestCtx, constant.EmailGinCtxKey, email)
+		if branchID != "" {
+			requestCtx = context.WithValue(requestCtx, constant.BranchIDGinCtxKey, branchID)
+		}
+		// the token tenant replaces the one of the header, a token without tenant is of the default tenant. Only the
+		// super admin, who manages every tenant, keeps the tenant of the header.
+		if tenantID == "" && role != model.RoleSuperAdmin.String() {
+			tenantID = tenant.Default
+		}
+		if tenantID != "" {
+			requestCtx = tenant.NewContext(requestCtx, tenantID)
+		}
 		ctx.Request = ctx.Request.WithContext(requestCtx)
 
 		ctx.Next()
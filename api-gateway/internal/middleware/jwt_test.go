@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/pkg/jwt"
+	"api-gateway/pkg/tenant"
+)
+
+func TestGinJWT_Tenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwt.New("secret")
+
+	tests := []struct {
+		name        string
+		role        string
+		tokenTenant string
+		anonymous   bool
+		want        string
+	}{
+		{name: "token tenant over the header", role: "member", tokenTenant: "tenant-a", want: "tenant-a"},
+		{name: "token without tenant", role: "admin", want: tenant.Default},
+		{name: "super admin without tenant", role: "super_admin", want: "tenant-b"},
+		{name: "super admin of a tenant", role: "super_admin", tokenTenant: "tenant-a", want: "tenant-a"},
+		{name: "anonymous", anonymous: true, want: "tenant-b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			router := gin.New()
+			router.Use(GinTenant())
+			router.GET("/", GinJWT(jwtService), func(ctx *gin.Context) {
+				got = tenant.FromContext(ctx.Request.Context())
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(constant.TenantHeader, "tenant-b")
+			if !tt.anonymous {
+				token, err := jwtService.GenerateToken("user-1", "user@example.com", tt.role, "", tt.tokenTenant)
+				if err != nil {
+					t.Fatalf("GenerateToken() error = %v", err)
+				}
+				request.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusOK || got != tt.want {
+				t.Errorf("tenant = %q with status %d, want %q", got, recorder.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"api-gateway/pkg/logger"
+)
+
+// GinLogger logs every request when it is served, with the user and tenant set by the following middlewares
+func GinLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		level := zerolog.InfoLevel
+		switch status := ctx.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			level = zerolog.ErrorLevel
+		case status >= http.StatusBadRequest:
+			level = zerolog.WarnLevel
+		}
+
+		logger.Ctx(ctx.Request.Context()).WithLevel(level).
+			Str("method", ctx.Request.Method).
+			Str("path", ctx.Request.URL.Path).
+			Int("status", ctx.Writer.Status()).
+			Int("size", ctx.Writer.Size()).
+			Str("client_ip", ctx.ClientIP()).
+			Dur("duration_ms", time.Since(start)).
+			Msg("HTTP request served")
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/pkg/logger"
+)
+
+// requestIDPattern keeps a request ID given by the client from forging the log lines
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// GinRequestID propagates the X-Request-ID header of the client, or generates it, and returns it in the response
+func GinRequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(constant.RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		ctx.Header(constant.RequestIDHeader, requestID)
+		ctx.Request = ctx.Request.WithContext(logger.NewRequestIDContext(ctx.Request.Context(), requestID))
+
+		ctx.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/internal/graph/model"
+)
+
+// GinHasRole guards the HTTP endpoints outside GraphQL, it must be used after GinJWT
+func GinHasRole(roles ...model.Role) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role, ok := ctx.Request.Context().Value(constant.RoleGinCtxKey).(string)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized,
+				map[string]interface{}{
+					"errors": "unauthorized",
+				})
+			return
+		}
+
+		for _, allowed := range roles {
+			if role == allowed.String() {
+				ctx.Next()
+				return
+			}
+		}
+		ctx.AbortWithStatusJSON(http.StatusForbidden,
+			map[string]interface{}{
+				"errors": fmt.Sprintf("unauthorized role: %s", role),
+			})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/domain/constant"
+	"api-gateway/pkg/tenant"
+)
+
+// GinTenant scopes the request to the tenant of the X-Tenant-ID header, or of the tenant query parameter for the links
+// like the cover URLs. GinJWT replaces it with the tenant of the token, so it only matters for the anonymous requests
+// and the super admin.
+func GinTenant() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := ctx.GetHeader(constant.TenantHeader)
+		if tenantID == "" {
+			tenantID = ctx.Query(constant.TenantQueryParam)
+		}
+		if tenantID != "" {
+			ctx.Request = ctx.Request.WithContext(tenant.NewContext(ctx.Request.Context(), tenantID))
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+// Config is the configuration of the app
+type Config struct {
+	HTTPPort string `yaml:"http_port" env:"HTTP_PORT" default:":8000" validate:"required,addr"`
+	// JWTSecretKey validates the login tokens, the user service signs them with the same key
+	JWTSecretKey string `yaml:"jwt_secret_key" env:"JWT_SECRET_KEY" validate:"required" secret:"true"`
+
+	UserService         Backend `yaml:"user_service" env:"USER_SERVICE_"`
+	BookService         Backend `yaml:"book_service" env:"BOOK_SERVICE_"`
+	LendingService      Backend `yaml:"lending_service" env:"LENDING_SERVICE_"`
+	NotificationService Backend `yaml:"notification_service" env:"NOTIFICATION_SERVICE_"`
+	TLS                 TLS     `yaml:"tls" env:"TLS_"`
+	Log                 Log     `yaml:"log"`
+	Tracing             Tracing `yaml:"tracing"`
+	PProf               PProf   `yaml:"pprof"`
+}
+
+// Backend is the address of another service
+type Backend struct {
+	Host string `yaml:"host" env:"HOST" validate:"required"`
+	Port string `yaml:"port" env:"PORT" validate:"required,addr"`
+}
+
+func (b Backend) Address() string {
+	return b.Host + b.Port
+}
+
+// TLS secures the connections to the services, the certificate is the client certificate of mutual TLS.
+// The files are read again when they change, so they are rotated without restart.
+type TLS struct {
+	CertFile string `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"KEY_FILE"`
+	// CAFile verifies the services, the gateway connects with TLS when it is set
+	CAFile string `yaml:"ca_file" env:"CA_FILE"`
+}
+
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info" validate:"oneof=trace debug info warn error"`
+}
+
+// Tracing selects the exporter of the traces, the OTLP exporter is configured by the standard variables
+// like OTEL_EXPORTER_OTLP_ENDPOINT
+type Tracing struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" default:"none" validate:"oneof=none otlp stdout"`
+}
+
+type PProf struct {
+	Enable         bool   `yaml:"enable" env:"ENABLE_PPROF"`
+	HTTPPort       string `yaml:"http_port" env:"PPROF_HTTP_PORT" default:":6060" validate:"addr"`
+	FolderPath     string `yaml:"folder_path" env:"PPROF_FOLDER_PATH" default:"profile"`
+	CPUFileName    string `yaml:"cpu_file_name" env:"CPU_PPROF_FILE_NAME"`
+	MemoryFileName string `yaml:"memory_file_name" env:"MEMORY_PPROF_FILE_NAME"`
+}
+
+// Load reads the configuration of the app, see load
+func Load(path string) (Config, error) {
+	var cfg Config
+	err := load(path, &cfg)
+	return cfg, err
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The fields of a configuration are described by their tags:
+//
+//	yaml      key of the field in the YAML file
+//	env       environment variable of the field, or prefix of the variables of a struct field
+//	default   value of the field when neither the YAML file nor the environment sets it
+//	validate  comma separated rules: required, addr (host:port), url, oneof=a b c
+//	secret    the value is redacted when printed, userinfo redacts the password of a URL only
+const redacted = "[REDACTED]"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// load fills the configuration from the field defaults, then from the YAML file when the path is not empty,
+// then from the environment variables, which override the file. The commands load the .env file into the
+// environment before. The configuration is validated once loaded.
+func load(path string, cfg interface{}) error {
+	v := reflect.ValueOf(cfg).Elem()
+
+	err := fields(v, "", func(field reflect.Value, tag reflect.StructTag, env string) error {
+		if value, ok := tag.Lookup("default"); ok {
+			return set(field, env, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if path != "" {
+		if err = decodeFile(path, cfg); err != nil {
+			return err
+		}
+	}
+
+	err = fields(v, "", func(field reflect.Value, _ reflect.StructTag, env string) error {
+		if value, ok := os.LookupEnv(env); ok && env != "" {
+			return set(field, env, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return validate(v)
+}
+
+func decodeFile(path string, cfg interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	// a misspelled key would silently keep the default
+	decoder.KnownFields(true)
+	if err = decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
+
+// fields calls fn with every leaf field of the struct and its environment variable
+func fields(v reflect.Value, prefix string, fn func(field reflect.Value, tag reflect.StructTag, env string) error) error {
+	for i := 0; i < v.NumField(); i++ {
+		field, structField := v.Field(i), v.Type().Field(i)
+		env := prefix + structField.Tag.Get("env")
+
+		if field.Kind() == reflect.Struct {
+			if err := fields(field, env, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(field, structField.Tag, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func set(field reflect.Value, env, value string) error {
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a duration, like 5s: %s", env, value)
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false: %s", env, value)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a number: %s", env, value)
+		}
+		field.SetInt(int64(n))
+	default:
+		return fmt.Errorf("%s has an unsupported type %s", env, field.Type())
+	}
+	return nil
+}
+
+// validate checks every rule of the fields, so all the invalid values are reported at once
+func validate(v reflect.Value) error {
+	problems := make([]string, 0)
+	_ = fields(v, "", func(field reflect.Value, tag reflect.StructTag, env string) error {
+		rules := tag.Get("validate")
+		if rules == "" {
+			return nil
+		}
+
+		for _, rule := range strings.Split(rules, ",") {
+			if problem := check(field, env, rule); problem != "" {
+				problems = append(problems, problem)
+				break
+			}
+		}
+		return nil
+	})
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func check(field reflect.Value, env, rule string) string {
+	if rule == "required" {
+		if field.IsZero() {
+			return fmt.Sprintf("%s is required", env)
+		}
+		return ""
+	}
+
+	// the other rules apply to the values that are set
+	value := fmt.Sprint(field.Interface())
+	if field.IsZero() {
+		return ""
+	}
+
+	switch {
+	case rule == "addr":
+		_, port, err := net.SplitHostPort(value)
+		if n, convErr := strconv.Atoi(port); err != nil || convErr != nil || n < 0 || n > 65535 {
+			return fmt.Sprintf("%s must be an address like :8000 or host:8000, not %s", env, value)
+		}
+	case rule == "url":
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Sprintf("%s must be a URL", env)
+		}
+	case strings.HasPrefix(rule, "oneof="):
+		allowed := strings.Fields(strings.TrimPrefix(rule, "oneof="))
+		for _, a := range allowed {
+			if value == a {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s must be one of %s, not %s", env, strings.Join(allowed, ", "), value)
+	default:
+		return fmt.Sprintf("%s has an unknown validation rule %s", env, rule)
+	}
+	return ""
+}
+
+// Print writes the configuration as YAML, with the secrets redacted and the environment variable
+// of every field in a comment
+func Print(w io.Writer, cfg interface{}) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node(v, "")); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func node(v reflect.Value, prefix string) *yaml.Node {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i < v.NumField(); i++ {
+		field, structField := v.Field(i), v.Type().Field(i)
+		env := prefix + structField.Tag.Get("env")
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: structField.Tag.Get("yaml")}
+
+		if field.Kind() == reflect.Struct {
+			mapping.Content = append(mapping.Content, key, node(field, env))
+			continue
+		}
+
+		value := &yaml.Node{Kind: yaml.ScalarNode, Value: redact(field, structField.Tag.Get("secret")), LineComment: env}
+		if field.Kind() == reflect.String {
+			value.Style = yaml.DoubleQuotedStyle
+		}
+		mapping.Content = append(mapping.Content, key, value)
+	}
+	return mapping
+}
+
+func redact(field reflect.Value, secret string) string {
+	value := fmt.Sprint(field.Interface())
+	switch {
+	case secret == "" || field.IsZero():
+		return value
+	case secret == "userinfo":
+		if u, err := url.Parse(value); err == nil {
+			return u.Redacted()
+		}
+	}
+	return redacted
+}
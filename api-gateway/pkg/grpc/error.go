@@ -3,16 +3,31 @@ package grpc
 import (
 	"fmt"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// StatusError is a gRPC error presented to the user, it keeps the code for the GraphQL error extensions
+type StatusError struct {
+	Code    codes.Code
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+}
+
+func (e *StatusError) GRPCStatus() *status.Status {
+	return status.New(e.Code, e.Message)
+}
+
 func ParseErrorStatus(err error) error {
 	errStatus, ok := status.FromError(err)
 	if ok {
-		errMessage := errStatus.Message()
-		errCode := errStatus.Code().String()
-
-		return fmt.Errorf("[%s] %s", errCode, errMessage)
+		return &StatusError{
+			Code:    errStatus.Code(),
+			Message: errStatus.Message(),
+		}
 	}
 
 	return nil
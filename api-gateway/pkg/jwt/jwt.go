@@ -2,7 +2,6 @@ package jwt
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -15,9 +14,9 @@ type Service struct {
 	ExpiredTime time.Duration
 }
 
-func New() Service {
+func New(secretKey string) Service {
 	return Service{
-		SecretKey:   os.Getenv("JWT_SECRET_KEY"),
+		SecretKey:   secretKey,
 		ExpiredTime: 1,
 	}
 }
@@ -27,10 +26,14 @@ type jwtCustomClaim struct {
 	ID    string `json:"id"`
 	Email string `json:"email"`
 	Role  string `json:"role"`
+	// BranchID scopes a librarian to a branch
+	BranchID string `json:"branch_id,omitempty"`
+	// TenantID is the library of the user, every request of the token is scoped to it
+	TenantID string `json:"tenant_id"`
 }
 
 func (s Service) GenerateToken(
-	id, email, role string,
+	id, email, role, branchID, tenantID string,
 ) (string, error) {
 	token := jwt.NewWithClaims(
 		jwt.SigningMethodHS256,
@@ -39,9 +42,11 @@ func (s Service) GenerateToken(
 				ExpiresAt: time.Now().Add(time.Hour * s.ExpiredTime).Unix(),
 				IssuedAt:  time.Now().Unix(),
 			},
-			ID:    id,
-			Email: email,
-			Role:  role,
+			ID:       id,
+			Email:    email,
+			Role:     role,
+			BranchID: branchID,
+			TenantID: tenantID,
 		},
 	)
 
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// level of a completed RPC, the errors caused by the request are warnings
+func level(err error, okLevel zerolog.Level) zerolog.Level {
+	switch status.Code(err) {
+	case codes.OK:
+		return okLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unauthenticated:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+func logRPC(ctx context.Context, message, method string, start time.Time, err error, okLevel zerolog.Level) {
+	Ctx(ctx).WithLevel(level(err, okLevel)).
+		Str("grpc_method", method).
+		Str("grpc_code", status.Code(err).String()).
+		Dur("duration_ms", time.Since(start)).
+		Err(err).
+		Msg(message)
+}
+
+// UnaryClientInterceptor forwards the request ID and user to the called service, and logs the completed call
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(toOutgoingContext(ctx), method, req, reply, cc, opts...)
+		logRPC(ctx, "gRPC call completed", method, start, err, zerolog.DebugLevel)
+		return err
+	}
+}
+
+// StreamClientInterceptor logs a stream when it is completed, that is when the last response is received
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(toOutgoingContext(ctx), desc, cc, method, opts...)
+		if err != nil {
+			logRPC(ctx, "gRPC call completed", method, start, err, zerolog.DebugLevel)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: stream, ctx: ctx, desc: desc, method: method, start: start}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	desc   *grpc.StreamDesc
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+	case !s.desc.ServerStreams:
+		// a client streaming RPC has a single response
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		logRPC(s.ctx, "gRPC call completed", s.method, s.start, err, zerolog.DebugLevel)
+	})
+}
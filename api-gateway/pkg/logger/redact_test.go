@@ -0,0 +1,40 @@
+package logger
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "email in a message",
+			in:   `{"level":"warn","error":"user with john.doe@example.com email is not found"}`,
+			want: `{"level":"warn","error":"user with j***@example.com email is not found"}`,
+		},
+		{
+			name: "password field",
+			in:   `{"email":"a@b.co","password":"s3cr\"et"}`,
+			want: `{"email":"a***@b.co","password":"[REDACTED]"}`,
+		},
+		{
+			name: "password in a message",
+			in:   `{"message":"login with password=hunter2 failed"}`,
+			want: `{"message":"login with password=[REDACTED] failed"}`,
+		},
+		{
+			name: "nothing to redact",
+			in:   `{"grpc_method":"/proto.BookService/FindBook"}`,
+			want: `{"grpc_method":"/proto.BookService/FindBook"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.in); got != tt.want {
+				t.Errorf("Redact() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	graphQLOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_operations_total",
+		Help: "Total number of GraphQL operations, by root field and status.",
+	}, []string{"operation", "status"})
+	graphQLOperationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_operation_duration_seconds",
+		Help:    "Latency of the GraphQL operations, by root field and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "status"})
+)
+
+// GraphQLExtension records the root fields of the GraphQL operations, the operation name is chosen by the client
+// so the root field is used as the operation label.
+type GraphQLExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+} = GraphQLExtension{}
+
+func (GraphQLExtension) ExtensionName() string {
+	return "Metrics"
+}
+
+func (GraphQLExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (GraphQLExtension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fieldCtx := graphql.GetFieldContext(ctx)
+	// only the root fields are operations, the nested fields would multiply the series
+	if fieldCtx == nil || fieldCtx.Parent != nil {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	graphQLOperations.WithLabelValues(fieldCtx.Field.Name, status).Inc()
+	graphQLOperationSeconds.WithLabelValues(fieldCtx.Field.Name, status).Observe(time.Since(start).Seconds())
+	return res, err
+}
@@ -0,0 +1,168 @@
+package opds
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const (
+	AtomNamespace       = "http://www.w3.org/2005/Atom"
+	OPDSNamespace       = "http://opds-spec.org/2010/catalog"
+	OpenSearchNamespace = "http://a9.com/-/spec/opensearch/1.1/"
+	DCNamespace         = "http://purl.org/dc/terms/"
+
+	NavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	EntryType       = "application/atom+xml;type=entry;profile=opds-catalog"
+	OpenSearchType  = "application/opensearchdescription+xml"
+
+	RelSelf       = "self"
+	RelStart      = "start"
+	RelUp         = "up"
+	RelFirst      = "first"
+	RelPrevious   = "previous"
+	RelNext       = "next"
+	RelLast       = "last"
+	RelSearch     = "search"
+	RelSubsection = "subsection"
+	RelAlternate  = "alternate"
+	RelBorrow     = "http://opds-spec.org/acquisition/borrow"
+	RelImage      = "http://opds-spec.org/image"
+	RelThumbnail  = "http://opds-spec.org/image/thumbnail"
+
+	StatusAvailable   = "available"
+	StatusUnavailable = "unavailable"
+)
+
+// Feed is an OPDS 1.2 catalog feed, the namespaces are set by NewFeed
+type Feed struct {
+	XMLName         xml.Name `xml:"feed"`
+	Xmlns           string   `xml:"xmlns,attr"`
+	XmlnsOPDS       string   `xml:"xmlns:opds,attr"`
+	XmlnsOpenSearch string   `xml:"xmlns:opensearch,attr"`
+	XmlnsDC         string   `xml:"xmlns:dc,attr"`
+	ID              string   `xml:"id"`
+	Title           string   `xml:"title"`
+	Updated         string   `xml:"updated"`
+	Links           []Link   `xml:"link"`
+	TotalResults    int      `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage    int      `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex      int      `xml:"opensearch:startIndex,omitempty"`
+	Entries         []Entry  `xml:"entry"`
+}
+
+func NewFeed(id, title, updated string) *Feed {
+	return &Feed{
+		Xmlns:           AtomNamespace,
+		XmlnsOPDS:       OPDSNamespace,
+		XmlnsOpenSearch: OpenSearchNamespace,
+		XmlnsDC:         DCNamespace,
+		ID:              id,
+		Title:           title,
+		Updated:         updated,
+	}
+}
+
+type Entry struct {
+	XMLName    xml.Name   `xml:"entry"`
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    string     `xml:"updated"`
+	Authors    []Author   `xml:"author"`
+	Identifier string     `xml:"dc:identifier,omitempty"`
+	Categories []Category `xml:"category"`
+	Content    *Content   `xml:"content,omitempty"`
+	Links      []Link     `xml:"link"`
+}
+
+// CompleteEntry is a standalone entry document, so it declares the namespaces itself
+type CompleteEntry struct {
+	XMLName   xml.Name `xml:"entry"`
+	Xmlns     string   `xml:"xmlns,attr"`
+	XmlnsOPDS string   `xml:"xmlns:opds,attr"`
+	XmlnsDC   string   `xml:"xmlns:dc,attr"`
+	Entry
+}
+
+func NewCompleteEntry(entry Entry) *CompleteEntry {
+	return &CompleteEntry{
+		Xmlns:     AtomNamespace,
+		XmlnsOPDS: OPDSNamespace,
+		XmlnsDC:   DCNamespace,
+		Entry:     entry,
+	}
+}
+
+type Author struct {
+	Name string `xml:"name"`
+}
+
+type Category struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type Content struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type Link struct {
+	Rel          string        `xml:"rel,attr,omitempty"`
+	Href         string        `xml:"href,attr"`
+	Type         string        `xml:"type,attr,omitempty"`
+	Title        string        `xml:"title,attr,omitempty"`
+	Availability *Availability `xml:"opds:availability,omitempty"`
+	Copies       *Copies       `xml:"opds:copies,omitempty"`
+}
+
+// Availability and Copies describe the library holdings of a borrow link
+type Availability struct {
+	Status string `xml:"status,attr"`
+}
+
+type Copies struct {
+	Available int `xml:"available,attr"`
+}
+
+type OpenSearchDescription struct {
+	XMLName        xml.Name        `xml:"OpenSearchDescription"`
+	Xmlns          string          `xml:"xmlns,attr"`
+	ShortName      string          `xml:"ShortName"`
+	Description    string          `xml:"Description"`
+	InputEncoding  string          `xml:"InputEncoding"`
+	OutputEncoding string          `xml:"OutputEncoding"`
+	URLs           []OpenSearchURL `xml:"Url"`
+}
+
+type OpenSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+func NewOpenSearchDescription(shortName, description string, urls ...OpenSearchURL) *OpenSearchDescription {
+	return &OpenSearchDescription{
+		Xmlns:          OpenSearchNamespace,
+		ShortName:      shortName,
+		Description:    description,
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URLs:           urls,
+	}
+}
+
+// Write encodes a feed, an entry, or an OpenSearch description as an XML document
+func Write(w io.Writer, document interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
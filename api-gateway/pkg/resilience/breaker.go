@@ -0,0 +1,132 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	breakerThreshold = 5
+	breakerCooldown  = 10 * time.Second
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker stops the calls to a backend after consecutive failures, so they fail fast instead of waiting for their
+// deadline. After the cooldown, a single call is let through to probe the backend, and closes the breaker when it succeeds.
+type Breaker struct {
+	backend   string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	probeTime time.Time
+}
+
+func NewBreaker(backend string, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		backend:   backend,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow tells whether a call can be sent, the result of an allowed call must be given to Record
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case breakerOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(breakerHalfOpen)
+	case breakerHalfOpen:
+		// a probe which never completes, like an abandoned stream, must not keep the breaker half-open
+		if b.probing && now.Sub(b.probeTime) < b.cooldown {
+			return false
+		}
+	default:
+		return true
+	}
+
+	b.probing = true
+	b.probeTime = now
+	return true
+}
+
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case isBackendFailure(err):
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= b.threshold {
+			b.openedAt = b.now()
+			b.probing = false
+			b.setState(breakerOpen)
+		}
+	case status.Code(err) == codes.Canceled:
+		// the caller went away, it tells nothing about the backend
+		if b.state == breakerHalfOpen {
+			b.probing = false
+		}
+	default:
+		b.failures = 0
+		b.probing = false
+		b.setState(breakerClosed)
+	}
+}
+
+func (b *Breaker) setState(state breakerState) {
+	if b.state == state {
+		return
+	}
+
+	event := log.Info()
+	if state == breakerOpen {
+		event = log.Warn().Int("failures", b.failures)
+	}
+	event.Str("backend", b.backend).Str("state", state.String()).Msg("Circuit breaker state changed")
+	b.state = state
+}
+
+// isBackendFailure tells whether the backend is down or overloaded, the other errors are answers of a working backend
+func isBackendFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
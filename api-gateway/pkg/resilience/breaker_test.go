@@ -0,0 +1,49 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker("book-service", 2, 10*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	notFound := status.Error(codes.NotFound, "book is not found")
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		record    bool
+		result    error
+		wantAllow bool
+	}{
+		{name: "closed", record: true, result: unavailable, wantAllow: true},
+		{name: "answers of a working backend reset the failures", record: true, result: notFound, wantAllow: true},
+		{name: "first failure", record: true, result: unavailable, wantAllow: true},
+		{name: "second failure opens", record: true, result: unavailable, wantAllow: true},
+		{name: "open during cooldown", wantAllow: false},
+		{name: "half-open probe after cooldown", advance: 10 * time.Second, record: true, result: unavailable, wantAllow: true},
+		{name: "failed probe opens again", advance: time.Second, wantAllow: false},
+		{name: "second probe", advance: 10 * time.Second, wantAllow: true},
+		{name: "single probe at a time", wantAllow: false},
+		{name: "abandoned probe is replaced", advance: 10 * time.Second, record: true, wantAllow: true},
+		{name: "successful probe closes", wantAllow: true},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		allowed := breaker.Allow()
+		if allowed != step.wantAllow {
+			t.Fatalf("%s: Allow() = %v, want %v", step.name, allowed, step.wantAllow)
+		}
+		if step.record {
+			breaker.Record(step.result)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClient_UnaryClientInterceptor(t *testing.T) {
+	const readMethod = "/book.BookService/FindByID"
+
+	tests := []struct {
+		name         string
+		method       string
+		errors       []error
+		wantCode     codes.Code
+		wantAttempts int
+	}{
+		{
+			name:         "read retried until it succeeds",
+			method:       readMethod,
+			errors:       []error{status.Error(codes.Unavailable, "connection refused"), nil},
+			wantCode:     codes.OK,
+			wantAttempts: 2,
+		},
+		{
+			name:   "read retries exhausted",
+			method: readMethod,
+			errors: []error{
+				status.Error(codes.Unavailable, "connection refused"),
+				status.Error(codes.Unavailable, "connection refused"),
+				status.Error(codes.Unavailable, "connection refused"),
+			},
+			wantCode:     codes.Unavailable,
+			wantAttempts: 3,
+		},
+		{
+			name:         "answer of the backend not retried",
+			method:       readMethod,
+			errors:       []error{status.Error(codes.NotFound, "book is not found")},
+			wantCode:     codes.NotFound,
+			wantAttempts: 1,
+		},
+		{
+			name:         "write not retried",
+			method:       "/book.BookService/UpdateBookStock",
+			errors:       []error{status.Error(codes.Unavailable, "connection refused")},
+			wantCode:     codes.Unavailable,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("book-service", Policy{Timeout: time.Second}, map[string]Policy{
+				readMethod: {Timeout: time.Second, Retries: 2},
+			})
+
+			attempts := 0
+			invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Error("attempt without deadline")
+				}
+				err := tt.errors[attempts]
+				attempts++
+				return err
+			}
+
+			err := client.UnaryClientInterceptor()(context.Background(), tt.method, nil, nil, nil, invoker)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("code = %s, want %s", status.Code(err), tt.wantCode)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if tt.wantCode == codes.Unavailable && status.Convert(err).Message() != "book-service is temporarily unavailable" {
+				t.Errorf("message = %s, want the clean unavailable message", status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+func TestClient_OpenBreakerFailsFast(t *testing.T) {
+	client := NewClient("book-service", Policy{}, nil)
+
+	attempts := 0
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		attempts++
+		return status.Error(codes.DeadlineExceeded, "context deadline exceeded")
+	}
+
+	for i := 0; i < breakerThreshold+3; i++ {
+		_ = client.UnaryClientInterceptor()(context.Background(), "/book.BookService/CreateBook", nil, nil, nil, invoker)
+	}
+	if attempts != breakerThreshold {
+		t.Errorf("attempts = %d, want %d before the breaker opens", attempts, breakerThreshold)
+	}
+}
@@ -0,0 +1,113 @@
+// Package testserver serves the GraphQL gateway in front of services reached through dialers, so the tests spanning the
+// services run in one process.
+package testserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"api-gateway/internal/graph/model"
+	grpcClient "api-gateway/internal/grpc"
+	httpHandler "api-gateway/internal/http"
+	"api-gateway/internal/middleware"
+	"api-gateway/pkg/jwt"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/proto"
+	"api-gateway/pkg/tenant"
+)
+
+// Dialers connect the gateway to each service
+type Dialers struct {
+	UserService         func(context.Context, string) (net.Conn, error)
+	BookService         func(context.Context, string) (net.Conn, error)
+	LendingService      func(context.Context, string) (net.Conn, error)
+	NotificationService func(context.Context, string) (net.Conn, error)
+}
+
+// Server is the gateway with the routes of the API, without the probes, metrics and playground
+type Server struct {
+	http.Handler
+	clientConns []*grpc.ClientConn
+}
+
+// New returns the gateway validating the tokens with jwtSecretKey. The connections are not blocking, so the services
+// can be started after it.
+func New(dialers Dialers, jwtSecretKey string) (*Server, error) {
+	s := new(Server)
+
+	userGRPCClientConn, err := s.dial("user-service", dialers.UserService)
+	if err != nil {
+		return nil, err
+	}
+	bookGRPCClientConn, err := s.dial("book-service", dialers.BookService)
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+	lendingGRPCClientConn, err := s.dial("lending-service", dialers.LendingService)
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+	notificationGRPCClientConn, err := s.dial("notification-service", dialers.NotificationService)
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+
+	userGRPCService := grpcClient.NewUserGRPCService(proto.NewUserServiceClient(userGRPCClientConn))
+	bookGRPCService := grpcClient.NewBookGRPCService(proto.NewBookServiceClient(bookGRPCClientConn))
+	lendingGRPCService := grpcClient.NewLendingGRPCService(proto.NewLendingServiceClient(lendingGRPCClientConn))
+	notificationGRPCService := grpcClient.NewNotificationGRPCService(proto.NewNotificationServiceClient(notificationGRPCClientConn))
+	webhookGRPCService := grpcClient.NewWebhookGRPCService(proto.NewWebhookServiceClient(notificationGRPCClientConn))
+
+	jwtService := jwt.New(jwtSecretKey)
+
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(middleware.GinRequestID())
+	server.Use(middleware.GinTenant())
+	server.POST("/query", middleware.GinJWT(jwtService), httpHandler.GraphQLHandler(
+		userGRPCService,
+		bookGRPCService,
+		lendingGRPCService,
+		notificationGRPCService,
+		webhookGRPCService,
+	))
+	server.GET("/export/books", middleware.GinJWT(jwtService), middleware.GinHasRole(model.RoleLibrarian),
+		httpHandler.ExportBooksHandler(bookGRPCService))
+	server.GET("/reports/:report", middleware.GinJWT(jwtService), middleware.GinHasRole(model.RoleLibrarian),
+		httpHandler.ReportHandler(lendingGRPCService, bookGRPCService, userGRPCService))
+	server.GET("/covers/:id", httpHandler.CoverHandler(bookGRPCService))
+
+	s.Handler = server
+	return s, nil
+}
+
+// Stop closes the connections to the services
+func (s *Server) Stop() {
+	for _, clientConn := range s.clientConns {
+		_ = clientConn.Close()
+	}
+}
+
+func (s *Server) dial(target string, dialer func(context.Context, string) (net.Conn, error)) (*grpc.ClientConn, error) {
+	clientConn, err := grpc.Dial(
+		target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer),
+		grpc.WithChainUnaryInterceptor(tenant.UnaryClientInterceptor(), logger.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tenant.StreamClientInterceptor(), logger.StreamClientInterceptor()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clientConns = append(s.clientConns, clientConn)
+	return clientConn, nil
+}
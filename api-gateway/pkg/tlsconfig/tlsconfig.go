@@ -0,0 +1,117 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// reloadCheckInterval is how often the files are checked for a rotation, at most once per handshake
+const reloadCheckInterval = 10 * time.Second
+
+// reloader keeps the certificate and the CA read from the files, it reads them again once they are modified
+type reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	modTime   time.Time
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+}
+
+func newReloader(certFile, keyFile, caFile string) (*reloader, error) {
+	r := &reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+	if r.cert, r.pool, err = r.load(); err != nil {
+		return nil, err
+	}
+	r.modTime, r.checkedAt = modTime, time.Now()
+
+	return r, nil
+}
+
+// current returns the certificate and the CA, read again when the files changed since the last check.
+// A rotation that can't be read, like a key written before its certificate, keeps the previous ones until the next check.
+func (r *reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < reloadCheckInterval {
+		return r.cert, r.pool
+	}
+	r.checkedAt = time.Now()
+
+	modTime, err := r.latestModTime()
+	if err != nil {
+		log.Error().Err(err).Msg("Error checking TLS files")
+		return r.cert, r.pool
+	}
+	if !modTime.After(r.modTime) {
+		return r.cert, r.pool
+	}
+
+	cert, pool, err := r.load()
+	if err != nil {
+		log.Error().Err(err).Msg("Error reloading TLS files, the previous ones are kept")
+		return r.cert, r.pool
+	}
+	r.cert, r.pool, r.modTime = cert, pool, modTime
+	log.Info().Str("cert_file", r.certFile).Str("ca_file", r.caFile).Msg("TLS files reloaded")
+
+	return r.cert, r.pool
+}
+
+func (r *reloader) load() (*tls.Certificate, *x509.CertPool, error) {
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		cert = &pair
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificate found in %s", r.caFile)
+		}
+	}
+
+	return cert, pool, nil
+}
+
+func (r *reloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
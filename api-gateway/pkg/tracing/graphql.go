@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const graphQLTracerName = "graphql"
+
+// GraphQLExtension traces the GraphQL operation and its resolvers, the fields without a resolver are not traced
+// since they only read the parent object.
+type GraphQLExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = GraphQLExtension{}
+
+func (GraphQLExtension) ExtensionName() string {
+	return "Tracing"
+}
+
+func (GraphQLExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (GraphQLExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	operationCtx := graphql.GetOperationContext(ctx)
+	name := operationCtx.OperationName
+	if name == "" {
+		name = "anonymous"
+	}
+
+	ctx, span := otel.Tracer(graphQLTracerName).Start(ctx, "graphql.operation "+name, trace.WithAttributes(
+		attribute.String("graphql.operation.name", operationCtx.OperationName),
+	))
+	defer span.End()
+
+	response := next(ctx)
+	if response != nil && len(response.Errors) > 0 {
+		span.SetStatus(codes.Error, response.Errors.Error())
+	}
+	return response
+}
+
+func (GraphQLExtension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fieldCtx := graphql.GetFieldContext(ctx)
+	if fieldCtx == nil || !fieldCtx.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := otel.Tracer(graphQLTracerName).Start(ctx, fmt.Sprintf("graphql.resolve %s.%s", fieldCtx.Object, fieldCtx.Field.Name),
+		trace.WithAttributes(attribute.String("graphql.field.path", fieldCtx.Path().String())),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"time"
+
+	"api-gateway/pkg/resilience"
+)
+
+var (
+	// defaultPolicy is the policy of the unary calls without their own, the writes are never retried
+	defaultPolicy = resilience.Policy{Timeout: 5 * time.Second}
+	// readPolicy retries the idempotent reads
+	readPolicy = resilience.Policy{Timeout: 3 * time.Second, Retries: 2}
+	// bulkPolicy is for the writes of many documents
+	bulkPolicy = resilience.Policy{Timeout: 30 * time.Second}
+	// healthPolicy answers the readiness probe before its own timeout
+	healthPolicy = resilience.Policy{Timeout: time.Second}
+	// webhookTestPolicy waits for the request of the test event to the webhook, which has the WEBHOOK_TIMEOUT
+	webhookTestPolicy = resilience.Policy{Timeout: 15 * time.Second}
+)
+
+// the streams of the catalog import, export, and covers have no timeout beside the one of the caller
+var (
+	userServicePolicies = map[string]resilience.Policy{
+		"/user.UserService/FetchUser":   readPolicy,
+		"/user.UserService/FindByID":    readPolicy,
+		"/user.UserService/FindByEmail": readPolicy,
+		"/user.UserService/FetchTenant": readPolicy,
+		"/user.UserService/DeleteUser":  bulkPolicy,
+		"/grpc.health.v1.Health/Check":  healthPolicy,
+	}
+	bookServicePolicies = map[string]resilience.Policy{
+		"/book.BookService/FetchBook":          readPolicy,
+		"/book.BookService/FindByID":           readPolicy,
+		"/book.BookService/FindByTitle":        readPolicy,
+		"/book.BookService/BookStockHistory":   readPolicy,
+		"/book.BookService/FetchCategory":      readPolicy,
+		"/book.BookService/FindCategoryByID":   readPolicy,
+		"/book.BookService/BrowseCategory":     readPolicy,
+		"/book.BookService/FindImportJob":      readPolicy,
+		"/book.BookService/FetchBranch":        readPolicy,
+		"/book.BookService/FindBranchByID":     readPolicy,
+		"/book.BookService/FindDefaultBranch":  readPolicy,
+		"/book.BookService/FetchTransfer":      readPolicy,
+		"/book.BookService/ReconcileBookStock": bulkPolicy,
+		"/grpc.health.v1.Health/Check":         healthPolicy,
+	}
+	lendingServicePolicies = map[string]resilience.Policy{
+		"/lending.LendingService/FetchLending":         readPolicy,
+		"/lending.LendingService/CountActiveLendings":  readPolicy,
+		"/lending.LendingService/FinishActiveLendings": bulkPolicy,
+		"/grpc.health.v1.Health/Check":                 healthPolicy,
+	}
+	notificationServicePolicies = map[string]resilience.Policy{
+		"/notification.NotificationService/FindPreference":    readPolicy,
+		"/notification.NotificationService/FetchNotification": readPolicy,
+		"/notification.WebhookService/FetchWebhook":           readPolicy,
+		"/notification.WebhookService/FetchWebhookDelivery":   readPolicy,
+		"/notification.WebhookService/SendTestWebhookEvent":   webhookTestPolicy,
+		"/grpc.health.v1.Health/Check":                        healthPolicy,
+	}
+)
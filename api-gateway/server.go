@@ -3,30 +3,44 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime/pprof"
-	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
+	"api-gateway/internal/graph/model"
 	grpcClient "api-gateway/internal/grpc"
 	httpHandler "api-gateway/internal/http"
 	"api-gateway/internal/middleware"
+	"api-gateway/pkg/config"
+	"api-gateway/pkg/jwt"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/metrics"
 	"api-gateway/pkg/proto"
+	"api-gateway/pkg/resilience"
+	"api-gateway/pkg/tenant"
+	"api-gateway/pkg/tlsconfig"
+	"api-gateway/pkg/tracing"
 )
 
 const (
-	defaultHTTPPort      = ":8000"
-	defaultPProfHTTPPort = ":6060"
+	serviceName = "api-gateway"
+
+	grpcMaxReconnectDelay = 10 * time.Second
 )
 
 func init() {
@@ -34,24 +48,47 @@ func init() {
 }
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file, the environment variables override it")
+	printConfig := flag.Bool("print-config", false, "print the configuration with the secrets redacted, then exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if *printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatal().Err(err).Msg("Error printing configuration")
+		}
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading configuration")
+	}
+	if *printConfig {
+		return
+	}
+
+	logger.Init(serviceName, cfg.Log.Level)
+
+	shutdownTracing, err := tracing.Init(context.Background(), serviceName, cfg.Tracing.Exporter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error initializing tracing")
+	}
+
 	pprofServer := new(http.Server)
-	enableProf, _ := strconv.ParseBool(os.Getenv("ENABLE_PPROF"))
-	if enableProf {
-		profileDirPath := os.Getenv("PPROF_FOLDER_PATH")
+	if cfg.PProf.Enable {
+		profileDirPath := cfg.PProf.FolderPath
 		if _, err := os.Stat(profileDirPath); os.IsNotExist(err) {
 			if err := os.Mkdir(profileDirPath, os.ModePerm); err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			}
 		}
 
-		if cpuProfile := os.Getenv("CPU_PPROF_FILE_NAME"); cpuProfile != "" {
+		if cpuProfile := cfg.PProf.CPUFileName; cpuProfile != "" {
 			f, err := os.Create(fmt.Sprintf("%s/%s", profileDirPath, cpuProfile))
 			if err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			} else {
 				defer func() {
 					if err := f.Close(); err != nil {
-						log.Printf("Error closing cpu profile file: %v", err)
+						log.Error().Err(err).Msg("Error closing cpu profile file")
 					}
 				}()
 
@@ -59,14 +96,14 @@ func main() {
 			}
 		}
 
-		if memProfile := os.Getenv("MEMORY_PPROF_FILE_NAME"); memProfile != "" {
+		if memProfile := cfg.PProf.MemoryFileName; memProfile != "" {
 			f, err := os.Create(fmt.Sprintf("%s/%s", profileDirPath, memProfile))
 			if err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			} else {
 				defer func() {
 					if err := f.Close(); err != nil {
-						log.Printf("Error closing memory profile file: %v", err)
+						log.Error().Err(err).Msg("Error closing memory profile file")
 					}
 				}()
 
@@ -74,79 +111,113 @@ func main() {
 			}
 		}
 
-		pprofHTPPPort := os.Getenv("PPROF_HTTP_PORT")
-		if pprofHTPPPort == "" {
-			pprofHTPPPort = defaultPProfHTTPPort
-		}
-
 		pprofServer = &http.Server{
-			Addr:    pprofHTPPPort,
+			Addr:    cfg.PProf.HTTPPort,
 			Handler: nil,
 		}
 
 		go func() {
 			if err := pprofServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-				log.Printf("Error starting pprof server: %v", err)
+				log.Error().Err(err).Msg("Error starting pprof server")
 			}
 		}()
 	}
 
-	grpcDialCtx, cancelGRPCDial := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancelGRPCDial()
+	tlsDialOption, err := tlsconfig.DialOption(cfg.TLS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading TLS client certificates")
+	}
 
-	userGRPCClientConn, err := grpc.DialContext(
-		grpcDialCtx,
-		fmt.Sprintf("%s%s", os.Getenv("USER_SERVICE_HOST"), os.Getenv("USER_SERVICE_PORT")),
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
+	userGRPCClientConn, err := dialBackend(
+		cfg.UserService.Address(),
+		tlsDialOption,
+		resilience.NewClient("user-service", defaultPolicy, userServicePolicies),
 	)
 	if err != nil {
-		log.Fatalf("Error dial to user service: %v", err)
+		log.Fatal().Err(err).Msg("Error dial to user service")
 	}
 
-	bookGRPCClientConn, err := grpc.DialContext(
-		grpcDialCtx,
-		fmt.Sprintf("%s%s", os.Getenv("BOOK_SERVICE_HOST"), os.Getenv("BOOK_SERVICE_PORT")),
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
+	bookGRPCClientConn, err := dialBackend(
+		cfg.BookService.Address(),
+		tlsDialOption,
+		resilience.NewClient("book-service", defaultPolicy, bookServicePolicies),
 	)
 	if err != nil {
-		log.Fatalf("Error dial to book service: %v", err)
+		log.Fatal().Err(err).Msg("Error dial to book service")
 	}
 
-	lendingGRPCClientConn, err := grpc.DialContext(
-		grpcDialCtx,
-		fmt.Sprintf("%s%s", os.Getenv("LENDING_SERVICE_HOST"), os.Getenv("LENDING_SERVICE_PORT")),
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
+	lendingGRPCClientConn, err := dialBackend(
+		cfg.LendingService.Address(),
+		tlsDialOption,
+		resilience.NewClient("lending-service", defaultPolicy, lendingServicePolicies),
 	)
 	if err != nil {
-		log.Fatalf("Error dial to lending service: %v", err)
+		log.Fatal().Err(err).Msg("Error dial to lending service")
+	}
+
+	notificationGRPCClientConn, err := dialBackend(
+		cfg.NotificationService.Address(),
+		tlsDialOption,
+		resilience.NewClient("notification-service", defaultPolicy, notificationServicePolicies),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error dial to notification service")
 	}
 
 	userServiceClient := proto.NewUserServiceClient(userGRPCClientConn)
 	bookServiceClient := proto.NewBookServiceClient(bookGRPCClientConn)
 	lendingServiceClient := proto.NewLendingServiceClient(lendingGRPCClientConn)
+	notificationServiceClient := proto.NewNotificationServiceClient(notificationGRPCClientConn)
+	webhookServiceClient := proto.NewWebhookServiceClient(notificationGRPCClientConn)
 
 	userGRPCService := grpcClient.NewUserGRPCService(userServiceClient)
 	bookGRPCService := grpcClient.NewBookGRPCService(bookServiceClient)
 	lendingGRPCService := grpcClient.NewLendingGRPCService(lendingServiceClient)
+	notificationGRPCService := grpcClient.NewNotificationGRPCService(notificationServiceClient)
+	webhookGRPCService := grpcClient.NewWebhookGRPCService(webhookServiceClient)
+	healthGRPCService := grpcClient.NewHealthGRPCService(map[string]healthpb.HealthClient{
+		"user-service":         healthpb.NewHealthClient(userGRPCClientConn),
+		"book-service":         healthpb.NewHealthClient(bookGRPCClientConn),
+		"lending-service":      healthpb.NewHealthClient(lendingGRPCClientConn),
+		"notification-service": healthpb.NewHealthClient(notificationGRPCClientConn),
+	})
+
+	jwtService := jwt.New(cfg.JWTSecretKey)
 
-	server := gin.Default()
+	server := gin.New()
+	server.Use(gin.RecoveryWithWriter(log.Logger))
+	// the probes are polled, so they are registered before the request logger
+	server.GET("/healthz", httpHandler.LivenessHandler())
+	server.GET("/readyz", httpHandler.ReadinessHandler(healthGRPCService))
+	server.Use(middleware.GinRequestID())
+	server.Use(otelgin.Middleware(serviceName))
+	server.Use(middleware.GinLogger())
+	server.Use(middleware.GinTenant())
 	server.GET("/", httpHandler.GraphPlaygroundHandler())
-	server.POST("/query", middleware.GinJWT(), httpHandler.GraphQLHandler(
+	server.GET("/metrics", gin.WrapH(metrics.Handler()))
+	server.POST("/query", middleware.GinJWT(jwtService), httpHandler.GraphQLHandler(
 		userGRPCService,
 		bookGRPCService,
 		lendingGRPCService,
+		notificationGRPCService,
+		webhookGRPCService,
 	))
+	server.GET("/export/books", middleware.GinJWT(jwtService), middleware.GinHasRole(model.RoleLibrarian),
+		httpHandler.ExportBooksHandler(bookGRPCService))
+	server.GET("/reports/:report", middleware.GinJWT(jwtService), middleware.GinHasRole(model.RoleLibrarian),
+		httpHandler.ReportHandler(lendingGRPCService, bookGRPCService, userGRPCService))
+	server.GET("/covers/:id", httpHandler.CoverHandler(bookGRPCService))
 
-	httpPort := os.Getenv("HTTP_PORT")
-	if httpPort == "" {
-		httpPort = defaultHTTPPort
-	}
+	opdsRouter := server.Group("/opds",
+		middleware.GinBasicAuth(userGRPCService), middleware.GinJWT(jwtService), middleware.GinIsAuthenticated())
+	opdsRouter.GET("", httpHandler.OPDSRootHandler())
+	opdsRouter.GET("/opensearch.xml", httpHandler.OPDSSearchDescriptionHandler())
+	opdsRouter.GET("/books", httpHandler.OPDSBooksHandler(bookGRPCService))
+	opdsRouter.GET("/books/:id", httpHandler.OPDSBookHandler(bookGRPCService))
+	opdsRouter.GET("/categories", httpHandler.OPDSCategoriesHandler(bookGRPCService))
 
 	httpServer := &http.Server{
-		Addr:    httpPort,
+		Addr:    cfg.HTTPPort,
 		Handler: server,
 	}
 
@@ -164,20 +235,40 @@ func main() {
 		defer cancel()
 
 		if err := httpServer.Shutdown(ctx); err != nil {
-			log.Println(err)
+			log.Error().Err(err).Send()
+		}
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error().Err(err).Send()
 		}
-		if enableProf {
+		if cfg.PProf.Enable {
 			pprof.StopCPUProfile()
 			if err := pprofServer.Shutdown(ctx); err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			}
 		}
 	}()
 
-	log.Println("starting to serve")
+	log.Info().Str("port", cfg.HTTPPort).Msg("starting to serve")
 	if err = httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Fatal(err)
+		log.Fatal().Err(err).Send()
 	}
 	wg.Wait()
-	log.Println("service is gracefully shutdown")
+	log.Info().Msg("service is gracefully shutdown")
+}
+
+// dialBackend connects to a backend without blocking, so the gateway starts degraded while a backend is down and
+// reconnects to it. The resilience interceptors are the last ones, so the retries of a call are logged and measured once.
+func dialBackend(target string, credentials grpc.DialOption, resilienceClient *resilience.Client) (*grpc.ClientConn, error) {
+	reconnectBackoff := backoff.DefaultConfig
+	reconnectBackoff.MaxDelay = grpcMaxReconnectDelay
+
+	return grpc.Dial(
+		target,
+		credentials,
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: reconnectBackoff}),
+		grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor(), tenant.UnaryClientInterceptor(), logger.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor(), resilienceClient.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor(), tenant.StreamClientInterceptor(), logger.StreamClientInterceptor(),
+			metrics.StreamClientInterceptor(), resilienceClient.StreamClientInterceptor()),
+	)
 }
@@ -20,6 +20,12 @@ func init() {
 		log.Println("success create book collection")
 		return nil
 	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.BookCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop book collection")
 		return nil
 	})
 }
@@ -0,0 +1,74 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.StockMovementCollection)
+		if err != nil {
+			return err
+		}
+
+		opt := options.Index().SetName(constant.StockMovementBookIndex)
+		keys := bson.D{{"book_id", 1}, {"meta.created_at", -1}}
+		model := mongo.IndexModel{Keys: keys, Options: opt}
+
+		_, err = db.Collection(constant.StockMovementCollection).Indexes().
+			CreateOne(context.TODO(), model)
+		if err != nil {
+			return err
+		}
+
+		// open the ledger of existing books with their current stock
+		cursor, err := db.Collection(constant.BookCollection).
+			Find(context.TODO(), bson.D{{"stock", bson.D{{"$ne", 0}}}})
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(context.TODO())
+
+		for cursor.Next(context.TODO()) {
+			var book domain.Book
+			if err = cursor.Decode(&book); err != nil {
+				return err
+			}
+
+			movement := domain.StockMovement{
+				ID:     primitive.NewObjectID(),
+				BookID: book.ID,
+				Delta:  book.Stock,
+				Stock:  book.Stock,
+				Reason: constant.StockCorrection,
+			}
+			movement.Meta.Create()
+
+			_, err = db.Collection(constant.StockMovementCollection).InsertOne(context.TODO(), &movement)
+			if err != nil {
+				return err
+			}
+		}
+
+		log.Println("success create stock movement collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.StockMovementCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop stock movement collection")
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.CategoryCollection)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.CategoryCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"ancestors", 1}},
+				Options: options.Index().SetName(constant.CategoryAncestorsIndex),
+			})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BookCollection).Indexes().
+			CreateMany(context.TODO(), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{"category_id", 1}},
+					Options: options.Index().SetName(constant.BookCategoryIndex),
+				},
+				{
+					Keys:    bson.D{{"tags", 1}},
+					Options: options.Index().SetName(constant.BookTagsIndex),
+				},
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create category collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		indexes := db.Collection(constant.BookCollection).Indexes()
+		for _, index := range []string{constant.BookCategoryIndex, constant.BookTagsIndex} {
+			if _, err := indexes.DropOne(context.TODO(), index); err != nil {
+				return err
+			}
+		}
+
+		err := db.Collection(constant.CategoryCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop category collection")
+		return nil
+	})
+}
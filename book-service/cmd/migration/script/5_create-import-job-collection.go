@@ -0,0 +1,47 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.ImportJobCollection)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BookCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"isbn", 1}},
+				Options: options.Index().SetName(constant.BookISBNIndex),
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create import job collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		_, err := db.Collection(constant.BookCollection).Indexes().DropOne(context.TODO(), constant.BookISBNIndex)
+		if err != nil {
+			return err
+		}
+
+		err = db.Collection(constant.ImportJobCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop import job collection")
+		return nil
+	})
+}
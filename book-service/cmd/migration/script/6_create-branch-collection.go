@@ -0,0 +1,119 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.BranchCollection)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BranchCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"code", 1}},
+				Options: options.Index().SetName(constant.BranchCodeIndex).SetUnique(true),
+			})
+		if err != nil {
+			return err
+		}
+
+		err = db.CreateCollection(context.TODO(), constant.TransferCollection)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.TransferCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"book_id", 1}, {"meta.created_at", -1}},
+				Options: options.Index().SetName(constant.TransferBookIndex),
+			})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BookCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"branches.branch_id", 1}},
+				Options: options.Index().SetName(constant.BookBranchIndex),
+			})
+		if err != nil {
+			return err
+		}
+
+		// the library before the branches becomes the default branch
+		branch := domain.Branch{
+			ID:      primitive.NewObjectID(),
+			Name:    "Main",
+			Code:    "main",
+			Default: true,
+		}
+		branch.Meta.Create()
+
+		_, err = db.Collection(constant.BranchCollection).InsertOne(context.TODO(), &branch)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BookCollection).UpdateMany(context.TODO(), bson.D{}, mongo.Pipeline{
+			{{"$set", bson.D{{"branches", bson.A{
+				bson.D{{"branch_id", branch.ID}, {"stock", "$stock"}},
+			}}}}},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.StockMovementCollection).UpdateMany(context.TODO(),
+			bson.D{{"branch_id", bson.D{{"$exists", false}}}},
+			bson.D{{"$set", bson.D{{"branch_id", branch.ID}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create branch collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		// the stock of the books stays the sum of their branch stock
+		_, err := db.Collection(constant.BookCollection).UpdateMany(context.TODO(), bson.D{},
+			bson.D{{"$unset", bson.D{{"branches", ""}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BookCollection).Indexes().DropOne(context.TODO(), constant.BookBranchIndex)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.StockMovementCollection).UpdateMany(context.TODO(), bson.D{},
+			bson.D{{"$unset", bson.D{{"branch_id", ""}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, collection := range []string{constant.TransferCollection, constant.BranchCollection} {
+			if err = db.Collection(collection).Drop(context.TODO()); err != nil {
+				return err
+			}
+		}
+
+		log.Println("success drop branch collection")
+		return nil
+	})
+}
@@ -0,0 +1,99 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+// tenantCollections are the collections scoped by tenant
+var tenantCollections = []string{
+	constant.BookCollection,
+	constant.StockMovementCollection,
+	constant.CategoryCollection,
+	constant.ImportJobCollection,
+	constant.BranchCollection,
+	constant.TransferCollection,
+}
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		// the existing documents belong to the default tenant
+		for _, collection := range tenantCollections {
+			_, err := db.Collection(collection).UpdateMany(context.TODO(),
+				bson.D{{"meta.tenant_id", bson.D{{"$in", bson.A{nil, ""}}}}},
+				bson.D{{"$set", bson.D{{"meta.tenant_id", tenant.Default}}}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		// a branch code is unique inside its tenant only
+		_, err := db.Collection(constant.BranchCollection).Indexes().DropOne(context.TODO(), constant.BranchCodeIndex)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BranchCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"meta.tenant_id", 1}, {"code", 1}},
+				Options: options.Index().SetName(constant.BranchTenantCodeIndex).SetUnique(true),
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success add tenant ID")
+		return nil
+	}, func(db *mongo.Database) error {
+		// the documents of other tenants would be merged into a single library
+		for _, collection := range tenantCollections {
+			count, err := db.Collection(collection).CountDocuments(context.TODO(),
+				bson.D{{"meta.tenant_id", bson.D{{"$nin", bson.A{nil, "", tenant.Default}}}}},
+			)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("%s collection has documents of other tenants than %s, remove them first",
+					collection, tenant.Default)
+			}
+		}
+
+		_, err := db.Collection(constant.BranchCollection).Indexes().DropOne(context.TODO(), constant.BranchTenantCodeIndex)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.BranchCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"code", 1}},
+				Options: options.Index().SetName(constant.BranchCodeIndex).SetUnique(true),
+			})
+		if err != nil {
+			return err
+		}
+
+		for _, collection := range tenantCollections {
+			_, err = db.Collection(collection).UpdateMany(context.TODO(),
+				bson.D{{"meta.tenant_id", tenant.Default}},
+				bson.D{{"$unset", bson.D{{"meta.tenant_id", ""}}}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		log.Println("success remove tenant ID")
+		return nil
+	})
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/config"
+	"book-service/pkg/mongodb"
+	"book-service/pkg/seed"
+)
+
+func init() {
+	_ = godotenv.Load()
+}
+
+func main() {
+	cfg, err := config.LoadCommand()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mongodb.Configure(cfg.MongoDB.URI, cfg.MongoDB.Database)
+
+	options, err := seed.ParseOptions(os.Args[1:], os.Stdout, cfg.SeedProfile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = run(context.Background(), mongodb.GetDatabase(), options, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run seeds the books of the profile with the ledger of their stock, into the default branch of the tenant
+func run(ctx context.Context, db *mongo.Database, options seed.Options, out io.Writer) error {
+	if options.Profile.Name == "" {
+		_, _ = fmt.Fprintln(out, "no seed profile, nothing to seed")
+		return nil
+	}
+
+	var branch domain.Branch
+	err := db.Collection(constant.BranchCollection).
+		FindOne(ctx, bson.D{{"meta.tenant_id", options.TenantID}, {"default", true}}).
+		Decode(&branch)
+	if err != nil {
+		return fmt.Errorf("finding the default branch of tenant %s: %w", options.TenantID, err)
+	}
+
+	books := make([]seed.Document, 0, options.Profile.Books)
+	movements := make([]seed.Document, 0, options.Profile.Books)
+	for i := 0; i < options.Profile.Books; i++ {
+		book := newBook(options, i, branch.ID)
+		books = append(books, seed.Document{Filter: bson.D{{"_id", book.ID}}, Value: book})
+
+		// the ledger opens with the seeded stock
+		movement := domain.StockMovement{
+			ID:       options.ID("stock-movement", i),
+			BookID:   book.ID,
+			BranchID: branch.ID,
+			Delta:    book.Stock,
+			Stock:    book.Stock,
+			Reason:   constant.StockCorrection,
+		}
+		movement.Meta.Create()
+		movement.Meta.TenantID = options.TenantID
+		movements = append(movements, seed.Document{Filter: bson.D{{"_id", movement.ID}}, Value: movement})
+	}
+
+	inserted, err := seed.InsertMissing(ctx, db.Collection(constant.BookCollection), books)
+	if err != nil {
+		return err
+	}
+	if _, err = seed.InsertMissing(ctx, db.Collection(constant.StockMovementCollection), movements); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(out, "seeded %d of %d books of the %s profile\n", inserted, len(books), options.Profile.Name)
+	return err
+}
+
+func newBook(options seed.Options, i int, branchID primitive.ObjectID) domain.Book {
+	r := options.Rand("book", i)
+
+	title, author := demoBooks[i%len(demoBooks)].title, demoBooks[i%len(demoBooks)].author
+	if i >= len(demoBooks) {
+		title = fmt.Sprintf("%s, Volume %d", title, i/len(demoBooks)+1)
+	}
+
+	book := domain.Book{
+		ID:     options.ID("book", i),
+		Title:  title,
+		ISBN:   isbn(i),
+		Author: author,
+		Stock:  1 + r.Intn(10),
+		Tags:   []string{tags[r.Intn(len(tags))]},
+	}
+	book.Branches = []domain.BranchStock{{BranchID: branchID, Stock: book.Stock}}
+	book.Meta.Create()
+	book.Meta.TenantID = options.TenantID
+	return book
+}
+
+// isbn returns a valid ISBN-13 from the seed range of the 979-8 prefix
+func isbn(i int) string {
+	digits := fmt.Sprintf("979899%06d", i)
+
+	sum := 0
+	for j, r := range digits {
+		weight := 1
+		if j%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return fmt.Sprintf("%s%d", digits, (10-sum%10)%10)
+}
+
+var tags = []string{"classic", "fiction", "adventure", "mystery", "science", "poetry"}
+
+var demoBooks = []struct {
+	title  string
+	author string
+}{
+	{"Pride and Prejudice", "Jane Austen"},
+	{"Moby-Dick", "Herman Melville"},
+	{"Frankenstein", "Mary Shelley"},
+	{"The Adventures of Sherlock Holmes", "Arthur Conan Doyle"},
+	{"Alice's Adventures in Wonderland", "Lewis Carroll"},
+	{"The Time Machine", "H. G. Wells"},
+	{"Great Expectations", "Charles Dickens"},
+	{"The Odyssey", "Homer"},
+	{"Dracula", "Bram Stoker"},
+	{"The Picture of Dorian Gray", "Oscar Wilde"},
+	{"Treasure Island", "Robert Louis Stevenson"},
+	{"Leaves of Grass", "Walt Whitman"},
+}
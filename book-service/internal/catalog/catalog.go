@@ -0,0 +1,116 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"book-service/internal/domain/constant"
+)
+
+var ErrInvalidISBN = errors.New("invalid ISBN")
+
+// Record is a single book of an import or export file, ID is only set on export
+type Record struct {
+	Row          int
+	ID           string
+	Title        string
+	ISBN         string
+	Author       string
+	Stock        int
+	CategoryCode string
+	Tags         []string
+}
+
+// Reader reads the records of an import file one by one.
+// A *RowError only invalidates its row, so reading can continue, any other error stops the import.
+type Reader interface {
+	Next() (Record, error)
+}
+
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+func NewReader(format constant.ImportFormat, r io.Reader) (Reader, error) {
+	switch format {
+	case constant.ImportCSV:
+		return newCSVReader(r)
+	case constant.ImportMARC21:
+		return newMARCReader(marcISO2709, r), nil
+	case constant.ImportMARCXML:
+		return newMARCReader(marcXML, r), nil
+	}
+
+	return nil, fmt.Errorf("unsupported import format: %s", format)
+}
+
+// Writer writes the records of an export file, Close must be called to complete the file
+type Writer interface {
+	Write(record Record) error
+	Close() error
+}
+
+func NewWriter(format constant.ExportFormat, w io.Writer) (Writer, error) {
+	switch format {
+	case constant.ExportCSV:
+		return newCSVWriter(w), nil
+	case constant.ExportJSONLines:
+		return newJSONLinesWriter(w), nil
+	case constant.ExportMARCXML:
+		return newMARCXMLWriter(w), nil
+	}
+
+	return nil, fmt.Errorf("unsupported export format: %s", format)
+}
+
+// NormalizeISBN removes the separators of an ISBN-10 or ISBN-13 and validates its check digit
+func NormalizeISBN(isbn string) (string, error) {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(isbn)))
+
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, r := range isbn {
+			digit := int(r - '0')
+			if r == 'X' && i == 9 {
+				digit = 10
+			} else if r < '0' || r > '9' {
+				return "", fmt.Errorf("%w: %s", ErrInvalidISBN, isbn)
+			}
+			sum += (10 - i) * digit
+		}
+		if sum%11 != 0 {
+			return "", fmt.Errorf("%w: %s", ErrInvalidISBN, isbn)
+		}
+	case 13:
+		sum := 0
+		for i, r := range isbn {
+			if r < '0' || r > '9' {
+				return "", fmt.Errorf("%w: %s", ErrInvalidISBN, isbn)
+			}
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += weight * int(r-'0')
+		}
+		if sum%10 != 0 {
+			return "", fmt.Errorf("%w: %s", ErrInvalidISBN, isbn)
+		}
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidISBN, isbn)
+	}
+
+	return isbn, nil
+}
@@ -0,0 +1,209 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"book-service/internal/domain/constant"
+)
+
+// iso2709 encodes fields given as tag and content, data field contents start with the indicators
+func iso2709(fields ...[2]string) string {
+	var directory, data strings.Builder
+	for _, field := range fields {
+		content := field[1] + "\x1e"
+		fmt.Fprintf(&directory, "%s%04d%05d", field[0], len(content), data.Len())
+		data.WriteString(content)
+	}
+	directory.WriteString("\x1e")
+
+	baseAddress := 24 + directory.Len()
+	length := baseAddress + data.Len() + 1
+	leader := fmt.Sprintf("%05dnam a22%05d a 4500", length, baseAddress)
+
+	return leader + directory.String() + data.String() + "\x1d"
+}
+
+func readAll(t *testing.T, format constant.ImportFormat, input string) ([]Record, []int) {
+	t.Helper()
+
+	reader, err := NewReader(format, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	records := make([]Record, 0)
+	failedRows := make([]int, 0)
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records, failedRows
+		}
+
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			failedRows = append(failedRows, rowErr.Row)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         constant.ImportFormat
+		input          string
+		wantRecords    []Record
+		wantFailedRows []int
+	}{
+		{
+			name:   "csv with columns in any order",
+			format: constant.ImportCSV,
+			input: "Stock,Title,ISBN,tags\n" +
+				"3,The Go Programming Language,978-0-13-419044-0,programming;go\n" +
+				"lots,Broken Stock,,\n" +
+				",\"Quoted, Title\",,\n",
+			wantRecords: []Record{
+				{Row: 2, Title: "The Go Programming Language", ISBN: "978-0-13-419044-0", Stock: 3, Tags: []string{"programming", "go"}},
+				{Row: 4, Title: "Quoted, Title"},
+			},
+			wantFailedRows: []int{3},
+		},
+		{
+			name:   "marc21",
+			format: constant.ImportMARC21,
+			input: iso2709(
+				[2]string{"001", "123"},
+				[2]string{"020", "  \x1fa0306406152 (pbk.)"},
+				[2]string{"100", "1 \x1faDoe, Jane,"},
+				[2]string{"245", "10\x1faA title :\x1fbthe subtitle /"},
+				[2]string{"650", " 0\x1faScience fiction."},
+			) + "\n" + "00010broken\x1d",
+			wantRecords: []Record{
+				{Row: 1, Title: "A title: the subtitle", ISBN: "0306406152", Author: "Doe, Jane", Tags: []string{"Science fiction"}},
+			},
+			wantFailedRows: []int{2},
+		},
+		{
+			name:   "marcxml",
+			format: constant.ImportMARCXML,
+			input: `<?xml version="1.0"?>
+<collection xmlns="http://www.loc.gov/MARC21/slim">
+  <record>
+    <leader>00000nam a2200000 a 4500</leader>
+    <datafield tag="020" ind1=" " ind2=" "><subfield code="a">9780134190440</subfield></datafield>
+    <datafield tag="245" ind1="1" ind2="0"><subfield code="a">The Go Programming Language.</subfield></datafield>
+  </record>
+</collection>`,
+			wantRecords: []Record{
+				{Row: 1, Title: "The Go Programming Language", ISBN: "9780134190440", Tags: []string{}},
+			},
+			wantFailedRows: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, failedRows := readAll(t, tt.format, tt.input)
+			if !reflect.DeepEqual(records, tt.wantRecords) {
+				t.Errorf("records = %+v, want %+v", records, tt.wantRecords)
+			}
+			if !reflect.DeepEqual(failedRows, tt.wantFailedRows) {
+				t.Errorf("failed rows = %v, want %v", failedRows, tt.wantFailedRows)
+			}
+		})
+	}
+}
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		isbn    string
+		want    string
+		wantErr bool
+	}{
+		{isbn: "978-0-13-419044-0", want: "9780134190440"},
+		{isbn: "0 306 40615 2", want: "0306406152"},
+		{isbn: "080442957x", want: "080442957X"},
+		{isbn: "9780134190441", wantErr: true},
+		{isbn: "12345", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isbn, func(t *testing.T) {
+			got, err := NormalizeISBN(tt.isbn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeISBN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeISBN() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	records := []Record{
+		{ID: "1", Title: "The Go Programming Language", ISBN: "9780134190440", Author: "Donovan, Alan", Stock: 3, CategoryCode: "005", Tags: []string{"go", "programming"}},
+		{ID: "2", Title: "Untitled, with comma", Tags: []string{}},
+	}
+
+	tests := []struct {
+		name         string
+		exportFormat constant.ExportFormat
+		importFormat constant.ImportFormat
+		want         []Record
+	}{
+		{
+			name:         "csv",
+			exportFormat: constant.ExportCSV,
+			importFormat: constant.ImportCSV,
+			want: []Record{
+				{Row: 2, Title: "The Go Programming Language", ISBN: "9780134190440", Author: "Donovan, Alan", Stock: 3, CategoryCode: "005", Tags: []string{"go", "programming"}},
+				{Row: 3, Title: "Untitled, with comma"},
+			},
+		},
+		{
+			name:         "marcxml keeps the bibliographic data only",
+			exportFormat: constant.ExportMARCXML,
+			importFormat: constant.ImportMARCXML,
+			want: []Record{
+				{Row: 1, Title: "The Go Programming Language", ISBN: "9780134190440", Author: "Donovan, Alan", CategoryCode: "005", Tags: []string{"go", "programming"}},
+				{Row: 2, Title: "Untitled, with comma", Tags: []string{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var exported strings.Builder
+			writer, err := NewWriter(tt.exportFormat, &exported)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			for _, record := range records {
+				if err = writer.Write(record); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err = writer.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			got, failedRows := readAll(t, tt.importFormat, exported.String())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records = %+v, want %+v", got, tt.want)
+			}
+			if len(failedRows) > 0 {
+				t.Errorf("failed rows = %v, want none", failedRows)
+			}
+		})
+	}
+}
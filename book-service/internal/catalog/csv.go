@@ -0,0 +1,136 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVHeader is the column order of an exported catalog, the import accepts the columns in any order
+var CSVHeader = []string{"id", "title", "isbn", "author", "stock", "category_code", "tags"}
+
+// CSVTagSeparator separates the tags in the tags column
+const CSVTagSeparator = ";"
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		columns[column] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("CSV header has no title column")
+	}
+
+	return &csvReader{
+		reader:  reader,
+		columns: columns,
+		row:     1,
+	}, nil
+}
+
+func (r *csvReader) Next() (Record, error) {
+	fields, err := r.reader.Read()
+	r.row++
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Record{}, &RowError{Row: r.row, Err: parseErr.Err}
+		}
+		return Record{}, err
+	}
+
+	record := Record{
+		Row:          r.row,
+		Title:        r.field(fields, "title"),
+		ISBN:         r.field(fields, "isbn"),
+		Author:       r.field(fields, "author"),
+		CategoryCode: r.field(fields, "category_code"),
+	}
+	if tags := r.field(fields, "tags"); tags != "" {
+		record.Tags = strings.Split(tags, CSVTagSeparator)
+	}
+	if stock := r.field(fields, "stock"); stock != "" {
+		record.Stock, err = strconv.Atoi(stock)
+		if err != nil || record.Stock < 0 {
+			return Record{}, &RowError{Row: r.row, Err: fmt.Errorf("invalid stock: %s", stock)}
+		}
+	}
+
+	return record, nil
+}
+
+func (r *csvReader) field(fields []string, column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(fields) {
+		return ""
+	}
+	return strings.TrimSpace(fields[i])
+}
+
+type csvWriter struct {
+	writer  *csv.Writer
+	started bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{
+		writer: csv.NewWriter(w),
+	}
+}
+
+func (w *csvWriter) Write(record Record) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.writer.Write([]string{
+		record.ID,
+		record.Title,
+		record.ISBN,
+		record.Author,
+		strconv.Itoa(record.Stock),
+		record.CategoryCode,
+		strings.Join(record.Tags, CSVTagSeparator),
+	})
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// writeHeader is also called on Close, so an empty export still has the header
+func (w *csvWriter) writeHeader() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	return w.writer.Write(CSVHeader)
+}
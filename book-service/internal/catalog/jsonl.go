@@ -0,0 +1,48 @@
+package catalog
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonLinesRecord struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	ISBN         string   `json:"isbn,omitempty"`
+	Author       string   `json:"author,omitempty"`
+	Stock        int      `json:"stock"`
+	CategoryCode string   `json:"category_code,omitempty"`
+	Tags         []string `json:"tags"`
+}
+
+// jsonLinesWriter writes one JSON object per line
+type jsonLinesWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLinesWriter(w io.Writer) *jsonLinesWriter {
+	return &jsonLinesWriter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (w *jsonLinesWriter) Write(record Record) error {
+	tags := record.Tags
+	if tags == nil {
+		tags = make([]string, 0)
+	}
+
+	return w.encoder.Encode(jsonLinesRecord{
+		ID:           record.ID,
+		Title:        record.Title,
+		ISBN:         record.ISBN,
+		Author:       record.Author,
+		Stock:        record.Stock,
+		CategoryCode: record.CategoryCode,
+		Tags:         tags,
+	})
+}
+
+func (w *jsonLinesWriter) Close() error {
+	return nil
+}
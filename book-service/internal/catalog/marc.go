@@ -0,0 +1,108 @@
+package catalog
+
+import (
+	"errors"
+	"io"
+
+	"book-service/pkg/marc"
+)
+
+type marcRecordReader interface {
+	Next() (marc.Record, error)
+}
+
+type marcEncoding int
+
+const (
+	marcISO2709 marcEncoding = iota
+	marcXML
+)
+
+// marcReader maps MARC 21 bibliographic records, the row is the record position in the file
+type marcReader struct {
+	reader marcRecordReader
+	row    int
+}
+
+func newMARCReader(encoding marcEncoding, r io.Reader) *marcReader {
+	var reader marcRecordReader = marc.NewISO2709Reader(r)
+	if encoding == marcXML {
+		reader = marc.NewXMLReader(r)
+	}
+
+	return &marcReader{
+		reader: reader,
+	}
+}
+
+func (r *marcReader) Next() (Record, error) {
+	marcRecord, err := r.reader.Next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		r.row++
+		if errors.Is(err, marc.ErrInvalidRecord) {
+			return Record{}, &RowError{Row: r.row, Err: err}
+		}
+		return Record{}, err
+	}
+	r.row++
+
+	return Record{
+		Row:          r.row,
+		Title:        marcRecord.Title(),
+		ISBN:         marcRecord.ISBN(),
+		Author:       marcRecord.Author(),
+		CategoryCode: marcRecord.Classification(),
+		Tags:         marcRecord.Subjects(),
+	}, nil
+}
+
+// marcXMLWriter maps a book to a minimal MARC 21 bibliographic record
+type marcXMLWriter struct {
+	writer *marc.XMLWriter
+}
+
+func newMARCXMLWriter(w io.Writer) *marcXMLWriter {
+	return &marcXMLWriter{
+		writer: marc.NewXMLWriter(w),
+	}
+}
+
+func (w *marcXMLWriter) Write(record Record) error {
+	marcRecord := marc.Record{
+		Leader:        "00000nam a2200000 a 4500",
+		ControlFields: []marc.ControlField{{Tag: "001", Value: record.ID}},
+	}
+	if record.ISBN != "" {
+		marcRecord.DataFields = append(marcRecord.DataFields, dataField("020", " ", " ", record.ISBN))
+	}
+	if record.CategoryCode != "" {
+		marcRecord.DataFields = append(marcRecord.DataFields, dataField("084", " ", " ", record.CategoryCode))
+	}
+	titleIndicator := "0"
+	if record.Author != "" {
+		titleIndicator = "1"
+		marcRecord.DataFields = append(marcRecord.DataFields, dataField("100", "1", " ", record.Author))
+	}
+	marcRecord.DataFields = append(marcRecord.DataFields, dataField("245", titleIndicator, "0", record.Title))
+	for _, tag := range record.Tags {
+		marcRecord.DataFields = append(marcRecord.DataFields, dataField("650", " ", "4", tag))
+	}
+
+	return w.writer.Write(marcRecord)
+}
+
+func (w *marcXMLWriter) Close() error {
+	return w.writer.Close()
+}
+
+func dataField(tag, ind1, ind2, value string) marc.DataField {
+	return marc.DataField{
+		Tag:       tag,
+		Ind1:      ind1,
+		Ind2:      ind2,
+		Subfields: []marc.Subfield{{Code: "a", Value: value}},
+	}
+}
@@ -0,0 +1,95 @@
+package cover
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"book-service/internal/domain/constant"
+)
+
+const (
+	MaxSize = 5 << 20
+	// maxPixels guards the decoder against small files of huge images
+	maxPixels        = 40_000_000
+	thumbnailQuality = 85
+)
+
+var ErrUnsupportedType = errors.New("unsupported cover image type, it must be JPEG, PNG or WebP")
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// Widths of the thumbnails, a smaller image is never upscaled
+var Widths = map[constant.CoverSize]int{
+	constant.CoverSmall:  96,
+	constant.CoverMedium: 240,
+	constant.CoverLarge:  480,
+}
+
+// DetectContentType sniffs the image type from the content, the type declared by the client is not trusted
+func DetectContentType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := extensions[contentType]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	return contentType, nil
+}
+
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// Thumbnails renders a JPEG thumbnail for every size of Widths
+func Thumbnails(data []byte) (map[constant.CoverSize][]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("cover image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := make(map[constant.CoverSize][]byte, len(Widths))
+	for size, width := range Widths {
+		var buffer bytes.Buffer
+		if err = jpeg.Encode(&buffer, resize(src, width), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return nil, err
+		}
+		thumbnails[size] = buffer.Bytes()
+	}
+
+	return thumbnails, nil
+}
+
+func resize(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= width {
+		width = bounds.Dx()
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	// JPEG has no transparency, so a transparent PNG is flattened on white
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
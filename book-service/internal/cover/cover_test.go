@@ -0,0 +1,62 @@
+package cover
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"book-service/internal/domain/constant"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 200, A: 255})
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestDetectContentType(t *testing.T) {
+	contentType, err := DetectContentType(encodePNG(t, 4, 4))
+	if err != nil || contentType != "image/png" || Extension(contentType) != ".png" {
+		t.Errorf("DetectContentType(png) = %q, %v", contentType, err)
+	}
+
+	if _, err = DetectContentType([]byte("%PDF-1.4")); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("DetectContentType(pdf) error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestThumbnails(t *testing.T) {
+	thumbnails, err := Thumbnails(encodePNG(t, 300, 450))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[constant.CoverSize][2]int{
+		constant.CoverSmall:  {96, 144},
+		constant.CoverMedium: {240, 360},
+		// never upscaled
+		constant.CoverLarge: {300, 450},
+	}
+	for size, bounds := range want {
+		config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnails[size]))
+		if err != nil {
+			t.Fatalf("%s: %v", size, err)
+		}
+		if config.Width != bounds[0] || config.Height != bounds[1] {
+			t.Errorf("%s = %dx%d, want %dx%d", size, config.Width, config.Height, bounds[0], bounds[1])
+		}
+	}
+}
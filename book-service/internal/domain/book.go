@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
@@ -11,17 +12,45 @@ import (
 type Book struct {
 	ID           primitive.ObjectID `json:"id" bson:"_id"`
 	mongodb.Meta `json:"meta" bson:"meta"`
-	Title        string `json:"title" bson:"title"`
-	Stock        int    `json:"stock" bson:"stock"`
+	Title        string              `json:"title" bson:"title"`
+	ISBN         string              `json:"isbn" bson:"isbn"`
+	Author       string              `json:"author" bson:"author"`
+	Stock        int                 `json:"stock" bson:"stock"`
+	CategoryID   *primitive.ObjectID `json:"category_id" bson:"category_id"`
+	Tags         []string            `json:"tags" bson:"tags"`
+	Cover        *BookCover          `json:"cover" bson:"cover"`
+	Branches     []BranchStock       `json:"branches" bson:"branches"`
+}
+
+// BranchStock is the stock of a book at one branch, Book.Stock is the total of every branch
+type BranchStock struct {
+	BranchID primitive.ObjectID `json:"branch_id" bson:"branch_id"`
+	Stock    int                `json:"stock" bson:"stock"`
+}
+
+// BookCover is the uploaded cover image, every upload is stored under a new version,
+// so the cover URLs can be cached forever
+type BookCover struct {
+	Version     string    `json:"version" bson:"version"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
 }
 
 type BookRepository interface {
 	Create(ctx context.Context, book *Book) error
 	Fetch(ctx context.Context, filter map[string]interface{}) ([]Book, error)
 	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FetchEach(ctx context.Context, filter map[string]interface{}, fn func(Book) error) error
 	FindByID(ctx context.Context, id string) (Book, error)
 	FindByTitle(ctx context.Context, title string) (Book, error)
-	Update(ctx context.Context, book *Book) error
-	UpdateStock(ctx context.Context, book *Book, newStock int) error
+	FindByISBN(ctx context.Context, isbn string) (Book, error)
+	FindByExactTitle(ctx context.Context, title string) (Book, error)
+	UpdateDetails(ctx context.Context, book *Book) error
+	UpdateClassification(ctx context.Context, book *Book) error
+	IncrementStock(ctx context.Context, id string, branchID primitive.ObjectID, delta int) (Book, error)
+	UpdateCover(ctx context.Context, id string, cover *BookCover) (Book, error)
 	Delete(ctx context.Context, book *Book) error
+	CountByCategory(ctx context.Context) (map[primitive.ObjectID]int, error)
+	// CountZeroStockByTenant is not scoped to the tenant of the request, it counts the books of every tenant
+	CountZeroStockByTenant(ctx context.Context) (map[string]int, error)
 }
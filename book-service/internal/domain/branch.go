@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"book-service/pkg/mongodb"
+)
+
+// Branch is a library location holding its own copies of the books.
+// The default branch takes the stock changes that do not name a branch.
+type Branch struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	Name         string `json:"name" bson:"name"`
+	Code         string `json:"code" bson:"code"`
+	Address      string `json:"address" bson:"address"`
+	Default      bool   `json:"default" bson:"default"`
+}
+
+type BranchRepository interface {
+	Create(ctx context.Context, branch *Branch) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]Branch, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FindByID(ctx context.Context, id string) (Branch, error)
+	FindDefault(ctx context.Context) (Branch, error)
+	Update(ctx context.Context, branch *Branch) error
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"book-service/pkg/mongodb"
+)
+
+// Category is a node of the book classification tree, e.g. "800 Literature" > "810 American literature".
+// Ancestors holds the category path from the root down to the parent.
+type Category struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	Name         string               `json:"name" bson:"name"`
+	Code         string               `json:"code" bson:"code"`
+	ParentID     *primitive.ObjectID  `json:"parent_id" bson:"parent_id"`
+	Ancestors    []primitive.ObjectID `json:"ancestors" bson:"ancestors"`
+	LoanDays     int                  `json:"loan_days" bson:"loan_days"`
+}
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *Category) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]Category, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FindByID(ctx context.Context, id string) (Category, error)
+	Update(ctx context.Context, category *Category) error
+	Delete(ctx context.Context, category *Category) error
+}
@@ -1,5 +1,24 @@
 package constant
 
 const (
-	BookCollection = "book"
+	BookCollection          = "book"
+	StockMovementCollection = "stock_movement"
+	CategoryCollection      = "category"
+	ImportJobCollection     = "import_job"
+	BranchCollection        = "branch"
+	TransferCollection      = "transfer"
+	OutboxCollection        = "outbox"
+
+	StockMovementBookIndex = "stock-movement-book-index"
+	CategoryAncestorsIndex = "category-ancestors-index"
+	BookCategoryIndex      = "book-category-index"
+	BookTagsIndex          = "book-tags-index"
+	BookISBNIndex          = "book-isbn-index"
+	BookBranchIndex        = "book-branch-index"
+	BranchCodeIndex        = "branch-code-index"
+	// BranchTenantCodeIndex replaces BranchCodeIndex, a branch code is unique inside its tenant only
+	BranchTenantCodeIndex = "branch-tenant-code-index"
+	TransferBookIndex     = "transfer-book-index"
+	// OutboxPublishedIndex expires the published events, the pending ones have no published time
+	OutboxPublishedIndex = "outbox-published-index"
 )
@@ -0,0 +1,18 @@
+package constant
+
+type CoverSize string
+
+const (
+	CoverSmall    CoverSize = "small"
+	CoverMedium   CoverSize = "medium"
+	CoverLarge    CoverSize = "large"
+	CoverOriginal CoverSize = "original"
+)
+
+func (s CoverSize) Valid() bool {
+	switch s {
+	case CoverSmall, CoverMedium, CoverLarge, CoverOriginal:
+		return true
+	}
+	return false
+}
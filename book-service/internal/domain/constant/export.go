@@ -0,0 +1,17 @@
+package constant
+
+type ExportFormat string
+
+const (
+	ExportCSV       ExportFormat = "csv"
+	ExportJSONLines ExportFormat = "jsonl"
+	ExportMARCXML   ExportFormat = "marcxml"
+)
+
+func (f ExportFormat) Valid() bool {
+	switch f {
+	case ExportCSV, ExportJSONLines, ExportMARCXML:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package constant
+
+type ImportFormat string
+
+const (
+	ImportCSV     ImportFormat = "csv"
+	ImportMARC21  ImportFormat = "marc21"
+	ImportMARCXML ImportFormat = "marcxml"
+)
+
+func (f ImportFormat) Valid() bool {
+	switch f {
+	case ImportCSV, ImportMARC21, ImportMARCXML:
+		return true
+	}
+	return false
+}
+
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
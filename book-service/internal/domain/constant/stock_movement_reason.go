@@ -0,0 +1,20 @@
+package constant
+
+type StockMovementReason string
+
+const (
+	StockPurchase   StockMovementReason = "purchase"
+	StockLoss       StockMovementReason = "loss"
+	StockLending    StockMovementReason = "lending"
+	StockReturn     StockMovementReason = "return"
+	StockCorrection StockMovementReason = "correction"
+	StockTransfer   StockMovementReason = "transfer"
+)
+
+func (r StockMovementReason) Valid() bool {
+	switch r {
+	case StockPurchase, StockLoss, StockLending, StockReturn, StockCorrection, StockTransfer:
+		return true
+	}
+	return false
+}
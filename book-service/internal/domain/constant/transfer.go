@@ -0,0 +1,29 @@
+package constant
+
+type TransferStatus string
+
+const (
+	TransferRequested TransferStatus = "requested"
+	TransferInTransit TransferStatus = "in_transit"
+	TransferReceived  TransferStatus = "received"
+	TransferCanceled  TransferStatus = "canceled"
+)
+
+func (s TransferStatus) Valid() bool {
+	switch s {
+	case TransferRequested, TransferInTransit, TransferReceived, TransferCanceled:
+		return true
+	}
+	return false
+}
+
+// From returns the only status a transfer can move to s from
+func (s TransferStatus) From() TransferStatus {
+	switch s {
+	case TransferInTransit, TransferCanceled:
+		return TransferRequested
+	case TransferReceived:
+		return TransferInTransit
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"book-service/internal/domain/constant"
+	"book-service/pkg/mongodb"
+)
+
+// ImportJob tracks the progress of a bulk catalog import, so the client can poll it while the file is processed.
+// In a dry run, Created counts the books that would be created.
+type ImportJob struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	Format       constant.ImportFormat `json:"format" bson:"format"`
+	DryRun       bool                  `json:"dry_run" bson:"dry_run"`
+	Status       constant.ImportStatus `json:"status" bson:"status"`
+	ActorID      string                `json:"actor_id" bson:"actor_id"`
+	FileName     string                `json:"file_name" bson:"file_name"`
+	BranchID     primitive.ObjectID    `json:"branch_id" bson:"branch_id"`
+	Processed    int                   `json:"processed" bson:"processed"`
+	Created      int                   `json:"created" bson:"created"`
+	Skipped      int                   `json:"skipped" bson:"skipped"`
+	Failed       int                   `json:"failed" bson:"failed"`
+	Errors       []ImportRowError      `json:"errors" bson:"errors"`
+	Message      string                `json:"message" bson:"message"`
+}
+
+// ImportRowError explains why a row is skipped or failed, BookID is set for a duplicate of an existing book
+type ImportRowError struct {
+	Row     int    `json:"row" bson:"row"`
+	Message string `json:"message" bson:"message"`
+	BookID  string `json:"book_id" bson:"book_id"`
+}
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *ImportJob) error
+	FindByID(ctx context.Context, id string) (ImportJob, error)
+	Update(ctx context.Context, job *ImportJob) error
+}
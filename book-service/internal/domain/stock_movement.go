@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"book-service/internal/domain/constant"
+	"book-service/pkg/mongodb"
+)
+
+// StockMovement is a ledger entry of a single book stock change
+type StockMovement struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	BookID       primitive.ObjectID           `json:"book_id" bson:"book_id"`
+	BranchID     primitive.ObjectID           `json:"branch_id" bson:"branch_id"`
+	Delta        int                          `json:"delta" bson:"delta"`
+	Stock        int                          `json:"stock" bson:"stock"`
+	Reason       constant.StockMovementReason `json:"reason" bson:"reason"`
+	ActorID      string                       `json:"actor_id" bson:"actor_id"`
+	LendingID    string                       `json:"lending_id" bson:"lending_id"`
+	TransferID   string                       `json:"transfer_id" bson:"transfer_id"`
+}
+
+type StockMovementRepository interface {
+	Create(ctx context.Context, movement *StockMovement) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]StockMovement, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	SumDelta(ctx context.Context, bookIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"book-service/internal/domain/constant"
+	"book-service/pkg/mongodb"
+)
+
+// Transfer is a request to move copies of a book between branches.
+// The copies leave the source stock when shipped and join the destination stock when received.
+type Transfer struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	BookID       primitive.ObjectID      `json:"book_id" bson:"book_id"`
+	FromBranchID primitive.ObjectID      `json:"from_branch_id" bson:"from_branch_id"`
+	ToBranchID   primitive.ObjectID      `json:"to_branch_id" bson:"to_branch_id"`
+	Quantity     int                     `json:"quantity" bson:"quantity"`
+	Status       constant.TransferStatus `json:"status" bson:"status"`
+	RequestedBy  string                  `json:"requested_by" bson:"requested_by"`
+	ShippedBy    string                  `json:"shipped_by" bson:"shipped_by"`
+	ReceivedBy   string                  `json:"received_by" bson:"received_by"`
+}
+
+type TransferRepository interface {
+	Create(ctx context.Context, transfer *Transfer) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]Transfer, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FindByID(ctx context.Context, id string) (Transfer, error)
+	UpdateStatus(ctx context.Context, transfer *Transfer, from constant.TransferStatus) error
+}
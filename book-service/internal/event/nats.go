@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"book-service/pkg/proto"
+)
+
+// natsFlushTimeout bounds the wait for the server to receive an event
+const natsFlushTimeout = 5 * time.Second
+
+// NATSConn is the part of *nats.Conn used by NATSPublisher
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+	FlushWithContext(ctx context.Context) error
+	Close()
+}
+
+// NATSPublisher publishes the events on <prefix>.<tenant>.<type>, like library.default.lending.finished,
+// so a consumer of a type subscribes to library.*.lending.finished
+type NATSPublisher struct {
+	conn          NATSConn
+	subjectPrefix string
+}
+
+func NewNATSPublisher(conn NATSConn, subjectPrefix string) *NATSPublisher {
+	return &NATSPublisher{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+// ConnectNATS connects to the comma separated NATS URLs. The connection is retried as long as the service runs,
+// the events stay in the outbox meanwhile.
+func ConnectNATS(url, subjectPrefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name(Source), nats.RetryOnFailedConnect(true), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNATSPublisher(conn, subjectPrefix), nil
+}
+
+// Publish waits until the server has received the event, so an event sent on a broken connection is not
+// marked as published
+func (p *NATSPublisher) Publish(ctx context.Context, event *proto.Event) error {
+	data, err := protobuf.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err = p.conn.Publish(p.Subject(event), data); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, natsFlushTimeout)
+	defer cancel()
+
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Subject(event *proto.Event) string {
+	return p.subjectPrefix + "." + event.TenantId + "." + event.Type
+}
+
+func (p *NATSPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
@@ -0,0 +1,51 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	protobuf "google.golang.org/protobuf/proto"
+
+	"book-service/pkg/proto"
+)
+
+type fakeNATSConn struct {
+	subject  string
+	data     []byte
+	flushErr error
+}
+
+func (c *fakeNATSConn) Publish(subject string, data []byte) error {
+	c.subject, c.data = subject, data
+	return nil
+}
+
+func (c *fakeNATSConn) FlushWithContext(context.Context) error {
+	return c.flushErr
+}
+
+func (c *fakeNATSConn) Close() {}
+
+func TestNATSPublisher_Publish(t *testing.T) {
+	conn := &fakeNATSConn{}
+	publisher := NewNATSPublisher(conn, "library")
+
+	event := &proto.Event{Id: "1", Type: StockChanged, TenantId: "default"}
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if conn.subject != "library.default.book.stock_changed" {
+		t.Errorf("subject = %s, want library.default.book.stock_changed", conn.subject)
+	}
+	var published proto.Event
+	if err := protobuf.Unmarshal(conn.data, &published); err != nil || published.Id != "1" {
+		t.Errorf("published = %v, %v, want the marshaled event", &published, err)
+	}
+
+	// an event not received by the server is not published
+	conn.flushErr = errors.New("nats: connection closed")
+	if err := publisher.Publish(context.Background(), event); err == nil {
+		t.Error("Publish() error = nil, want the flush error")
+	}
+}
@@ -0,0 +1,169 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"book-service/internal/domain"
+	"book-service/internal/repository"
+	"book-service/pkg/proto"
+	"book-service/pkg/tenant"
+)
+
+type failingPublisher struct {
+	failures  int
+	published []*proto.Event
+}
+
+func (p *failingPublisher) Publish(_ context.Context, event *proto.Event) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker is unavailable")
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func (p *failingPublisher) Close() error {
+	return nil
+}
+
+func TestRecord(t *testing.T) {
+	outboxRepository := repository.NewOutboxMemoryRepository()
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+
+	if err := Record(ctx, outboxRepository, BookCreated, "book-1", &proto.BookCreated{BookId: "book-1", Title: "Dune"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	publisher := NewChannelPublisher(1)
+	if _, err := NewRelay(outboxRepository, publisher, time.Second, 3).RelayPending(context.Background()); err != nil {
+		t.Fatalf("RelayPending() error = %v", err)
+	}
+
+	event := <-publisher.Events()
+	if event.Type != BookCreated || event.Version != 1 || event.Source != Source || event.TenantId != "tenant-1" ||
+		event.AggregateId != "book-1" || event.Id == "" || event.OccurredAt == nil {
+		t.Errorf("event = %v, want the envelope of book.created", event)
+	}
+
+	var payload proto.BookCreated
+	if err := event.Payload.UnmarshalTo(&payload); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	if payload.Title != "Dune" {
+		t.Errorf("payload = %v, want the created book", &payload)
+	}
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	outboxRepository := repository.NewOutboxMemoryRepository()
+	ctx := context.Background()
+	for _, id := range []string{"1", "2", "3"} {
+		if err := Record(ctx, outboxRepository, StockChanged, id, &proto.StockChanged{BookId: id}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	publisher := &failingPublisher{failures: 1}
+	relay := NewRelay(outboxRepository, publisher, time.Second, 3)
+
+	// the first event fails, so none is published after it
+	if published, err := relay.RelayPending(ctx); err == nil || published != 0 {
+		t.Fatalf("RelayPending() = %d, %v, want 0 and the broker error", published, err)
+	}
+	pending, _ := outboxRepository.FetchPending(ctx, 10)
+	if len(pending) != 3 || pending[0].Attempts != 1 || pending[0].LastError != "broker is unavailable" {
+		t.Fatalf("pending events = %+v, want 3 with the failure of the first one", pending)
+	}
+
+	if published, err := relay.RelayPending(ctx); err != nil || published != 3 {
+		t.Fatalf("RelayPending() = %d, %v, want 3 published events", published, err)
+	}
+	for i, event := range publisher.published {
+		if want := []string{"1", "2", "3"}[i]; event.AggregateId != want {
+			t.Errorf("published[%d] = %s, want %s", i, event.AggregateId, want)
+		}
+	}
+	if pending, _ = outboxRepository.FetchPending(ctx, 10); len(pending) != 0 {
+		t.Errorf("pending events = %d, want none", len(pending))
+	}
+}
+
+func TestRelay_RelayPending_MovesAside(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       []byte
+		failures      int
+		wantPublished []string
+	}{
+		{name: "failing every attempt", failures: 3, wantPublished: []string{"2"}},
+		{name: "unreadable payload", payload: []byte("not an event"), wantPublished: []string{"2"}},
+		{name: "published at the last attempt", failures: 2, wantPublished: []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outboxRepository := repository.NewOutboxMemoryRepository()
+			ctx := context.Background()
+
+			if tt.payload != nil {
+				if err := outboxRepository.Create(ctx, &domain.OutboxEvent{Type: StockChanged, Payload: tt.payload}); err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+			} else if err := Record(ctx, outboxRepository, StockChanged, "1", &proto.StockChanged{BookId: "1"}); err != nil {
+				t.Fatalf("Record() error = %v", err)
+			}
+			if err := Record(ctx, outboxRepository, StockChanged, "2", &proto.StockChanged{BookId: "2"}); err != nil {
+				t.Fatalf("Record() error = %v", err)
+			}
+
+			// every run retries the first event, until it is published or moved aside
+			publisher := &failingPublisher{failures: tt.failures}
+			relay := NewRelay(outboxRepository, publisher, time.Second, 3)
+			for i := 0; i < 3; i++ {
+				_, _ = relay.RelayPending(ctx)
+			}
+
+			published := make([]string, 0)
+			for _, event := range publisher.published {
+				published = append(published, event.AggregateId)
+			}
+			if !reflect.DeepEqual(published, tt.wantPublished) {
+				t.Errorf("published = %v, want %v", published, tt.wantPublished)
+			}
+			if pending, _ := outboxRepository.FetchPending(ctx, 10); len(pending) != 0 {
+				t.Errorf("pending events = %+v, want none", pending)
+			}
+		})
+	}
+}
+
+func TestRelay_Run(t *testing.T) {
+	outboxRepository := repository.NewOutboxMemoryRepository()
+	publisher := NewChannelPublisher(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewRelay(outboxRepository, publisher, 10*time.Millisecond, 3).Run(ctx)
+		close(done)
+	}()
+
+	if err := Record(context.Background(), outboxRepository, BookDeleted, "book-1", &proto.BookDeleted{BookId: "book-1"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	select {
+	case event := <-publisher.Events():
+		if event.Type != BookDeleted {
+			t.Errorf("event type = %s, want %s", event.Type, BookDeleted)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the recorded event is not relayed")
+	}
+
+	cancel()
+	<-done
+}
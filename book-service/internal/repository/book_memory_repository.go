@@ -0,0 +1,391 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"book-service/internal/domain"
+	"book-service/pkg/tenant"
+)
+
+// bookMemoryRepository keeps the books in memory with the semantics of bookMongoDBRepository,
+// the books are kept in insertion order like the natural order of a collection
+type bookMemoryRepository struct {
+	mu    sync.RWMutex
+	books []domain.Book
+}
+
+func NewBookMemoryRepository() domain.BookRepository {
+	return &bookMemoryRepository{}
+}
+
+func (r *bookMemoryRepository) Create(ctx context.Context, book *domain.Book) error {
+	book.ID = primitive.NewObjectID()
+	book.Meta.Create()
+	book.Meta.TenantID = tenant.FromContext(ctx)
+	if book.Branches == nil {
+		book.Branches = make([]domain.BranchStock, 0)
+	}
+
+	var stored domain.Book
+	copyDocument(book, &stored)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.books = append(r.books, stored)
+	return nil
+}
+
+func (r *bookMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Book, error) {
+	books, err := r.filter(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(books, func(i, j int) bool {
+		return newestFirst(books[i].CreatedAt, books[j].CreatedAt, books[i].ID, books[j].ID)
+	})
+	start, end := pageBounds(param, len(books))
+
+	return books[start:end], nil
+}
+
+func (r *bookMemoryRepository) FetchEach(ctx context.Context, param map[string]interface{}, fn func(domain.Book) error) error {
+	books, err := r.filter(ctx, param)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(books, func(i, j int) bool {
+		return strings.Compare(books[i].ID.Hex(), books[j].ID.Hex()) < 0
+	})
+	for _, book := range books {
+		if err = fn(book); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *bookMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	books, err := r.filter(ctx, param)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(books), nil
+}
+
+// filter returns a copy of the books matching the param, like bookMongoDBRepository.filterBy
+func (r *bookMemoryRepository) filter(ctx context.Context, param map[string]interface{}) ([]domain.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	books := make([]domain.Book, 0)
+	for _, book := range r.books {
+		if book.Meta.TenantID != tenantID || book.Meta.DeletedAt != nil {
+			continue
+		}
+
+		matched, err := bookMatches(book, param)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		var found domain.Book
+		copyDocument(book, &found)
+		books = append(books, found)
+	}
+
+	return books, nil
+}
+
+func bookMatches(book domain.Book, param map[string]interface{}) (bool, error) {
+	for key, value := range param {
+		switch key {
+		case "title":
+			matched, err := matchRegex(value.(string), book.Title)
+			if err != nil || !matched {
+				return false, err
+			}
+		case "category_ids":
+			if book.CategoryID == nil || !containsObjectID(value.([]primitive.ObjectID), *book.CategoryID) {
+				return false, nil
+			}
+		case "tags":
+			for _, tag := range value.([]string) {
+				if !containsString(book.Tags, tag) {
+					return false, nil
+				}
+			}
+		case "branch_id":
+			if branchIndex(book, value.(primitive.ObjectID)) < 0 {
+				return false, nil
+			}
+		case "exclude_ids":
+			if containsObjectID(value.([]primitive.ObjectID), book.ID) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// findOne returns the index of the first book of the tenant matching fn, or mongo.ErrNoDocuments.
+// The caller holds the lock.
+func (r *bookMemoryRepository) findOne(ctx context.Context, fn func(domain.Book) (bool, error)) (int, error) {
+	tenantID := tenant.FromContext(ctx)
+	for i, book := range r.books {
+		if book.Meta.TenantID != tenantID {
+			continue
+		}
+
+		matched, err := fn(book)
+		if err != nil {
+			return -1, err
+		}
+		if matched {
+			return i, nil
+		}
+	}
+
+	return -1, mongo.ErrNoDocuments
+}
+
+func (r *bookMemoryRepository) find(ctx context.Context, fn func(domain.Book) (bool, error)) (book domain.Book, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, err := r.findOne(ctx, fn)
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	copyDocument(r.books[i], &book)
+	return book, nil
+}
+
+func (r *bookMemoryRepository) FindByID(ctx context.Context, id string) (domain.Book, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	return r.find(ctx, func(book domain.Book) (bool, error) {
+		return book.ID == objectID, nil
+	})
+}
+
+func (r *bookMemoryRepository) FindByTitle(ctx context.Context, title string) (domain.Book, error) {
+	return r.find(ctx, func(book domain.Book) (bool, error) {
+		return matchRegex(title, book.Title)
+	})
+}
+
+func (r *bookMemoryRepository) FindByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	return r.find(ctx, func(book domain.Book) (bool, error) {
+		return book.ISBN == isbn && book.Meta.DeletedAt == nil, nil
+	})
+}
+
+func (r *bookMemoryRepository) FindByExactTitle(ctx context.Context, title string) (domain.Book, error) {
+	return r.find(ctx, func(book domain.Book) (bool, error) {
+		return strings.EqualFold(book.Title, title) && book.Meta.DeletedAt == nil, nil
+	})
+}
+
+func (r *bookMemoryRepository) UpdateDetails(ctx context.Context, book *domain.Book) error {
+	book.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.findOne(ctx, func(stored domain.Book) (bool, error) {
+		return stored.ID == book.ID, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	stored := &r.books[i]
+	stored.Title = book.Title
+	stored.ISBN = book.ISBN
+	stored.Author = book.Author
+	stored.Meta.UpdatedAt = book.Meta.UpdatedAt
+
+	copyDocument(stored, book)
+	return nil
+}
+
+func (r *bookMemoryRepository) UpdateClassification(ctx context.Context, book *domain.Book) error {
+	book.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.findOne(ctx, func(stored domain.Book) (bool, error) {
+		return stored.ID == book.ID, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	stored := &r.books[i]
+	stored.CategoryID = book.CategoryID
+	stored.Tags = book.Tags
+	stored.Meta.UpdatedAt = book.Meta.UpdatedAt
+
+	copyDocument(stored, book)
+	return nil
+}
+
+// IncrementStock applies the delta under the lock, so a decrease is refused below 0 like the filter of
+// bookMongoDBRepository.IncrementStock
+func (r *bookMemoryRepository) IncrementStock(ctx context.Context, id string, branchID primitive.ObjectID, delta int) (book domain.Book, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.findOne(ctx, func(stored domain.Book) (bool, error) {
+		return stored.ID == objectID, nil
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	stored := &r.books[i]
+	branch := branchIndex(*stored, branchID)
+	switch {
+	case branch >= 0 && (delta >= 0 || stored.Branches[branch].Stock >= -delta):
+		stored.Branches[branch].Stock += delta
+	case branch < 0 && delta >= 0:
+		stored.Branches = append(stored.Branches, domain.BranchStock{BranchID: branchID, Stock: delta})
+	default:
+		return domain.Book{}, mongo.ErrNoDocuments
+	}
+	stored.Stock += delta
+	stored.Meta.UpdatedAt = time.Now()
+
+	copyDocument(stored, &book)
+	return book, nil
+}
+
+func (r *bookMemoryRepository) UpdateCover(ctx context.Context, id string, cover *domain.BookCover) (book domain.Book, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.findOne(ctx, func(stored domain.Book) (bool, error) {
+		return stored.ID == objectID, nil
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	stored := &r.books[i]
+	stored.Cover = nil
+	if cover != nil {
+		stored.Cover = new(domain.BookCover)
+		copyDocument(cover, stored.Cover)
+	}
+	stored.Meta.UpdatedAt = time.Now()
+
+	copyDocument(stored, &book)
+	return book, nil
+}
+
+func (r *bookMemoryRepository) Delete(ctx context.Context, book *domain.Book) error {
+	book.Meta.Delete()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.findOne(ctx, func(stored domain.Book) (bool, error) {
+		return stored.ID == book.ID, nil
+	})
+	if err != nil {
+		// the update of bookMongoDBRepository.Delete matching nothing is not an error either
+		return nil
+	}
+
+	copyDocument(book, &r.books[i])
+	return nil
+}
+
+func (r *bookMemoryRepository) CountByCategory(ctx context.Context) (map[primitive.ObjectID]int, error) {
+	books, err := r.filter(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[primitive.ObjectID]int)
+	for _, book := range books {
+		if book.CategoryID != nil {
+			counts[*book.CategoryID]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *bookMemoryRepository) CountZeroStockByTenant(_ context.Context) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, book := range r.books {
+		if book.Meta.DeletedAt == nil && book.Stock <= 0 {
+			counts[book.Meta.TenantID]++
+		}
+	}
+
+	return counts, nil
+}
+
+func branchIndex(book domain.Book, branchID primitive.ObjectID) int {
+	for i, branch := range book.Branches {
+		if branch.BranchID == branchID {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,7 +13,7 @@ import (
 
 	"book-service/internal/domain"
 	"book-service/internal/domain/constant"
-	"book-service/pkg/mongodb"
+	"book-service/pkg/tenant"
 )
 
 type bookMongoDBRepository struct {
@@ -18,8 +21,7 @@ type bookMongoDBRepository struct {
 	collection *mongo.Collection
 }
 
-func NewBookMongoDBRepository() domain.BookRepository {
-	db := mongodb.GetDatabase()
+func NewBookMongoDBRepository(db *mongo.Database) domain.BookRepository {
 	return &bookMongoDBRepository{
 		db:         db,
 		collection: db.Collection(constant.BookCollection),
@@ -29,6 +31,11 @@ func NewBookMongoDBRepository() domain.BookRepository {
 func (r *bookMongoDBRepository) Create(ctx context.Context, book *domain.Book) error {
 	book.ID = primitive.NewObjectID()
 	book.Meta.Create()
+	book.Meta.TenantID = tenant.FromContext(ctx)
+	if book.Branches == nil {
+		// IncrementStock pushes to the branches, which fails on a null field
+		book.Branches = make([]domain.BranchStock, 0)
+	}
 
 	result, err := r.collection.InsertOne(ctx, book)
 	if err != nil {
@@ -40,7 +47,7 @@ func (r *bookMongoDBRepository) Create(ctx context.Context, book *domain.Book) e
 }
 
 func (r *bookMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Book, error) {
-	cursor, err := r.collection.Find(ctx, r.filterBy(param), r.pageBy(param))
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
 	if err != nil {
 		return nil, err
 	}
@@ -58,8 +65,30 @@ func (r *bookMongoDBRepository) Fetch(ctx context.Context, param map[string]inte
 	return books, nil
 }
 
+// FetchEach calls fn for every book matching the filter without paging, the books are never loaded at once
+func (r *bookMongoDBRepository) FetchEach(ctx context.Context, param map[string]interface{}, fn func(domain.Book) error) error {
+	findOptions := options.Find().SetSort(bson.D{{"_id", 1}})
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var book domain.Book
+		if err = cursor.Decode(&book); err != nil {
+			return err
+		}
+		if err = fn(book); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
 func (r *bookMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
-	count, err := r.collection.CountDocuments(ctx, r.filterBy(param))
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
 	if err != nil {
 		return 0, err
 	}
@@ -67,8 +96,8 @@ func (r *bookMongoDBRepository) Count(ctx context.Context, param map[string]inte
 	return int(count), nil
 }
 
-func (*bookMongoDBRepository) filterBy(param map[string]interface{}) bson.D {
-	filter := bson.D{}
+func (*bookMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
 
 	filter = append(filter, bson.E{"meta.deleted_at", nil})
 
@@ -76,6 +105,14 @@ func (*bookMongoDBRepository) filterBy(param map[string]interface{}) bson.D {
 		switch key {
 		case "title":
 			filter = append(filter, bson.E{key, primitive.Regex{Pattern: (value).(string), Options: "i"}})
+		case "category_ids":
+			filter = append(filter, bson.E{"category_id", bson.D{{"$in", value}}})
+		case "tags":
+			filter = append(filter, bson.E{key, bson.D{{"$all", value}}})
+		case "branch_id":
+			filter = append(filter, bson.E{"branches.branch_id", value})
+		case "exclude_ids":
+			filter = append(filter, bson.E{"_id", bson.D{{"$nin", value}}})
 		}
 	}
 
@@ -103,7 +140,7 @@ func (*bookMongoDBRepository) pageBy(param map[string]interface{}) *options.Find
 
 func (r *bookMongoDBRepository) FindOne(ctx context.Context, filter bson.D) (book domain.Book, err error) {
 	var decoded bson.M
-	err = r.collection.FindOne(ctx, filter).
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
 		Decode(&decoded)
 	if err != nil {
 		return domain.Book{}, err
@@ -129,45 +166,145 @@ func (r *bookMongoDBRepository) FindByTitle(ctx context.Context, title string) (
 	return r.FindOne(ctx, filter)
 }
 
-func (r *bookMongoDBRepository) Update(ctx context.Context, book *domain.Book) error {
+func (r *bookMongoDBRepository) FindByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	filter := bson.D{{"isbn", isbn}, {"meta.deleted_at", nil}}
+	return r.FindOne(ctx, filter)
+}
+
+// FindByExactTitle matches the whole title case-insensitively, unlike FindByTitle which matches a part of it
+func (r *bookMongoDBRepository) FindByExactTitle(ctx context.Context, title string) (domain.Book, error) {
+	pattern := "^" + regexp.QuoteMeta(title) + "$"
+	filter := bson.D{{"title", primitive.Regex{Pattern: pattern, Options: "i"}}, {"meta.deleted_at", nil}}
+	return r.FindOne(ctx, filter)
+}
+
+// UpdateDetails only sets the title, the ISBN and the author, so it never overwrites a concurrent stock change.
+// The book is updated with the stored document.
+func (r *bookMongoDBRepository) UpdateDetails(ctx context.Context, book *domain.Book) error {
 	book.Meta.Update()
 
 	filter := bson.D{{"_id", book.ID}}
-	update := bson.D{{"$set", book}}
+	update := bson.D{{"$set", bson.D{
+		{"title", book.Title},
+		{"isbn", book.ISBN},
+		{"author", book.Author},
+		{"meta.updated_at", book.Meta.UpdatedAt},
+	}}}
+
+	updated, err := r.findOneAndUpdate(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	*book = updated
+	return nil
+}
+
+// UpdateClassification only sets the category and the tags, so it never overwrites a concurrent stock change.
+// The book is updated with the stored document.
+func (r *bookMongoDBRepository) UpdateClassification(ctx context.Context, book *domain.Book) error {
+	book.Meta.Update()
+
+	filter := bson.D{{"_id", book.ID}}
+	update := bson.D{{"$set", bson.D{
+		{"category_id", book.CategoryID},
+		{"tags", book.Tags},
+		{"meta.updated_at", book.Meta.UpdatedAt},
+	}}}
+
+	updated, err := r.findOneAndUpdate(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	*book = updated
+	return nil
+}
+
+// IncrementStock atomically adds delta to the book stock at the branch and to the total stock.
+// A decrease is only applied when the branch stock is enough, otherwise mongo.ErrNoDocuments is returned.
+func (r *bookMongoDBRepository) IncrementStock(ctx context.Context, id string, branchID primitive.ObjectID, delta int) (domain.Book, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	branchFilter := bson.D{{"branch_id", branchID}}
+	if delta < 0 {
+		branchFilter = append(branchFilter, bson.E{"stock", bson.D{{"$gte", -delta}}})
+	}
+	filter := bson.D{{"_id", objectID}, {"branches", bson.D{{"$elemMatch", branchFilter}}}}
+	update := bson.D{
+		{"$inc", bson.D{{"stock", delta}, {"branches.$.stock", delta}}},
+		{"$set", bson.D{{"meta.updated_at", time.Now()}}},
+	}
+
+	book, err := r.findOneAndUpdate(ctx, filter, update)
+	if delta < 0 || !errors.Is(err, mongo.ErrNoDocuments) {
+		return book, err
+	}
+
+	// the first copies at the branch, the filter keeps a concurrent push from adding the branch twice
+	filter = bson.D{{"_id", objectID}, {"branches.branch_id", bson.D{{"$ne", branchID}}}}
+	update = bson.D{
+		{"$inc", bson.D{{"stock", delta}}},
+		{"$push", bson.D{{"branches", domain.BranchStock{BranchID: branchID, Stock: delta}}}},
+		{"$set", bson.D{{"meta.updated_at", time.Now()}}},
+	}
+
+	book, err = r.findOneAndUpdate(ctx, filter, update)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return book, err
+	}
+
+	// the branch was pushed concurrently, or the book does not exist
+	filter = bson.D{{"_id", objectID}, {"branches.branch_id", branchID}}
+	update = bson.D{
+		{"$inc", bson.D{{"stock", delta}, {"branches.$.stock", delta}}},
+		{"$set", bson.D{{"meta.updated_at", time.Now()}}},
+	}
+	return r.findOneAndUpdate(ctx, filter, update)
+}
+
+func (r *bookMongoDBRepository) findOneAndUpdate(ctx context.Context, filter, update bson.D) (book domain.Book, err error) {
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
 	var decode bson.M
-	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).
+	err = r.collection.FindOneAndUpdate(ctx, byTenant(ctx, filter), update, opts).
 		Decode(&decode)
 	if err != nil {
-		return err
+		return domain.Book{}, err
 	}
 
 	bsonBytes, _ := bson.Marshal(decode)
-	err = bson.Unmarshal(bsonBytes, book)
-	return nil
+	err = bson.Unmarshal(bsonBytes, &book)
+	return
 }
 
-func (r *bookMongoDBRepository) UpdateStock(ctx context.Context, book *domain.Book, newStock int) error {
-	book.Meta.Update()
+// UpdateCover only sets the cover, so it never overwrites a concurrent stock change
+func (r *bookMongoDBRepository) UpdateCover(ctx context.Context, id string, cover *domain.BookCover) (book domain.Book, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
 
-	filter := bson.D{{"_id", book.ID}, {"stock", book.Stock}}
+	filter := bson.D{{"_id", objectID}}
 	update := bson.D{{"$set", bson.D{
-		{"stock", newStock},
-		{"meta.updated_at", book.UpdatedAt},
+		{"cover", cover},
+		{"meta.updated_at", time.Now()},
 	}}}
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
 	var decode bson.M
-	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).
+	err = r.collection.FindOneAndUpdate(ctx, byTenant(ctx, filter), update, opts).
 		Decode(&decode)
 	if err != nil {
-		return err
+		return domain.Book{}, err
 	}
 
 	bsonBytes, _ := bson.Marshal(decode)
-	err = bson.Unmarshal(bsonBytes, book)
-	return nil
+	err = bson.Unmarshal(bsonBytes, &book)
+	return
 }
 
 func (r *bookMongoDBRepository) Delete(ctx context.Context, book *domain.Book) error {
@@ -176,10 +313,64 @@ func (r *bookMongoDBRepository) Delete(ctx context.Context, book *domain.Book) e
 	filter := bson.D{{"_id", book.ID}}
 	update := bson.D{{"$set", book}}
 
-	_, err := r.collection.UpdateOne(ctx, filter, update)
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, filter), update)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (r *bookMongoDBRepository) CountByCategory(ctx context.Context) (map[primitive.ObjectID]int, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", byTenant(ctx, bson.D{{"meta.deleted_at", nil}, {"category_id", bson.D{{"$ne", nil}}}})}},
+		{{"$group", bson.D{{"_id", "$category_id"}, {"count", bson.D{{"$sum", 1}}}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := make(map[primitive.ObjectID]int)
+	for cursor.Next(ctx) {
+		var result struct {
+			CategoryID primitive.ObjectID `bson:"_id"`
+			Count      int                `bson:"count"`
+		}
+		if err = cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+		counts[result.CategoryID] = result.Count
+	}
+
+	return counts, nil
+}
+
+func (r *bookMongoDBRepository) CountZeroStockByTenant(ctx context.Context) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"meta.deleted_at", nil}, {"stock", bson.D{{"$lte", 0}}}}}},
+		{{"$group", bson.D{{"_id", "$meta.tenant_id"}, {"count", bson.D{{"$sum", 1}}}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := make(map[string]int)
+	for cursor.Next(ctx) {
+		var result struct {
+			TenantID string `bson:"_id"`
+			Count    int    `bson:"count"`
+		}
+		if err = cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+		counts[result.TenantID] = result.Count
+	}
+
+	return counts, nil
+}
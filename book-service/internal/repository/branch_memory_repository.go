@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+// branchMemoryRepository keeps the unique branch code of a tenant like constant.BranchTenantCodeIndex
+type branchMemoryRepository struct {
+	mu       sync.RWMutex
+	branches []domain.Branch
+}
+
+func NewBranchMemoryRepository() domain.BranchRepository {
+	return &branchMemoryRepository{}
+}
+
+func (r *branchMemoryRepository) Create(ctx context.Context, branch *domain.Branch) error {
+	branch.ID = primitive.NewObjectID()
+	branch.Meta.Create()
+	branch.Meta.TenantID = tenant.FromContext(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.codeTaken(*branch) {
+		return duplicateKeyError(constant.BranchCollection, constant.BranchTenantCodeIndex)
+	}
+
+	var stored domain.Branch
+	copyDocument(branch, &stored)
+	r.branches = append(r.branches, stored)
+	return nil
+}
+
+// codeTaken checks the unique index, the deleted branches included. The caller holds the lock.
+func (r *branchMemoryRepository) codeTaken(branch domain.Branch) bool {
+	for _, stored := range r.branches {
+		if stored.ID != branch.ID && stored.Meta.TenantID == branch.Meta.TenantID && stored.Code == branch.Code {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *branchMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Branch, error) {
+	branches, err := r.filter(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branches[i].Code < branches[j].Code
+	})
+	start, end := pageBounds(param, len(branches))
+
+	return branches[start:end], nil
+}
+
+func (r *branchMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	branches, err := r.filter(ctx, param)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(branches), nil
+}
+
+// filter returns a copy of the branches matching the param, like branchMongoDBRepository.filterBy
+func (r *branchMemoryRepository) filter(ctx context.Context, param map[string]interface{}) ([]domain.Branch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	branches := make([]domain.Branch, 0)
+	for _, branch := range r.branches {
+		if branch.Meta.TenantID != tenantID || branch.Meta.DeletedAt != nil {
+			continue
+		}
+
+		matched, err := branchMatches(branch, param)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		var found domain.Branch
+		copyDocument(branch, &found)
+		branches = append(branches, found)
+	}
+
+	return branches, nil
+}
+
+func branchMatches(branch domain.Branch, param map[string]interface{}) (bool, error) {
+	for key, value := range param {
+		switch key {
+		case "name":
+			matched, err := matchRegex(value.(string), branch.Name)
+			if err != nil || !matched {
+				return false, err
+			}
+		case "code":
+			if branch.Code != value.(string) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func (r *branchMemoryRepository) find(ctx context.Context, fn func(domain.Branch) bool) (branch domain.Branch, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.branches {
+		if stored.Meta.TenantID == tenantID && stored.Meta.DeletedAt == nil && fn(stored) {
+			copyDocument(stored, &branch)
+			return branch, nil
+		}
+	}
+
+	return domain.Branch{}, mongo.ErrNoDocuments
+}
+
+func (r *branchMemoryRepository) FindByID(ctx context.Context, id string) (domain.Branch, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Branch{}, err
+	}
+
+	return r.find(ctx, func(branch domain.Branch) bool {
+		return branch.ID == objectID
+	})
+}
+
+func (r *branchMemoryRepository) FindDefault(ctx context.Context) (domain.Branch, error) {
+	return r.find(ctx, func(branch domain.Branch) bool {
+		return branch.Default
+	})
+}
+
+func (r *branchMemoryRepository) Update(ctx context.Context, branch *domain.Branch) error {
+	branch.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.branches {
+		if stored.ID != branch.ID || stored.Meta.TenantID != tenantID {
+			continue
+		}
+		if r.codeTaken(*branch) {
+			return duplicateKeyError(constant.BranchCollection, constant.BranchTenantCodeIndex)
+		}
+
+		copyDocument(branch, &r.branches[i])
+		return nil
+	}
+
+	return nil
+}
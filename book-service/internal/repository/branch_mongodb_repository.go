@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+type branchMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewBranchMongoDBRepository(db *mongo.Database) domain.BranchRepository {
+	return &branchMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.BranchCollection),
+	}
+}
+
+func (r *branchMongoDBRepository) Create(ctx context.Context, branch *domain.Branch) error {
+	branch.ID = primitive.NewObjectID()
+	branch.Meta.Create()
+	branch.Meta.TenantID = tenant.FromContext(ctx)
+
+	result, err := r.collection.InsertOne(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	branch.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *branchMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Branch, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	branches := make([]domain.Branch, 0)
+	for cursor.Next(ctx) {
+		var branch domain.Branch
+		if err = cursor.Decode(&branch); err != nil {
+			return nil, err
+		}
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
+
+func (r *branchMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*branchMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
+
+	filter = append(filter, bson.E{"meta.deleted_at", nil})
+
+	for key, value := range param {
+		switch key {
+		case "name":
+			filter = append(filter, bson.E{key, primitive.Regex{Pattern: (value).(string), Options: "i"}})
+		case "code":
+			filter = append(filter, bson.E{key, value})
+		}
+	}
+
+	return filter
+}
+
+func (*branchMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.D{{"code", 1}})
+
+	return findOptions
+}
+
+func (r *branchMongoDBRepository) FindByID(ctx context.Context, id string) (branch domain.Branch, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Branch{}, err
+	}
+
+	filter := bson.D{{"_id", objectID}, {"meta.deleted_at", nil}}
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
+		Decode(&branch)
+	return
+}
+
+func (r *branchMongoDBRepository) FindDefault(ctx context.Context) (branch domain.Branch, err error) {
+	filter := bson.D{{"default", true}, {"meta.deleted_at", nil}}
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
+		Decode(&branch)
+	return
+}
+
+func (r *branchMongoDBRepository) Update(ctx context.Context, branch *domain.Branch) error {
+	branch.Meta.Update()
+
+	filter := bson.D{{"_id", branch.ID}}
+	update := bson.D{{"$set", branch}}
+
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, filter), update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"book-service/internal/domain"
+	"book-service/pkg/tenant"
+)
+
+type categoryMemoryRepository struct {
+	mu         sync.RWMutex
+	categories []domain.Category
+}
+
+func NewCategoryMemoryRepository() domain.CategoryRepository {
+	return &categoryMemoryRepository{}
+}
+
+func (r *categoryMemoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	category.ID = primitive.NewObjectID()
+	category.Meta.Create()
+	category.Meta.TenantID = tenant.FromContext(ctx)
+
+	var stored domain.Category
+	copyDocument(category, &stored)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.categories = append(r.categories, stored)
+	return nil
+}
+
+func (r *categoryMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Category, error) {
+	categories, err := r.filter(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].Code != categories[j].Code {
+			return categories[i].Code < categories[j].Code
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	start, end := pageBounds(param, len(categories))
+
+	return categories[start:end], nil
+}
+
+func (r *categoryMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	categories, err := r.filter(ctx, param)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(categories), nil
+}
+
+// filter returns a copy of the categories matching the param, like categoryMongoDBRepository.filterBy
+func (r *categoryMemoryRepository) filter(ctx context.Context, param map[string]interface{}) ([]domain.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	categories := make([]domain.Category, 0)
+	for _, category := range r.categories {
+		if category.Meta.TenantID != tenantID || category.Meta.DeletedAt != nil {
+			continue
+		}
+
+		matched, err := categoryMatches(category, param)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		var found domain.Category
+		copyDocument(category, &found)
+		categories = append(categories, found)
+	}
+
+	return categories, nil
+}
+
+func categoryMatches(category domain.Category, param map[string]interface{}) (bool, error) {
+	for key, value := range param {
+		switch key {
+		case "name":
+			matched, err := matchRegex(value.(string), category.Name)
+			if err != nil || !matched {
+				return false, err
+			}
+		case "code":
+			if category.Code != value.(string) {
+				return false, nil
+			}
+		case "parent_id":
+			// an empty parent ID fetches the root categories
+			objectID, err := primitive.ObjectIDFromHex(value.(string))
+			if err != nil {
+				if category.ParentID != nil {
+					return false, nil
+				}
+				continue
+			}
+			if category.ParentID == nil || *category.ParentID != objectID {
+				return false, nil
+			}
+		case "ancestor_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if !containsObjectID(category.Ancestors, objectID) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func (r *categoryMemoryRepository) FindByID(ctx context.Context, id string) (category domain.Category, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.categories {
+		if stored.ID == objectID && stored.Meta.TenantID == tenantID && stored.Meta.DeletedAt == nil {
+			copyDocument(stored, &category)
+			return category, nil
+		}
+	}
+
+	return domain.Category{}, mongo.ErrNoDocuments
+}
+
+func (r *categoryMemoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	category.Meta.Update()
+	r.save(ctx, category)
+	return nil
+}
+
+func (r *categoryMemoryRepository) Delete(ctx context.Context, category *domain.Category) error {
+	category.Meta.Delete()
+	r.save(ctx, category)
+	return nil
+}
+
+// save replaces the stored category, a missing one is not an error like the update of categoryMongoDBRepository
+func (r *categoryMemoryRepository) save(ctx context.Context, category *domain.Category) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.categories {
+		if stored.ID == category.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(category, &r.categories[i])
+			return
+		}
+	}
+}
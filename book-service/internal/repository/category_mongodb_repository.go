@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+type categoryMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewCategoryMongoDBRepository(db *mongo.Database) domain.CategoryRepository {
+	return &categoryMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.CategoryCollection),
+	}
+}
+
+func (r *categoryMongoDBRepository) Create(ctx context.Context, category *domain.Category) error {
+	category.ID = primitive.NewObjectID()
+	category.Meta.Create()
+	category.Meta.TenantID = tenant.FromContext(ctx)
+
+	result, err := r.collection.InsertOne(ctx, category)
+	if err != nil {
+		return err
+	}
+
+	category.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *categoryMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Category, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	categories := make([]domain.Category, 0)
+	for cursor.Next(ctx) {
+		var category domain.Category
+		if err = cursor.Decode(&category); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+func (r *categoryMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*categoryMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
+
+	filter = append(filter, bson.E{"meta.deleted_at", nil})
+
+	for key, value := range param {
+		switch key {
+		case "name":
+			filter = append(filter, bson.E{key, primitive.Regex{Pattern: (value).(string), Options: "i"}})
+		case "code":
+			filter = append(filter, bson.E{key, value})
+		case "parent_id":
+			// an empty parent ID fetches the root categories
+			objectID, err := primitive.ObjectIDFromHex(value.(string))
+			if err != nil {
+				filter = append(filter, bson.E{key, nil})
+				continue
+			}
+			filter = append(filter, bson.E{key, objectID})
+		case "ancestor_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			filter = append(filter, bson.E{"ancestors", objectID})
+		}
+	}
+
+	return filter
+}
+
+func (*categoryMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.D{{"code", 1}, {"name", 1}})
+
+	return findOptions
+}
+
+func (r *categoryMongoDBRepository) FindByID(ctx context.Context, id string) (category domain.Category, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	filter := bson.D{{"_id", objectID}, {"meta.deleted_at", nil}}
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
+		Decode(&category)
+	return
+}
+
+func (r *categoryMongoDBRepository) Update(ctx context.Context, category *domain.Category) error {
+	category.Meta.Update()
+
+	filter := bson.D{{"_id", category.ID}}
+	update := bson.D{{"$set", category}}
+
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, filter), update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *categoryMongoDBRepository) Delete(ctx context.Context, category *domain.Category) error {
+	category.Meta.Delete()
+
+	filter := bson.D{{"_id", category.ID}}
+	update := bson.D{{"$set", category}}
+
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, filter), update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
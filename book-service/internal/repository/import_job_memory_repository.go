@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"book-service/internal/domain"
+	"book-service/pkg/tenant"
+)
+
+type importJobMemoryRepository struct {
+	mu   sync.RWMutex
+	jobs []domain.ImportJob
+}
+
+func NewImportJobMemoryRepository() domain.ImportJobRepository {
+	return &importJobMemoryRepository{}
+}
+
+func (r *importJobMemoryRepository) Create(ctx context.Context, job *domain.ImportJob) error {
+	job.ID = primitive.NewObjectID()
+	job.Meta.Create()
+	job.Meta.TenantID = tenant.FromContext(ctx)
+
+	var stored domain.ImportJob
+	copyDocument(job, &stored)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs = append(r.jobs, stored)
+	return nil
+}
+
+func (r *importJobMemoryRepository) FindByID(ctx context.Context, id string) (job domain.ImportJob, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.jobs {
+		if stored.ID == objectID && stored.Meta.TenantID == tenantID {
+			copyDocument(stored, &job)
+			return job, nil
+		}
+	}
+
+	return domain.ImportJob{}, mongo.ErrNoDocuments
+}
+
+// Update replaces the stored job, a missing one is not an error like the update of importJobMongoDBRepository
+func (r *importJobMemoryRepository) Update(ctx context.Context, job *domain.ImportJob) error {
+	job.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.jobs {
+		if stored.ID == job.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(job, &r.jobs[i])
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+type importJobMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewImportJobMongoDBRepository(db *mongo.Database) domain.ImportJobRepository {
+	return &importJobMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.ImportJobCollection),
+	}
+}
+
+func (r *importJobMongoDBRepository) Create(ctx context.Context, job *domain.ImportJob) error {
+	job.ID = primitive.NewObjectID()
+	job.Meta.Create()
+	job.Meta.TenantID = tenant.FromContext(ctx)
+
+	result, err := r.collection.InsertOne(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	job.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *importJobMongoDBRepository) FindByID(ctx context.Context, id string) (job domain.ImportJob, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+
+	filter := bson.D{{"_id", objectID}}
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
+		Decode(&job)
+	return
+}
+
+func (r *importJobMongoDBRepository) Update(ctx context.Context, job *domain.ImportJob) error {
+	job.Meta.Update()
+
+	filter := bson.D{{"_id", job.ID}}
+	update := bson.D{{"$set", job}}
+
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, filter), update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"book-service/internal/domain"
+	"book-service/pkg/tenant"
+)
+
+type stockMovementMemoryRepository struct {
+	mu        sync.RWMutex
+	movements []domain.StockMovement
+}
+
+func NewStockMovementMemoryRepository() domain.StockMovementRepository {
+	return &stockMovementMemoryRepository{}
+}
+
+func (r *stockMovementMemoryRepository) Create(ctx context.Context, movement *domain.StockMovement) error {
+	movement.ID = primitive.NewObjectID()
+	movement.Meta.Create()
+	movement.Meta.TenantID = tenant.FromContext(ctx)
+
+	var stored domain.StockMovement
+	copyDocument(movement, &stored)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.movements = append(r.movements, stored)
+	return nil
+}
+
+func (r *stockMovementMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.StockMovement, error) {
+	movements := r.filter(ctx, param)
+
+	sort.SliceStable(movements, func(i, j int) bool {
+		return newestFirst(movements[i].CreatedAt, movements[j].CreatedAt, movements[i].ID, movements[j].ID)
+	})
+	start, end := pageBounds(param, len(movements))
+
+	return movements[start:end], nil
+}
+
+func (r *stockMovementMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(ctx, param)), nil
+}
+
+// filter returns a copy of the movements matching the param, like stockMovementMongoDBRepository.filterBy
+func (r *stockMovementMemoryRepository) filter(ctx context.Context, param map[string]interface{}) []domain.StockMovement {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	movements := make([]domain.StockMovement, 0)
+	for _, movement := range r.movements {
+		if movement.Meta.TenantID != tenantID || !stockMovementMatches(movement, param) {
+			continue
+		}
+
+		var found domain.StockMovement
+		copyDocument(movement, &found)
+		movements = append(movements, found)
+	}
+
+	return movements
+}
+
+func stockMovementMatches(movement domain.StockMovement, param map[string]interface{}) bool {
+	for key, value := range param {
+		switch key {
+		case "reason":
+			if string(movement.Reason) != fmt.Sprint(value) {
+				return false
+			}
+		case "book_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if movement.BookID != objectID {
+				return false
+			}
+		case "branch_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if movement.BranchID != objectID {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (r *stockMovementMemoryRepository) SumDelta(ctx context.Context, bookIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	stocks := make(map[primitive.ObjectID]int)
+	for _, movement := range r.movements {
+		if movement.Meta.TenantID == tenantID && containsObjectID(bookIDs, movement.BookID) {
+			stocks[movement.BookID] += movement.Delta
+		}
+	}
+
+	return stocks, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+type stockMovementMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewStockMovementMongoDBRepository(db *mongo.Database) domain.StockMovementRepository {
+	return &stockMovementMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.StockMovementCollection),
+	}
+}
+
+func (r *stockMovementMongoDBRepository) Create(ctx context.Context, movement *domain.StockMovement) error {
+	movement.ID = primitive.NewObjectID()
+	movement.Meta.Create()
+	movement.Meta.TenantID = tenant.FromContext(ctx)
+
+	result, err := r.collection.InsertOne(ctx, movement)
+	if err != nil {
+		return err
+	}
+
+	movement.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *stockMovementMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.StockMovement, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	movements := make([]domain.StockMovement, 0)
+	for cursor.Next(ctx) {
+		var movement domain.StockMovement
+		if err = cursor.Decode(&movement); err != nil {
+			return nil, err
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+func (r *stockMovementMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*stockMovementMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
+
+	for key, value := range param {
+		switch key {
+		case "reason":
+			filter = append(filter, bson.E{key, value})
+		case "book_id", "branch_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			filter = append(filter, bson.E{key, objectID})
+		}
+	}
+
+	return filter
+}
+
+func (*stockMovementMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.D{{"meta.created_at", -1}, {"_id", -1}})
+
+	return findOptions
+}
+
+// SumDelta recomputes the stock of each book from its ledger entries
+func (r *stockMovementMongoDBRepository) SumDelta(ctx context.Context, bookIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", byTenant(ctx, bson.D{{"book_id", bson.D{{"$in", bookIDs}}}})}},
+		{{"$group", bson.D{{"_id", "$book_id"}, {"stock", bson.D{{"$sum", "$delta"}}}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stocks := make(map[primitive.ObjectID]int)
+	for cursor.Next(ctx) {
+		var result struct {
+			BookID primitive.ObjectID `bson:"_id"`
+			Stock  int                `bson:"stock"`
+		}
+		if err = cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+		stocks[result.BookID] = result.Stock
+	}
+
+	return stocks, nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"book-service/pkg/tenant"
+)
+
+// byTenant scopes the filter to the tenant of the request, every query of the repositories goes through it
+func byTenant(ctx context.Context, filter bson.D) bson.D {
+	return append(bson.D{{"meta.tenant_id", tenant.FromContext(ctx)}}, filter...)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+type transferMemoryRepository struct {
+	mu        sync.RWMutex
+	transfers []domain.Transfer
+}
+
+func NewTransferMemoryRepository() domain.TransferRepository {
+	return &transferMemoryRepository{}
+}
+
+func (r *transferMemoryRepository) Create(ctx context.Context, transfer *domain.Transfer) error {
+	transfer.ID = primitive.NewObjectID()
+	transfer.Meta.Create()
+	transfer.Meta.TenantID = tenant.FromContext(ctx)
+
+	var stored domain.Transfer
+	copyDocument(transfer, &stored)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.transfers = append(r.transfers, stored)
+	return nil
+}
+
+func (r *transferMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Transfer, error) {
+	transfers := r.filter(ctx, param)
+
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return newestFirst(transfers[i].CreatedAt, transfers[j].CreatedAt, transfers[i].ID, transfers[j].ID)
+	})
+	start, end := pageBounds(param, len(transfers))
+
+	return transfers[start:end], nil
+}
+
+func (r *transferMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(ctx, param)), nil
+}
+
+// filter returns a copy of the transfers matching the param, like transferMongoDBRepository.filterBy
+func (r *transferMemoryRepository) filter(ctx context.Context, param map[string]interface{}) []domain.Transfer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	transfers := make([]domain.Transfer, 0)
+	for _, transfer := range r.transfers {
+		if transfer.Meta.TenantID != tenantID || !transferMatches(transfer, param) {
+			continue
+		}
+
+		var found domain.Transfer
+		copyDocument(transfer, &found)
+		transfers = append(transfers, found)
+	}
+
+	return transfers
+}
+
+func transferMatches(transfer domain.Transfer, param map[string]interface{}) bool {
+	for key, value := range param {
+		switch key {
+		case "status":
+			if string(transfer.Status) != fmt.Sprint(value) {
+				return false
+			}
+		case "book_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if transfer.BookID != objectID {
+				return false
+			}
+		case "branch_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if transfer.FromBranchID != objectID && transfer.ToBranchID != objectID {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (r *transferMemoryRepository) FindByID(ctx context.Context, id string) (transfer domain.Transfer, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.transfers {
+		if stored.ID == objectID && stored.Meta.TenantID == tenantID {
+			copyDocument(stored, &transfer)
+			return transfer, nil
+		}
+	}
+
+	return domain.Transfer{}, mongo.ErrNoDocuments
+}
+
+// UpdateStatus saves the transfer only while it still has the from status, otherwise mongo.ErrNoDocuments is returned
+func (r *transferMemoryRepository) UpdateStatus(ctx context.Context, transfer *domain.Transfer, from constant.TransferStatus) error {
+	transfer.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.transfers {
+		if stored.ID == transfer.ID && stored.Meta.TenantID == tenantID && stored.Status == from {
+			copyDocument(transfer, &r.transfers[i])
+			return nil
+		}
+	}
+
+	return mongo.ErrNoDocuments
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/tenant"
+)
+
+type transferMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewTransferMongoDBRepository(db *mongo.Database) domain.TransferRepository {
+	return &transferMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.TransferCollection),
+	}
+}
+
+func (r *transferMongoDBRepository) Create(ctx context.Context, transfer *domain.Transfer) error {
+	transfer.ID = primitive.NewObjectID()
+	transfer.Meta.Create()
+	transfer.Meta.TenantID = tenant.FromContext(ctx)
+
+	result, err := r.collection.InsertOne(ctx, transfer)
+	if err != nil {
+		return err
+	}
+
+	transfer.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *transferMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Transfer, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	transfers := make([]domain.Transfer, 0)
+	for cursor.Next(ctx) {
+		var transfer domain.Transfer
+		if err = cursor.Decode(&transfer); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
+
+func (r *transferMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*transferMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
+
+	for key, value := range param {
+		switch key {
+		case "status":
+			filter = append(filter, bson.E{key, value})
+		case "book_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			filter = append(filter, bson.E{key, objectID})
+		case "branch_id":
+			// a branch sees the transfers it ships and the ones it receives
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			filter = append(filter, bson.E{"$or", bson.A{
+				bson.D{{"from_branch_id", objectID}},
+				bson.D{{"to_branch_id", objectID}},
+			}})
+		}
+	}
+
+	return filter
+}
+
+func (*transferMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.D{{"meta.created_at", -1}, {"_id", -1}})
+
+	return findOptions
+}
+
+func (r *transferMongoDBRepository) FindByID(ctx context.Context, id string) (transfer domain.Transfer, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+
+	filter := bson.D{{"_id", objectID}}
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
+		Decode(&transfer)
+	return
+}
+
+// UpdateStatus saves the transfer only while it still has the from status,
+// so a transfer is never shipped or received twice. Otherwise mongo.ErrNoDocuments is returned.
+func (r *transferMongoDBRepository) UpdateStatus(ctx context.Context, transfer *domain.Transfer, from constant.TransferStatus) error {
+	transfer.Meta.Update()
+
+	filter := bson.D{{"_id", transfer.ID}, {"status", from}}
+	update := bson.D{{"$set", transfer}}
+
+	result, err := r.collection.UpdateOne(ctx, byTenant(ctx, filter), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"book-service/internal/domain"
+	"book-service/pkg/logger"
+)
+
+const collectTimeout = 5 * time.Second
+
+// BookCollector exports the book gauges of every tenant, they are counted from the database on each scrape
+type BookCollector struct {
+	bookRepository domain.BookRepository
+	zeroStock      *prometheus.Desc
+}
+
+func NewBookCollector(bookRepository domain.BookRepository) *BookCollector {
+	return &BookCollector{
+		bookRepository: bookRepository,
+		zeroStock: prometheus.NewDesc("library_zero_stock_books",
+			"Number of books without any copy left to lend, by tenant.", []string{"tenant"}, nil),
+	}
+}
+
+func (c *BookCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.zeroStock
+}
+
+func (c *BookCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.bookRepository.CountZeroStockByTenant(ctx)
+	if err != nil {
+		// the other metrics are still worth scraping
+		logger.Ctx(ctx).Error().Err(err).Msg("Error collecting book metrics")
+		return
+	}
+
+	for tenantID, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.zeroStock, prometheus.GaugeValue, float64(count), tenantID)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"book-service/internal/domain"
+	"book-service/internal/event"
+	"book-service/pkg/proto"
+)
+
+func (s *BookGRPCService) recordBookCreated(ctx context.Context, book domain.Book) error {
+	var categoryID string
+	if book.CategoryID != nil {
+		categoryID = book.CategoryID.Hex()
+	}
+
+	return event.Record(ctx, s.outboxRepository, event.BookCreated, book.ID.Hex(), &proto.BookCreated{
+		BookId:     book.ID.Hex(),
+		Title:      book.Title,
+		Isbn:       book.ISBN,
+		Author:     book.Author,
+		CategoryId: categoryID,
+	})
+}
+
+func (s *BookGRPCService) recordBookDeleted(ctx context.Context, book domain.Book) error {
+	return event.Record(ctx, s.outboxRepository, event.BookDeleted, book.ID.Hex(), &proto.BookDeleted{
+		BookId: book.ID.Hex(),
+		Title:  book.Title,
+	})
+}
+
+func (s *BookGRPCService) recordStockChanged(ctx context.Context, movement domain.StockMovement) error {
+	return event.Record(ctx, s.outboxRepository, event.StockChanged, movement.BookID.Hex(), &proto.StockChanged{
+		BookId:     movement.BookID.Hex(),
+		BranchId:   movement.BranchID.Hex(),
+		Delta:      int32(movement.Delta),
+		Stock:      int32(movement.Stock),
+		Reason:     string(movement.Reason),
+		ActorId:    movement.ActorID,
+		LendingId:  movement.LendingID,
+		TransferId: movement.TransferID,
+	})
+}
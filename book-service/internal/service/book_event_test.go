@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	protobuf "google.golang.org/protobuf/proto"
+
+	"book-service/internal/event"
+	"book-service/pkg/proto"
+)
+
+func TestBookGRPCService_RecordsEvents(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	book, err := s.CreateBook(ctx, &proto.CreateBookRequest{Title: "Dune"})
+	if err != nil {
+		t.Fatalf("CreateBook() error = %v", err)
+	}
+	if _, err = s.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{Id: book.Id, StockChange: 2, Reason: "purchase"}); err != nil {
+		t.Fatalf("UpdateBookStock() error = %v", err)
+	}
+	if _, err = s.DeleteBook(ctx, &proto.DeleteBookRequest{Id: book.Id}); err != nil {
+		t.Fatalf("DeleteBook() error = %v", err)
+	}
+
+	outboxEvents, err := s.outboxRepository.FetchPending(ctx, 10)
+	if err != nil {
+		t.Fatalf("FetchPending() error = %v", err)
+	}
+
+	wantTypes := []string{event.BookCreated, event.StockChanged, event.BookDeleted}
+	if len(outboxEvents) != len(wantTypes) {
+		t.Fatalf("events = %d, want %v", len(outboxEvents), wantTypes)
+	}
+	for i, outboxEvent := range outboxEvents {
+		var recorded proto.Event
+		if err = protobuf.Unmarshal(outboxEvent.Payload, &recorded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if recorded.Type != wantTypes[i] || recorded.AggregateId != book.Id {
+			t.Errorf("event[%d] = %s of %s, want %s of %s", i, recorded.Type, recorded.AggregateId, wantTypes[i], book.Id)
+		}
+
+		if recorded.Type == event.StockChanged {
+			var stockChanged proto.StockChanged
+			if err = recorded.Payload.UnmarshalTo(&stockChanged); err != nil {
+				t.Fatalf("UnmarshalTo() error = %v", err)
+			}
+			if stockChanged.Delta != 2 || stockChanged.Stock != 2 || stockChanged.Reason != "purchase" || stockChanged.BranchId == "" {
+				t.Errorf("stock changed = %v, want a purchase of 2 at the default branch", &stockChanged)
+			}
+		}
+	}
+}
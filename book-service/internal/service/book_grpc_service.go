@@ -3,54 +3,120 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
 	"math"
-	"time"
+	"strings"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"book-service/internal/catalog"
 	"book-service/internal/domain"
-	"book-service/internal/repository"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/blob"
+	"book-service/pkg/mongodb"
 	"book-service/pkg/proto"
 )
 
 type BookGRPCService struct {
 	proto.UnimplementedBookServiceServer
-	bookRepository domain.BookRepository
+	bookRepository          domain.BookRepository
+	stockMovementRepository domain.StockMovementRepository
+	categoryRepository      domain.CategoryRepository
+	importJobRepository     domain.ImportJobRepository
+	branchRepository        domain.BranchRepository
+	transferRepository      domain.TransferRepository
+	outboxRepository        domain.OutboxRepository
+	txRepository            mongodb.TXRepository
+	lendingServiceClient    proto.LendingServiceClient
+	blobStore               blob.Store
 }
 
-func NewBookGRPCService() *BookGRPCService {
+func NewBookGRPCService(
+	bookRepository domain.BookRepository,
+	stockMovementRepository domain.StockMovementRepository,
+	categoryRepository domain.CategoryRepository,
+	importJobRepository domain.ImportJobRepository,
+	branchRepository domain.BranchRepository,
+	transferRepository domain.TransferRepository,
+	outboxRepository domain.OutboxRepository,
+	txRepository mongodb.TXRepository,
+	lendingServiceClient proto.LendingServiceClient,
+	blobStore blob.Store,
+) *BookGRPCService {
 	return &BookGRPCService{
-		bookRepository: repository.NewBookMongoDBRepository(),
+		bookRepository:          bookRepository,
+		stockMovementRepository: stockMovementRepository,
+		categoryRepository:      categoryRepository,
+		importJobRepository:     importJobRepository,
+		branchRepository:        branchRepository,
+		transferRepository:      transferRepository,
+		outboxRepository:        outboxRepository,
+		txRepository:            txRepository,
+		lendingServiceClient:    lendingServiceClient,
+		blobStore:               blobStore,
 	}
 }
 
 func (s *BookGRPCService) CreateBook(ctx context.Context, request *proto.CreateBookRequest) (*proto.Book, error) {
+	categoryID, err := s.findCategoryID(ctx, request.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	isbn, err := normalizeISBN(request.Isbn)
+	if err != nil {
+		return nil, err
+	}
+
 	book := domain.Book{
-		Title: request.Title,
+		Title:      request.Title,
+		ISBN:       isbn,
+		Author:     request.Author,
+		CategoryID: categoryID,
+		Tags:       normalizeTags(request.Tags),
 	}
 
-	if err := s.bookRepository.Create(ctx, &book); err != nil {
+	err = s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.bookRepository.Create(ctx, &book); err != nil {
+			return err
+		}
+		return s.recordBookCreated(ctx, book)
+	})
+	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.Book{
-		Id:    book.ID.Hex(),
-		Title: book.Title,
-		Stock: int32(book.Stock),
-	}, nil
+	return toProtoBook(book), nil
 }
 
 func (s *BookGRPCService) FetchBook(ctx context.Context, request *proto.FetchBookRequest) (*proto.FetchBookResponse, error) {
 	page, limit := request.Pagination.Page, request.Pagination.Limit
-	fetchFilter := map[string]interface{}{
-		"page":  page,
-		"limit": limit,
+	fetchFilter, err := s.bookFetchFilter(ctx, request.Title, request.CategoryId, request.Tags)
+	if err != nil {
+		return nil, err
 	}
-	if title := request.Title; title != "" {
-		fetchFilter["title"] = title
+	fetchFilter["page"] = page
+	fetchFilter["limit"] = limit
+	if request.BranchId != "" {
+		branch, err := s.findBranch(ctx, request.BranchId)
+		if err != nil {
+			return nil, err
+		}
+		fetchFilter["branch_id"] = branch.ID
+	}
+	if len(request.ExcludeIds) > 0 {
+		excludeIDs := make([]primitive.ObjectID, 0, len(request.ExcludeIds))
+		for _, id := range request.ExcludeIds {
+			objectID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid book ID: %s", id)
+			}
+			excludeIDs = append(excludeIDs, objectID)
+		}
+		fetchFilter["exclude_ids"] = excludeIDs
 	}
 
 	books, err := s.bookRepository.Fetch(ctx, fetchFilter)
@@ -60,11 +126,7 @@ func (s *BookGRPCService) FetchBook(ctx context.Context, request *proto.FetchBoo
 
 	protoBooks := make([]*proto.Book, 0)
 	for _, book := range books {
-		protoBooks = append(protoBooks, &proto.Book{
-			Id:    book.ID.Hex(),
-			Title: book.Title,
-			Stock: int32(book.Stock),
-		})
+		protoBooks = append(protoBooks, toProtoBook(book))
 	}
 
 	total, err := s.bookRepository.Count(ctx, fetchFilter)
@@ -92,11 +154,7 @@ func (s *BookGRPCService) FindByID(ctx context.Context, request *proto.FindBookB
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.Book{
-		Id:    book.ID.Hex(),
-		Title: book.Title,
-		Stock: int32(book.Stock),
-	}, nil
+	return toProtoBook(book), nil
 }
 
 func (s *BookGRPCService) FindByTitle(ctx context.Context, request *proto.FindBookByTitleRequest) (*proto.Book, error) {
@@ -108,11 +166,7 @@ func (s *BookGRPCService) FindByTitle(ctx context.Context, request *proto.FindBo
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.Book{
-		Id:    book.ID.Hex(),
-		Title: book.Title,
-		Stock: int32(book.Stock),
-	}, nil
+	return toProtoBook(book), nil
 }
 
 func (s *BookGRPCService) UpdateBook(ctx context.Context, request *proto.UpdateBookRequest) (*proto.Book, error) {
@@ -125,60 +179,125 @@ func (s *BookGRPCService) UpdateBook(ctx context.Context, request *proto.UpdateB
 	}
 
 	book.Title = request.Title
+	if request.Isbn != "" {
+		book.ISBN, err = normalizeISBN(request.Isbn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if request.Author != "" {
+		book.Author = request.Author
+	}
 
-	err = s.bookRepository.Update(ctx, &book)
+	err = s.bookRepository.UpdateDetails(ctx, &book)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.Book{
-		Id:    book.ID.Hex(),
-		Title: book.Title,
-		Stock: int32(book.Stock),
-	}, nil
+	return toProtoBook(book), nil
+}
+
+func (s *BookGRPCService) UpdateBookClassification(ctx context.Context, request *proto.UpdateBookClassificationRequest) (*proto.Book, error) {
+	book, err := s.bookRepository.FindByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(codes.NotFound, "book with %s ID is not found", request.Id)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	book.CategoryID, err = s.findCategoryID(ctx, request.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+	book.Tags = normalizeTags(request.Tags)
+
+	err = s.bookRepository.UpdateClassification(ctx, &book)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoBook(book), nil
 }
 
 func (s *BookGRPCService) UpdateBookStock(ctx context.Context, request *proto.UpdateBookStockRequest) (*proto.Book, error) {
 	if request.StockChange == 0 {
 		return nil, status.Error(codes.InvalidArgument, "stock change requested is 0")
 	}
+	reason := constant.StockMovementReason(request.Reason)
+	if !reason.Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid stock change reason: %s", request.Reason)
+	}
+	if reason == constant.StockTransfer {
+		return nil, status.Error(codes.InvalidArgument, "stock is transferred between branches with a transfer request")
+	}
+
+	branch, err := s.findBranchOrDefault(ctx, request.BranchId)
+	if err != nil {
+		return nil, err
+	}
 
-	retry := 3
+	book, err := s.changeStock(ctx, request.Id, domain.StockMovement{
+		BranchID:  branch.ID,
+		Delta:     int(request.StockChange),
+		Reason:    reason,
+		ActorID:   request.ActorId,
+		LendingID: request.LendingId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoBook(book), nil
+}
 
-	for {
-		book, err := s.bookRepository.FindByID(ctx, request.Id)
+// changeStock applies the movement delta to the book stock at the movement branch, and records the movement and its
+// event in one transaction, so the ledger always adds up to the stock
+func (s *BookGRPCService) changeStock(ctx context.Context, bookID string, movement domain.StockMovement) (domain.Book, error) {
+	var book domain.Book
+	err := s.withTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		book, err = s.bookRepository.IncrementStock(ctx, bookID, movement.BranchID, movement.Delta)
 		if err != nil {
-			if errors.Is(err, mongo.ErrNoDocuments) {
-				return nil, status.Errorf(codes.NotFound, "book with %s ID is not found", request.Id)
-			}
-			return nil, status.Error(codes.Internal, err.Error())
+			return err
 		}
 
-		newStock := book.Stock + int(request.StockChange)
-		if newStock < 0 {
-			return nil, status.Error(codes.Aborted, "stock cannot be decreased to below 0")
+		movement.BookID = book.ID
+		movement.Stock = book.Stock
+		if err = s.stockMovementRepository.Create(ctx, &movement); err != nil {
+			return err
 		}
-
-		err = s.bookRepository.UpdateStock(ctx, &book, newStock)
-		if err != nil {
-			if errors.Is(err, mongo.ErrNoDocuments) && retry >= 0 {
-				time.Sleep(200 * time.Millisecond)
-				retry--
-				continue
+		return s.recordStockChanged(ctx, movement)
+	})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			_, err = s.bookRepository.FindByID(ctx, bookID)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return domain.Book{}, status.Errorf(codes.NotFound, "book with %s ID is not found", bookID)
 			}
-			if retry == 0 {
-				err = fmt.Errorf("failed update stock after retry 3 times: %w", err)
+			if err != nil {
+				return domain.Book{}, status.Error(codes.Internal, err.Error())
 			}
-
-			return nil, status.Error(codes.Internal, err.Error())
+			return domain.Book{}, status.Errorf(codes.Aborted, "stock at branch with %s ID cannot be decreased to below 0",
+				movement.BranchID.Hex())
 		}
+		return domain.Book{}, status.Error(codes.Internal, err.Error())
+	}
+	return book, nil
+}
 
-		return &proto.Book{
-			Id:    book.ID.Hex(),
-			Title: book.Title,
-			Stock: int32(book.Stock),
-		}, nil
+// withTransaction runs fn in a transaction, the repositories take part in it through the context given to fn
+func (s *BookGRPCService) withTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := s.txRepository.StartSession()
+	if err != nil {
+		return err
 	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
 }
 
 func (s *BookGRPCService) DeleteBook(ctx context.Context, request *proto.DeleteBookRequest) (*proto.DeleteBookResponse, error) {
@@ -190,10 +309,200 @@ func (s *BookGRPCService) DeleteBook(ctx context.Context, request *proto.DeleteB
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	err = s.bookRepository.Delete(ctx, &book)
+	activeLendings, err := s.lendingServiceClient.CountActiveLendings(ctx, &proto.CountActiveLendingsRequest{
+		BookId: book.ID.Hex(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if activeLendings.Count > 0 {
+		if !request.Force {
+			return nil, status.Errorf(codes.FailedPrecondition, "book with %s ID still has %d active lending(s): %s",
+				request.Id, activeLendings.Count, joinLendingIDs(activeLendings.Lendings))
+		}
+
+		_, err = s.lendingServiceClient.FinishActiveLendings(ctx, &proto.FinishActiveLendingsRequest{
+			BookId: book.ID.Hex(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.bookRepository.Delete(ctx, &book); err != nil {
+			return err
+		}
+		return s.recordBookDeleted(ctx, book)
+	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	return &proto.DeleteBookResponse{}, nil
 }
+
+func (s *BookGRPCService) BookStockHistory(ctx context.Context, request *proto.BookStockHistoryRequest) (*proto.BookStockHistoryResponse, error) {
+	page, limit := request.Pagination.Page, request.Pagination.Limit
+	fetchFilter := map[string]interface{}{
+		"page":    page,
+		"limit":   limit,
+		"book_id": request.BookId,
+	}
+	if reason := request.Reason; reason != "" {
+		fetchFilter["reason"] = reason
+	}
+	if branchID := request.BranchId; branchID != "" {
+		fetchFilter["branch_id"] = branchID
+	}
+
+	movements, err := s.stockMovementRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoMovements := make([]*proto.StockMovement, 0)
+	for _, movement := range movements {
+		protoMovements = append(protoMovements, &proto.StockMovement{
+			Id:         movement.ID.Hex(),
+			BookId:     movement.BookID.Hex(),
+			Delta:      int32(movement.Delta),
+			Stock:      int32(movement.Stock),
+			Reason:     string(movement.Reason),
+			ActorId:    movement.ActorID,
+			LendingId:  movement.LendingID,
+			CreatedAt:  timestamppb.New(movement.CreatedAt),
+			BranchId:   movement.BranchID.Hex(),
+			TransferId: movement.TransferID,
+		})
+	}
+
+	total, err := s.stockMovementRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.BookStockHistoryResponse{
+		Pagination: &proto.BookPaginationResponse{
+			Limit:    limit,
+			Page:     page,
+			LastPage: int32(math.Ceil(float64(total) / float64(limit))),
+			Total:    int32(total),
+		},
+		Movements: protoMovements,
+	}, nil
+}
+
+// ReconcileBookStock compares book stock with the stock recomputed from the ledger.
+// Without book ID, every book is checked and only the inconsistent ones are returned.
+func (s *BookGRPCService) ReconcileBookStock(ctx context.Context, request *proto.ReconcileBookStockRequest) (*proto.ReconcileBookStockResponse, error) {
+	var books []domain.Book
+	if request.BookId != "" {
+		book, err := s.bookRepository.FindByID(ctx, request.BookId)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, status.Errorf(codes.NotFound, "book with %s ID is not found", request.BookId)
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		books = append(books, book)
+	} else {
+		total, err := s.bookRepository.Count(ctx, map[string]interface{}{})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		books, err = s.bookRepository.Fetch(ctx, map[string]interface{}{
+			"limit": int32(total),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	bookIDs := make([]primitive.ObjectID, 0, len(books))
+	for _, book := range books {
+		bookIDs = append(bookIDs, book.ID)
+	}
+
+	ledgerStocks, err := s.stockMovementRepository.SumDelta(ctx, bookIDs)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	reconciliations := make([]*proto.BookStockReconciliation, 0)
+	for _, book := range books {
+		ledgerStock := ledgerStocks[book.ID]
+		consistent := ledgerStock == book.Stock
+		if consistent && request.BookId == "" {
+			continue
+		}
+
+		reconciliations = append(reconciliations, &proto.BookStockReconciliation{
+			BookId:      book.ID.Hex(),
+			Stock:       int32(book.Stock),
+			LedgerStock: int32(ledgerStock),
+			Consistent:  consistent,
+		})
+	}
+
+	return &proto.ReconcileBookStockResponse{
+		Reconciliations: reconciliations,
+	}, nil
+}
+
+// bookFetchFilter includes the books of the category descendants when filtered by category
+func (s *BookGRPCService) bookFetchFilter(ctx context.Context, title, categoryID string, tags []string) (map[string]interface{}, error) {
+	fetchFilter := make(map[string]interface{})
+	if title != "" {
+		fetchFilter["title"] = title
+	}
+	if categoryID != "" {
+		categoryIDs, err := s.categoryTreeIDs(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		fetchFilter["category_ids"] = categoryIDs
+	}
+	if tags := normalizeTags(tags); len(tags) > 0 {
+		fetchFilter["tags"] = tags
+	}
+
+	return fetchFilter, nil
+}
+
+func toProtoBook(book domain.Book) *proto.Book {
+	return &proto.Book{
+		Id:           book.ID.Hex(),
+		Title:        book.Title,
+		Stock:        int32(book.Stock),
+		CategoryId:   objectIDHex(book.CategoryID),
+		Tags:         book.Tags,
+		Isbn:         book.ISBN,
+		Author:       book.Author,
+		CoverVersion: coverVersion(book.Cover),
+		Branches:     toProtoBranchStocks(book.Branches),
+	}
+}
+
+// normalizeISBN accepts an empty ISBN, a book without ISBN is still valid
+func normalizeISBN(isbn string) (string, error) {
+	if isbn == "" {
+		return "", nil
+	}
+
+	normalized, err := catalog.NormalizeISBN(isbn)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return normalized, nil
+}
+
+func joinLendingIDs(lendings []*proto.Lending) string {
+	ids := make([]string, 0, len(lendings))
+	for _, lending := range lendings {
+		ids = append(ids, lending.GetId())
+	}
+
+	return strings.Join(ids, ", ")
+}
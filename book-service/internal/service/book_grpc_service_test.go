@@ -0,0 +1,1105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/internal/repository"
+	"book-service/pkg/blob"
+	"book-service/pkg/mongodb"
+	"book-service/pkg/proto"
+	"book-service/pkg/tenant"
+)
+
+// newMemoryBookGRPCService runs the service against the in-memory repositories and a blob store in a temporary folder
+func newMemoryBookGRPCService(t *testing.T) *BookGRPCService {
+	t.Helper()
+
+	blobStore, err := blob.NewFileSystemStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSystemStore() error = %v", err)
+	}
+
+	return NewBookGRPCService(
+		repository.NewBookMemoryRepository(),
+		repository.NewStockMovementMemoryRepository(),
+		repository.NewCategoryMemoryRepository(),
+		repository.NewImportJobMemoryRepository(),
+		repository.NewBranchMemoryRepository(),
+		repository.NewTransferMemoryRepository(),
+		repository.NewOutboxMemoryRepository(),
+		mongodb.NewNoTXRepository(),
+		&lendingServiceClientStub{},
+		blobStore,
+	)
+}
+
+// newMongoDBBookGRPCService runs the service against the MongoDB from MONGODB_URI,
+// so the stock guarantees are checked against the real atomic update.
+// The test runs in its own tenant, whose documents are removed after the test.
+func newMongoDBBookGRPCService(t *testing.T) (*BookGRPCService, context.Context) {
+	t.Helper()
+
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI is not set")
+	}
+	database := os.Getenv("MONGODB_DATABASE")
+	if database == "" {
+		database = "book-service-test"
+	}
+	mongodb.Configure(uri, database)
+	db := mongodb.GetDatabase()
+
+	tenantID := "test-" + primitive.NewObjectID().Hex()
+	t.Cleanup(func() {
+		for _, collection := range []string{constant.BookCollection, constant.BranchCollection, constant.StockMovementCollection, constant.OutboxCollection} {
+			_, _ = db.Collection(collection).DeleteMany(context.Background(), bson.D{{"meta.tenant_id", tenantID}})
+		}
+	})
+
+	s := NewBookGRPCService(
+		repository.NewBookMongoDBRepository(db),
+		repository.NewStockMovementMongoDBRepository(db),
+		repository.NewCategoryMongoDBRepository(db),
+		repository.NewImportJobMongoDBRepository(db),
+		repository.NewBranchMongoDBRepository(db),
+		repository.NewTransferMongoDBRepository(db),
+		repository.NewOutboxMongoDBRepository(db),
+		mongodb.NewTXRepository(db),
+		&lendingServiceClientStub{},
+		nil,
+	)
+	return s, tenant.NewContext(context.Background(), tenantID)
+}
+
+// lendingServiceClientStub answers with the active lendings of each book ID, the finished books are recorded
+type lendingServiceClientStub struct {
+	proto.LendingServiceClient
+	activeLendings map[string][]*proto.Lending
+	finished       []string
+}
+
+func (c *lendingServiceClientStub) CountActiveLendings(_ context.Context, in *proto.CountActiveLendingsRequest, _ ...grpc.CallOption) (*proto.CountActiveLendingsResponse, error) {
+	lendings := c.activeLendings[in.BookId]
+	return &proto.CountActiveLendingsResponse{Count: int32(len(lendings)), Lendings: lendings}, nil
+}
+
+func (c *lendingServiceClientStub) FinishActiveLendings(_ context.Context, in *proto.FinishActiveLendingsRequest, _ ...grpc.CallOption) (*proto.FinishActiveLendingsResponse, error) {
+	c.finished = append(c.finished, in.BookId)
+	return &proto.FinishActiveLendingsResponse{Lendings: c.activeLendings[in.BookId]}, nil
+}
+
+func createTestBook(t *testing.T, s *BookGRPCService, request *proto.CreateBookRequest) *proto.Book {
+	t.Helper()
+
+	book, err := s.CreateBook(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CreateBook() error = %v", err)
+	}
+	return book
+}
+
+// stockTestBook creates a book with stock at the default branch
+func stockTestBook(t *testing.T, s *BookGRPCService, title string, stock int32) *proto.Book {
+	t.Helper()
+
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: title})
+	book, err := s.UpdateBookStock(context.Background(), &proto.UpdateBookStockRequest{
+		Id:          book.Id,
+		StockChange: stock,
+		Reason:      string(constant.StockPurchase),
+	})
+	if err != nil {
+		t.Fatalf("UpdateBookStock() error = %v", err)
+	}
+	return book
+}
+
+func TestBookGRPCService_CreateBook(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	category, err := s.CreateCategory(ctx, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		request  *proto.CreateBookRequest
+		wantCode codes.Code
+		wantISBN string
+		wantTags []string
+	}{
+		{
+			name:     "normalizes ISBN and tags",
+			request:  &proto.CreateBookRequest{Title: "Dune", Isbn: "978-0-306-40615-7", Tags: []string{" Sci-Fi", "classic", "sci-fi"}},
+			wantISBN: "9780306406157",
+			wantTags: []string{"classic", "sci-fi"},
+		},
+		{
+			name:     "without ISBN",
+			request:  &proto.CreateBookRequest{Title: "Untitled"},
+			wantTags: []string{},
+		},
+		{
+			name:     "in category",
+			request:  &proto.CreateBookRequest{Title: "Beloved", CategoryId: category.Id},
+			wantTags: []string{},
+		},
+		{
+			name:     "invalid ISBN",
+			request:  &proto.CreateBookRequest{Title: "Dune", Isbn: "978-0-306-40615-8"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown category",
+			request:  &proto.CreateBookRequest{Title: "Dune", CategoryId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CreateBook(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateBook() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Id == "" || got.Title != tt.request.Title || got.Isbn != tt.wantISBN || got.CategoryId != tt.request.CategoryId {
+				t.Errorf("CreateBook() = %+v", got)
+			}
+			if len(got.Tags) != len(tt.wantTags) {
+				t.Fatalf("CreateBook() tags = %v, want %v", got.Tags, tt.wantTags)
+			}
+			for i := range got.Tags {
+				if got.Tags[i] != tt.wantTags[i] {
+					t.Errorf("CreateBook() tags = %v, want %v", got.Tags, tt.wantTags)
+				}
+			}
+		})
+	}
+}
+
+// failingOutboxRepository fails to record the events, the changes they describe must fail with them
+type failingOutboxRepository struct {
+	domain.OutboxRepository
+}
+
+func (failingOutboxRepository) Create(context.Context, *domain.OutboxEvent) error {
+	return errors.New("outbox is unavailable")
+}
+
+func TestBookGRPCService_OutboxFailure(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Dune"})
+	branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: "Main", Code: "MAIN"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	s.outboxRepository = failingOutboxRepository{}
+
+	if _, err = s.CreateBook(ctx, &proto.CreateBookRequest{Title: "Dune Messiah"}); status.Code(err) != codes.Internal {
+		t.Errorf("CreateBook() error = %v, want Internal", err)
+	}
+	_, err = s.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
+		Id:          book.Id,
+		StockChange: 1,
+		Reason:      string(constant.StockPurchase),
+		ActorId:     "librarian-1",
+		BranchId:    branch.Id,
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("UpdateBookStock() error = %v, want Internal", err)
+	}
+	if _, err = s.DeleteBook(ctx, &proto.DeleteBookRequest{Id: book.Id}); status.Code(err) != codes.Internal {
+		t.Errorf("DeleteBook() error = %v, want Internal", err)
+	}
+}
+
+func TestBookGRPCService_FetchBook(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	fiction, err := s.CreateCategory(ctx, &proto.CreateCategoryRequest{Name: "Fiction", Code: "800"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	novels, err := s.CreateCategory(ctx, &proto.CreateCategoryRequest{Name: "Novels", Code: "813", ParentId: fiction.Id})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: "East", Code: "east"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "Go in Action", Tags: []string{"go", "programming"}})
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "The Go Programming Language", Tags: []string{"go"}})
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "Moby Dick", CategoryId: fiction.Id})
+	beloved := createTestBook(t, s, &proto.CreateBookRequest{Title: "Beloved", CategoryId: novels.Id})
+	_, err = s.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
+		Id: beloved.Id, StockChange: 1, Reason: string(constant.StockPurchase), BranchId: branch.Id,
+	})
+	if err != nil {
+		t.Fatalf("UpdateBookStock() error = %v", err)
+	}
+
+	deleted := createTestBook(t, s, &proto.CreateBookRequest{Title: "Go Deleted"})
+	if _, err = s.DeleteBook(ctx, &proto.DeleteBookRequest{Id: deleted.Id}); err != nil {
+		t.Fatalf("DeleteBook() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		request      *proto.FetchBookRequest
+		wantCode     codes.Code
+		wantTitles   []string
+		wantTotal    int32
+		wantLastPage int32
+	}{
+		{
+			name:         "newest first by page",
+			request:      &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 3, Page: 1}},
+			wantTitles:   []string{"Beloved", "Moby Dick", "The Go Programming Language"},
+			wantTotal:    4,
+			wantLastPage: 2,
+		},
+		{
+			name:         "last page",
+			request:      &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 3, Page: 2}},
+			wantTitles:   []string{"Go in Action"},
+			wantTotal:    4,
+			wantLastPage: 2,
+		},
+		{
+			name:         "by title, case-insensitively",
+			request:      &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, Title: "go "},
+			wantTitles:   []string{"The Go Programming Language", "Go in Action"},
+			wantTotal:    2,
+			wantLastPage: 1,
+		},
+		{
+			name:         "by every tag",
+			request:      &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, Tags: []string{"Go", "programming"}},
+			wantTitles:   []string{"Go in Action"},
+			wantTotal:    1,
+			wantLastPage: 1,
+		},
+		{
+			name:         "by category with its descendants",
+			request:      &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, CategoryId: fiction.Id},
+			wantTitles:   []string{"Beloved", "Moby Dick"},
+			wantTotal:    2,
+			wantLastPage: 1,
+		},
+		{
+			name:         "by branch",
+			request:      &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BranchId: branch.Id},
+			wantTitles:   []string{"Beloved"},
+			wantTotal:    1,
+			wantLastPage: 1,
+		},
+		{
+			name:         "without the excluded",
+			request:      &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, CategoryId: fiction.Id, ExcludeIds: []string{beloved.Id}},
+			wantTitles:   []string{"Moby Dick"},
+			wantTotal:    1,
+			wantLastPage: 1,
+		},
+		{
+			name:     "invalid excluded ID",
+			request:  &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, ExcludeIds: []string{"dune"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown category",
+			request:  &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, CategoryId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "unknown branch",
+			request:  &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BranchId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FetchBook(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FetchBook() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			titles := make([]string, 0, len(got.Books))
+			for _, book := range got.Books {
+				titles = append(titles, book.Title)
+			}
+			if !equalStrings(titles, tt.wantTitles) {
+				t.Errorf("FetchBook() titles = %v, want %v", titles, tt.wantTitles)
+			}
+			if got.Pagination.Total != tt.wantTotal || got.Pagination.LastPage != tt.wantLastPage {
+				t.Errorf("FetchBook() pagination = %+v, want total %d, last page %d", got.Pagination, tt.wantTotal, tt.wantLastPage)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FindByID(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Dune"})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		id       string
+		wantCode codes.Code
+	}{
+		{
+			name: "found",
+			ctx:  context.Background(),
+			id:   book.Id,
+		},
+		{
+			name:     "not found",
+			ctx:      context.Background(),
+			id:       primitive.NewObjectID().Hex(),
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "book of another tenant",
+			ctx:      tenant.NewContext(context.Background(), "other"),
+			id:       book.Id,
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindByID(tt.ctx, &proto.FindBookByIDRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FindByID() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && got.Title != book.Title {
+				t.Errorf("FindByID() = %+v, want %+v", got, book)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FindByTitle(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: "The Left Hand of Darkness"})
+
+	tests := []struct {
+		name     string
+		title    string
+		wantCode codes.Code
+	}{
+		{
+			name:  "part of the title",
+			title: "left hand",
+		},
+		{
+			name:     "not found",
+			title:    "Dune",
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindByTitle(context.Background(), &proto.FindBookByTitleRequest{Title: tt.title})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FindByTitle() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && got.Id != book.Id {
+				t.Errorf("FindByTitle() = %+v, want %+v", got, book)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_UpdateBook(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Dune", Isbn: "9780306406157", Author: "Frank Herbert"})
+
+	tests := []struct {
+		name       string
+		request    *proto.UpdateBookRequest
+		wantCode   codes.Code
+		wantISBN   string
+		wantAuthor string
+	}{
+		{
+			name:       "keeps the empty fields",
+			request:    &proto.UpdateBookRequest{Id: book.Id, Title: "Dune Messiah"},
+			wantISBN:   "9780306406157",
+			wantAuthor: "Frank Herbert",
+		},
+		{
+			name:       "changes every field",
+			request:    &proto.UpdateBookRequest{Id: book.Id, Title: "Children of Dune", Isbn: "0-306-40615-2", Author: "F. Herbert"},
+			wantISBN:   "0306406152",
+			wantAuthor: "F. Herbert",
+		},
+		{
+			name:     "invalid ISBN",
+			request:  &proto.UpdateBookRequest{Id: book.Id, Title: "Dune", Isbn: "123"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "not found",
+			request:  &proto.UpdateBookRequest{Id: primitive.NewObjectID().Hex(), Title: "Dune"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.UpdateBook(context.Background(), tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateBook() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			found, err := s.FindByID(context.Background(), &proto.FindBookByIDRequest{Id: book.Id})
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			for _, b := range []*proto.Book{got, found} {
+				if b.Title != tt.request.Title || b.Isbn != tt.wantISBN || b.Author != tt.wantAuthor {
+					t.Errorf("UpdateBook() = %+v, want title %s, ISBN %s, author %s", b, tt.request.Title, tt.wantISBN, tt.wantAuthor)
+				}
+			}
+		})
+	}
+}
+
+// TestBookGRPCService_UpdateBook_ParallelStockChanges checks an update of the details never overwrites the stock
+// changed since the book was read
+func TestBookGRPCService_UpdateBook_ParallelStockChanges(t *testing.T) {
+	const changes = 20
+
+	backends := map[string]func(t *testing.T) (*BookGRPCService, context.Context){
+		"memory": func(t *testing.T) (*BookGRPCService, context.Context) {
+			return newMemoryBookGRPCService(t), context.Background()
+		},
+		"mongodb": newMongoDBBookGRPCService,
+	}
+	for backend, newService := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s, ctx := newService(t)
+
+			branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: t.Name(), Code: primitive.NewObjectID().Hex()})
+			if err != nil {
+				t.Fatalf("CreateBranch() error = %v", err)
+			}
+			book, err := s.CreateBook(ctx, &proto.CreateBookRequest{Title: t.Name()})
+			if err != nil {
+				t.Fatalf("CreateBook() error = %v", err)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < changes; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+
+					_, err := s.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
+						Id:          book.Id,
+						StockChange: 1,
+						Reason:      string(constant.StockPurchase),
+						ActorId:     t.Name(),
+						BranchId:    branch.Id,
+					})
+					if err != nil {
+						t.Errorf("UpdateBookStock() error = %v", err)
+					}
+				}()
+				go func() {
+					defer wg.Done()
+
+					if _, err := s.UpdateBook(ctx, &proto.UpdateBookRequest{Id: book.Id, Title: "Dune"}); err != nil {
+						t.Errorf("UpdateBook() error = %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			got, err := s.FindByID(ctx, &proto.FindBookByIDRequest{Id: book.Id})
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if got.Title != "Dune" || got.Stock != changes || len(got.Branches) != 1 || got.Branches[0].Stock != changes {
+				t.Errorf("FindByID() = %+v, want Dune with %d copies at the branch", got, changes)
+			}
+		})
+	}
+}
+
+// stockChangingBookRepository adds a copy at the branch right after every read of a book, like a stock change
+// landing between the read and the write of an update
+type stockChangingBookRepository struct {
+	domain.BookRepository
+	branchID primitive.ObjectID
+}
+
+func (r *stockChangingBookRepository) FindByID(ctx context.Context, id string) (domain.Book, error) {
+	book, err := r.BookRepository.FindByID(ctx, id)
+	if err != nil {
+		return book, err
+	}
+	_, err = r.BookRepository.IncrementStock(ctx, id, r.branchID, 1)
+	return book, err
+}
+
+// TestBookGRPCService_StockChangedAfterRead checks the updates of a book keep the copies added between their read
+// and their write
+func TestBookGRPCService_StockChangedAfterRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(s *BookGRPCService, id string) (*proto.Book, error)
+	}{
+		{
+			name: "details",
+			update: func(s *BookGRPCService, id string) (*proto.Book, error) {
+				return s.UpdateBook(context.Background(), &proto.UpdateBookRequest{Id: id, Title: "Dune Messiah"})
+			},
+		},
+		{
+			name: "classification",
+			update: func(s *BookGRPCService, id string) (*proto.Book, error) {
+				return s.UpdateBookClassification(context.Background(), &proto.UpdateBookClassificationRequest{Id: id, Tags: []string{"Sci-Fi"}})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemoryBookGRPCService(t)
+			book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Dune"})
+			branch, err := s.CreateBranch(context.Background(), &proto.CreateBranchRequest{Name: "Main", Code: "MAIN"})
+			if err != nil {
+				t.Fatalf("CreateBranch() error = %v", err)
+			}
+			branchID, _ := primitive.ObjectIDFromHex(branch.Id)
+			s.bookRepository = &stockChangingBookRepository{BookRepository: s.bookRepository, branchID: branchID}
+
+			got, err := tt.update(s, book.Id)
+			if err != nil {
+				t.Fatalf("update error = %v", err)
+			}
+			if got.Stock != 1 || len(got.Branches) != 1 {
+				t.Errorf("update = %+v, want the copy added after the read", got)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_UpdateBookClassification(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	category, err := s.CreateCategory(ctx, &proto.CreateCategoryRequest{Name: "Science", Code: "500"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Cosmos", Tags: []string{"old"}})
+
+	tests := []struct {
+		name     string
+		request  *proto.UpdateBookClassificationRequest
+		wantCode codes.Code
+		wantTags []string
+	}{
+		{
+			name:     "classifies",
+			request:  &proto.UpdateBookClassificationRequest{Id: book.Id, CategoryId: category.Id, Tags: []string{"Space", "astronomy"}},
+			wantTags: []string{"astronomy", "space"},
+		},
+		{
+			name:     "unclassifies",
+			request:  &proto.UpdateBookClassificationRequest{Id: book.Id},
+			wantTags: []string{},
+		},
+		{
+			name:     "unknown category",
+			request:  &proto.UpdateBookClassificationRequest{Id: book.Id, CategoryId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "book not found",
+			request:  &proto.UpdateBookClassificationRequest{Id: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.UpdateBookClassification(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateBookClassification() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.CategoryId != tt.request.CategoryId || !equalStrings(got.Tags, tt.wantTags) {
+				t.Errorf("UpdateBookClassification() = %+v, want category %q, tags %v", got, tt.request.CategoryId, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_UpdateBookStock(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: "East", Code: "east"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	book := stockTestBook(t, s, "Dune", 2)
+
+	tests := []struct {
+		name            string
+		request         *proto.UpdateBookStockRequest
+		wantCode        codes.Code
+		wantStock       int32
+		wantBranchStock int32
+	}{
+		{
+			name:            "purchase at a new branch",
+			request:         &proto.UpdateBookStockRequest{Id: book.Id, StockChange: 3, Reason: string(constant.StockPurchase), BranchId: branch.Id},
+			wantStock:       5,
+			wantBranchStock: 3,
+		},
+		{
+			name:            "lending at the branch",
+			request:         &proto.UpdateBookStockRequest{Id: book.Id, StockChange: -1, Reason: string(constant.StockLending), BranchId: branch.Id},
+			wantStock:       4,
+			wantBranchStock: 2,
+		},
+		{
+			name:     "below 0 at the branch",
+			request:  &proto.UpdateBookStockRequest{Id: book.Id, StockChange: -3, Reason: string(constant.StockLending), BranchId: branch.Id},
+			wantCode: codes.Aborted,
+		},
+		{
+			name:     "no change",
+			request:  &proto.UpdateBookStockRequest{Id: book.Id, Reason: string(constant.StockPurchase)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid reason",
+			request:  &proto.UpdateBookStockRequest{Id: book.Id, StockChange: 1, Reason: "gift"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "transfer reason",
+			request:  &proto.UpdateBookStockRequest{Id: book.Id, StockChange: 1, Reason: string(constant.StockTransfer)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown branch",
+			request:  &proto.UpdateBookStockRequest{Id: book.Id, StockChange: 1, Reason: string(constant.StockPurchase), BranchId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "book not found",
+			request:  &proto.UpdateBookStockRequest{Id: primitive.NewObjectID().Hex(), StockChange: 1, Reason: string(constant.StockPurchase)},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.UpdateBookStock(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateBookStock() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Stock != tt.wantStock {
+				t.Errorf("UpdateBookStock() stock = %d, want %d", got.Stock, tt.wantStock)
+			}
+			for _, branchStock := range got.Branches {
+				if branchStock.BranchId == branch.Id && branchStock.Stock != tt.wantBranchStock {
+					t.Errorf("UpdateBookStock() branch stock = %d, want %d", branchStock.Stock, tt.wantBranchStock)
+				}
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_UpdateBookStock_ParallelLendings(t *testing.T) {
+	tests := []struct {
+		name             string
+		stock            int32
+		otherBranchStock int32
+		lendings         int
+		wantSucceeded    int
+	}{
+		{
+			name:          "never oversell",
+			stock:         5,
+			lendings:      50,
+			wantSucceeded: 5,
+		},
+		{
+			name:          "never spuriously fail",
+			stock:         50,
+			lendings:      50,
+			wantSucceeded: 50,
+		},
+		{
+			name:             "never take the copies of another branch",
+			stock:            5,
+			otherBranchStock: 50,
+			lendings:         50,
+			wantSucceeded:    5,
+		},
+	}
+
+	// the memory backend always runs, the mongodb one checks the same against the atomic update of a real MongoDB
+
+	backends := map[string]func(t *testing.T) (*BookGRPCService, context.Context){
+		"memory": func(t *testing.T) (*BookGRPCService, context.Context) {
+			return newMemoryBookGRPCService(t), context.Background()
+		},
+		"mongodb": newMongoDBBookGRPCService,
+	}
+	for backend, newService := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s, ctx := newService(t)
+
+			branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: t.Name(), Code: primitive.NewObjectID().Hex()})
+			if err != nil {
+				t.Fatalf("CreateBranch() error = %v", err)
+			}
+			otherBranch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: t.Name() + " other", Code: primitive.NewObjectID().Hex()})
+			if err != nil {
+				t.Fatalf("CreateBranch() error = %v", err)
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					book, err := s.CreateBook(ctx, &proto.CreateBookRequest{Title: t.Name()})
+					if err != nil {
+						t.Fatalf("CreateBook() error = %v", err)
+					}
+
+					_, err = s.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
+						Id:          book.Id,
+						StockChange: tt.stock,
+						Reason:      string(constant.StockPurchase),
+						ActorId:     t.Name(),
+						BranchId:    branch.Id,
+					})
+					if err != nil {
+						t.Fatalf("UpdateBookStock() purchase error = %v", err)
+					}
+					if tt.otherBranchStock > 0 {
+						_, err = s.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
+							Id:          book.Id,
+							StockChange: tt.otherBranchStock,
+							Reason:      string(constant.StockPurchase),
+							ActorId:     t.Name(),
+							BranchId:    otherBranch.Id,
+						})
+						if err != nil {
+							t.Fatalf("UpdateBookStock() purchase at the other branch error = %v", err)
+						}
+					}
+
+					var (
+						wg        sync.WaitGroup
+						mu        sync.Mutex
+						succeeded int
+					)
+					for i := 0; i < tt.lendings; i++ {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+
+							_, err := s.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
+								Id:          book.Id,
+								StockChange: -1,
+								Reason:      string(constant.StockLending),
+								ActorId:     t.Name(),
+								BranchId:    branch.Id,
+							})
+							if err != nil {
+								if status.Code(err) != codes.Aborted {
+									t.Errorf("UpdateBookStock() unexpected error = %v", err)
+								}
+								return
+							}
+
+							mu.Lock()
+							succeeded++
+							mu.Unlock()
+						}()
+					}
+					wg.Wait()
+
+					if succeeded != tt.wantSucceeded {
+						t.Errorf("succeeded lendings = %d, want %d", succeeded, tt.wantSucceeded)
+					}
+
+					got, err := s.FindByID(ctx, &proto.FindBookByIDRequest{Id: book.Id})
+					if err != nil {
+						t.Fatalf("FindByID() error = %v", err)
+					}
+					want := tt.stock - int32(tt.wantSucceeded)
+					if got.Stock != want+tt.otherBranchStock || got.Branches[0].Stock != want {
+						t.Errorf("stock = %d, branch stock = %d, want %d and %d at the branch",
+							got.Stock, got.Branches[0].Stock, want+tt.otherBranchStock, want)
+					}
+
+					reconciled, err := s.ReconcileBookStock(ctx, &proto.ReconcileBookStockRequest{BookId: book.Id})
+					if err != nil {
+						t.Fatalf("ReconcileBookStock() error = %v", err)
+					}
+					if !reconciled.Reconciliations[0].Consistent {
+						t.Errorf("ReconcileBookStock() = %+v, want consistent", reconciled.Reconciliations[0])
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_DeleteBook(t *testing.T) {
+	lending := &proto.Lending{Id: primitive.NewObjectID().Hex()}
+
+	tests := []struct {
+		name         string
+		lendings     int
+		force        bool
+		missing      bool
+		wantCode     codes.Code
+		wantFinished bool
+	}{
+		{
+			name: "without lendings",
+		},
+		{
+			name:     "with active lendings",
+			lendings: 1,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "forced with active lendings",
+			lendings:     1,
+			force:        true,
+			wantFinished: true,
+		},
+		{
+			name:     "not found",
+			missing:  true,
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemoryBookGRPCService(t)
+			ctx := context.Background()
+
+			book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Dune"})
+			bookID := book.Id
+			if tt.missing {
+				bookID = primitive.NewObjectID().Hex()
+			}
+			lendingServiceClient := &lendingServiceClientStub{activeLendings: map[string][]*proto.Lending{}}
+			for i := 0; i < tt.lendings; i++ {
+				lendingServiceClient.activeLendings[bookID] = append(lendingServiceClient.activeLendings[bookID], lending)
+			}
+			s.lendingServiceClient = lendingServiceClient
+
+			_, err := s.DeleteBook(ctx, &proto.DeleteBookRequest{Id: bookID, Force: tt.force})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("DeleteBook() error = %v, want %v", err, tt.wantCode)
+			}
+			if finished := len(lendingServiceClient.finished) > 0; finished != tt.wantFinished {
+				t.Errorf("DeleteBook() finished lendings = %v, want %v", finished, tt.wantFinished)
+			}
+
+			fetched, err := s.FetchBook(ctx, &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}})
+			if err != nil {
+				t.Fatalf("FetchBook() error = %v", err)
+			}
+			if deleted := fetched.Pagination.Total == 0; deleted != (tt.wantCode == codes.OK) {
+				t.Errorf("DeleteBook() deleted = %v, want %v", deleted, tt.wantCode == codes.OK)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_BookStockHistory(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: "East", Code: "east"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	book := stockTestBook(t, s, "Dune", 3)
+	stockTestBook(t, s, "Emma", 1)
+	for _, request := range []*proto.UpdateBookStockRequest{
+		{Id: book.Id, StockChange: -1, Reason: string(constant.StockLending), LendingId: "lending-1"},
+		{Id: book.Id, StockChange: 2, Reason: string(constant.StockPurchase), BranchId: branch.Id},
+	} {
+		if _, err = s.UpdateBookStock(ctx, request); err != nil {
+			t.Fatalf("UpdateBookStock() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		request    *proto.BookStockHistoryRequest
+		wantDeltas []int32
+		wantTotal  int32
+	}{
+		{
+			name:       "newest first",
+			request:    &proto.BookStockHistoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BookId: book.Id},
+			wantDeltas: []int32{2, -1, 3},
+			wantTotal:  3,
+		},
+		{
+			name:       "by page",
+			request:    &proto.BookStockHistoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 2, Page: 2}, BookId: book.Id},
+			wantDeltas: []int32{3},
+			wantTotal:  3,
+		},
+		{
+			name:       "by reason",
+			request:    &proto.BookStockHistoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BookId: book.Id, Reason: string(constant.StockLending)},
+			wantDeltas: []int32{-1},
+			wantTotal:  1,
+		},
+		{
+			name:       "by branch",
+			request:    &proto.BookStockHistoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BookId: book.Id, BranchId: branch.Id},
+			wantDeltas: []int32{2},
+			wantTotal:  1,
+		},
+		{
+			name:       "unknown book",
+			request:    &proto.BookStockHistoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BookId: primitive.NewObjectID().Hex()},
+			wantDeltas: []int32{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.BookStockHistory(ctx, tt.request)
+			if err != nil {
+				t.Fatalf("BookStockHistory() error = %v", err)
+			}
+
+			deltas := make([]int32, 0, len(got.Movements))
+			for _, movement := range got.Movements {
+				deltas = append(deltas, movement.Delta)
+			}
+			if len(deltas) != len(tt.wantDeltas) || got.Pagination.Total != tt.wantTotal {
+				t.Fatalf("BookStockHistory() deltas = %v, total = %d, want %v, %d", deltas, got.Pagination.Total, tt.wantDeltas, tt.wantTotal)
+			}
+			for i := range deltas {
+				if deltas[i] != tt.wantDeltas[i] {
+					t.Errorf("BookStockHistory() deltas = %v, want %v", deltas, tt.wantDeltas)
+				}
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_ReconcileBookStock(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	consistent := stockTestBook(t, s, "Dune", 2)
+	inconsistent := stockTestBook(t, s, "Emma", 2)
+	// a stock change without its ledger entry, like a movement failed to be recorded
+	branch, err := s.FindDefaultBranch(ctx, &proto.FindDefaultBranchRequest{})
+	if err != nil {
+		t.Fatalf("FindDefaultBranch() error = %v", err)
+	}
+	branchID, _ := primitive.ObjectIDFromHex(branch.Id)
+	if _, err = s.bookRepository.IncrementStock(ctx, inconsistent.Id, branchID, 1); err != nil {
+		t.Fatalf("IncrementStock() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		bookID   string
+		wantCode codes.Code
+		want     []*proto.BookStockReconciliation
+	}{
+		{
+			name:   "consistent book",
+			bookID: consistent.Id,
+			want:   []*proto.BookStockReconciliation{{BookId: consistent.Id, Stock: 2, LedgerStock: 2, Consistent: true}},
+		},
+		{
+			name: "only the inconsistent books of every book",
+			want: []*proto.BookStockReconciliation{{BookId: inconsistent.Id, Stock: 3, LedgerStock: 2}},
+		},
+		{
+			name:     "not found",
+			bookID:   primitive.NewObjectID().Hex(),
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ReconcileBookStock(ctx, &proto.ReconcileBookStockRequest{BookId: tt.bookID})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("ReconcileBookStock() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(got.Reconciliations) != len(tt.want) {
+				t.Fatalf("ReconcileBookStock() = %v, want %v", got.Reconciliations, tt.want)
+			}
+			for i, reconciliation := range got.Reconciliations {
+				want := tt.want[i]
+				if reconciliation.BookId != want.BookId || reconciliation.Stock != want.Stock ||
+					reconciliation.LedgerStock != want.LedgerStock || reconciliation.Consistent != want.Consistent {
+					t.Errorf("ReconcileBookStock() = %+v, want %+v", reconciliation, want)
+				}
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
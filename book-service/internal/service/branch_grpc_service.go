@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/proto"
+)
+
+const (
+	defaultBranchName = "Main"
+	defaultBranchCode = "main"
+)
+
+func (s *BookGRPCService) CreateBranch(ctx context.Context, request *proto.CreateBranchRequest) (*proto.Branch, error) {
+	if request.Name == "" || request.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "branch name and code are required")
+	}
+
+	branch := domain.Branch{
+		Name:    request.Name,
+		Code:    strings.ToLower(request.Code),
+		Address: request.Address,
+	}
+
+	if err := s.branchRepository.Create(ctx, &branch); err != nil {
+		if strings.Contains(err.Error(), constant.BranchTenantCodeIndex) {
+			return nil, status.Errorf(codes.AlreadyExists, "branch with %s code already exists", branch.Code)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoBranch(branch), nil
+}
+
+func (s *BookGRPCService) FetchBranch(ctx context.Context, request *proto.FetchBranchRequest) (*proto.FetchBranchResponse, error) {
+	page, limit := request.Pagination.Page, request.Pagination.Limit
+	fetchFilter := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+	if name := request.Name; name != "" {
+		fetchFilter["name"] = name
+	}
+
+	branches, err := s.branchRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoBranches := make([]*proto.Branch, 0)
+	for _, branch := range branches {
+		protoBranches = append(protoBranches, toProtoBranch(branch))
+	}
+
+	total, err := s.branchRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.FetchBranchResponse{
+		Pagination: &proto.BookPaginationResponse{
+			Limit:    limit,
+			Page:     page,
+			LastPage: int32(math.Ceil(float64(total) / float64(limit))),
+			Total:    int32(total),
+		},
+		Branches: protoBranches,
+	}, nil
+}
+
+func (s *BookGRPCService) FindBranchByID(ctx context.Context, request *proto.FindBranchByIDRequest) (*proto.Branch, error) {
+	branch, err := s.findBranch(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoBranch(branch), nil
+}
+
+func (s *BookGRPCService) FindDefaultBranch(ctx context.Context, _ *proto.FindDefaultBranchRequest) (*proto.Branch, error) {
+	branch, err := s.findBranchOrDefault(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoBranch(branch), nil
+}
+
+func (s *BookGRPCService) UpdateBranch(ctx context.Context, request *proto.UpdateBranchRequest) (*proto.Branch, error) {
+	if request.Name == "" || request.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "branch name and code are required")
+	}
+
+	branch, err := s.findBranch(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	branch.Name = request.Name
+	branch.Code = strings.ToLower(request.Code)
+	branch.Address = request.Address
+
+	if err = s.branchRepository.Update(ctx, &branch); err != nil {
+		if strings.Contains(err.Error(), constant.BranchTenantCodeIndex) {
+			return nil, status.Errorf(codes.AlreadyExists, "branch with %s code already exists", branch.Code)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoBranch(branch), nil
+}
+
+func (s *BookGRPCService) findBranch(ctx context.Context, id string) (domain.Branch, error) {
+	branch, err := s.branchRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return domain.Branch{}, status.Errorf(codes.NotFound, "branch with %s ID is not found", id)
+		}
+		return domain.Branch{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return branch, nil
+}
+
+// findBranchOrDefault returns the default branch for an empty ID,
+// so the clients that are not aware of the branches keep working on the default one
+func (s *BookGRPCService) findBranchOrDefault(ctx context.Context, id string) (domain.Branch, error) {
+	if id != "" {
+		return s.findBranch(ctx, id)
+	}
+
+	branch, err := s.branchRepository.FindDefault(ctx)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		branch, err = s.createDefaultBranch(ctx)
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Branch{}, status.Error(codes.FailedPrecondition, "default branch is not found")
+		}
+		return domain.Branch{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return branch, nil
+}
+
+// createDefaultBranch gives a new tenant its default branch on first use,
+// the branch created by a concurrent request is returned on a code conflict
+func (s *BookGRPCService) createDefaultBranch(ctx context.Context) (domain.Branch, error) {
+	branch := domain.Branch{
+		Name:    defaultBranchName,
+		Code:    defaultBranchCode,
+		Default: true,
+	}
+
+	err := s.branchRepository.Create(ctx, &branch)
+	if err != nil && strings.Contains(err.Error(), constant.BranchTenantCodeIndex) {
+		return s.branchRepository.FindDefault(ctx)
+	}
+
+	return branch, err
+}
+
+func toProtoBranch(branch domain.Branch) *proto.Branch {
+	return &proto.Branch{
+		Id:      branch.ID.Hex(),
+		Name:    branch.Name,
+		Code:    branch.Code,
+		Address: branch.Address,
+		Default: branch.Default,
+	}
+}
+
+func toProtoBranchStocks(branchStocks []domain.BranchStock) []*proto.BranchStock {
+	protoBranchStocks := make([]*proto.BranchStock, 0, len(branchStocks))
+	for _, branchStock := range branchStocks {
+		protoBranchStocks = append(protoBranchStocks, &proto.BranchStock{
+			BranchId: branchStock.BranchID.Hex(),
+			Stock:    int32(branchStock.Stock),
+		})
+	}
+
+	return protoBranchStocks
+}
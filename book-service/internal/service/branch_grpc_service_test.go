@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/pkg/proto"
+	"book-service/pkg/tenant"
+)
+
+func TestBookGRPCService_CreateBranch(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		request        *proto.CreateBranchRequest
+		wantCode       codes.Code
+		wantBranchCode string
+	}{
+		{
+			name:           "lowercases the code",
+			ctx:            context.Background(),
+			request:        &proto.CreateBranchRequest{Name: "East", Code: "EAST", Address: "1 East Street"},
+			wantBranchCode: "east",
+		},
+		{
+			name:     "code already exists",
+			ctx:      context.Background(),
+			request:  &proto.CreateBranchRequest{Name: "East Side", Code: "east"},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:           "same code in another tenant",
+			ctx:            tenant.NewContext(context.Background(), "other"),
+			request:        &proto.CreateBranchRequest{Name: "East", Code: "east"},
+			wantBranchCode: "east",
+		},
+		{
+			name:     "without code",
+			ctx:      context.Background(),
+			request:  &proto.CreateBranchRequest{Name: "East"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CreateBranch(tt.ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateBranch() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && (got.Id == "" || got.Code != tt.wantBranchCode || got.Default) {
+				t.Errorf("CreateBranch() = %+v, want code %s", got, tt.wantBranchCode)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FetchBranch(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	for _, request := range []*proto.CreateBranchRequest{
+		{Name: "West", Code: "west"},
+		{Name: "East", Code: "east"},
+		{Name: "North East", Code: "north-east"},
+	} {
+		if _, err := s.CreateBranch(ctx, request); err != nil {
+			t.Fatalf("CreateBranch() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		request   *proto.FetchBranchRequest
+		wantCodes []string
+		wantTotal int32
+	}{
+		{
+			name:      "by code",
+			request:   &proto.FetchBranchRequest{Pagination: &proto.BookPaginationRequest{Limit: 2, Page: 1}},
+			wantCodes: []string{"east", "north-east"},
+			wantTotal: 3,
+		},
+		{
+			name:      "by page",
+			request:   &proto.FetchBranchRequest{Pagination: &proto.BookPaginationRequest{Limit: 2, Page: 2}},
+			wantCodes: []string{"west"},
+			wantTotal: 3,
+		},
+		{
+			name:      "by name",
+			request:   &proto.FetchBranchRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, Name: "east"},
+			wantCodes: []string{"east", "north-east"},
+			wantTotal: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FetchBranch(ctx, tt.request)
+			if err != nil {
+				t.Fatalf("FetchBranch() error = %v", err)
+			}
+
+			branchCodes := make([]string, 0, len(got.Branches))
+			for _, branch := range got.Branches {
+				branchCodes = append(branchCodes, branch.Code)
+			}
+			if !equalStrings(branchCodes, tt.wantCodes) || got.Pagination.Total != tt.wantTotal {
+				t.Errorf("FetchBranch() codes = %v, total = %d, want %v, %d", branchCodes, got.Pagination.Total, tt.wantCodes, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FindBranchByID(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+
+	branch, err := s.CreateBranch(context.Background(), &proto.CreateBranchRequest{Name: "East", Code: "east"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode codes.Code
+	}{
+		{
+			name: "found",
+			id:   branch.Id,
+		},
+		{
+			name:     "not found",
+			id:       primitive.NewObjectID().Hex(),
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindBranchByID(context.Background(), &proto.FindBranchByIDRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FindBranchByID() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && got.Code != branch.Code {
+				t.Errorf("FindBranchByID() = %+v, want %+v", got, branch)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FindDefaultBranch(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{
+			name: "created on first use",
+			ctx:  context.Background(),
+		},
+		{
+			name: "reused",
+			ctx:  context.Background(),
+		},
+		{
+			name: "per tenant",
+			ctx:  tenant.NewContext(context.Background(), "other"),
+		},
+	}
+
+	ids := make(map[string]bool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindDefaultBranch(tt.ctx, &proto.FindDefaultBranchRequest{})
+			if err != nil {
+				t.Fatalf("FindDefaultBranch() error = %v", err)
+			}
+			if !got.Default || got.Code != defaultBranchCode {
+				t.Errorf("FindDefaultBranch() = %+v, want the default branch", got)
+			}
+			ids[got.Id] = true
+		})
+	}
+
+	if len(ids) != 2 {
+		t.Errorf("FindDefaultBranch() created %d default branches, want 2", len(ids))
+	}
+}
+
+func TestBookGRPCService_UpdateBranch(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: "East", Code: "east"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	if _, err = s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: "West", Code: "west"}); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		request  *proto.UpdateBranchRequest
+		wantCode codes.Code
+	}{
+		{
+			name:    "keeps its own code",
+			request: &proto.UpdateBranchRequest{Id: branch.Id, Name: "East Side", Code: "east", Address: "1 East Street"},
+		},
+		{
+			name:    "new code",
+			request: &proto.UpdateBranchRequest{Id: branch.Id, Name: "East Side", Code: "east-side"},
+		},
+		{
+			name:     "code of another branch",
+			request:  &proto.UpdateBranchRequest{Id: branch.Id, Name: "East Side", Code: "WEST"},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "without name",
+			request:  &proto.UpdateBranchRequest{Id: branch.Id, Code: "east"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "not found",
+			request:  &proto.UpdateBranchRequest{Id: primitive.NewObjectID().Hex(), Name: "East", Code: "east"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.UpdateBranch(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateBranch() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindBranchByID(ctx, &proto.FindBranchByIDRequest{Id: branch.Id})
+			if err != nil {
+				t.Fatalf("FindBranchByID() error = %v", err)
+			}
+			if got.Name != tt.request.Name || got.Code != tt.request.Code || got.Address != tt.request.Address {
+				t.Errorf("UpdateBranch() = %+v, want %+v", got, tt.request)
+			}
+		})
+	}
+}
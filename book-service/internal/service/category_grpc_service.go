@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/domain"
+	"book-service/pkg/proto"
+)
+
+func (s *BookGRPCService) CreateCategory(ctx context.Context, request *proto.CreateCategoryRequest) (*proto.Category, error) {
+	if request.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "category name is required")
+	}
+
+	category := domain.Category{
+		Name:      request.Name,
+		Code:      request.Code,
+		Ancestors: make([]primitive.ObjectID, 0),
+		LoanDays:  int(request.LoanDays),
+	}
+
+	if request.ParentId != "" {
+		parent, err := s.findCategory(ctx, request.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = &parent.ID
+		category.Ancestors = append(parent.Ancestors, parent.ID)
+	}
+
+	if err := s.categoryRepository.Create(ctx, &category); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoCategory(category), nil
+}
+
+func (s *BookGRPCService) FetchCategory(ctx context.Context, request *proto.FetchCategoryRequest) (*proto.FetchCategoryResponse, error) {
+	page, limit := request.Pagination.Page, request.Pagination.Limit
+	fetchFilter := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+	if parentID := request.ParentId; parentID != "" {
+		fetchFilter["parent_id"] = parentID
+	}
+	if name := request.Name; name != "" {
+		fetchFilter["name"] = name
+	}
+
+	categories, err := s.categoryRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoCategories := make([]*proto.Category, 0)
+	for _, category := range categories {
+		protoCategories = append(protoCategories, toProtoCategory(category))
+	}
+
+	total, err := s.categoryRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.FetchCategoryResponse{
+		Pagination: &proto.BookPaginationResponse{
+			Limit:    limit,
+			Page:     page,
+			LastPage: int32(math.Ceil(float64(total) / float64(limit))),
+			Total:    int32(total),
+		},
+		Categories: protoCategories,
+	}, nil
+}
+
+func (s *BookGRPCService) FindCategoryByID(ctx context.Context, request *proto.FindCategoryByIDRequest) (*proto.Category, error) {
+	category, err := s.findCategory(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoCategory(category), nil
+}
+
+func (s *BookGRPCService) UpdateCategory(ctx context.Context, request *proto.UpdateCategoryRequest) (*proto.Category, error) {
+	if request.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "category name is required")
+	}
+
+	category, err := s.findCategory(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Name = request.Name
+	category.Code = request.Code
+	category.LoanDays = int(request.LoanDays)
+
+	if request.ParentId != objectIDHex(category.ParentID) {
+		if err = s.moveCategory(ctx, &category, request.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.categoryRepository.Update(ctx, &category); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoCategory(category), nil
+}
+
+// moveCategory changes the category parent and rewrites the ancestors of the category subtree
+func (s *BookGRPCService) moveCategory(ctx context.Context, category *domain.Category, parentID string) error {
+	var (
+		newParentID  *primitive.ObjectID
+		newAncestors = make([]primitive.ObjectID, 0)
+	)
+	if parentID != "" {
+		parent, err := s.findCategory(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if parent.ID == category.ID || containsObjectID(parent.Ancestors, category.ID) {
+			return status.Error(codes.InvalidArgument, "category cannot be moved under itself or its descendant")
+		}
+		newParentID = &parent.ID
+		newAncestors = append(parent.Ancestors, parent.ID)
+	}
+
+	descendants, err := s.fetchAllCategories(ctx, map[string]interface{}{
+		"ancestor_id": category.ID.Hex(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, descendant := range descendants {
+		for i, ancestorID := range descendant.Ancestors {
+			if ancestorID == category.ID {
+				ancestors := append([]primitive.ObjectID{}, newAncestors...)
+				descendant.Ancestors = append(append(ancestors, category.ID), descendant.Ancestors[i+1:]...)
+				break
+			}
+		}
+
+		if err = s.categoryRepository.Update(ctx, &descendant); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	category.ParentID = newParentID
+	category.Ancestors = newAncestors
+	return nil
+}
+
+func (s *BookGRPCService) DeleteCategory(ctx context.Context, request *proto.DeleteCategoryRequest) (*proto.DeleteCategoryResponse, error) {
+	category, err := s.findCategory(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.categoryRepository.Count(ctx, map[string]interface{}{
+		"parent_id": category.ID.Hex(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if children > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "category with %s ID still has %d child categories", request.Id, children)
+	}
+
+	books, err := s.bookRepository.Count(ctx, map[string]interface{}{
+		"category_ids": []primitive.ObjectID{category.ID},
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if books > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "category with %s ID still has %d books", request.Id, books)
+	}
+
+	if err = s.categoryRepository.Delete(ctx, &category); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.DeleteCategoryResponse{}, nil
+}
+
+// BrowseCategory returns the child categories of a parent, or the root categories,
+// with the number of books classified under each child subtree
+func (s *BookGRPCService) BrowseCategory(ctx context.Context, request *proto.BrowseCategoryRequest) (*proto.BrowseCategoryResponse, error) {
+	var (
+		response          = new(proto.BrowseCategoryResponse)
+		descendantsFilter = map[string]interface{}{}
+	)
+	if request.ParentId != "" {
+		parent, err := s.findCategory(ctx, request.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		response.Parent = toProtoCategory(parent)
+		descendantsFilter["ancestor_id"] = parent.ID.Hex()
+	}
+
+	descendants, err := s.fetchAllCategories(ctx, descendantsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	bookCounts, err := s.bookRepository.CountByCategory(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response.Children = make([]*proto.CategoryCount, 0)
+	for _, child := range descendants {
+		if objectIDHex(child.ParentID) != request.ParentId {
+			continue
+		}
+
+		categoryCount := &proto.CategoryCount{
+			Category:  toProtoCategory(child),
+			BookCount: int32(bookCounts[child.ID]),
+		}
+		for _, descendant := range descendants {
+			if !containsObjectID(descendant.Ancestors, child.ID) {
+				continue
+			}
+			categoryCount.BookCount += int32(bookCounts[descendant.ID])
+			if descendant.ParentID != nil && *descendant.ParentID == child.ID {
+				categoryCount.ChildCount++
+			}
+		}
+
+		response.Children = append(response.Children, categoryCount)
+	}
+
+	return response, nil
+}
+
+func (s *BookGRPCService) findCategory(ctx context.Context, id string) (domain.Category, error) {
+	category, err := s.categoryRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Category{}, status.Errorf(codes.NotFound, "category with %s ID is not found", id)
+		}
+		return domain.Category{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return category, nil
+}
+
+// findCategoryID validates the category of a book, an empty ID leaves the book uncategorized
+func (s *BookGRPCService) findCategoryID(ctx context.Context, id string) (*primitive.ObjectID, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	category, err := s.findCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category.ID, nil
+}
+
+// categoryTreeIDs returns the category ID with all of its descendant IDs
+func (s *BookGRPCService) categoryTreeIDs(ctx context.Context, id string) ([]primitive.ObjectID, error) {
+	category, err := s.findCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.fetchAllCategories(ctx, map[string]interface{}{
+		"ancestor_id": category.ID.Hex(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := []primitive.ObjectID{category.ID}
+	for _, descendant := range descendants {
+		categoryIDs = append(categoryIDs, descendant.ID)
+	}
+
+	return categoryIDs, nil
+}
+
+func (s *BookGRPCService) fetchAllCategories(ctx context.Context, fetchFilter map[string]interface{}) ([]domain.Category, error) {
+	total, err := s.categoryRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if total == 0 {
+		return []domain.Category{}, nil
+	}
+
+	fetchFilter["limit"] = int32(total)
+	categories, err := s.categoryRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return categories, nil
+}
+
+func toProtoCategory(category domain.Category) *proto.Category {
+	ancestorIDs := make([]string, 0, len(category.Ancestors))
+	for _, ancestorID := range category.Ancestors {
+		ancestorIDs = append(ancestorIDs, ancestorID.Hex())
+	}
+
+	return &proto.Category{
+		Id:          category.ID.Hex(),
+		Name:        category.Name,
+		Code:        category.Code,
+		ParentId:    objectIDHex(category.ParentID),
+		AncestorIds: ancestorIDs,
+		LoanDays:    int32(category.LoanDays),
+	}
+}
+
+// normalizeTags lowercases, trims and deduplicates free-form book tags
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+
+	return normalized
+}
+
+func objectIDHex(id *primitive.ObjectID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Hex()
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
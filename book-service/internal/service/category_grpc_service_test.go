@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/pkg/proto"
+)
+
+func createTestCategory(t *testing.T, s *BookGRPCService, request *proto.CreateCategoryRequest) *proto.Category {
+	t.Helper()
+
+	category, err := s.CreateCategory(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	return category
+}
+
+func TestBookGRPCService_CreateCategory(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	root := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+	child := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "American", Code: "810", ParentId: root.Id})
+
+	tests := []struct {
+		name            string
+		request         *proto.CreateCategoryRequest
+		wantCode        codes.Code
+		wantAncestorIDs []string
+	}{
+		{
+			name:            "root",
+			request:         &proto.CreateCategoryRequest{Name: "Science", Code: "500", LoanDays: 7},
+			wantAncestorIDs: []string{},
+		},
+		{
+			name:            "under a child",
+			request:         &proto.CreateCategoryRequest{Name: "Fiction", Code: "813", ParentId: child.Id},
+			wantAncestorIDs: []string{root.Id, child.Id},
+		},
+		{
+			name:     "without name",
+			request:  &proto.CreateCategoryRequest{Code: "500"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown parent",
+			request:  &proto.CreateCategoryRequest{Name: "Fiction", ParentId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CreateCategory(context.Background(), tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateCategory() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Name != tt.request.Name || got.ParentId != tt.request.ParentId || got.LoanDays != tt.request.LoanDays ||
+				!equalStrings(got.AncestorIds, tt.wantAncestorIDs) {
+				t.Errorf("CreateCategory() = %+v, want ancestors %v", got, tt.wantAncestorIDs)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FetchCategory(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	root := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+	createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Science", Code: "500"})
+	createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "American literature", Code: "810", ParentId: root.Id})
+
+	tests := []struct {
+		name      string
+		request   *proto.FetchCategoryRequest
+		wantCodes []string
+		wantTotal int32
+	}{
+		{
+			name:      "by code",
+			request:   &proto.FetchCategoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}},
+			wantCodes: []string{"500", "800", "810"},
+			wantTotal: 3,
+		},
+		{
+			name:      "children",
+			request:   &proto.FetchCategoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, ParentId: root.Id},
+			wantCodes: []string{"810"},
+			wantTotal: 1,
+		},
+		{
+			name:      "by name",
+			request:   &proto.FetchCategoryRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, Name: "literature"},
+			wantCodes: []string{"800", "810"},
+			wantTotal: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FetchCategory(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("FetchCategory() error = %v", err)
+			}
+
+			categoryCodes := make([]string, 0, len(got.Categories))
+			for _, category := range got.Categories {
+				categoryCodes = append(categoryCodes, category.Code)
+			}
+			if !equalStrings(categoryCodes, tt.wantCodes) || got.Pagination.Total != tt.wantTotal {
+				t.Errorf("FetchCategory() codes = %v, total = %d, want %v, %d", categoryCodes, got.Pagination.Total, tt.wantCodes, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FindCategoryByID(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	category := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode codes.Code
+	}{
+		{
+			name: "found",
+			id:   category.Id,
+		},
+		{
+			name:     "not found",
+			id:       primitive.NewObjectID().Hex(),
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindCategoryByID(context.Background(), &proto.FindCategoryByIDRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FindCategoryByID() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && got.Name != category.Name {
+				t.Errorf("FindCategoryByID() = %+v, want %+v", got, category)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_UpdateCategory(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	literature := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+	arts := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Arts", Code: "700"})
+	american := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "American", Code: "810", ParentId: literature.Id})
+	fiction := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Fiction", Code: "813", ParentId: american.Id})
+
+	tests := []struct {
+		name                      string
+		request                   *proto.UpdateCategoryRequest
+		wantCode                  codes.Code
+		wantDescendantAncestorIDs []string
+	}{
+		{
+			name:                      "renames",
+			request:                   &proto.UpdateCategoryRequest{Id: american.Id, Name: "American literature", Code: "810", ParentId: literature.Id, LoanDays: 14},
+			wantDescendantAncestorIDs: []string{literature.Id, american.Id},
+		},
+		{
+			name:                      "moves the subtree",
+			request:                   &proto.UpdateCategoryRequest{Id: american.Id, Name: "American", Code: "810", ParentId: arts.Id},
+			wantDescendantAncestorIDs: []string{arts.Id, american.Id},
+		},
+		{
+			name:                      "moves the subtree to the root",
+			request:                   &proto.UpdateCategoryRequest{Id: american.Id, Name: "American", Code: "810"},
+			wantDescendantAncestorIDs: []string{american.Id},
+		},
+		{
+			name:     "under its descendant",
+			request:  &proto.UpdateCategoryRequest{Id: american.Id, Name: "American", Code: "810", ParentId: fiction.Id},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "under itself",
+			request:  &proto.UpdateCategoryRequest{Id: american.Id, Name: "American", Code: "810", ParentId: american.Id},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "without name",
+			request:  &proto.UpdateCategoryRequest{Id: american.Id},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "not found",
+			request:  &proto.UpdateCategoryRequest{Id: primitive.NewObjectID().Hex(), Name: "American"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.UpdateCategory(context.Background(), tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateCategory() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Name != tt.request.Name || got.ParentId != tt.request.ParentId || got.LoanDays != tt.request.LoanDays {
+				t.Errorf("UpdateCategory() = %+v, want %+v", got, tt.request)
+			}
+
+			descendant, err := s.FindCategoryByID(context.Background(), &proto.FindCategoryByIDRequest{Id: fiction.Id})
+			if err != nil {
+				t.Fatalf("FindCategoryByID() error = %v", err)
+			}
+			if !equalStrings(descendant.AncestorIds, tt.wantDescendantAncestorIDs) {
+				t.Errorf("UpdateCategory() descendant ancestors = %v, want %v", descendant.AncestorIds, tt.wantDescendantAncestorIDs)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_DeleteCategory(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	parent := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+	createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "American", Code: "810", ParentId: parent.Id})
+	classified := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Science", Code: "500"})
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "Cosmos", CategoryId: classified.Id})
+	empty := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Arts", Code: "700"})
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode codes.Code
+	}{
+		{
+			name: "empty category",
+			id:   empty.Id,
+		},
+		{
+			name:     "already deleted",
+			id:       empty.Id,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "with child categories",
+			id:       parent.Id,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "with books",
+			id:       classified.Id,
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.DeleteCategory(context.Background(), &proto.DeleteCategoryRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("DeleteCategory() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_BrowseCategory(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	literature := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+	american := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "American", Code: "810", ParentId: literature.Id})
+	fiction := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Fiction", Code: "813", ParentId: american.Id})
+	science := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Science", Code: "500"})
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "Leaves of Grass", CategoryId: american.Id})
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "Beloved", CategoryId: fiction.Id})
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "Moby Dick", CategoryId: fiction.Id})
+
+	tests := []struct {
+		name       string
+		parentID   string
+		wantCode   codes.Code
+		wantParent string
+		want       map[string][2]int32
+	}{
+		{
+			name: "roots",
+			want: map[string][2]int32{
+				literature.Id: {3, 1},
+				science.Id:    {0, 0},
+			},
+		},
+		{
+			name:       "children with the books of the subtree",
+			parentID:   literature.Id,
+			wantParent: literature.Id,
+			want: map[string][2]int32{
+				american.Id: {3, 1},
+			},
+		},
+		{
+			name:       "leaf",
+			parentID:   fiction.Id,
+			wantParent: fiction.Id,
+			want:       map[string][2]int32{},
+		},
+		{
+			name:     "unknown parent",
+			parentID: primitive.NewObjectID().Hex(),
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.BrowseCategory(context.Background(), &proto.BrowseCategoryRequest{ParentId: tt.parentID})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("BrowseCategory() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Parent.GetId() != tt.wantParent {
+				t.Errorf("BrowseCategory() parent = %v, want %s", got.Parent, tt.wantParent)
+			}
+			if len(got.Children) != len(tt.want) {
+				t.Fatalf("BrowseCategory() children = %v, want %v", got.Children, tt.want)
+			}
+			for _, child := range got.Children {
+				want, ok := tt.want[child.Category.Id]
+				if !ok || child.BookCount != want[0] || child.ChildCount != want[1] {
+					t.Errorf("BrowseCategory() child %s = %d books, %d children, want %v", child.Category.Name, child.BookCount, child.ChildCount, want)
+				}
+			}
+		})
+	}
+}
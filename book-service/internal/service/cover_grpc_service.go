@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/cover"
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/blob"
+	"book-service/pkg/logger"
+	"book-service/pkg/proto"
+)
+
+const coverChunkSize = 64 << 10
+
+// UploadBookCover stores the original image with its thumbnails, then replaces the previous cover of the book
+func (s *BookGRPCService) UploadBookCover(stream proto.BookService_UploadBookCoverServer) error {
+	ctx := stream.Context()
+
+	var (
+		bookID string
+		data   bytes.Buffer
+	)
+	for {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if bookID == "" {
+			bookID = request.BookId
+		}
+		if data.Len()+len(request.Chunk) > cover.MaxSize {
+			return status.Errorf(codes.InvalidArgument, "cover image is larger than %d MB", cover.MaxSize>>20)
+		}
+		data.Write(request.Chunk)
+	}
+
+	book, err := s.bookRepository.FindByID(ctx, bookID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return status.Errorf(codes.NotFound, "book with %s ID is not found", bookID)
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	contentType, err := cover.DetectContentType(data.Bytes())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	thumbnails, err := cover.Thumbnails(data.Bytes())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid cover image: %v", err)
+	}
+
+	checksum := sha256.Sum256(data.Bytes())
+	bookCover := &domain.BookCover{
+		Version:     hex.EncodeToString(checksum[:8]),
+		ContentType: contentType,
+		UpdatedAt:   time.Now(),
+	}
+
+	err = s.blobStore.Put(ctx, coverKey(bookID, bookCover, constant.CoverOriginal), bytes.NewReader(data.Bytes()), contentType)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	for size, thumbnail := range thumbnails {
+		err = s.blobStore.Put(ctx, coverKey(bookID, bookCover, size), bytes.NewReader(thumbnail), "image/jpeg")
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	updated, err := s.bookRepository.UpdateCover(ctx, bookID, bookCover)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	// the same image keeps the same version, so there is nothing to remove
+	if previous := book.Cover; previous != nil && previous.Version != bookCover.Version {
+		s.deleteCover(ctx, bookID, previous)
+	}
+
+	return stream.SendAndClose(toProtoBook(updated))
+}
+
+// FetchBookCover streams the cover image of the requested size, medium by default
+func (s *BookGRPCService) FetchBookCover(request *proto.FetchBookCoverRequest, stream proto.BookService_FetchBookCoverServer) error {
+	ctx := stream.Context()
+
+	size := constant.CoverSize(request.Size)
+	if size == "" {
+		size = constant.CoverMedium
+	}
+	if !size.Valid() {
+		return status.Errorf(codes.InvalidArgument, "invalid cover size: %s", request.Size)
+	}
+
+	book, err := s.bookRepository.FindByID(ctx, request.BookId)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return status.Errorf(codes.NotFound, "book with %s ID is not found", request.BookId)
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	if book.Cover == nil {
+		return status.Errorf(codes.NotFound, "book with %s ID has no cover", request.BookId)
+	}
+
+	reader, attributes, err := s.blobStore.Get(ctx, coverKey(request.BookId, book.Cover, size))
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return status.Errorf(codes.NotFound, "%s cover of book with %s ID is not found", size, request.BookId)
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer reader.Close()
+
+	response := &proto.FetchBookCoverResponse{
+		ContentType: attributes.ContentType,
+		Version:     book.Cover.Version,
+	}
+	chunk := make([]byte, coverChunkSize)
+	for {
+		n, err := reader.Read(chunk)
+		if n > 0 {
+			response.Chunk = chunk[:n]
+			if err := stream.Send(response); err != nil {
+				return err
+			}
+			response = &proto.FetchBookCoverResponse{}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s *BookGRPCService) deleteCover(ctx context.Context, bookID string, bookCover *domain.BookCover) {
+	keys := []string{coverKey(bookID, bookCover, constant.CoverOriginal)}
+	for size := range cover.Widths {
+		keys = append(keys, coverKey(bookID, bookCover, size))
+	}
+
+	for _, key := range keys {
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Str("book_id", bookID).Str("key", key).Msg("Error deleting previous cover")
+		}
+	}
+}
+
+// coverKey is versioned, e.g. covers/<book ID>/<version>/small.jpg
+func coverKey(bookID string, bookCover *domain.BookCover, size constant.CoverSize) string {
+	extension := ".jpg"
+	if size == constant.CoverOriginal {
+		extension = cover.Extension(bookCover.ContentType)
+	}
+	return fmt.Sprintf("covers/%s/%s/%s%s", bookID, bookCover.Version, size, extension)
+}
+
+func coverVersion(bookCover *domain.BookCover) string {
+	if bookCover == nil {
+		return ""
+	}
+	return bookCover.Version
+}
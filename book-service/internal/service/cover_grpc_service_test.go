@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/domain/constant"
+	"book-service/pkg/proto"
+)
+
+// uploadBookCoverServerStub receives the requests in order, then io.EOF
+type uploadBookCoverServerStub struct {
+	serverStreamStub
+	requests []*proto.UploadBookCoverRequest
+	response *proto.Book
+}
+
+func (s *uploadBookCoverServerStub) Recv() (*proto.UploadBookCoverRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+
+	request := s.requests[0]
+	s.requests = s.requests[1:]
+	return request, nil
+}
+
+func (s *uploadBookCoverServerStub) SendAndClose(book *proto.Book) error {
+	s.response = book
+	return nil
+}
+
+// fetchBookCoverServerStub collects the sent chunks
+type fetchBookCoverServerStub struct {
+	serverStreamStub
+	contentType string
+	data        bytes.Buffer
+}
+
+func (s *fetchBookCoverServerStub) Send(response *proto.FetchBookCoverResponse) error {
+	if response.ContentType != "" {
+		s.contentType = response.ContentType
+	}
+	s.data.Write(response.Chunk)
+	return nil
+}
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image.NewNRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func uploadTestCover(s *BookGRPCService, bookID string, data []byte) (*proto.Book, error) {
+	stream := &uploadBookCoverServerStub{requests: []*proto.UploadBookCoverRequest{
+		{BookId: bookID, Chunk: data[:len(data)/2]},
+		{Chunk: data[len(data)/2:]},
+	}}
+	err := s.UploadBookCover(stream)
+	return stream.response, err
+}
+
+func TestBookGRPCService_UploadBookCover(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Dune"})
+
+	tests := []struct {
+		name     string
+		bookID   string
+		data     []byte
+		wantCode codes.Code
+	}{
+		{
+			name:   "PNG",
+			bookID: book.Id,
+			data:   encodeTestPNG(t, 300, 450),
+		},
+		{
+			name:   "replaces the previous cover",
+			bookID: book.Id,
+			data:   encodeTestPNG(t, 200, 300),
+		},
+		{
+			name:     "not an image",
+			bookID:   book.Id,
+			data:     []byte("%PDF-1.4 not a cover"),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "book not found",
+			bookID:   primitive.NewObjectID().Hex(),
+			data:     encodeTestPNG(t, 10, 10),
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uploadTestCover(s, tt.bookID, tt.data)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UploadBookCover() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.CoverVersion == "" {
+				t.Fatalf("UploadBookCover() = %+v, want a cover version", got)
+			}
+
+			stream := &fetchBookCoverServerStub{}
+			err = s.FetchBookCover(&proto.FetchBookCoverRequest{BookId: tt.bookID, Size: string(constant.CoverOriginal)}, stream)
+			if err != nil {
+				t.Fatalf("FetchBookCover() error = %v", err)
+			}
+			if stream.contentType != "image/png" || !bytes.Equal(stream.data.Bytes(), tt.data) {
+				t.Errorf("FetchBookCover() = %s of %d bytes, want the uploaded %d bytes", stream.contentType, stream.data.Len(), len(tt.data))
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FetchBookCover(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	book := createTestBook(t, s, &proto.CreateBookRequest{Title: "Dune"})
+	withoutCover := createTestBook(t, s, &proto.CreateBookRequest{Title: "Emma"})
+	if _, err := uploadTestCover(s, book.Id, encodeTestPNG(t, 300, 450)); err != nil {
+		t.Fatalf("UploadBookCover() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		request         *proto.FetchBookCoverRequest
+		wantCode        codes.Code
+		wantContentType string
+		wantBounds      image.Point
+	}{
+		{
+			name:            "medium by default",
+			request:         &proto.FetchBookCoverRequest{BookId: book.Id},
+			wantContentType: "image/jpeg",
+			wantBounds:      image.Pt(240, 360),
+		},
+		{
+			name:            "small",
+			request:         &proto.FetchBookCoverRequest{BookId: book.Id, Size: string(constant.CoverSmall)},
+			wantContentType: "image/jpeg",
+			wantBounds:      image.Pt(96, 144),
+		},
+		{
+			name:            "original",
+			request:         &proto.FetchBookCoverRequest{BookId: book.Id, Size: string(constant.CoverOriginal)},
+			wantContentType: "image/png",
+			wantBounds:      image.Pt(300, 450),
+		},
+		{
+			name:     "invalid size",
+			request:  &proto.FetchBookCoverRequest{BookId: book.Id, Size: "huge"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "without cover",
+			request:  &proto.FetchBookCoverRequest{BookId: withoutCover.Id},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "book not found",
+			request:  &proto.FetchBookCoverRequest{BookId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &fetchBookCoverServerStub{}
+			err := s.FetchBookCover(tt.request, stream)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FetchBookCover() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			config, _, err := image.DecodeConfig(bytes.NewReader(stream.data.Bytes()))
+			if err != nil {
+				t.Fatalf("DecodeConfig() error = %v", err)
+			}
+			if stream.contentType != tt.wantContentType || config.Width != tt.wantBounds.X || config.Height != tt.wantBounds.Y {
+				t.Errorf("FetchBookCover() = %s %dx%d, want %s %v", stream.contentType, config.Width, config.Height, tt.wantContentType, tt.wantBounds)
+			}
+		})
+	}
+
+}
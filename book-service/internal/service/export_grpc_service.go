@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bufio"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/catalog"
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/proto"
+)
+
+const exportChunkSize = 64 << 10
+
+// ExportBooks streams the whole catalog matching the filter as a file in the requested format
+func (s *BookGRPCService) ExportBooks(request *proto.ExportBooksRequest, stream proto.BookService_ExportBooksServer) error {
+	ctx := stream.Context()
+
+	format := constant.ExportFormat(request.Format)
+	if !format.Valid() {
+		return status.Errorf(codes.InvalidArgument, "unsupported export format: %s", request.Format)
+	}
+
+	fetchFilter, err := s.bookFetchFilter(ctx, request.Title, request.CategoryId, request.Tags)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.fetchAllCategories(ctx, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	categoryCodes := make(map[string]string, len(categories))
+	for _, category := range categories {
+		categoryCodes[category.ID.Hex()] = category.Code
+	}
+
+	buffer := bufio.NewWriterSize(exportStreamWriter{stream: stream}, exportChunkSize)
+	writer, err := catalog.NewWriter(format, buffer)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.bookRepository.FetchEach(ctx, fetchFilter, func(book domain.Book) error {
+		return writer.Write(catalog.Record{
+			ID:           book.ID.Hex(),
+			Title:        book.Title,
+			ISBN:         book.ISBN,
+			Author:       book.Author,
+			Stock:        book.Stock,
+			CategoryCode: categoryCodes[objectIDHex(book.CategoryID)],
+			Tags:         book.Tags,
+		})
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		err = buffer.Flush()
+	}
+	if err != nil {
+		// an error of sending the chunk is already a status error
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// exportStreamWriter sends every write as a chunk, it is buffered to send chunks of exportChunkSize
+type exportStreamWriter struct {
+	stream proto.BookService_ExportBooksServer
+}
+
+func (w exportStreamWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&proto.ExportBooksResponse{Chunk: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
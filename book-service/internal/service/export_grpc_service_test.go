@@ -0,0 +1,106 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/domain/constant"
+	"book-service/pkg/proto"
+)
+
+// exportBooksServerStub collects the sent chunks
+type exportBooksServerStub struct {
+	serverStreamStub
+	file bytes.Buffer
+}
+
+func (s *exportBooksServerStub) Send(response *proto.ExportBooksResponse) error {
+	s.file.Write(response.Chunk)
+	return nil
+}
+
+func TestBookGRPCService_ExportBooks(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	category := createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+	dune := createTestBook(t, s, &proto.CreateBookRequest{
+		Title: "Dune", Isbn: "9780306406157", Author: "Frank Herbert", CategoryId: category.Id, Tags: []string{"sci-fi", "classic"},
+	})
+	createTestBook(t, s, &proto.CreateBookRequest{Title: "Emma"})
+
+	tests := []struct {
+		name      string
+		request   *proto.ExportBooksRequest
+		wantCode  codes.Code
+		wantLines []string
+	}{
+		{
+			name:    "CSV",
+			request: &proto.ExportBooksRequest{Format: string(constant.ExportCSV), Title: "dune"},
+			wantLines: []string{
+				"id,title,isbn,author,stock,category_code,tags",
+				dune.Id + ",Dune,9780306406157,Frank Herbert,0,800,classic;sci-fi",
+			},
+		},
+		{
+			name:      "empty CSV",
+			request:   &proto.ExportBooksRequest{Format: string(constant.ExportCSV), Title: "nothing"},
+			wantLines: []string{"id,title,isbn,author,stock,category_code,tags"},
+		},
+		{
+			name:    "JSON lines",
+			request: &proto.ExportBooksRequest{Format: string(constant.ExportJSONLines), CategoryId: category.Id},
+			wantLines: []string{
+				`{"id":"` + dune.Id + `","title":"Dune","isbn":"9780306406157","author":"Frank Herbert","stock":0,"category_code":"800","tags":["classic","sci-fi"]}`,
+			},
+		},
+		{
+			name:     "unsupported format",
+			request:  &proto.ExportBooksRequest{Format: "xlsx"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &exportBooksServerStub{}
+			err := s.ExportBooks(tt.request, stream)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("ExportBooks() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			lines := strings.Split(strings.TrimSpace(stream.file.String()), "\n")
+			if !equalStrings(lines, tt.wantLines) {
+				t.Errorf("ExportBooks() = %q, want %q", lines, tt.wantLines)
+			}
+		})
+	}
+
+	// the exported catalog is imported again as duplicates of the existing books
+	t.Run("round trip", func(t *testing.T) {
+		stream := &exportBooksServerStub{}
+		if err := s.ExportBooks(&proto.ExportBooksRequest{Format: string(constant.ExportCSV)}, stream); err != nil {
+			t.Fatalf("ExportBooks() error = %v", err)
+		}
+
+		job, err := s.CreateImportJob(ctx, &proto.CreateImportJobRequest{Format: string(constant.ImportCSV)})
+		if err != nil {
+			t.Fatalf("CreateImportJob() error = %v", err)
+		}
+		importStream := &importBooksServerStub{requests: importChunks(job.Id, stream.file.String())}
+		if err = s.ImportBooks(importStream); err != nil {
+			t.Fatalf("ImportBooks() error = %v", err)
+		}
+		if got := importStream.response; got.Processed != 2 || got.Skipped != 2 {
+			t.Errorf("ImportBooks() = %+v, want 2 skipped duplicates", got)
+		}
+	})
+}
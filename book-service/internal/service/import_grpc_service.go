@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"book-service/internal/catalog"
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/detached"
+	"book-service/pkg/logger"
+	"book-service/pkg/proto"
+)
+
+const (
+	importProgressInterval = 50
+	maxImportRowErrors     = 1000
+)
+
+func (s *BookGRPCService) CreateImportJob(ctx context.Context, request *proto.CreateImportJobRequest) (*proto.ImportJob, error) {
+	format := constant.ImportFormat(request.Format)
+	if !format.Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported import format: %s", request.Format)
+	}
+
+	branch, err := s.findBranchOrDefault(ctx, request.BranchId)
+	if err != nil {
+		return nil, err
+	}
+
+	job := domain.ImportJob{
+		Format:   format,
+		DryRun:   request.DryRun,
+		Status:   constant.ImportPending,
+		ActorID:  request.ActorId,
+		FileName: request.FileName,
+		BranchID: branch.ID,
+		Errors:   make([]domain.ImportRowError, 0),
+	}
+
+	if err = s.importJobRepository.Create(ctx, &job); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoImportJob(job), nil
+}
+
+func (s *BookGRPCService) FindImportJob(ctx context.Context, request *proto.FindImportJobRequest) (*proto.ImportJob, error) {
+	job, err := s.findImportJob(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoImportJob(job), nil
+}
+
+// ImportBooks processes the file of a pending import job while it is streamed,
+// the job progress is saved periodically so it can be followed with FindImportJob.
+func (s *BookGRPCService) ImportBooks(stream proto.BookService_ImportBooksServer) error {
+	ctx := stream.Context()
+
+	request, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "import job ID is required")
+		}
+		return err
+	}
+
+	job, err := s.findImportJob(ctx, request.JobId)
+	if err != nil {
+		return err
+	}
+	if job.Status != constant.ImportPending {
+		return status.Errorf(codes.FailedPrecondition, "import job with %s ID is already %s", request.JobId, job.Status)
+	}
+
+	job.Status = constant.ImportRunning
+	if err = s.importJobRepository.Update(ctx, &job); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		chunk := request.Chunk
+		for {
+			if _, err := writer.Write(chunk); err != nil {
+				return
+			}
+
+			request, err := stream.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				_ = writer.CloseWithError(err)
+				return
+			}
+			chunk = request.Chunk
+		}
+	}()
+
+	err = s.importBooks(ctx, &job, reader)
+	// unblocks the receiving goroutine when the import stops before the end of the file
+	_ = reader.Close()
+
+	job.Status = constant.ImportCompleted
+	if err != nil {
+		job.Status = constant.ImportFailed
+		job.Message = err.Error()
+	}
+	// the stream context may be cancelled already, the final state must still be saved
+	if err := s.importJobRepository.Update(detached.Context(ctx), &job); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("import_job_id", job.ID.Hex()).Msg("Error saving import job")
+	}
+
+	return stream.SendAndClose(toProtoImportJob(job))
+}
+
+func (s *BookGRPCService) importBooks(ctx context.Context, job *domain.ImportJob, r io.Reader) error {
+	reader, err := catalog.NewReader(job.Format, r)
+	if err != nil {
+		return err
+	}
+
+	importer := &bookImporter{
+		service:     s,
+		job:         job,
+		seenRows:    make(map[string]int),
+		categoryIDs: make(map[string]*primitive.ObjectID),
+	}
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var rowErr *catalog.RowError
+		switch {
+		case errors.As(err, &rowErr):
+			job.Processed++
+			importer.fail(rowErr.Row, rowErr.Err.Error())
+		case err != nil:
+			return err
+		default:
+			job.Processed++
+			importer.importRecord(ctx, record)
+		}
+
+		if job.Processed%importProgressInterval == 0 {
+			if err = s.importJobRepository.Update(ctx, job); err != nil {
+				logger.Ctx(ctx).Error().Err(err).Str("import_job_id", job.ID.Hex()).Msg("Error saving import job progress")
+			}
+		}
+	}
+}
+
+// bookImporter creates the books of an import job, skipping the duplicates of existing books or earlier rows
+type bookImporter struct {
+	service     *BookGRPCService
+	job         *domain.ImportJob
+	seenRows    map[string]int
+	categoryIDs map[string]*primitive.ObjectID
+}
+
+func (i *bookImporter) importRecord(ctx context.Context, record catalog.Record) {
+	if record.Title == "" {
+		i.fail(record.Row, "title is required")
+		return
+	}
+
+	isbn := record.ISBN
+	if isbn != "" {
+		var err error
+		if isbn, err = catalog.NormalizeISBN(isbn); err != nil {
+			i.fail(record.Row, err.Error())
+			return
+		}
+	}
+
+	key := "title:" + strings.ToLower(record.Title)
+	if isbn != "" {
+		key = "isbn:" + isbn
+	}
+	if row, ok := i.seenRows[key]; ok {
+		i.skip(record.Row, fmt.Sprintf("duplicate of row %d", row), "")
+		return
+	}
+
+	existing, err := i.findDuplicate(ctx, isbn, record.Title)
+	if err == nil {
+		i.skip(record.Row, fmt.Sprintf("duplicate of book with %s ID", existing.ID.Hex()), existing.ID.Hex())
+		return
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		i.fail(record.Row, err.Error())
+		return
+	}
+
+	categoryID, err := i.findCategoryID(ctx, record.CategoryCode)
+	if err != nil {
+		i.fail(record.Row, err.Error())
+		return
+	}
+
+	if i.job.DryRun {
+		i.seenRows[key] = record.Row
+		i.job.Created++
+		return
+	}
+
+	book := domain.Book{
+		Title:      record.Title,
+		ISBN:       isbn,
+		Author:     record.Author,
+		Stock:      record.Stock,
+		CategoryID: categoryID,
+		Tags:       normalizeTags(record.Tags),
+		Branches:   []domain.BranchStock{{BranchID: i.job.BranchID, Stock: record.Stock}},
+	}
+	if err = i.service.withTransaction(ctx, func(ctx context.Context) error {
+		return i.createBook(ctx, &book)
+	}); err != nil {
+		i.fail(record.Row, err.Error())
+		return
+	}
+	i.seenRows[key] = record.Row
+	i.job.Created++
+}
+
+// createBook stores the book with the purchase of its stock and their events, it runs in the transaction of the row
+func (i *bookImporter) createBook(ctx context.Context, book *domain.Book) error {
+	if err := i.service.bookRepository.Create(ctx, book); err != nil {
+		return err
+	}
+	if err := i.service.recordBookCreated(ctx, *book); err != nil {
+		return err
+	}
+	if book.Stock <= 0 {
+		return nil
+	}
+
+	movement := domain.StockMovement{
+		BookID:   book.ID,
+		BranchID: i.job.BranchID,
+		Delta:    book.Stock,
+		Stock:    book.Stock,
+		Reason:   constant.StockPurchase,
+		ActorID:  i.job.ActorID,
+	}
+	if err := i.service.stockMovementRepository.Create(ctx, &movement); err != nil {
+		return err
+	}
+	return i.service.recordStockChanged(ctx, movement)
+}
+
+func (i *bookImporter) findDuplicate(ctx context.Context, isbn, title string) (domain.Book, error) {
+	if isbn != "" {
+		return i.service.bookRepository.FindByISBN(ctx, isbn)
+	}
+	return i.service.bookRepository.FindByExactTitle(ctx, title)
+}
+
+func (i *bookImporter) findCategoryID(ctx context.Context, code string) (*primitive.ObjectID, error) {
+	if code == "" {
+		return nil, nil
+	}
+	if categoryID, ok := i.categoryIDs[code]; ok {
+		return categoryID, nil
+	}
+
+	categories, err := i.service.categoryRepository.Fetch(ctx, map[string]interface{}{
+		"code":  code,
+		"limit": int32(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("category with %s code is not found", code)
+	}
+
+	i.categoryIDs[code] = &categories[0].ID
+	return &categories[0].ID, nil
+}
+
+func (i *bookImporter) fail(row int, message string) {
+	i.job.Failed++
+	i.addRowError(row, message, "")
+}
+
+func (i *bookImporter) skip(row int, message, bookID string) {
+	i.job.Skipped++
+	i.addRowError(row, message, bookID)
+}
+
+func (i *bookImporter) addRowError(row int, message, bookID string) {
+	// the counters stay exact, only the details are capped to keep the job document small
+	if len(i.job.Errors) >= maxImportRowErrors {
+		return
+	}
+
+	i.job.Errors = append(i.job.Errors, domain.ImportRowError{
+		Row:     row,
+		Message: message,
+		BookID:  bookID,
+	})
+}
+
+func (s *BookGRPCService) findImportJob(ctx context.Context, id string) (domain.ImportJob, error) {
+	job, err := s.importJobRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return domain.ImportJob{}, status.Errorf(codes.NotFound, "import job with %s ID is not found", id)
+		}
+		return domain.ImportJob{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return job, nil
+}
+
+func toProtoImportJob(job domain.ImportJob) *proto.ImportJob {
+	rowErrors := make([]*proto.ImportRowError, 0, len(job.Errors))
+	for _, rowErr := range job.Errors {
+		rowErrors = append(rowErrors, &proto.ImportRowError{
+			Row:     int32(rowErr.Row),
+			Message: rowErr.Message,
+			BookId:  rowErr.BookID,
+		})
+	}
+
+	return &proto.ImportJob{
+		Id:        job.ID.Hex(),
+		Format:    string(job.Format),
+		DryRun:    job.DryRun,
+		Status:    string(job.Status),
+		ActorId:   job.ActorID,
+		FileName:  job.FileName,
+		BranchId:  job.BranchID.Hex(),
+		Processed: int32(job.Processed),
+		Created:   int32(job.Created),
+		Skipped:   int32(job.Skipped),
+		Failed:    int32(job.Failed),
+		Errors:    rowErrors,
+		Message:   job.Message,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+		UpdatedAt: timestamppb.New(job.UpdatedAt),
+	}
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/mongodb"
+	"book-service/pkg/proto"
+)
+
+// serverStreamStub is the context of the stream stubs, the other grpc.ServerStream methods are not used by the service
+type serverStreamStub struct {
+	grpc.ServerStream
+}
+
+func (serverStreamStub) Context() context.Context {
+	return context.Background()
+}
+
+// importBooksServerStub receives the requests in order, then io.EOF
+type importBooksServerStub struct {
+	serverStreamStub
+	requests []*proto.ImportBooksRequest
+	response *proto.ImportJob
+}
+
+func (s *importBooksServerStub) Recv() (*proto.ImportBooksRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+
+	request := s.requests[0]
+	s.requests = s.requests[1:]
+	return request, nil
+}
+
+func (s *importBooksServerStub) SendAndClose(job *proto.ImportJob) error {
+	s.response = job
+	return nil
+}
+
+// importChunks splits the file in chunks of a few bytes, so the rows are split across the stream messages
+func importChunks(jobID, file string) []*proto.ImportBooksRequest {
+	requests := make([]*proto.ImportBooksRequest, 0)
+	for len(file) > 0 {
+		n := 7
+		if n > len(file) {
+			n = len(file)
+		}
+		requests = append(requests, &proto.ImportBooksRequest{Chunk: []byte(file[:n])})
+		file = file[n:]
+	}
+	if len(requests) == 0 {
+		requests = append(requests, &proto.ImportBooksRequest{})
+	}
+
+	requests[0].JobId = jobID
+	return requests
+}
+
+func TestBookGRPCService_CreateImportJob(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+
+	branch, err := s.CreateBranch(ctx, &proto.CreateBranchRequest{Name: "East", Code: "east"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	defaultBranch, err := s.FindDefaultBranch(ctx, &proto.FindDefaultBranchRequest{})
+	if err != nil {
+		t.Fatalf("FindDefaultBranch() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		request      *proto.CreateImportJobRequest
+		wantCode     codes.Code
+		wantBranchID string
+	}{
+		{
+			name:         "at the default branch",
+			request:      &proto.CreateImportJobRequest{Format: string(constant.ImportCSV), FileName: "books.csv", ActorId: "librarian"},
+			wantBranchID: defaultBranch.Id,
+		},
+		{
+			name:         "at a branch",
+			request:      &proto.CreateImportJobRequest{Format: string(constant.ImportMARC21), BranchId: branch.Id, DryRun: true},
+			wantBranchID: branch.Id,
+		},
+		{
+			name:     "unsupported format",
+			request:  &proto.CreateImportJobRequest{Format: "xlsx"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown branch",
+			request:  &proto.CreateImportJobRequest{Format: string(constant.ImportCSV), BranchId: primitive.NewObjectID().Hex()},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CreateImportJob(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateImportJob() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Status != string(constant.ImportPending) || got.BranchId != tt.wantBranchID || got.DryRun != tt.request.DryRun {
+				t.Errorf("CreateImportJob() = %+v, want pending at %s", got, tt.wantBranchID)
+			}
+
+			found, err := s.FindImportJob(ctx, &proto.FindImportJobRequest{Id: got.Id})
+			if err != nil || found.FileName != tt.request.FileName {
+				t.Errorf("FindImportJob() = %+v, %v", found, err)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := s.FindImportJob(ctx, &proto.FindImportJobRequest{Id: primitive.NewObjectID().Hex()})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("FindImportJob() error = %v, want %v", err, codes.NotFound)
+		}
+	})
+}
+
+func TestBookGRPCService_ImportBooks(t *testing.T) {
+	const file = "title,isbn,author,stock,category_code,tags\n" +
+		"Dune,978-0-306-40615-7,Frank Herbert,3,800,sci-fi;Classic\n" +
+		"Dune again,9780306406157,,1,,\n" +
+		"Emma,,Jane Austen,0,,\n" +
+		",,Nobody,1,,\n" +
+		"Existing,,,1,,\n" +
+		"Lost,,,1,999,\n" +
+		"Broken,,,-1,,\n"
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		file        string
+		wantStatus  constant.ImportStatus
+		wantCreated int32
+		wantSkipped int32
+		wantFailed  int32
+		wantBooks   int32
+	}{
+		{
+			name:        "creates the valid rows",
+			file:        file,
+			wantStatus:  constant.ImportCompleted,
+			wantCreated: 2,
+			wantSkipped: 2,
+			wantFailed:  3,
+			wantBooks:   3,
+		},
+		{
+			name:        "dry run",
+			dryRun:      true,
+			file:        file,
+			wantStatus:  constant.ImportCompleted,
+			wantCreated: 2,
+			wantSkipped: 2,
+			wantFailed:  3,
+			wantBooks:   1,
+		},
+		{
+			name:       "invalid file",
+			file:       "isbn\n9780306406157\n",
+			wantStatus: constant.ImportFailed,
+			wantBooks:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemoryBookGRPCService(t)
+			ctx := context.Background()
+
+			createTestCategory(t, s, &proto.CreateCategoryRequest{Name: "Literature", Code: "800"})
+			createTestBook(t, s, &proto.CreateBookRequest{Title: "Existing"})
+			job, err := s.CreateImportJob(ctx, &proto.CreateImportJobRequest{Format: string(constant.ImportCSV), DryRun: tt.dryRun})
+			if err != nil {
+				t.Fatalf("CreateImportJob() error = %v", err)
+			}
+
+			stream := &importBooksServerStub{requests: importChunks(job.Id, tt.file)}
+			if err = s.ImportBooks(stream); err != nil {
+				t.Fatalf("ImportBooks() error = %v", err)
+			}
+
+			got := stream.response
+			if got.Status != string(tt.wantStatus) || got.Created != tt.wantCreated || got.Skipped != tt.wantSkipped || got.Failed != tt.wantFailed {
+				t.Errorf("ImportBooks() = %+v, want %s with %d created, %d skipped, %d failed",
+					got, tt.wantStatus, tt.wantCreated, tt.wantSkipped, tt.wantFailed)
+			}
+			if found, err := s.FindImportJob(ctx, &proto.FindImportJobRequest{Id: job.Id}); err != nil || found.Status != got.Status {
+				t.Errorf("FindImportJob() = %+v, %v, want the saved import", found, err)
+			}
+
+			books, err := s.FetchBook(ctx, &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}})
+			if err != nil {
+				t.Fatalf("FetchBook() error = %v", err)
+			}
+			if books.Pagination.Total != tt.wantBooks {
+				t.Errorf("FetchBook() total = %d, want %d", books.Pagination.Total, tt.wantBooks)
+			}
+		})
+	}
+
+	t.Run("already imported", func(t *testing.T) {
+		s := newMemoryBookGRPCService(t)
+
+		job, err := s.CreateImportJob(context.Background(), &proto.CreateImportJobRequest{Format: string(constant.ImportCSV)})
+		if err != nil {
+			t.Fatalf("CreateImportJob() error = %v", err)
+		}
+		if err = s.ImportBooks(&importBooksServerStub{requests: importChunks(job.Id, "title\nDune\n")}); err != nil {
+			t.Fatalf("ImportBooks() error = %v", err)
+		}
+
+		err = s.ImportBooks(&importBooksServerStub{requests: importChunks(job.Id, "title\nDune\n")})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("ImportBooks() error = %v, want %v", err, codes.FailedPrecondition)
+		}
+	})
+
+	t.Run("without job", func(t *testing.T) {
+		s := newMemoryBookGRPCService(t)
+
+		err := s.ImportBooks(&importBooksServerStub{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ImportBooks() error = %v, want %v", err, codes.InvalidArgument)
+		}
+	})
+}
+
+// rollbackTXRepository stands in for the MongoDB transactions over the in-memory book repository, the books created
+// by a failed transaction are deleted
+type rollbackTXRepository struct {
+	bookRepository domain.BookRepository
+}
+
+func (r rollbackTXRepository) StartSession() (mongodb.Session, error) {
+	return r, nil
+}
+
+func (rollbackTXRepository) EndSession(context.Context) {}
+
+func (r rollbackTXRepository) WithTransaction(
+	ctx context.Context,
+	fn func(sessCtx mongo.SessionContext) (interface{}, error),
+) (interface{}, error) {
+	before := map[primitive.ObjectID]bool{}
+	if err := r.bookRepository.FetchEach(ctx, map[string]interface{}{}, func(book domain.Book) error {
+		before[book.ID] = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	result, err := fn(mongo.NewSessionContext(ctx, nil))
+	if err == nil {
+		return result, nil
+	}
+
+	_ = r.bookRepository.FetchEach(ctx, map[string]interface{}{}, func(book domain.Book) error {
+		if !before[book.ID] {
+			return r.bookRepository.Delete(ctx, &book)
+		}
+		return nil
+	})
+	return nil, err
+}
+
+// failingStockMovementRepository fails to record the stock movements
+type failingStockMovementRepository struct {
+	domain.StockMovementRepository
+}
+
+func (failingStockMovementRepository) Create(context.Context, *domain.StockMovement) error {
+	return errors.New("stock movements are unavailable")
+}
+
+func TestBookGRPCService_ImportBooks_RollsBackFailedRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *BookGRPCService)
+	}{
+		{
+			name: "stock movement is not recorded",
+			setup: func(s *BookGRPCService) {
+				s.stockMovementRepository = failingStockMovementRepository{}
+			},
+		},
+		{
+			name: "event is not recorded",
+			setup: func(s *BookGRPCService) {
+				s.outboxRepository = failingOutboxRepository{}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemoryBookGRPCService(t)
+			s.txRepository = rollbackTXRepository{bookRepository: s.bookRepository}
+			tt.setup(s)
+			ctx := context.Background()
+
+			job, err := s.CreateImportJob(ctx, &proto.CreateImportJobRequest{Format: string(constant.ImportCSV)})
+			if err != nil {
+				t.Fatalf("CreateImportJob() error = %v", err)
+			}
+			stream := &importBooksServerStub{requests: importChunks(job.Id, "title,stock\nDune,3\n")}
+			if err = s.ImportBooks(stream); err != nil {
+				t.Fatalf("ImportBooks() error = %v", err)
+			}
+
+			got := stream.response
+			if got.Created != 0 || got.Failed != 1 || len(got.Errors) != 1 || got.Errors[0].Row != 2 {
+				t.Errorf("ImportBooks() = %+v, want the row 2 failed", got)
+			}
+
+			books, err := s.FetchBook(ctx, &proto.FetchBookRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}})
+			if err != nil {
+				t.Fatalf("FetchBook() error = %v", err)
+			}
+			if books.Pagination.Total != 0 {
+				t.Errorf("FetchBook() total = %d, want no book left", books.Pagination.Total)
+			}
+		})
+	}
+}
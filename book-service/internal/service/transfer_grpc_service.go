@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"book-service/internal/domain"
+	"book-service/internal/domain/constant"
+	"book-service/pkg/logger"
+	"book-service/pkg/proto"
+)
+
+// CreateTransfer requests copies of a book from another branch, the stock is only moved when the transfer is shipped
+func (s *BookGRPCService) CreateTransfer(ctx context.Context, request *proto.CreateTransferRequest) (*proto.Transfer, error) {
+	if request.Quantity <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "transfer quantity must be more than 0")
+	}
+	if request.FromBranchId == request.ToBranchId {
+		return nil, status.Error(codes.InvalidArgument, "transfer source and destination branch must be different")
+	}
+
+	book, err := s.bookRepository.FindByID(ctx, request.BookId)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return nil, status.Errorf(codes.NotFound, "book with %s ID is not found", request.BookId)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	fromBranch, err := s.findBranch(ctx, request.FromBranchId)
+	if err != nil {
+		return nil, err
+	}
+	toBranch, err := s.findBranch(ctx, request.ToBranchId)
+	if err != nil {
+		return nil, err
+	}
+
+	if stock := branchStock(book, fromBranch.ID); stock < int(request.Quantity) {
+		return nil, status.Errorf(codes.FailedPrecondition, "branch with %s ID only has %d copies of the book",
+			request.FromBranchId, stock)
+	}
+
+	transfer := domain.Transfer{
+		BookID:       book.ID,
+		FromBranchID: fromBranch.ID,
+		ToBranchID:   toBranch.ID,
+		Quantity:     int(request.Quantity),
+		Status:       constant.TransferRequested,
+		RequestedBy:  request.ActorId,
+	}
+
+	if err = s.transferRepository.Create(ctx, &transfer); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoTransfer(transfer), nil
+}
+
+func (s *BookGRPCService) FetchTransfer(ctx context.Context, request *proto.FetchTransferRequest) (*proto.FetchTransferResponse, error) {
+	page, limit := request.Pagination.Page, request.Pagination.Limit
+	fetchFilter := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+	if bookID := request.BookId; bookID != "" {
+		fetchFilter["book_id"] = bookID
+	}
+	if branchID := request.BranchId; branchID != "" {
+		fetchFilter["branch_id"] = branchID
+	}
+	if transferStatus := request.Status; transferStatus != "" {
+		fetchFilter["status"] = transferStatus
+	}
+
+	transfers, err := s.transferRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoTransfers := make([]*proto.Transfer, 0)
+	for _, transfer := range transfers {
+		protoTransfers = append(protoTransfers, toProtoTransfer(transfer))
+	}
+
+	total, err := s.transferRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.FetchTransferResponse{
+		Pagination: &proto.BookPaginationResponse{
+			Limit:    limit,
+			Page:     page,
+			LastPage: int32(math.Ceil(float64(total) / float64(limit))),
+			Total:    int32(total),
+		},
+		Transfers: protoTransfers,
+	}, nil
+}
+
+// UpdateTransferStatus ships, receives, or cancels a transfer.
+// Shipping takes the copies from the source branch stock, receiving adds them to the destination branch stock,
+// so the copies in transit are not lendable at either branch.
+func (s *BookGRPCService) UpdateTransferStatus(ctx context.Context, request *proto.UpdateTransferStatusRequest) (*proto.Transfer, error) {
+	target := constant.TransferStatus(request.Status)
+	from := target.From()
+	if from == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "transfer cannot be changed to %s status", request.Status)
+	}
+
+	transfer, err := s.findTransfer(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != from {
+		return nil, status.Errorf(codes.FailedPrecondition, "transfer with %s ID is %s, it cannot be changed to %s",
+			request.Id, transfer.Status, target)
+	}
+
+	if request.ActorBranchId != "" {
+		actorBranch := request.ActorBranchId
+		allowed := false
+		switch target {
+		case constant.TransferInTransit:
+			allowed = actorBranch == transfer.FromBranchID.Hex()
+		case constant.TransferReceived:
+			allowed = actorBranch == transfer.ToBranchID.Hex()
+		case constant.TransferCanceled:
+			allowed = actorBranch == transfer.FromBranchID.Hex() || actorBranch == transfer.ToBranchID.Hex()
+		}
+		if !allowed {
+			return nil, status.Errorf(codes.PermissionDenied, "branch with %s ID cannot change transfer with %s ID to %s",
+				actorBranch, request.Id, target)
+		}
+	}
+
+	previous := transfer
+	transfer.Status = target
+	movement := domain.StockMovement{
+		Reason:     constant.StockTransfer,
+		ActorID:    request.ActorId,
+		TransferID: transfer.ID.Hex(),
+	}
+	switch target {
+	case constant.TransferInTransit:
+		transfer.ShippedBy = request.ActorId
+		movement.BranchID = transfer.FromBranchID
+		movement.Delta = -transfer.Quantity
+	case constant.TransferReceived:
+		transfer.ReceivedBy = request.ActorId
+		movement.BranchID = transfer.ToBranchID
+		movement.Delta = transfer.Quantity
+	}
+
+	if err = s.transferRepository.UpdateStatus(ctx, &transfer, from); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(codes.Aborted, "transfer with %s ID is changed concurrently", request.Id)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if movement.Delta != 0 {
+		if _, err = s.changeStock(ctx, transfer.BookID.Hex(), movement); err != nil {
+			if revertErr := s.transferRepository.UpdateStatus(ctx, &previous, target); revertErr != nil {
+				logger.Ctx(ctx).Error().Err(revertErr).Str("transfer_id", transfer.ID.Hex()).Str("status", string(from)).Msg("Error reverting transfer")
+			}
+			return nil, err
+		}
+	}
+
+	return toProtoTransfer(transfer), nil
+}
+
+func (s *BookGRPCService) findTransfer(ctx context.Context, id string) (domain.Transfer, error) {
+	transfer, err := s.transferRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return domain.Transfer{}, status.Errorf(codes.NotFound, "transfer with %s ID is not found", id)
+		}
+		return domain.Transfer{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return transfer, nil
+}
+
+func branchStock(book domain.Book, branchID primitive.ObjectID) int {
+	for _, branch := range book.Branches {
+		if branch.BranchID == branchID {
+			return branch.Stock
+		}
+	}
+	return 0
+}
+
+func toProtoTransfer(transfer domain.Transfer) *proto.Transfer {
+	return &proto.Transfer{
+		Id:           transfer.ID.Hex(),
+		BookId:       transfer.BookID.Hex(),
+		FromBranchId: transfer.FromBranchID.Hex(),
+		ToBranchId:   transfer.ToBranchID.Hex(),
+		Quantity:     int32(transfer.Quantity),
+		Status:       string(transfer.Status),
+		RequestedBy:  transfer.RequestedBy,
+		ShippedBy:    transfer.ShippedBy,
+		ReceivedBy:   transfer.ReceivedBy,
+		CreatedAt:    timestamppb.New(transfer.CreatedAt),
+		UpdatedAt:    timestamppb.New(transfer.UpdatedAt),
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"book-service/internal/domain/constant"
+	"book-service/pkg/proto"
+)
+
+// transferTestBook creates a book with stock at the default branch, and a second branch without stock
+func transferTestBook(t *testing.T, s *BookGRPCService, stock int32) (book *proto.Book, from, to *proto.Branch) {
+	t.Helper()
+
+	book = stockTestBook(t, s, "Dune", stock)
+	from, err := s.FindDefaultBranch(context.Background(), &proto.FindDefaultBranchRequest{})
+	if err != nil {
+		t.Fatalf("FindDefaultBranch() error = %v", err)
+	}
+	to, err = s.CreateBranch(context.Background(), &proto.CreateBranchRequest{Name: "East", Code: "east"})
+	if err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	return book, from, to
+}
+
+func TestBookGRPCService_CreateTransfer(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	book, from, to := transferTestBook(t, s, 2)
+
+	tests := []struct {
+		name     string
+		request  *proto.CreateTransferRequest
+		wantCode codes.Code
+	}{
+		{
+			name:    "requested",
+			request: &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: from.Id, ToBranchId: to.Id, Quantity: 2, ActorId: "librarian"},
+		},
+		{
+			name:     "more than the branch stock",
+			request:  &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: from.Id, ToBranchId: to.Id, Quantity: 3},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "from a branch without stock",
+			request:  &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: to.Id, ToBranchId: from.Id, Quantity: 1},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "same branch",
+			request:  &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: from.Id, ToBranchId: from.Id, Quantity: 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "no quantity",
+			request:  &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: from.Id, ToBranchId: to.Id},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown book",
+			request:  &proto.CreateTransferRequest{BookId: primitive.NewObjectID().Hex(), FromBranchId: from.Id, ToBranchId: to.Id, Quantity: 1},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "unknown branch",
+			request:  &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: from.Id, ToBranchId: primitive.NewObjectID().Hex(), Quantity: 1},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CreateTransfer(context.Background(), tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateTransfer() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && (got.Status != string(constant.TransferRequested) || got.RequestedBy != tt.request.ActorId) {
+				t.Errorf("CreateTransfer() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_FetchTransfer(t *testing.T) {
+	s := newMemoryBookGRPCService(t)
+	ctx := context.Background()
+	book, from, to := transferTestBook(t, s, 3)
+	other := stockTestBook(t, s, "Emma", 1)
+
+	first, err := s.CreateTransfer(ctx, &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: from.Id, ToBranchId: to.Id, Quantity: 1})
+	if err != nil {
+		t.Fatalf("CreateTransfer() error = %v", err)
+	}
+	second, err := s.CreateTransfer(ctx, &proto.CreateTransferRequest{BookId: other.Id, FromBranchId: from.Id, ToBranchId: to.Id, Quantity: 1})
+	if err != nil {
+		t.Fatalf("CreateTransfer() error = %v", err)
+	}
+	if _, err = s.UpdateTransferStatus(ctx, &proto.UpdateTransferStatusRequest{Id: second.Id, Status: string(constant.TransferCanceled)}); err != nil {
+		t.Fatalf("UpdateTransferStatus() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		request *proto.FetchTransferRequest
+		wantIDs []string
+	}{
+		{
+			name:    "newest first",
+			request: &proto.FetchTransferRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}},
+			wantIDs: []string{second.Id, first.Id},
+		},
+		{
+			name:    "by book",
+			request: &proto.FetchTransferRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BookId: book.Id},
+			wantIDs: []string{first.Id},
+		},
+		{
+			name:    "by destination branch",
+			request: &proto.FetchTransferRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, BranchId: to.Id},
+			wantIDs: []string{second.Id, first.Id},
+		},
+		{
+			name:    "by status",
+			request: &proto.FetchTransferRequest{Pagination: &proto.BookPaginationRequest{Limit: 10, Page: 1}, Status: string(constant.TransferCanceled)},
+			wantIDs: []string{second.Id},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FetchTransfer(ctx, tt.request)
+			if err != nil {
+				t.Fatalf("FetchTransfer() error = %v", err)
+			}
+
+			ids := make([]string, 0, len(got.Transfers))
+			for _, transfer := range got.Transfers {
+				ids = append(ids, transfer.Id)
+			}
+			if !equalStrings(ids, tt.wantIDs) || got.Pagination.Total != int32(len(tt.wantIDs)) {
+				t.Errorf("FetchTransfer() IDs = %v, total = %d, want %v", ids, got.Pagination.Total, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestBookGRPCService_UpdateTransferStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []constant.TransferStatus
+		actorBranch   func(from, to *proto.Branch) string
+		wantCode      codes.Code
+		wantFromStock int32
+		wantToStock   int32
+	}{
+		{
+			name:          "shipped takes the copies from the source branch",
+			statuses:      []constant.TransferStatus{constant.TransferInTransit},
+			wantFromStock: 1,
+		},
+		{
+			name:          "received adds the copies to the destination branch",
+			statuses:      []constant.TransferStatus{constant.TransferInTransit, constant.TransferReceived},
+			wantFromStock: 1,
+			wantToStock:   2,
+		},
+		{
+			name:          "canceled keeps the stock",
+			statuses:      []constant.TransferStatus{constant.TransferCanceled},
+			wantFromStock: 3,
+		},
+		{
+			name:          "received before shipped",
+			statuses:      []constant.TransferStatus{constant.TransferReceived},
+			wantCode:      codes.FailedPrecondition,
+			wantFromStock: 3,
+		},
+		{
+			name:          "canceled after shipped",
+			statuses:      []constant.TransferStatus{constant.TransferInTransit, constant.TransferCanceled},
+			wantCode:      codes.FailedPrecondition,
+			wantFromStock: 1,
+		},
+		{
+			name:          "back to requested",
+			statuses:      []constant.TransferStatus{constant.TransferRequested},
+			wantCode:      codes.InvalidArgument,
+			wantFromStock: 3,
+		},
+		{
+			name:          "shipped by the destination branch",
+			statuses:      []constant.TransferStatus{constant.TransferInTransit},
+			actorBranch:   func(_, to *proto.Branch) string { return to.Id },
+			wantCode:      codes.PermissionDenied,
+			wantFromStock: 3,
+		},
+		{
+			name:          "shipped by the source branch",
+			statuses:      []constant.TransferStatus{constant.TransferInTransit},
+			actorBranch:   func(from, _ *proto.Branch) string { return from.Id },
+			wantFromStock: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemoryBookGRPCService(t)
+			ctx := context.Background()
+			book, from, to := transferTestBook(t, s, 3)
+
+			transfer, err := s.CreateTransfer(ctx, &proto.CreateTransferRequest{BookId: book.Id, FromBranchId: from.Id, ToBranchId: to.Id, Quantity: 2})
+			if err != nil {
+				t.Fatalf("CreateTransfer() error = %v", err)
+			}
+
+			request := &proto.UpdateTransferStatusRequest{Id: transfer.Id, ActorId: "librarian"}
+			if tt.actorBranch != nil {
+				request.ActorBranchId = tt.actorBranch(from, to)
+			}
+			for _, transferStatus := range tt.statuses {
+				request.Status = string(transferStatus)
+				transfer, err = s.UpdateTransferStatus(ctx, request)
+				if err != nil {
+					break
+				}
+			}
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateTransferStatus() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && transfer.Status != request.Status {
+				t.Errorf("UpdateTransferStatus() status = %s, want %s", transfer.Status, request.Status)
+			}
+
+			got, err := s.FindByID(ctx, &proto.FindBookByIDRequest{Id: book.Id})
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			stocks := map[string]int32{}
+			for _, branchStock := range got.Branches {
+				stocks[branchStock.BranchId] = branchStock.Stock
+			}
+			if stocks[from.Id] != tt.wantFromStock || stocks[to.Id] != tt.wantToStock {
+				t.Errorf("branch stocks = %d, %d, want %d, %d", stocks[from.Id], stocks[to.Id], tt.wantFromStock, tt.wantToStock)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		s := newMemoryBookGRPCService(t)
+
+		_, err := s.UpdateTransferStatus(context.Background(), &proto.UpdateTransferStatusRequest{
+			Id:     primitive.NewObjectID().Hex(),
+			Status: string(constant.TransferInTransit),
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("UpdateTransferStatus() error = %v, want %v", err, codes.NotFound)
+		}
+	})
+}
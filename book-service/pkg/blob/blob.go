@@ -0,0 +1,24 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("blob is not found")
+
+// Store keeps binary objects by slash separated keys. It follows the S3 object model,
+// so an S3-compatible store can replace the file system one without changing the callers.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, Attributes, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Attributes struct {
+	ContentType string
+	Size        int64
+	ModifiedAt  time.Time
+}
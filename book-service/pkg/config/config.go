@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Config is the configuration of the app
+type Config struct {
+	GRPCPort        string `yaml:"grpc_port" env:"GRPC_PORT" default:":8000" validate:"required,addr"`
+	MetricsHTTPPort string `yaml:"metrics_http_port" env:"METRICS_HTTP_PORT" default:":9100" validate:"required,addr"`
+	BlobStorePath   string `yaml:"blob_store_path" env:"BLOB_STORE_PATH" default:"storage" validate:"required"`
+	// AllowPendingMigrations starts the app even when some migrations have not run
+	AllowPendingMigrations bool `yaml:"allow_pending_migrations" env:"ALLOW_PENDING_MIGRATIONS"`
+
+	MongoDB        MongoDB `yaml:"mongodb" env:"MONGODB_"`
+	LendingService Backend `yaml:"lending_service" env:"LENDING_SERVICE_"`
+	TLS            TLS     `yaml:"tls" env:"TLS_"`
+	Events         Events  `yaml:"events" env:"EVENT_"`
+	Log            Log     `yaml:"log"`
+	Tracing        Tracing `yaml:"tracing"`
+	PProf          PProf   `yaml:"pprof"`
+}
+
+// CommandConfig is the configuration of the migration and seed commands
+type CommandConfig struct {
+	MongoDB     MongoDB `yaml:"mongodb" env:"MONGODB_"`
+	SeedProfile string  `yaml:"seed_profile" env:"SEED_PROFILE" validate:"oneof=minimal demo load-test"`
+}
+
+type MongoDB struct {
+	URI      string `yaml:"uri" env:"URI" validate:"required,url" secret:"userinfo"`
+	Database string `yaml:"database" env:"DATABASE" validate:"required"`
+}
+
+// Backend is the address of another service
+type Backend struct {
+	Host string `yaml:"host" env:"HOST" validate:"required"`
+	Port string `yaml:"port" env:"PORT" validate:"required,addr"`
+}
+
+func (b Backend) Address() string {
+	return b.Host + b.Port
+}
+
+// TLS secures the gRPC connections, the certificate is the identity of the service as a server and as a client.
+// The files are read again when they change, so they are rotated without restart.
+type TLS struct {
+	CertFile string `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"KEY_FILE"`
+	// CAFile verifies the other services, the clients connect with TLS when it is set
+	CAFile string `yaml:"ca_file" env:"CA_FILE"`
+	// ClientAuth makes the server require a client certificate signed by the CA, which is mutual TLS
+	ClientAuth bool `yaml:"client_auth" env:"CLIENT_AUTH"`
+}
+
+// Events relays the domain events from the outbox to the broker, with the none broker they stay in the outbox.
+// An event failing to be published RelayMaxAttempts times is moved aside.
+type Events struct {
+	Broker           string        `yaml:"broker" env:"BROKER" default:"none" validate:"oneof=none nats"`
+	NATSURL          string        `yaml:"nats_url" env:"NATS_URL" default:"nats://127.0.0.1:4222" validate:"url"`
+	SubjectPrefix    string        `yaml:"subject_prefix" env:"SUBJECT_PREFIX" default:"library" validate:"required"`
+	RelayInterval    time.Duration `yaml:"relay_interval" env:"RELAY_INTERVAL" default:"1s"`
+	RelayMaxAttempts int           `yaml:"relay_max_attempts" env:"RELAY_MAX_ATTEMPTS" default:"10"`
+}
+
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info" validate:"oneof=trace debug info warn error"`
+}
+
+// Tracing selects the exporter of the traces, the OTLP exporter is configured by the standard variables
+// like OTEL_EXPORTER_OTLP_ENDPOINT
+type Tracing struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" default:"none" validate:"oneof=none otlp stdout"`
+}
+
+type PProf struct {
+	Enable         bool   `yaml:"enable" env:"ENABLE_PPROF"`
+	HTTPPort       string `yaml:"http_port" env:"PPROF_HTTP_PORT" default:":6060" validate:"addr"`
+	FolderPath     string `yaml:"folder_path" env:"PPROF_FOLDER_PATH" default:"profile"`
+	CPUFileName    string `yaml:"cpu_file_name" env:"CPU_PPROF_FILE_NAME"`
+	MemoryFileName string `yaml:"memory_file_name" env:"MEMORY_PPROF_FILE_NAME"`
+}
+
+// Load reads the configuration of the app, see load
+func Load(path string) (Config, error) {
+	var cfg Config
+	err := load(path, &cfg)
+	return cfg, err
+}
+
+// LoadCommand reads the configuration of the commands from the environment and the CONFIG_FILE
+func LoadCommand() (CommandConfig, error) {
+	var cfg CommandConfig
+	err := load(os.Getenv("CONFIG_FILE"), &cfg)
+	return cfg, err
+}
@@ -0,0 +1,32 @@
+package detached
+
+import (
+	"context"
+	"time"
+)
+
+// Context keeps the values of ctx, like the tenant and the trace span, without its deadline and cancellation.
+// It is used for the work that must finish after the request is gone, like a compensation.
+func Context(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,54 @@
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	interval = 5 * time.Second
+	timeout  = 2 * time.Second
+)
+
+// Watch runs the check periodically until ctx is done, and sets the serving status of the server and of the given
+// services from its result.
+func Watch(ctx context.Context, server *health.Server, check func(ctx context.Context) error, services ...string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := check(checkCtx)
+		cancel()
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		if status != last {
+			event := log.Info()
+			if err != nil {
+				event = log.Warn().Err(err)
+			}
+			event.Str("status", status.String()).Msg("Health status changed")
+
+			// the empty service name is the health of the whole server
+			server.SetServingStatus("", status)
+			for _, service := range services {
+				server.SetServingStatus(service, status)
+			}
+			last = status
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
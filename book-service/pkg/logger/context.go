@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// RequestIDMetadataKey carries the ID given to the request by the gateway between the services
+	RequestIDMetadataKey = "x-request-id"
+	// UserIDMetadataKey carries the authenticated user of the request between the services
+	UserIDMetadataKey = "x-user-id"
+)
+
+type (
+	requestIDContextKey struct{}
+	userIDContextKey    struct{}
+)
+
+func NewRequestIDContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+func NewUserIDContext(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDContextKey{}, userID)
+}
+
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDContextKey{}).(string)
+	return userID
+}
+
+func fromIncomingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+		ctx = NewRequestIDContext(ctx, values[0])
+	}
+	if values := md.Get(UserIDMetadataKey); len(values) > 0 {
+		ctx = NewUserIDContext(ctx, values[0])
+	}
+	return ctx
+}
+
+func toOutgoingContext(ctx context.Context) context.Context {
+	kv := make([]string, 0, 4)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		kv = append(kv, RequestIDMetadataKey, requestID)
+	}
+	if userID := UserIDFromContext(ctx); userID != "" {
+		kv = append(kv, UserIDMetadataKey, userID)
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryServerInterceptor(t *testing.T) {
+	interceptor := RecoveryUnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/book.BookService/ImportBooks"}
+
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		var entries []int
+		return entries[1], nil
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("interceptor() error = %v, want Internal", err)
+	}
+
+	resp, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if resp != "ok" || err != nil {
+		t.Errorf("interceptor() = %v, %v, want the response of the handler", resp, err)
+	}
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"io"
+	"regexp"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+)`)
+	// passwordFieldPattern matches a password JSON field, passwordTextPattern a password written in a message
+	passwordFieldPattern = regexp.MustCompile(`(?i)("password"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	passwordTextPattern  = regexp.MustCompile(`(?i)(password\s*[=:]\s*)[^\s",}]+`)
+)
+
+// Redact masks the emails, keeping their first letter and domain, and removes the passwords
+func Redact(s string) string {
+	return string(redact([]byte(s)))
+}
+
+func redact(p []byte) []byte {
+	p = emailPattern.ReplaceAll(p, []byte("${1}***@${2}"))
+	p = passwordFieldPattern.ReplaceAll(p, []byte(`${1}"[REDACTED]"`))
+	return passwordTextPattern.ReplaceAll(p, []byte("${1}[REDACTED]"))
+}
+
+// redactWriter redacts every log line, so a message or an error never leaks an email or a password
+type redactWriter struct {
+	w io.Writer
+}
+
+func (w redactWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
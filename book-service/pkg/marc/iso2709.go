@@ -0,0 +1,97 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	recordTerminator   = 0x1D
+	fieldTerminator    = 0x1E
+	subfieldDelimiter  = 0x1F
+	leaderLength       = 24
+	directoryEntrySize = 12
+)
+
+var ErrInvalidRecord = errors.New("invalid MARC record")
+
+// ISO2709Reader reads binary MARC 21 records (ISO 2709) one by one
+type ISO2709Reader struct {
+	reader *bufio.Reader
+}
+
+func NewISO2709Reader(r io.Reader) *ISO2709Reader {
+	return &ISO2709Reader{
+		reader: bufio.NewReader(r),
+	}
+}
+
+// Next returns the next record, or io.EOF when there is no record left
+func (r *ISO2709Reader) Next() (Record, error) {
+	raw, err := r.reader.ReadBytes(recordTerminator)
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(bytes.TrimSpace(raw)) > 0 {
+			return Record{}, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+		}
+		return Record{}, err
+	}
+
+	// records are often separated by new lines
+	raw = bytes.TrimLeft(raw, "\r\n")
+	return parseISO2709(raw)
+}
+
+func parseISO2709(raw []byte) (Record, error) {
+	if len(raw) < leaderLength {
+		return Record{}, fmt.Errorf("%w: record is shorter than the leader", ErrInvalidRecord)
+	}
+
+	leader := string(raw[:leaderLength])
+	baseAddress, err := strconv.Atoi(leader[12:17])
+	if err != nil || baseAddress <= leaderLength || baseAddress > len(raw) {
+		return Record{}, fmt.Errorf("%w: invalid base address of data", ErrInvalidRecord)
+	}
+
+	record := Record{Leader: leader}
+	directory := raw[leaderLength : baseAddress-1]
+	data := raw[baseAddress:]
+	for len(directory) >= directoryEntrySize {
+		entry := directory[:directoryEntrySize]
+		directory = directory[directoryEntrySize:]
+
+		tag := string(entry[:3])
+		length, lengthErr := strconv.Atoi(string(entry[3:7]))
+		start, startErr := strconv.Atoi(string(entry[7:12]))
+		if lengthErr != nil || startErr != nil || start < 0 || length <= 0 || start+length > len(data) {
+			return Record{}, fmt.Errorf("%w: invalid directory entry of field %s", ErrInvalidRecord, tag)
+		}
+
+		field := bytes.TrimRight(data[start:start+length], string([]byte{fieldTerminator}))
+		if tag < "010" {
+			record.ControlFields = append(record.ControlFields, ControlField{Tag: tag, Value: string(field)})
+			continue
+		}
+
+		dataField := DataField{Tag: tag, Ind1: " ", Ind2: " "}
+		if len(field) >= 2 {
+			dataField.Ind1, dataField.Ind2 = string(field[0]), string(field[1])
+			field = field[2:]
+		}
+		for _, subfield := range bytes.Split(field, []byte{subfieldDelimiter}) {
+			if len(subfield) == 0 {
+				continue
+			}
+			dataField.Subfields = append(dataField.Subfields, Subfield{
+				Code:  string(subfield[0]),
+				Value: string(subfield[1:]),
+			})
+		}
+		record.DataFields = append(record.DataFields, dataField)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,58 @@
+package marc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// record encodes a record whose directory is given as is, so the tests can break its entries
+func record(directory, data string) string {
+	baseAddress := leaderLength + len(directory) + 1
+	length := baseAddress + len(data) + 1
+	leader := fmt.Sprintf("%05dnam a22%05d a 4500", length, baseAddress)
+
+	return leader + directory + "\x1e" + data + "\x1d"
+}
+
+func TestISO2709Reader_Next(t *testing.T) {
+	const title = "10\x1faDune\x1e"
+
+	tests := []struct {
+		name      string
+		directory string
+		wantErr   bool
+	}{
+		{name: "valid entry", directory: "245000900000"},
+		{name: "negative start", directory: "2450005-0001", wantErr: true},
+		{name: "negative length", directory: "245-00100000", wantErr: true},
+		{name: "zero length", directory: "245000000000", wantErr: true},
+		{name: "field past the data", directory: "245000900002", wantErr: true},
+		{name: "not a number", directory: "2450009abcde", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the record after the invalid one is still read
+			input := record(tt.directory, title) + "\n" + record("245000900000", title)
+			reader := NewISO2709Reader(strings.NewReader(input))
+
+			got, err := reader.Next()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRecord) {
+					t.Fatalf("Next() error = %v, want %v", err, ErrInvalidRecord)
+				}
+			} else if err != nil || got.Title() != "Dune" {
+				t.Fatalf("Next() = %+v, %v, want Dune", got, err)
+			}
+
+			if got, err = reader.Next(); err != nil || got.Title() != "Dune" {
+				t.Errorf("Next() of the following record = %+v, %v, want Dune", got, err)
+			}
+			if _, err = reader.Next(); !errors.Is(err, io.EOF) {
+				t.Errorf("Next() at the end error = %v, want io.EOF", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package marc
+
+import (
+	"strings"
+)
+
+// Record is a MARC 21 bibliographic record
+type Record struct {
+	Leader        string
+	ControlFields []ControlField
+	DataFields    []DataField
+}
+
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+type DataField struct {
+	Tag       string
+	Ind1      string
+	Ind2      string
+	Subfields []Subfield
+}
+
+type Subfield struct {
+	Code  string
+	Value string
+}
+
+// ControlField returns the value of the first control field with the tag
+func (r Record) ControlField(tag string) string {
+	for _, field := range r.ControlFields {
+		if field.Tag == tag {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// Subfield returns the value of the first subfield code in the first data field with the tag
+func (r Record) Subfield(tag, code string) string {
+	for _, field := range r.DataFields {
+		if field.Tag == tag {
+			return field.Subfield(code)
+		}
+	}
+	return ""
+}
+
+func (f DataField) Subfield(code string) string {
+	for _, subfield := range f.Subfields {
+		if subfield.Code == code {
+			return subfield.Value
+		}
+	}
+	return ""
+}
+
+// Title returns the title statement (245 $a $b) without ISBD punctuation
+func (r Record) Title() string {
+	title := trimPunctuation(r.Subfield("245", "a"))
+	if remainder := trimPunctuation(r.Subfield("245", "b")); remainder != "" {
+		title += ": " + remainder
+	}
+	return title
+}
+
+// Author returns the main entry personal name (100 $a)
+func (r Record) Author() string {
+	return trimPunctuation(r.Subfield("100", "a"))
+}
+
+// ISBN returns the first ISBN (020 $a) without qualifier
+func (r Record) ISBN() string {
+	isbn := r.Subfield("020", "a")
+	if i := strings.IndexAny(isbn, " ("); i >= 0 {
+		isbn = isbn[:i]
+	}
+	return isbn
+}
+
+// Classification returns the first classification number (084 $a)
+func (r Record) Classification() string {
+	return strings.TrimSpace(r.Subfield("084", "a"))
+}
+
+// Subjects returns the topical subject headings (650 $a)
+func (r Record) Subjects() []string {
+	subjects := make([]string, 0)
+	for _, field := range r.DataFields {
+		if field.Tag == "650" {
+			if subject := trimPunctuation(field.Subfield("a")); subject != "" {
+				subjects = append(subjects, subject)
+			}
+		}
+	}
+	return subjects
+}
+
+func trimPunctuation(value string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(value), " /:;,."))
+}
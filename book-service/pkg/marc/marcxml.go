@@ -0,0 +1,81 @@
+package marc
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+const XMLNamespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLReader reads MARCXML records one by one, with or without a collection element
+type XMLReader struct {
+	decoder *xml.Decoder
+}
+
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{
+		decoder: xml.NewDecoder(r),
+	}
+}
+
+// Next returns the next record, or io.EOF when there is no record left
+func (r *XMLReader) Next() (Record, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return Record{}, io.EOF
+			}
+			return Record{}, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var decoded xmlRecord
+		if err = r.decoder.DecodeElement(&decoded, &start); err != nil {
+			return Record{}, err
+		}
+
+		record := Record{Leader: decoded.Leader}
+		for _, field := range decoded.ControlFields {
+			record.ControlFields = append(record.ControlFields, ControlField(field))
+		}
+		for _, field := range decoded.DataFields {
+			dataField := DataField{Tag: field.Tag, Ind1: field.Ind1, Ind2: field.Ind2}
+			for _, subfield := range field.Subfields {
+				dataField.Subfields = append(dataField.Subfields, Subfield(subfield))
+			}
+			record.DataFields = append(record.DataFields, dataField)
+		}
+
+		return record, nil
+	}
+}
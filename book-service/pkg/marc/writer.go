@@ -0,0 +1,73 @@
+package marc
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// XMLWriter writes records as a MARCXML collection, Close must be called to end the collection
+type XMLWriter struct {
+	writer  io.Writer
+	encoder *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	return &XMLWriter{
+		writer:  w,
+		encoder: encoder,
+	}
+}
+
+func (w *XMLWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	if _, err := io.WriteString(w.writer, xml.Header); err != nil {
+		return err
+	}
+	return w.encoder.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "collection"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: XMLNamespace}},
+	})
+}
+
+func (w *XMLWriter) Write(record Record) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	encoded := xmlRecord{Leader: record.Leader}
+	for _, field := range record.ControlFields {
+		encoded.ControlFields = append(encoded.ControlFields, xmlControlField(field))
+	}
+	for _, field := range record.DataFields {
+		dataField := xmlDataField{Tag: field.Tag, Ind1: field.Ind1, Ind2: field.Ind2}
+		for _, subfield := range field.Subfields {
+			dataField.Subfields = append(dataField.Subfields, xmlSubfield(subfield))
+		}
+		encoded.DataFields = append(encoded.DataFields, dataField)
+	}
+
+	return w.encoder.Encode(encoded)
+}
+
+func (w *XMLWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := w.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+	if err := w.encoder.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w.writer, "\n")
+	return err
+}
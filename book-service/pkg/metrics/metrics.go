@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler serves the metrics of the default registry, including the Go runtime and process metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
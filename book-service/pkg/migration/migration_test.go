@@ -0,0 +1,122 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestPlan(t *testing.T) {
+	noop := func(*mongo.Database) error { return nil }
+	// the versions have a gap, like a migration removed from the scripts
+	migrations := []migrate.Migration{
+		{Version: 1, Description: "create-book-collection", Up: noop, Down: noop},
+		{Version: 3, Description: "create-stock-movement-collection", Up: noop, Down: noop},
+		{Version: 4, Description: "create-category-collection", Up: noop, Down: noop},
+	}
+
+	tests := []struct {
+		name      string
+		current   uint64
+		target    uint64
+		wantSteps []string
+		// wantVersions are the versions recorded after every step
+		wantVersions []uint64
+	}{
+		{
+			name:    "up all",
+			current: 0,
+			target:  UpTarget(migrations, 0, 0),
+			wantSteps: []string{
+				"up 1_create-book-collection",
+				"up 3_create-stock-movement-collection",
+				"up 4_create-category-collection",
+			},
+			wantVersions: []uint64{1, 3, 4},
+		},
+		{
+			name:         "up one",
+			current:      1,
+			target:       UpTarget(migrations, 1, 1),
+			wantSteps:    []string{"up 3_create-stock-movement-collection"},
+			wantVersions: []uint64{3},
+		},
+		{
+			name:         "down one",
+			current:      4,
+			target:       DownTarget(migrations, 4, 1),
+			wantSteps:    []string{"down 4_create-category-collection"},
+			wantVersions: []uint64{3},
+		},
+		{
+			name:         "down more than applied",
+			current:      3,
+			target:       DownTarget(migrations, 3, 5),
+			wantSteps:    []string{"down 3_create-stock-movement-collection", "down 1_create-book-collection"},
+			wantVersions: []uint64{1, 0},
+		},
+		{
+			name:         "to a version below",
+			current:      4,
+			target:       1,
+			wantSteps:    []string{"down 4_create-category-collection", "down 3_create-stock-movement-collection"},
+			wantVersions: []uint64{3, 1},
+		},
+		{
+			name:         "nothing pending",
+			current:      4,
+			target:       UpTarget(migrations, 4, 0),
+			wantSteps:    []string{},
+			wantVersions: []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := Plan(migrations, tt.current, tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotSteps := make([]string, 0, len(steps))
+			gotVersions := make([]uint64, 0, len(steps))
+			for _, step := range steps {
+				gotSteps = append(gotSteps, step.String())
+				gotVersions = append(gotVersions, step.Version)
+			}
+			if !reflect.DeepEqual(gotSteps, tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", gotSteps, tt.wantSteps)
+			}
+			if !reflect.DeepEqual(gotVersions, tt.wantVersions) {
+				t.Errorf("versions = %v, want %v", gotVersions, tt.wantVersions)
+			}
+		})
+	}
+}
+
+func TestPlan_WithoutDown(t *testing.T) {
+	migrations := []migrate.Migration{{Version: 1, Description: "irreversible"}}
+	if _, err := Plan(migrations, 1, 0); err == nil {
+		t.Error("Plan() error = nil, want an error for a migration without down step")
+	}
+}
+
+func TestChecker_Unreachable(t *testing.T) {
+	// the client is never connected, so the database does not answer
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	checker := NewChecker(client.Database("book-service-test"), false)
+
+	for i := 0; i < 2; i++ {
+		if err := checker.Check(context.Background()); !errors.Is(err, ErrUnreachable) {
+			t.Errorf("Check() error = %v, want %v until the database answers", err, ErrUnreachable)
+		}
+	}
+}
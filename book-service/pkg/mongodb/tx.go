@@ -49,3 +49,26 @@ func (s *session) WithTransaction(
 ) (interface{}, error) {
 	return s.session.WithTransaction(ctx, fn)
 }
+
+type noTXRepository struct{}
+
+// NewNoTXRepository returns a TXRepository running the functions without transaction, for the in-memory repositories
+// of the tests, which have nothing to roll back
+func NewNoTXRepository() TXRepository {
+	return noTXRepository{}
+}
+
+func (noTXRepository) StartSession() (Session, error) {
+	return noTXSession{}, nil
+}
+
+type noTXSession struct{}
+
+func (noTXSession) EndSession(context.Context) {}
+
+func (noTXSession) WithTransaction(
+	ctx context.Context,
+	fn func(sessCtx mongo.SessionContext) (interface{}, error),
+) (interface{}, error) {
+	return fn(mongo.NewSessionContext(ctx, nil))
+}
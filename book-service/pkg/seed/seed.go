@@ -0,0 +1,158 @@
+package seed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"book-service/pkg/tenant"
+)
+
+const (
+	Minimal  = "minimal"
+	Demo     = "demo"
+	LoadTest = "load-test"
+
+	batchSize = 1000
+	// idEpoch is the timestamp of the generated object IDs, they sort by their index from it
+	idEpoch = 1577836800
+)
+
+// Profile is how many documents are seeded, the services seed the same profile so the lendings
+// of the lending service refer to the books and the members seeded by the other services
+type Profile struct {
+	Name     string
+	Books    int
+	Members  int
+	Lendings int
+}
+
+// Options are read from the command line of the seed command
+type Options struct {
+	Profile Profile
+	// Seed makes the generated documents, and their IDs, the same on every run
+	Seed     int64
+	TenantID string
+}
+
+const usage = `Usage: seed [--profile minimal|demo|load-test] [--scale N] [--seed N] [--tenant CODE]
+
+Profiles:
+  minimal    the admin users only
+  demo       a small library with members and past lendings
+  load-test  N thousand books, members, and lendings, with N the scale
+
+The profile defaults to SEED_PROFILE, nothing is seeded without profile.
+`
+
+// ParseOptions reads the options of the seed command, the profile is empty when nothing must be seeded
+func ParseOptions(args []string, out io.Writer, defaultProfile string) (Options, error) {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(out, usage)
+		flags.PrintDefaults()
+	}
+	name := flags.String("profile", defaultProfile, "seed profile")
+	scale := flags.Int("scale", 1, "thousands of documents of the load-test profile")
+	seed := flags.Int64("seed", 1, "random seed of the generated documents")
+	tenantID := flags.String("tenant", tenant.Default, "tenant of the seeded documents")
+	if err := flags.Parse(args); err != nil {
+		return Options{}, err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return Options{}, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	profile, err := ProfileByName(*name, *scale)
+	if err != nil {
+		return Options{}, err
+	}
+	return Options{Profile: profile, Seed: *seed, TenantID: *tenantID}, nil
+}
+
+// ProfileByName returns the profile, the scale is the thousands of documents of the load-test profile
+func ProfileByName(name string, scale int) (Profile, error) {
+	switch name {
+	case "":
+		return Profile{}, nil
+	case Minimal:
+		return Profile{Name: name}, nil
+	case Demo:
+		return Profile{Name: name, Books: 12, Members: 10, Lendings: 30}, nil
+	case LoadTest:
+		if scale <= 0 {
+			return Profile{}, fmt.Errorf("invalid scale: %d", scale)
+		}
+		n := scale * 1000
+		return Profile{Name: name, Books: n, Members: n, Lendings: n}, nil
+	default:
+		return Profile{}, fmt.Errorf("unknown profile: %s", name)
+	}
+}
+
+// ID returns the object ID of the i-th document of a kind, the same for a seed and a tenant
+func (o Options) ID(kind string, i int) primitive.ObjectID {
+	sum := o.hash(kind, i)
+
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[:4], uint32(idEpoch+i))
+	copy(id[4:], sum[:8])
+	return id
+}
+
+// Rand returns the random source of the i-th document of a kind, so a document does not depend on
+// how many documents are seeded before it
+func (o Options) Rand(kind string, i int) *rand.Rand {
+	sum := o.hash(kind, i)
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+}
+
+func (o Options) hash(kind string, i int) [sha256.Size]byte {
+	return sha256.Sum256([]byte(fmt.Sprintf("%d/%s/%s/%d", o.Seed, o.TenantID, kind, i)))
+}
+
+// Document is inserted unless a document matches its filter
+type Document struct {
+	Filter bson.D
+	Value  interface{}
+}
+
+// InsertMissing inserts the documents not found by their filter, the existing ones are left unchanged.
+// It returns how many documents are inserted.
+func InsertMissing(ctx context.Context, collection *mongo.Collection, documents []Document) (int64, error) {
+	var inserted int64
+	for start := 0; start < len(documents); start += batchSize {
+		end := start + batchSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+
+		models := make([]mongo.WriteModel, 0, end-start)
+		for _, document := range documents[start:end] {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(document.Filter).
+				SetUpdate(bson.D{{"$setOnInsert", document.Value}}).
+				SetUpsert(true))
+		}
+
+		result, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return inserted, err
+		}
+		inserted += result.UpsertedCount
+	}
+
+	return inserted, nil
+}
@@ -0,0 +1,58 @@
+package seed
+
+import (
+	"testing"
+)
+
+func TestOptions_ID(t *testing.T) {
+	options := Options{Seed: 1, TenantID: "default"}
+
+	if options.ID("book", 3) != options.ID("book", 3) {
+		t.Error("ID() differs between calls with the same seed")
+	}
+	if options.ID("book", 3) == options.ID("member", 3) {
+		t.Error("ID() is the same for two kinds")
+	}
+	if other := (Options{Seed: 2, TenantID: "default"}); options.ID("book", 3) == other.ID("book", 3) {
+		t.Error("ID() is the same for two seeds")
+	}
+	if options.ID("book", 3).Hex() >= options.ID("book", 4).Hex() {
+		t.Error("ID() does not sort by index")
+	}
+}
+
+func TestOptions_Rand(t *testing.T) {
+	options := Options{Seed: 42, TenantID: "default"}
+
+	if options.Rand("lending", 7).Int63() != options.Rand("lending", 7).Int63() {
+		t.Error("Rand() differs between calls with the same seed")
+	}
+}
+
+func TestProfileByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		scale   int
+		want    Profile
+		wantErr bool
+	}{
+		{name: "", want: Profile{}},
+		{name: Minimal, want: Profile{Name: Minimal}},
+		{name: Demo, want: Profile{Name: Demo, Books: 12, Members: 10, Lendings: 30}},
+		{name: LoadTest, scale: 2, want: Profile{Name: LoadTest, Books: 2000, Members: 2000, Lendings: 2000}},
+		{name: LoadTest, scale: 0, wantErr: true},
+		{name: "production", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ProfileByName(tt.name, tt.scale)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProfileByName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ProfileByName() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package testserver serves the book service backed by in-memory repositories, so the tests spanning the services run
+// in one process without MongoDB.
+package testserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"book-service/internal/event"
+	"book-service/internal/repository"
+	"book-service/internal/service"
+	"book-service/pkg/blob"
+	"book-service/pkg/logger"
+	"book-service/pkg/mongodb"
+	"book-service/pkg/proto"
+	"book-service/pkg/tenant"
+)
+
+// the events are relayed soon after the requests
+const (
+	relayInterval    = 10 * time.Millisecond
+	relayMaxAttempts = 10
+	eventBufferSize  = 100
+)
+
+// Server is the book service, its repositories live as long as it
+type Server struct {
+	*grpc.Server
+	lendingGRPCClientConn *grpc.ClientConn
+	publisher             *event.ChannelPublisher
+	stopRelay             context.CancelFunc
+	relayDone             chan struct{}
+}
+
+// New returns the book service calling the lending service through lendingServiceDialer, the covers are stored under
+// blobPath. The connection is not blocking, so the services can be created in any order.
+func New(lendingServiceDialer func(context.Context, string) (net.Conn, error), blobPath string) (*Server, error) {
+	lendingGRPCClientConn, err := grpc.Dial(
+		"lending-service",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(lendingServiceDialer),
+		grpc.WithChainUnaryInterceptor(tenant.UnaryClientInterceptor(), logger.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tenant.StreamClientInterceptor(), logger.StreamClientInterceptor()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	blobStore, err := blob.NewFileSystemStore(blobPath)
+	if err != nil {
+		_ = lendingGRPCClientConn.Close()
+		return nil, err
+	}
+
+	outboxRepository := repository.NewOutboxMemoryRepository()
+	bookService := service.NewBookGRPCService(
+		repository.NewBookMemoryRepository(),
+		repository.NewStockMovementMemoryRepository(),
+		repository.NewCategoryMemoryRepository(),
+		repository.NewImportJobMemoryRepository(),
+		repository.NewBranchMemoryRepository(),
+		repository.NewTransferMemoryRepository(),
+		outboxRepository,
+		mongodb.NewNoTXRepository(),
+		proto.NewLendingServiceClient(lendingGRPCClientConn),
+		blobStore,
+	)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(tenant.UnaryServerInterceptor(), logger.UnaryServerInterceptor(), logger.RecoveryUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(tenant.StreamServerInterceptor(), logger.StreamServerInterceptor(), logger.RecoveryStreamServerInterceptor()),
+	)
+	proto.RegisterBookServiceServer(server, bookService)
+
+	s := &Server{
+		Server:                server,
+		lendingGRPCClientConn: lendingGRPCClientConn,
+		publisher:             event.NewChannelPublisher(eventBufferSize),
+		relayDone:             make(chan struct{}),
+	}
+
+	var relayCtx context.Context
+	relayCtx, s.stopRelay = context.WithCancel(context.Background())
+	go func() {
+		defer close(s.relayDone)
+		event.NewRelay(outboxRepository, s.publisher, relayInterval, relayMaxAttempts).Run(relayCtx)
+	}()
+
+	return s, nil
+}
+
+// Events receives the events published by the service. The events wait in the outbox while the channel is full,
+// so a test not reading them does not block the service.
+func (s *Server) Events() <-chan *proto.Event {
+	return s.publisher.Events()
+}
+
+// Stop stops serving and relaying the events, and closes the connection to the lending service
+func (s *Server) Stop() {
+	s.Server.Stop()
+	s.stopRelay()
+	<-s.relayDone
+	_ = s.lendingGRPCClientConn.Close()
+}
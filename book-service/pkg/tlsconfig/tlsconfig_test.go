@@ -0,0 +1,227 @@
+package tlsconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"book-service/pkg/config"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	ca := &testCA{cert: cert, key: key, dir: t.TempDir()}
+	writePEM(t, ca.path("ca.pem"), "CERTIFICATE", der)
+	return ca
+}
+
+func (ca *testCA) path(name string) string {
+	return filepath.Join(ca.dir, name)
+}
+
+// issue writes the certificate and the key of a service under its name, valid as server and as client
+func (ca *testCA) issue(t *testing.T, name string, serial int64) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile = ca.path(name+".pem"), ca.path(name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve starts a gRPC server with the health service on a local port
+func serve(t *testing.T, cfg config.TLS) string {
+	t.Helper()
+
+	option, err := ServerOption(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(option)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func check(t *testing.T, target string, cfg config.TLS) error {
+	t.Helper()
+
+	option, err := DialOption(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, target, option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestTLS(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "book-service", 2)
+	clientCertFile, clientKeyFile := ca.issue(t, "api-gateway", 3)
+
+	t.Run("server TLS", func(t *testing.T) {
+		target := serve(t, config.TLS{CertFile: certFile, KeyFile: keyFile})
+
+		if err := check(t, target, config.TLS{CAFile: ca.path("ca.pem")}); err != nil {
+			t.Errorf("Check() with the CA error = %v", err)
+		}
+	})
+
+	t.Run("server certificate of another CA", func(t *testing.T) {
+		target := serve(t, config.TLS{CertFile: certFile, KeyFile: keyFile})
+		other := newTestCA(t)
+
+		if err := check(t, target, config.TLS{CAFile: other.path("ca.pem")}); err == nil {
+			t.Error("Check() error = nil, want the server certificate rejected")
+		}
+	})
+
+	t.Run("mutual TLS", func(t *testing.T) {
+		target := serve(t, config.TLS{CertFile: certFile, KeyFile: keyFile, CAFile: ca.path("ca.pem"), ClientAuth: true})
+
+		if err := check(t, target, config.TLS{CAFile: ca.path("ca.pem")}); err == nil {
+			t.Error("Check() without client certificate error = nil, want it rejected")
+		}
+		err := check(t, target, config.TLS{CertFile: clientCertFile, KeyFile: clientKeyFile, CAFile: ca.path("ca.pem")})
+		if err != nil {
+			t.Errorf("Check() with the client certificate error = %v", err)
+		}
+	})
+}
+
+func TestReloader(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "book-service", 2)
+
+	r, err := newReloader(certFile, keyFile, ca.path("ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := r.current()
+
+	// the rotation writes the files again, with a later modification time
+	ca.issue(t, "book-service", 4)
+	later := time.Now().Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		if err = os.Chtimes(file, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if after, _ := r.current(); !bytes.Equal(after.Certificate[0], before.Certificate[0]) {
+		t.Error("current() reloaded before the check interval")
+	}
+
+	r.checkedAt = time.Time{}
+	after, _ := r.current()
+	if bytes.Equal(after.Certificate[0], before.Certificate[0]) {
+		t.Error("current() kept the certificate after the rotation")
+	}
+}
+
+func TestServerOption_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.TLS
+	}{
+		{name: "client auth without certificate", cfg: config.TLS{ClientAuth: true}},
+		{name: "certificate without key", cfg: config.TLS{CertFile: "cert.pem"}},
+		{name: "client auth without CA", cfg: config.TLS{CertFile: "cert.pem", KeyFile: "key.pem", ClientAuth: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ServerOption(tt.cfg); err == nil {
+				t.Error("ServerOption() error = nil, want an error")
+			}
+		})
+	}
+}
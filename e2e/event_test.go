@@ -0,0 +1,51 @@
+package e2e
+
+import (
+	"testing"
+
+	bookProto "book-service/pkg/proto"
+	"e2e/harness"
+	lendingProto "lending-service/pkg/proto"
+)
+
+func TestEvents(t *testing.T) {
+	h := harness.Start(t)
+	librarian := h.LoginAsLibrarian(t)
+	member := h.LoginAsMember(t)
+
+	registered := h.WaitForEvent(t, "user.registered", member.ID)
+	if registered.Source != "user-service" || registered.TenantID != "default" {
+		t.Errorf("user.registered = %+v, want from user-service for the default tenant", registered)
+	}
+
+	dune := createBook(t, librarian, "Dune", 1)
+	h.WaitForEvent(t, "book.created", dune.ID)
+
+	lent, err := lendBook(member, dune.ID)
+	if err != nil {
+		t.Fatalf("lendBook() error = %v", err)
+	}
+
+	var created lendingProto.LendingCreated
+	if err = h.WaitForEvent(t, "lending.created", lent.ID).Payload.UnmarshalTo(&created); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	if created.BookId != dune.ID || created.UserId != member.ID {
+		t.Errorf("lending.created = %v, want %s lent by %s", &created, dune.ID, member.ID)
+	}
+
+	// the stock of the book changes twice, for the purchase and then for the lending
+	wantReasons := []string{"purchase", "lending"}
+	for i, event := range h.WaitForEvents(t, "book.stock_changed", dune.ID, len(wantReasons)) {
+		var stockChanged bookProto.StockChanged
+		if err = event.Payload.UnmarshalTo(&stockChanged); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if stockChanged.Reason != wantReasons[i] {
+			t.Errorf("book.stock_changed[%d] reason = %s, want %s", i, stockChanged.Reason, wantReasons[i])
+		}
+		if stockChanged.Reason == "lending" && (stockChanged.LendingId != lent.ID || stockChanged.Stock != 0) {
+			t.Errorf("book.stock_changed of the lending = %v, want lending %s leaving no stock", &stockChanged, lent.ID)
+		}
+	}
+}
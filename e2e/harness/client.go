@@ -0,0 +1,102 @@
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// the roles of the users, as the services name them
+const (
+	RoleAdmin     = "admin"
+	RoleLibrarian = "librarian"
+	RoleMember    = "member"
+)
+
+// Client sends the GraphQL operations of a user to the gateway, it is safe for concurrent use
+type Client struct {
+	handler http.Handler
+	token   string
+
+	// ID is the ID of a registered user, empty for the admin and the anonymous client
+	ID    string
+	Email string
+}
+
+// Error is an error of the response, the services errors have their gRPC code in the code extension
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+// Errors are the errors of a response
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Code returns the gRPC code of the first error, empty when it does not come from a service
+func (e Errors) Code() string {
+	if len(e) == 0 {
+		return ""
+	}
+	code, _ := e[0].Extensions["code"].(string)
+	return code
+}
+
+// Do runs a GraphQL operation and decodes its data into data, which is skipped when nil. The errors of the response
+// are returned as Errors, the data is decoded even then since it is partial.
+func (c *Client) Do(query string, variables map[string]interface{}, data interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/query", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	recorder := httptest.NewRecorder()
+	c.handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		return fmt.Errorf("status %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors Errors          `json:"errors"`
+	}
+	if err = json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		return err
+	}
+	if data != nil && len(response.Data) > 0 && string(response.Data) != "null" {
+		if err = json.Unmarshal(response.Data, data); err != nil {
+			return err
+		}
+	}
+	if len(response.Errors) > 0 {
+		return response.Errors
+	}
+	return nil
+}
+
+// MustDo runs a GraphQL operation, failing the test on any error
+func (c *Client) MustDo(t testing.TB, query string, variables map[string]interface{}, data interface{}) {
+	t.Helper()
+
+	if err := c.Do(query, variables, data); err != nil {
+		t.Fatalf("GraphQL operation failed: %v\n%s", err, query)
+	}
+}
@@ -0,0 +1,121 @@
+package harness
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	bookProto "book-service/pkg/proto"
+	lendingProto "lending-service/pkg/proto"
+	userProto "user-service/pkg/proto"
+)
+
+// eventTimeout is how long WaitForEvent waits for the relays, which run every few milliseconds
+const eventTimeout = 2 * time.Second
+
+// Event is a domain event published by one of the services, the payload is unmarshaled with the proto of its type
+type Event struct {
+	ID          string
+	Type        string
+	Version     int32
+	Source      string
+	TenantID    string
+	AggregateID string
+	Payload     *anypb.Any
+}
+
+// eventLog collects the events of every service, so the channels of the services never fill up, and forwards them to
+// the consumers like the broker does
+type eventLog struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	events  []Event
+	forward func(Event)
+}
+
+func newEventLog(forward func(Event)) *eventLog {
+	l := &eventLog{forward: forward}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *eventLog) add(event Event) {
+	l.mu.Lock()
+	l.events = append(l.events, event)
+	l.mu.Unlock()
+	l.cond.Broadcast()
+
+	l.forward(event)
+}
+
+// collect reads the events of the services until done is closed. Each service has its own copy of the event proto,
+// so each one has its own loop.
+func (l *eventLog) collect(done <-chan struct{}, book <-chan *bookProto.Event, user <-chan *userProto.Event,
+	lending <-chan *lendingProto.Event) {
+	go func() {
+		for {
+			select {
+			case e := <-book:
+				l.add(Event{e.Id, e.Type, e.Version, e.Source, e.TenantId, e.AggregateId, e.Payload})
+			case <-done:
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case e := <-user:
+				l.add(Event{e.Id, e.Type, e.Version, e.Source, e.TenantId, e.AggregateId, e.Payload})
+			case <-done:
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case e := <-lending:
+				l.add(Event{e.Id, e.Type, e.Version, e.Source, e.TenantId, e.AggregateId, e.Payload})
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// WaitForEvent returns the first event of the type about the aggregate, failing the test when it is not published
+// in time
+func (h *Harness) WaitForEvent(t testing.TB, eventType, aggregateID string) Event {
+	t.Helper()
+	return h.WaitForEvents(t, eventType, aggregateID, 1)[0]
+}
+
+// WaitForEvents returns the first count events of the type about the aggregate, in the order they were published
+func (h *Harness) WaitForEvents(t testing.TB, eventType, aggregateID string, count int) []Event {
+	t.Helper()
+
+	timer := time.AfterFunc(eventTimeout, h.events.cond.Broadcast)
+	defer timer.Stop()
+	deadline := time.Now().Add(eventTimeout)
+
+	h.events.mu.Lock()
+	defer h.events.mu.Unlock()
+	for {
+		found := make([]Event, 0, count)
+		for _, event := range h.events.events {
+			if event.Type == eventType && event.AggregateID == aggregateID {
+				found = append(found, event)
+			}
+			if len(found) == count {
+				return found
+			}
+		}
+		if !time.Now().Before(deadline) {
+			t.Fatalf("%d %s event(s) of %s are not published after %v, got %d", count, eventType, aggregateID, eventTimeout, len(found))
+		}
+		h.events.cond.Wait()
+	}
+}
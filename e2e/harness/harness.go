@@ -0,0 +1,188 @@
+// Package harness boots the book, user, lending and notification services and the GraphQL gateway in one process,
+// connected through bufconn and backed by in-memory repositories, so the scenarios spanning the services run without
+// docker-compose.
+//
+// Each service generates its own copy of the shared protos, so the test binaries run with
+// GOLANG_PROTOBUF_REGISTRATION_CONFLICT=ignore like `make test` does, otherwise Start fails the test.
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc/test/bufconn"
+
+	gatewayServer "api-gateway/pkg/testserver"
+	bookServer "book-service/pkg/testserver"
+	"e2e/harness/internal/protoconflict"
+	lendingServer "lending-service/pkg/testserver"
+	notificationProto "notification-service/pkg/proto"
+	notificationServer "notification-service/pkg/testserver"
+	userServer "user-service/pkg/testserver"
+)
+
+const (
+	bufferSize = 1024 * 1024
+
+	jwtSecretKey = "harness-secret"
+	// Password is the password of every user of the harness
+	Password = "harness-password"
+	// AdminEmail is the admin of the default tenant, created on start
+	AdminEmail = "admin@harness.test"
+)
+
+// Harness is a running library, every test gets its own
+type Harness struct {
+	gateway *gatewayServer.Server
+	events  *eventLog
+
+	userCount int64
+
+	registrarMutex sync.Mutex
+	registrar      *Client
+}
+
+// Start boots the services and the gateway, they are stopped with the test
+func Start(t testing.TB) *Harness {
+	t.Helper()
+
+	if protoconflict.Missing {
+		t.Fatalf("%s is not set: every service links its own copy of the same protos, run the tests with "+
+			"%s=ignore, e.g. with make test", protoconflict.Env, protoconflict.Env)
+	}
+
+	userListener := bufconn.Listen(bufferSize)
+	bookListener := bufconn.Listen(bufferSize)
+	lendingListener := bufconn.Listen(bufferSize)
+	notificationListener := bufconn.Listen(bufferSize)
+
+	userService, err := userServer.New(dialer(lendingListener), dialer(bookListener), jwtSecretKey)
+	if err != nil {
+		t.Fatalf("starting user service: %v", err)
+	}
+	t.Cleanup(userService.Stop)
+
+	bookService, err := bookServer.New(dialer(lendingListener), t.TempDir())
+	if err != nil {
+		t.Fatalf("starting book service: %v", err)
+	}
+	t.Cleanup(bookService.Stop)
+
+	lendingService, err := lendingServer.New(dialer(userListener), dialer(bookListener))
+	if err != nil {
+		t.Fatalf("starting lending service: %v", err)
+	}
+	t.Cleanup(lendingService.Stop)
+
+	// the messages are checked through the notifications of the gateway
+	notificationService := notificationServer.New(io.Discard)
+	t.Cleanup(notificationService.Stop)
+
+	// Stop closes the listeners, so Serve only returns once the test is over
+	go func() { _ = userService.Serve(userListener) }()
+	go func() { _ = bookService.Serve(bookListener) }()
+	go func() { _ = lendingService.Serve(lendingListener) }()
+	go func() { _ = notificationService.Serve(notificationListener) }()
+
+	events := newEventLog(func(e Event) {
+		notificationService.Receive(&notificationProto.Event{
+			Id:          e.ID,
+			Type:        e.Type,
+			Version:     e.Version,
+			Source:      e.Source,
+			TenantId:    e.TenantID,
+			AggregateId: e.AggregateID,
+			Payload:     e.Payload,
+		})
+	})
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	events.collect(done, bookService.Events(), userService.Events(), lendingService.Events())
+
+	gateway, err := gatewayServer.New(gatewayServer.Dialers{
+		UserService:         dialer(userListener),
+		BookService:         dialer(bookListener),
+		LendingService:      dialer(lendingListener),
+		NotificationService: dialer(notificationListener),
+	}, jwtSecretKey)
+	if err != nil {
+		t.Fatalf("starting gateway: %v", err)
+	}
+	t.Cleanup(gateway.Stop)
+
+	if err = userService.CreateUser(context.Background(), AdminEmail, Password, RoleAdmin); err != nil {
+		t.Fatalf("creating admin: %v", err)
+	}
+
+	return &Harness{gateway: gateway, events: events}
+}
+
+func dialer(listener *bufconn.Listener) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}
+}
+
+// Anonymous returns a client without token
+func (h *Harness) Anonymous() *Client {
+	return &Client{handler: h.gateway}
+}
+
+// Login logs in through the gateway
+func (h *Harness) Login(t testing.TB, email, password string) *Client {
+	t.Helper()
+
+	var data struct {
+		Login string `json:"login"`
+	}
+	h.Anonymous().MustDo(t, `mutation($input: Login!) { login(input: $input) }`,
+		map[string]interface{}{"input": map[string]interface{}{"email": email, "password": password}}, &data)
+
+	return &Client{handler: h.gateway, Email: email, token: data.Login}
+}
+
+// LoginAsAdmin logs in as the admin created on start
+func (h *Harness) LoginAsAdmin(t testing.TB) *Client {
+	t.Helper()
+	return h.Login(t, AdminEmail, Password)
+}
+
+// LoginAsLibrarian registers a new librarian of every branch with the admin, then logs in as them
+func (h *Harness) LoginAsLibrarian(t testing.TB) *Client {
+	t.Helper()
+	return h.register(t, h.LoginAsAdmin(t), "registerLibrarian", RoleLibrarian)
+}
+
+// LoginAsMember registers a new member with a librarian, then logs in as them
+func (h *Harness) LoginAsMember(t testing.TB) *Client {
+	t.Helper()
+
+	h.registrarMutex.Lock()
+	if h.registrar == nil {
+		h.registrar = h.LoginAsLibrarian(t)
+	}
+	registrar := h.registrar
+	h.registrarMutex.Unlock()
+
+	return h.register(t, registrar, "registerMember", RoleMember)
+}
+
+func (h *Harness) register(t testing.TB, registrar *Client, mutation, role string) *Client {
+	t.Helper()
+
+	email := fmt.Sprintf("%s%d@harness.test", role, atomic.AddInt64(&h.userCount, 1))
+	var data map[string]struct {
+		ID string `json:"id"`
+	}
+	registrar.MustDo(t, fmt.Sprintf(`mutation($input: NewUser!) { %s(input: $input) { id } }`, mutation),
+		map[string]interface{}{"input": map[string]interface{}{"email": email, "password": Password}}, &data)
+
+	client := h.Login(t, email, Password)
+	client.ID = data[mutation].ID
+	return client
+}
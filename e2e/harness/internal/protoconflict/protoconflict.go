@@ -0,0 +1,20 @@
+// Package protoconflict turns the registration panic of the protos into a test failure. Every service generates its
+// own copy of the shared protos, so one binary linking them all only starts with
+// GOLANG_PROTOBUF_REGISTRATION_CONFLICT=ignore. Without it the package ignores the conflicts just long enough for the
+// harness to fail the test with a clear message. It only depends on os, so it is initialized before the proto
+// packages.
+package protoconflict
+
+import "os"
+
+// Env is the variable ignoring the registration conflicts of the protos
+const Env = "GOLANG_PROTOBUF_REGISTRATION_CONFLICT"
+
+// Missing is true when the tests are run without Env, the harness refuses to start then
+var Missing = os.Getenv(Env) == ""
+
+func init() {
+	if Missing {
+		_ = os.Setenv(Env, "ignore")
+	}
+}
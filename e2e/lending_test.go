@@ -0,0 +1,168 @@
+package e2e
+
+import (
+	"sync"
+	"testing"
+
+	"e2e/harness"
+)
+
+type book struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Stock int    `json:"stock"`
+}
+
+type lending struct {
+	ID     string `json:"id"`
+	BookID string `json:"bookID"`
+	UserID string `json:"userID"`
+	Status string `json:"status"`
+}
+
+func createBook(t *testing.T, librarian *harness.Client, title string, stock int) book {
+	t.Helper()
+
+	var data struct {
+		CreateBook      book `json:"createBook"`
+		UpdateBookStock book `json:"updateBookStock"`
+	}
+	librarian.MustDo(t, `mutation($input: NewBook!) { createBook(input: $input) { id } }`,
+		map[string]interface{}{"input": map[string]interface{}{"title": title, "stock": 0}}, &data)
+	// the stock of a new book comes from a purchase at the default branch
+	librarian.MustDo(t, `mutation($input: UpdateBookStock!) { updateBookStock(input: $input) { id title stock } }`,
+		map[string]interface{}{"input": map[string]interface{}{"id": data.CreateBook.ID, "stockChange": stock, "reason": "purchase"}}, &data)
+	return data.UpdateBookStock
+}
+
+func findBook(t *testing.T, client *harness.Client, title string) book {
+	t.Helper()
+
+	var data struct {
+		FetchBook struct {
+			Books []book `json:"books"`
+		} `json:"fetchBook"`
+	}
+	client.MustDo(t, `mutation($input: FetchBookFilter!) { fetchBook(input: $input) { books { id title stock } } }`,
+		map[string]interface{}{"input": map[string]interface{}{"page": 1, "limit": 10, "title": title}}, &data)
+	if len(data.FetchBook.Books) != 1 {
+		t.Fatalf("fetchBook(%s) = %+v, want one book", title, data.FetchBook.Books)
+	}
+	return data.FetchBook.Books[0]
+}
+
+const lendBookMutation = `mutation($input: NewLending!) { lendBook(input: $input) { id bookID userID status } }`
+
+func lendBook(member *harness.Client, bookID string) (lending, error) {
+	var data struct {
+		LendBook lending `json:"lendBook"`
+	}
+	err := member.Do(lendBookMutation, map[string]interface{}{"input": map[string]interface{}{"bookID": bookID}}, &data)
+	return data.LendBook, err
+}
+
+func TestLendBook(t *testing.T) {
+	h := harness.Start(t)
+	librarian := h.LoginAsLibrarian(t)
+	member := h.LoginAsMember(t)
+
+	dune := createBook(t, librarian, "Dune", 1)
+
+	got, err := lendBook(member, dune.ID)
+	if err != nil {
+		t.Fatalf("lendBook() error = %v", err)
+	}
+	if got.BookID != dune.ID || got.UserID != member.ID || got.Status != "ACTIVE" {
+		t.Errorf("lendBook() = %+v, want an active lending of %s by %s", got, dune.ID, member.ID)
+	}
+	if stock := findBook(t, member, "Dune").Stock; stock != 0 {
+		t.Errorf("stock after lending = %d, want 0", stock)
+	}
+
+	var data struct {
+		FinishLending lending `json:"finishLending"`
+	}
+	librarian.MustDo(t, `mutation($input: FinishLendingRequest!) { finishLending(input: $input) { id status } }`,
+		map[string]interface{}{"input": map[string]interface{}{"id": got.ID}}, &data)
+	if data.FinishLending.Status != "INACTIVE" {
+		t.Errorf("finishLending() = %+v, want INACTIVE", data.FinishLending)
+	}
+	if stock := findBook(t, member, "Dune").Stock; stock != 1 {
+		t.Errorf("stock after return = %d, want 1", stock)
+	}
+}
+
+func TestLendBook_Unauthorized(t *testing.T) {
+	h := harness.Start(t)
+	librarian := h.LoginAsLibrarian(t)
+	dune := createBook(t, librarian, "Dune", 1)
+
+	tests := []struct {
+		name   string
+		client *harness.Client
+	}{
+		{
+			name:   "anonymous",
+			client: h.Anonymous(),
+		},
+		{
+			name:   "librarian",
+			client: librarian,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := lendBook(tt.client, dune.ID); err == nil {
+				t.Errorf("lendBook() error = nil, want unauthorized")
+			}
+		})
+	}
+}
+
+// two members race for the last copy, exactly one of them gets it and the other lending is never active
+func TestLendBook_RaceForLastCopy(t *testing.T) {
+	h := harness.Start(t)
+	librarian := h.LoginAsLibrarian(t)
+	members := []*harness.Client{h.LoginAsMember(t), h.LoginAsMember(t)}
+
+	dune := createBook(t, librarian, "Dune", 1)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(members))
+	start := make(chan struct{})
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, member *harness.Client) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = lendBook(member, dune.ID)
+		}(i, member)
+	}
+	close(start)
+	wg.Wait()
+
+	lent := 0
+	for _, err := range errs {
+		if err == nil {
+			lent++
+		}
+	}
+	if lent != 1 {
+		t.Fatalf("lendBook() errors = %v, want exactly one lending", errs)
+	}
+
+	if stock := findBook(t, librarian, "Dune").Stock; stock != 0 {
+		t.Errorf("stock after the race = %d, want 0", stock)
+	}
+
+	var data struct {
+		FetchLending struct {
+			Lendings []lending `json:"lendings"`
+		} `json:"fetchLending"`
+	}
+	librarian.MustDo(t, `mutation($input: FetchLendingRequest) { fetchLending(input: $input) { lendings { id status } } }`,
+		map[string]interface{}{"input": map[string]interface{}{"page": 1, "limit": 10, "status": "ACTIVE"}}, &data)
+	if got := len(data.FetchLending.Lendings); got != 1 {
+		t.Errorf("active lendings = %d, want 1", got)
+	}
+}
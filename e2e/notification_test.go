@@ -0,0 +1,94 @@
+package e2e
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"e2e/harness"
+)
+
+// notificationTimeout is how long the tests wait for the dispatcher of the notification service
+const notificationTimeout = 2 * time.Second
+
+type notification struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Channel   string `json:"channel"`
+	Recipient string `json:"recipient"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+}
+
+const myNotificationMutation = `mutation($input: MyNotificationRequest) {
+	myNotification(input: $input) { notifications { id kind channel recipient status attempts } }
+}`
+
+// waitForNotification polls the notifications of the member until one of kind is sent
+func waitForNotification(t *testing.T, member *harness.Client, kind string) notification {
+	t.Helper()
+
+	deadline := time.Now().Add(notificationTimeout)
+	for {
+		var data struct {
+			MyNotification struct {
+				Notifications []notification `json:"notifications"`
+			} `json:"myNotification"`
+		}
+		member.MustDo(t, myNotificationMutation,
+			map[string]interface{}{"input": map[string]interface{}{"status": "SENT"}}, &data)
+		for _, n := range data.MyNotification.Notifications {
+			if n.Kind == kind {
+				return n
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			t.Fatalf("no %s notification sent to %s after %s", kind, member.ID, notificationTimeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNotification_AccountCreated(t *testing.T) {
+	h := harness.Start(t)
+	member := h.LoginAsMember(t)
+
+	got := waitForNotification(t, member, "account_created")
+	if got.Channel != "email" || got.Recipient != member.Email || got.Attempts != 1 {
+		t.Errorf("account_created = %+v, want one email to %s", got, member.Email)
+	}
+}
+
+func TestNotification_UpdatePreference(t *testing.T) {
+	h := harness.Start(t)
+	member := h.LoginAsMember(t)
+
+	const mutation = `mutation($input: UpdateNotificationPreference!) {
+		updateMyNotificationPreference(input: $input) { userID email phone channels }
+	}`
+
+	// a channel needs its address
+	err := member.Do(mutation, map[string]interface{}{"input": map[string]interface{}{"channels": []string{"sms"}}}, nil)
+	var errs harness.Errors
+	if !errors.As(err, &errs) || errs.Code() != "InvalidArgument" {
+		t.Errorf("updateMyNotificationPreference() without phone error = %v, want InvalidArgument", err)
+	}
+
+	var data struct {
+		UpdateMyNotificationPreference struct {
+			UserID   string   `json:"userID"`
+			Email    string   `json:"email"`
+			Phone    string   `json:"phone"`
+			Channels []string `json:"channels"`
+		} `json:"updateMyNotificationPreference"`
+	}
+	member.MustDo(t, mutation, map[string]interface{}{"input": map[string]interface{}{
+		"phone": "+15550100", "channels": []string{"email", "sms"},
+	}}, &data)
+
+	got := data.UpdateMyNotificationPreference
+	if got.UserID != member.ID || got.Email != member.Email || got.Phone != "+15550100" || len(got.Channels) != 2 {
+		t.Errorf("updateMyNotificationPreference() = %+v, want email and sms for %s", got, member.ID)
+	}
+}
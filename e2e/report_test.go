@@ -0,0 +1,105 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"e2e/harness"
+)
+
+const reportsQuery = `query($input: ReportRequest!) {
+	reports(input: $input) {
+		loansPerPeriod { period loans returns }
+		topBorrowedBooks { bookID book { title } loans }
+		neverBorrowedBooks { title }
+		summary { loans returns overdue activeMembers }
+		activeMembers { userID user { email } loans active }
+	}
+}`
+
+type reports struct {
+	LoansPerPeriod []struct {
+		Period  string `json:"period"`
+		Loans   int    `json:"loans"`
+		Returns int    `json:"returns"`
+	} `json:"loansPerPeriod"`
+	TopBorrowedBooks []struct {
+		BookID string `json:"bookID"`
+		Book   struct {
+			Title string `json:"title"`
+		} `json:"book"`
+		Loans int `json:"loans"`
+	} `json:"topBorrowedBooks"`
+	NeverBorrowedBooks []book `json:"neverBorrowedBooks"`
+	Summary            struct {
+		Loans         int `json:"loans"`
+		Returns       int `json:"returns"`
+		Overdue       int `json:"overdue"`
+		ActiveMembers int `json:"activeMembers"`
+	} `json:"summary"`
+	ActiveMembers []struct {
+		UserID string `json:"userID"`
+		User   struct {
+			Email string `json:"email"`
+		} `json:"user"`
+		Loans  int `json:"loans"`
+		Active int `json:"active"`
+	} `json:"activeMembers"`
+}
+
+func TestReports(t *testing.T) {
+	h := harness.Start(t)
+	librarian := h.LoginAsLibrarian(t)
+	member := h.LoginAsMember(t)
+
+	dune := createBook(t, librarian, "Dune", 2)
+	createBook(t, librarian, "Solaris", 1)
+
+	lent, err := lendBook(member, dune.ID)
+	if err != nil {
+		t.Fatalf("lendBook() error = %v", err)
+	}
+	librarian.MustDo(t, `mutation($input: FinishLendingRequest!) { finishLending(input: $input) { id } }`,
+		map[string]interface{}{"input": map[string]interface{}{"id": lent.ID}}, nil)
+	if _, err := lendBook(member, dune.ID); err != nil {
+		t.Fatalf("lendBook() error = %v", err)
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+	var data struct {
+		Reports reports `json:"reports"`
+	}
+	librarian.MustDo(t, reportsQuery, map[string]interface{}{"input": map[string]interface{}{"from": today, "to": today}}, &data)
+	got := data.Reports
+
+	if len(got.LoansPerPeriod) != 1 || got.LoansPerPeriod[0].Period != today ||
+		got.LoansPerPeriod[0].Loans != 2 || got.LoansPerPeriod[0].Returns != 1 {
+		t.Errorf("loansPerPeriod = %+v, want 2 loans and 1 return today", got.LoansPerPeriod)
+	}
+	if len(got.TopBorrowedBooks) != 1 || got.TopBorrowedBooks[0].Book.Title != "Dune" || got.TopBorrowedBooks[0].Loans != 2 {
+		t.Errorf("topBorrowedBooks = %+v, want Dune lent twice", got.TopBorrowedBooks)
+	}
+	if len(got.NeverBorrowedBooks) != 1 || got.NeverBorrowedBooks[0].Title != "Solaris" {
+		t.Errorf("neverBorrowedBooks = %+v, want Solaris", got.NeverBorrowedBooks)
+	}
+	if got.Summary.Loans != 2 || got.Summary.Returns != 1 || got.Summary.Overdue != 0 || got.Summary.ActiveMembers != 1 {
+		t.Errorf("summary = %+v, want 2 loans, 1 return and 1 member", got.Summary)
+	}
+	if len(got.ActiveMembers) != 1 || got.ActiveMembers[0].User.Email != member.Email ||
+		got.ActiveMembers[0].Loans != 2 || got.ActiveMembers[0].Active != 1 {
+		t.Errorf("activeMembers = %+v, want %s with 2 loans, 1 active", got.ActiveMembers, member.Email)
+	}
+}
+
+func TestReports_Unauthorized(t *testing.T) {
+	h := harness.Start(t)
+
+	input := map[string]interface{}{"input": map[string]interface{}{"from": "2021-02-01", "to": "2021-02-28"}}
+	for name, client := range map[string]*harness.Client{"anonymous": h.Anonymous(), "member": h.LoginAsMember(t)} {
+		t.Run(name, func(t *testing.T) {
+			if err := client.Do(`query($input: ReportRequest!) { reports(input: $input) { summary { loans } } }`, input, nil); err == nil {
+				t.Errorf("reports() error = nil, want unauthorized")
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package e2e
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"e2e/harness"
+)
+
+// webhookTimeout is how long the tests wait for the deliveries of the notification service
+const webhookTimeout = 2 * time.Second
+
+type webhook struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+	Active     bool     `json:"active"`
+}
+
+type webhookDelivery struct {
+	ID        string `json:"id"`
+	EventType string `json:"eventType"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	Log       []struct {
+		StatusCode int `json:"statusCode"`
+	} `json:"log"`
+}
+
+// webhookPayload is the part of the payload the tests check
+type webhookPayload struct {
+	ID   string                 `json:"id"`
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// portal is the endpoint of a webhook, it answers with status and sends the payloads with a valid signature
+type portal struct {
+	secret   string
+	status   int
+	payloads chan webhookPayload
+}
+
+func newPortal(t *testing.T, status int) (*portal, *httptest.Server) {
+	p := &portal{status: status, payloads: make(chan webhookPayload, 10)}
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+	return p, server
+}
+
+func (p *portal) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	// the signature is the HMAC-SHA256 of "<timestamp>.<body>"
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write([]byte(r.Header.Get("X-Library-Timestamp") + "."))
+	mac.Write(body)
+	if !hmac.Equal([]byte(r.Header.Get("X-Library-Signature")), []byte("sha256="+hex.EncodeToString(mac.Sum(nil)))) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload webhookPayload
+	_ = json.Unmarshal(body, &payload)
+	if p.status == http.StatusOK {
+		p.payloads <- payload
+	}
+	w.WriteHeader(p.status)
+}
+
+// waitForPayload returns the next payload of the type received by the portal
+func (p *portal) waitForPayload(t *testing.T, eventType string) webhookPayload {
+	t.Helper()
+
+	timeout := time.After(webhookTimeout)
+	for {
+		select {
+		case payload := <-p.payloads:
+			if payload.Type == eventType {
+				return payload
+			}
+		case <-timeout:
+			t.Fatalf("no %s payload received after %s", eventType, webhookTimeout)
+		}
+	}
+}
+
+func createWebhook(t *testing.T, admin *harness.Client, url string, eventTypes ...string) webhook {
+	t.Helper()
+
+	var data struct {
+		CreateWebhook webhook `json:"createWebhook"`
+	}
+	admin.MustDo(t, `mutation($input: NewWebhook!) { createWebhook(input: $input) { id url secret eventTypes active } }`,
+		map[string]interface{}{"input": map[string]interface{}{"url": url, "eventTypes": eventTypes}}, &data)
+	return data.CreateWebhook
+}
+
+const webhookDeliveryFields = `id eventType status attempts log { statusCode }`
+
+func TestWebhook_Deliveries(t *testing.T) {
+	h := harness.Start(t)
+	admin := h.LoginAsAdmin(t)
+	librarian := h.LoginAsLibrarian(t)
+	member := h.LoginAsMember(t)
+
+	p, server := newPortal(t, http.StatusOK)
+	created := createWebhook(t, admin, server.URL, "lending.created", "lending.finished")
+	if created.Secret == "" || !created.Active {
+		t.Fatalf("createWebhook() = %+v, want an active webhook with its secret", created)
+	}
+	p.secret = created.Secret
+
+	var data struct {
+		SendTestWebhookEvent webhookDelivery `json:"sendTestWebhookEvent"`
+	}
+	admin.MustDo(t, `mutation($input: SendTestWebhookEvent!) { sendTestWebhookEvent(input: $input) { `+webhookDeliveryFields+` } }`,
+		map[string]interface{}{"input": map[string]interface{}{"webhookID": created.ID}}, &data)
+	if got := data.SendTestWebhookEvent; got.Status != "SENT" || len(got.Log) != 1 || got.Log[0].StatusCode != http.StatusOK {
+		t.Errorf("sendTestWebhookEvent() = %+v, want sent at the first attempt", got)
+	}
+	p.waitForPayload(t, "webhook.test")
+
+	dune := createBook(t, librarian, "Dune", 1)
+	lent, err := lendBook(member, dune.ID)
+	if err != nil {
+		t.Fatalf("lendBook() error = %v", err)
+	}
+
+	payload := p.waitForPayload(t, "lending.created")
+	if payload.Data["lending_id"] != lent.ID || payload.Data["user_id"] != member.ID {
+		t.Errorf("lending.created payload = %+v, want lending %s of %s", payload, lent.ID, member.ID)
+	}
+}
+
+func TestWebhook_DeadLetter(t *testing.T) {
+	h := harness.Start(t)
+	admin := h.LoginAsAdmin(t)
+	librarian := h.LoginAsLibrarian(t)
+
+	p, server := newPortal(t, http.StatusServiceUnavailable)
+	created := createWebhook(t, admin, server.URL, "book.created")
+	p.secret = created.Secret
+
+	createBook(t, librarian, "Dune", 1)
+
+	// the test notification service makes 3 attempts a few milliseconds apart
+	var deadLetter webhookDelivery
+	deadline := time.Now().Add(webhookTimeout)
+	for deadLetter.ID == "" {
+		var data struct {
+			FetchWebhookDeadLetter struct {
+				Deliveries []webhookDelivery `json:"deliveries"`
+			} `json:"fetchWebhookDeadLetter"`
+		}
+		admin.MustDo(t, `mutation($input: FetchWebhookDeadLetterRequest) { fetchWebhookDeadLetter(input: $input) { deliveries { `+webhookDeliveryFields+` } } }`,
+			map[string]interface{}{"input": map[string]interface{}{"webhookID": created.ID}}, &data)
+		if len(data.FetchWebhookDeadLetter.Deliveries) > 0 {
+			deadLetter = data.FetchWebhookDeadLetter.Deliveries[0]
+			break
+		}
+
+		if !time.Now().Before(deadline) {
+			t.Fatalf("no dead letter for %s after %s", created.ID, webhookTimeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if deadLetter.EventType != "book.created" || deadLetter.Attempts != 3 || len(deadLetter.Log) != 3 ||
+		deadLetter.Log[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("dead letter = %+v, want book.created after 3 refused attempts", deadLetter)
+	}
+
+	var data struct {
+		RedeliverWebhookDelivery webhookDelivery `json:"redeliverWebhookDelivery"`
+	}
+	admin.MustDo(t, `mutation($input: RedeliverWebhookDelivery!) { redeliverWebhookDelivery(input: $input) { `+webhookDeliveryFields+` } }`,
+		map[string]interface{}{"input": map[string]interface{}{"id": deadLetter.ID}}, &data)
+	if got := data.RedeliverWebhookDelivery; got.Status != "PENDING" || got.Attempts != 0 || len(got.Log) != 3 {
+		t.Errorf("redeliverWebhookDelivery() = %+v, want pending with its log", got)
+	}
+
+	// the webhooks are managed by the admins only
+	err := librarian.Do(`mutation { fetchWebhook { webhooks { id } } }`, nil, nil)
+	if err == nil {
+		t.Errorf("fetchWebhook() by a librarian error = nil, want an error")
+	}
+}
@@ -3,69 +3,106 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime/pprof"
-	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	_ "lending-service/cmd/migration/script" // registers the migrations checked at startup
+	"lending-service/internal/event"
+	"lending-service/internal/repository"
 	"lending-service/internal/service"
+	"lending-service/pkg/config"
+	"lending-service/pkg/healthcheck"
+	"lending-service/pkg/logger"
+	"lending-service/pkg/metrics"
+	"lending-service/pkg/migration"
 	"lending-service/pkg/mongodb"
 	"lending-service/pkg/proto"
+	"lending-service/pkg/resilience"
+	"lending-service/pkg/tenant"
+	"lending-service/pkg/tlsconfig"
+	"lending-service/pkg/tracing"
 )
 
-const (
-	defaultGRPCPort      = ":8000"
-	defaultPProfHTTPPort = ":6060"
-)
+const serviceName = "lending-service"
 
 func init() {
 	_ = godotenv.Load()
 }
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file, the environment variables override it")
+	printConfig := flag.Bool("print-config", false, "print the configuration with the secrets redacted, then exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if *printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatal().Err(err).Msg("Error printing configuration")
+		}
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading configuration")
+	}
+	if *printConfig {
+		return
+	}
+
+	logger.Init(serviceName, cfg.Log.Level)
+	mongodb.Configure(cfg.MongoDB.URI, cfg.MongoDB.Database)
+
+	shutdownTracing, err := tracing.Init(context.Background(), serviceName, cfg.Tracing.Exporter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error initializing tracing")
+	}
+
 	pprofServer := new(http.Server)
-	enableProf, _ := strconv.ParseBool(os.Getenv("ENABLE_PPROF"))
-	if enableProf {
-		profileDirPath := os.Getenv("PPROF_FOLDER_PATH")
+	if cfg.PProf.Enable {
+		profileDirPath := cfg.PProf.FolderPath
 		if _, err := os.Stat(profileDirPath); os.IsNotExist(err) {
 			if err := os.Mkdir(profileDirPath, os.ModePerm); err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			}
 		}
 
-		if cpuProfile := os.Getenv("CPU_PPROF_FILE_NAME"); cpuProfile != "" {
+		if cpuProfile := cfg.PProf.CPUFileName; cpuProfile != "" {
 			f, err := os.Create(fmt.Sprintf("%s/%s", profileDirPath, cpuProfile))
 			if err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			} else {
 				defer func() {
 					if err := f.Close(); err != nil {
-						log.Printf("Error closing cpu profile file: %v", err)
+						log.Error().Err(err).Msg("Error closing cpu profile file")
 					}
 				}()
 
 				_ = pprof.StartCPUProfile(f)
 			}
 		}
-		if memProfile := os.Getenv("MEMORY_PPROF_FILE_NAME"); memProfile != "" {
+		if memProfile := cfg.PProf.MemoryFileName; memProfile != "" {
 			f, err := os.Create(fmt.Sprintf("%s/%s", profileDirPath, memProfile))
 			if err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			} else {
 				defer func() {
 					if err := f.Close(); err != nil {
-						log.Printf("Error closing memory profile file: %v", err)
+						log.Error().Err(err).Msg("Error closing memory profile file")
 					}
 				}()
 
@@ -73,71 +110,128 @@ func main() {
 			}
 		}
 
-		pprofHTPPPort := os.Getenv("PPROF_HTTP_PORT")
-		if pprofHTPPPort == "" {
-			pprofHTPPPort = defaultPProfHTTPPort
-		}
-
 		pprofServer = &http.Server{
-			Addr:    pprofHTPPPort,
+			Addr:    cfg.PProf.HTTPPort,
 			Handler: nil,
 		}
 
 		go func() {
 			if err := pprofServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-				log.Printf("Error starting pprof server: %v", err)
+				log.Error().Err(err).Msg("Error starting pprof server")
 			}
 		}()
 	}
 
-	mongodb.GetDatabase()
+	db := mongodb.GetDatabase()
+
+	pendingMigrations := migration.NewChecker(db, cfg.AllowPendingMigrations)
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 5*time.Second)
+	err = pendingMigrations.Check(checkCtx)
+	cancelCheck()
+	if errors.Is(err, migration.ErrUnreachable) {
+		// the health check is not serving until the migrations are checked
+		log.Warn().Err(err).Msg("Starting before checking the pending migrations")
+	} else if err != nil {
+		log.Fatal().Err(err).Msg("Error starting with pending migrations")
+	}
+
+	lendingRepository := repository.NewLendingMongoDBRepository(db)
+	prometheus.MustRegister(service.NewLendingCollector(lendingRepository))
+
+	metricsServer := &http.Server{
+		Addr:    cfg.MetricsHTTPPort,
+		Handler: metrics.Handler(),
+	}
+
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("Error starting metrics server")
+		}
+	}()
 
-	grpcDialCtx, cancelGRPCDial := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancelGRPCDial()
+	tlsDialOption, err := tlsconfig.DialOption(cfg.TLS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading TLS client certificates")
+	}
 
-	userGRPCClientConn, err := grpc.DialContext(
-		grpcDialCtx,
-		fmt.Sprintf("%s%s", os.Getenv("USER_SERVICE_HOST"), os.Getenv("USER_SERVICE_PORT")),
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
+	userGRPCClientConn, err := dialBackend(
+		cfg.UserService.Address(),
+		tlsDialOption,
+		resilience.NewClient("user-service", defaultPolicy, userServicePolicies),
 	)
 	if err != nil {
-		log.Fatalf("Error dial to user service: %v", err)
+		log.Fatal().Err(err).Msg("Error dial to user service")
 	}
 
-	bookGRPCClientConn, err := grpc.DialContext(
-		grpcDialCtx,
-		fmt.Sprintf("%s%s", os.Getenv("BOOK_SERVICE_HOST"), os.Getenv("BOOK_SERVICE_PORT")),
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
+	bookGRPCClientConn, err := dialBackend(
+		cfg.BookService.Address(),
+		tlsDialOption,
+		resilience.NewClient("book-service", defaultPolicy, bookServicePolicies),
 	)
 	if err != nil {
-		log.Fatalf("Error dial to book service: %v", err)
+		log.Fatal().Err(err).Msg("Error dial to book service")
 	}
 
 	userServiceClient := proto.NewUserServiceClient(userGRPCClientConn)
 	bookServiceClient := proto.NewBookServiceClient(bookGRPCClientConn)
 
-	lendingGRPCService := service.NewLendingGRPCService(userServiceClient, bookServiceClient)
-	server := grpc.NewServer()
-	proto.RegisterLendingServiceServer(server, lendingGRPCService)
+	outboxRepository := repository.NewOutboxMongoDBRepository(db)
 
-	reflection.Register(server)
+	// without a broker the events stay in the outbox
+	var publisher event.Publisher
+	if cfg.Events.Broker == "nats" {
+		publisher, err = event.ConnectNATS(cfg.Events.NATSURL, cfg.Events.SubjectPrefix)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error connecting to NATS")
+		}
+	}
+
+	lendingGRPCService := service.NewLendingGRPCService(lendingRepository, outboxRepository, mongodb.NewTXRepository(db), userServiceClient, bookServiceClient)
 
-	grpcPort := os.Getenv("GRPC_PORT")
-	if grpcPort == "" {
-		grpcPort = defaultGRPCPort
+	tlsServerOption, err := tlsconfig.ServerOption(cfg.TLS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading TLS server certificates")
 	}
 
-	listener, err := net.Listen("tcp", grpcPort)
+	server := grpc.NewServer(
+		tlsServerOption,
+		grpc.ChainUnaryInterceptor(otelgrpc.UnaryServerInterceptor(), metrics.UnaryServerInterceptor(), tenant.UnaryServerInterceptor(), logger.UnaryServerInterceptor(), logger.RecoveryUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(otelgrpc.StreamServerInterceptor(), metrics.StreamServerInterceptor(), tenant.StreamServerInterceptor(), logger.StreamServerInterceptor(), logger.RecoveryStreamServerInterceptor()),
+	)
+	proto.RegisterLendingServiceServer(server, lendingGRPCService)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	listener, err := net.Listen("tcp", cfg.GRPCPort)
 	if err != nil {
-		log.Fatalln(err)
+		log.Fatal().Err(err).Msg("Error listening on gRPC port")
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	go healthcheck.Watch(ctx, healthServer, func(ctx context.Context) error {
+		if err := mongodb.Ping(ctx); err != nil {
+			return err
+		}
+		return pendingMigrations.Check(ctx)
+	}, "lending.LendingService")
+
 	wg := new(sync.WaitGroup)
+	if publisher != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			event.NewRelay(outboxRepository, publisher, cfg.Events.RelayInterval, cfg.Events.RelayMaxAttempts).Run(ctx)
+			if err := publisher.Close(); err != nil {
+				log.Error().Err(err).Send()
+			}
+		}()
+	}
+
 	wg.Add(1)
 
 	go func() {
@@ -147,19 +241,40 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		// the clients stop sending requests before the server stops
+		healthServer.Shutdown()
 		server.GracefulStop()
-		if enableProf {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Send()
+		}
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error().Err(err).Send()
+		}
+		if cfg.PProf.Enable {
 			pprof.StopCPUProfile()
 			if err := pprofServer.Shutdown(ctx); err != nil {
-				log.Println(err)
+				log.Error().Err(err).Send()
 			}
 		}
 	}()
 
-	log.Println("starting to serve")
+	log.Info().Str("port", cfg.GRPCPort).Msg("starting to serve")
 	if err = server.Serve(listener); err != nil {
-		log.Fatalln(err)
+		log.Fatal().Err(err).Send()
 	}
 	wg.Wait()
-	log.Println("service is gracefully shutdown")
+	log.Info().Msg("service is gracefully shutdown")
+}
+
+// dialBackend connects to a dependency without blocking, so the service starts while it is down and reconnects to it.
+// The resilience interceptors are the last ones, so the retries of a call are logged and measured once.
+func dialBackend(target string, credentials grpc.DialOption, resilienceClient *resilience.Client) (*grpc.ClientConn, error) {
+	return grpc.Dial(
+		target,
+		credentials,
+		grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor(), tenant.UnaryClientInterceptor(), logger.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor(), resilienceClient.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor(), tenant.StreamClientInterceptor(), logger.StreamClientInterceptor(),
+			metrics.StreamClientInterceptor(), resilienceClient.StreamClientInterceptor()),
+	)
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"time"
+
+	"lending-service/pkg/resilience"
+)
+
+var (
+	// defaultPolicy is the policy of the calls without their own, the stock updates are never retried
+	// since a retry after a lost answer would take the stock twice
+	defaultPolicy = resilience.Policy{Timeout: 5 * time.Second}
+	// readPolicy retries the idempotent reads
+	readPolicy = resilience.Policy{Timeout: 3 * time.Second, Retries: 2}
+)
+
+var (
+	userServicePolicies = map[string]resilience.Policy{
+		"/user.UserService/FindByID": readPolicy,
+	}
+	bookServicePolicies = map[string]resilience.Policy{
+		"/book.BookService/FindByID":          readPolicy,
+		"/book.BookService/FindBranchByID":    readPolicy,
+		"/book.BookService/FindDefaultBranch": readPolicy,
+		"/book.BookService/FindCategoryByID":  readPolicy,
+	}
+)
@@ -20,6 +20,12 @@ func init() {
 		log.Println("success create lending collection")
 		return nil
 	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.LendingCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop lending collection")
 		return nil
 	})
 }
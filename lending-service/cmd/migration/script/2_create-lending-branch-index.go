@@ -0,0 +1,45 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"lending-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		_, err := db.Collection(constant.LendingCollection).Indexes().
+			CreateMany(context.TODO(), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{"branch_id", 1}},
+					Options: options.Index().SetName(constant.LendingBranchIndex),
+				},
+				{
+					Keys:    bson.D{{"return_branch_id", 1}},
+					Options: options.Index().SetName(constant.LendingReturnBranchIndex),
+				},
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create lending branch index")
+		return nil
+	}, func(db *mongo.Database) error {
+		indexes := db.Collection(constant.LendingCollection).Indexes()
+		for _, index := range []string{constant.LendingBranchIndex, constant.LendingReturnBranchIndex} {
+			if _, err := indexes.DropOne(context.TODO(), index); err != nil {
+				return err
+			}
+		}
+
+		log.Println("success drop lending branch index")
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"lending-service/internal/domain/constant"
+	"lending-service/pkg/tenant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		// the existing lendings belong to the default tenant
+		_, err := db.Collection(constant.LendingCollection).UpdateMany(context.TODO(),
+			bson.D{{"meta.tenant_id", bson.D{{"$in", bson.A{nil, ""}}}}},
+			bson.D{{"$set", bson.D{{"meta.tenant_id", tenant.Default}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		log.Println("success add tenant ID")
+		return nil
+	}, func(db *mongo.Database) error {
+		// the lendings of other tenants would be merged into a single library
+		count, err := db.Collection(constant.LendingCollection).CountDocuments(context.TODO(),
+			bson.D{{"meta.tenant_id", bson.D{{"$nin", bson.A{nil, "", tenant.Default}}}}},
+		)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("lending collection has documents of other tenants than %s, remove them first", tenant.Default)
+		}
+
+		_, err = db.Collection(constant.LendingCollection).UpdateMany(context.TODO(),
+			bson.D{{"meta.tenant_id", tenant.Default}},
+			bson.D{{"$unset", bson.D{{"meta.tenant_id", ""}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		log.Println("success remove tenant ID")
+		return nil
+	})
+}
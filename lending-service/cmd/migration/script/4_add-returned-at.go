@@ -0,0 +1,57 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"lending-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		collection := db.Collection(constant.LendingCollection)
+
+		// a finished lending was last updated when its book was returned
+		_, err := collection.UpdateMany(context.TODO(),
+			bson.D{{"status", constant.LendingInactive}, {"returned_at", nil}},
+			mongo.Pipeline{{{"$set", bson.D{{"returned_at", "$meta.updated_at"}}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		// the reports match the lendings of a tenant lent in a date range
+		_, err = collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+			Keys:    bson.D{{"meta.tenant_id", 1}, {"meta.created_at", 1}},
+			Options: options.Index().SetName(constant.LendingTenantCreatedAtIndex),
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success add returned at")
+		return nil
+	}, func(db *mongo.Database) error {
+		collection := db.Collection(constant.LendingCollection)
+
+		if _, err := collection.Indexes().DropOne(context.TODO(), constant.LendingTenantCreatedAtIndex); err != nil {
+			return err
+		}
+
+		_, err := collection.UpdateMany(context.TODO(),
+			bson.D{},
+			bson.D{{"$unset", bson.D{{"returned_at", ""}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		log.Println("success remove returned at")
+		return nil
+	})
+}
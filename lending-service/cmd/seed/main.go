@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+	"lending-service/pkg/config"
+	"lending-service/pkg/mongodb"
+	"lending-service/pkg/seed"
+)
+
+const (
+	// the seeded lendings are returned ones, from the last months, so they do not hold any book stock
+	lendingDays = 14
+	historyDays = 180
+)
+
+func init() {
+	_ = godotenv.Load()
+}
+
+func main() {
+	cfg, err := config.LoadCommand()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mongodb.Configure(cfg.MongoDB.URI, cfg.MongoDB.Database)
+
+	options, err := seed.ParseOptions(os.Args[1:], os.Stdout, cfg.SeedProfile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = run(context.Background(), mongodb.GetDatabase(), options, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run seeds the lendings of the profile, between the books and the members the other services seed
+// with the same options
+func run(ctx context.Context, db *mongo.Database, options seed.Options, out io.Writer) error {
+	if options.Profile.Name == "" {
+		_, _ = fmt.Fprintln(out, "no seed profile, nothing to seed")
+		return nil
+	}
+
+	now := time.Now().Truncate(24 * time.Hour)
+	lendings := make([]seed.Document, 0, options.Profile.Lendings)
+	for i := 0; i < options.Profile.Lendings; i++ {
+		r := options.Rand("lending", i)
+
+		lentAt := now.AddDate(0, 0, -lendingDays-r.Intn(historyDays))
+		lending := domain.Lending{
+			ID:         options.ID("lending", i),
+			BookID:     options.ID("book", r.Intn(options.Profile.Books)),
+			UserID:     options.ID("member", r.Intn(options.Profile.Members)),
+			Status:     constant.LendingInactive,
+			ReturnDate: lentAt.AddDate(0, 0, lendingDays),
+		}
+		lending.Meta.CreatedAt = lentAt
+		returnedAt := lentAt.AddDate(0, 0, r.Intn(lendingDays+1))
+		lending.ReturnedAt = &returnedAt
+		lending.Meta.UpdatedAt = returnedAt
+		lending.Meta.TenantID = options.TenantID
+		lendings = append(lendings, seed.Document{Filter: bson.D{{"_id", lending.ID}}, Value: lending})
+	}
+
+	inserted, err := seed.InsertMissing(ctx, db.Collection(constant.LendingCollection), lendings)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(out, "seeded %d of %d lendings of the %s profile\n", inserted, len(lendings), options.Profile.Name)
+	return err
+}
@@ -2,4 +2,11 @@ package constant
 
 const (
 	LendingCollection = "lending"
+	OutboxCollection  = "outbox"
+
+	LendingBranchIndex          = "lending-branch-index"
+	LendingReturnBranchIndex    = "lending-return-branch-index"
+	LendingTenantCreatedAtIndex = "lending-tenant-created-at-index"
+	// OutboxPublishedIndex expires the published events, the pending ones have no published time
+	OutboxPublishedIndex = "outbox-published-index"
 )
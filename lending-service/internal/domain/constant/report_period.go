@@ -0,0 +1,56 @@
+package constant
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReportPeriod groups the lendings of a report, the weeks are the ISO weeks
+type ReportPeriod string
+
+const (
+	ReportDay   ReportPeriod = "day"
+	ReportWeek  ReportPeriod = "week"
+	ReportMonth ReportPeriod = "month"
+)
+
+// Label names the period of t in UTC, like 2021-02-14, 2021-W07, or 2021-02, so the labels sort in time order
+func (p ReportPeriod) Label(t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case ReportWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case ReportMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// Start returns the start of the period of t in UTC, the weeks start on Monday
+func (p ReportPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case ReportWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case ReportMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// Next returns a time of the period after the one of t
+func (p ReportPeriod) Next(t time.Time) time.Time {
+	switch p {
+	case ReportWeek:
+		return t.AddDate(0, 0, 7)
+	case ReportMonth:
+		// from the first day, so a 31st never skips a shorter month
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
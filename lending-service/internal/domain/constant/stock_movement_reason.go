@@ -0,0 +1,9 @@
+package constant
+
+type StockMovementReason string
+
+const (
+	StockLending    StockMovementReason = "lending"
+	StockReturn     StockMovementReason = "return"
+	StockCorrection StockMovementReason = "correction"
+)
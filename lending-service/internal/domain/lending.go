@@ -17,6 +17,11 @@ type Lending struct {
 	UserID       primitive.ObjectID     `json:"user_id" bson:"user_id"`
 	Status       constant.LendingStatus `json:"status" bson:"status"`
 	ReturnDate   time.Time              `json:"return_date" bson:"return_date"`
+	// BranchID is where the book is lent, ReturnBranchID is where it is returned, which may be another branch
+	BranchID       *primitive.ObjectID `json:"branch_id" bson:"branch_id"`
+	ReturnBranchID *primitive.ObjectID `json:"return_branch_id" bson:"return_branch_id"`
+	// ReturnedAt is when the book is returned, nil until the lending is finished
+	ReturnedAt *time.Time `json:"returned_at" bson:"returned_at"`
 }
 
 type LendingRepository interface {
@@ -25,4 +30,20 @@ type LendingRepository interface {
 	FindByID(ctx context.Context, id string) (Lending, error)
 	Count(ctx context.Context, filter map[string]interface{}) (int, error)
 	Update(ctx context.Context, lending *Lending) error
+	// CountActiveByTenant is not scoped to the tenant of the request, it counts the lendings of every tenant
+	CountActiveByTenant(ctx context.Context, now time.Time) ([]TenantLendingCount, error)
+
+	// the reports count the lent lendings, the drafts and the canceled ones are never lent
+	LoansPerPeriod(ctx context.Context, filter ReportFilter, period constant.ReportPeriod) ([]PeriodCount, error)
+	TopBorrowedBooks(ctx context.Context, filter ReportFilter, limit int) ([]BookLoanCount, error)
+	BorrowedBookIDs(ctx context.Context, filter ReportFilter) ([]primitive.ObjectID, error)
+	CirculationSummary(ctx context.Context, filter ReportFilter, now time.Time) (CirculationSummary, error)
+	ActiveMembers(ctx context.Context, filter ReportFilter, now time.Time, limit int) ([]MemberActivity, error)
+}
+
+// TenantLendingCount counts the active lendings of a tenant, Overdue are the ones past their return date
+type TenantLendingCount struct {
+	TenantID string `bson:"_id"`
+	Active   int    `bson:"active"`
+	Overdue  int    `bson:"overdue"`
 }
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportFilter bounds a report to the loans from From until To, and to the returns in the same range.
+// A BranchID keeps the lendings of the branch they are lent at.
+type ReportFilter struct {
+	From     time.Time
+	To       time.Time
+	BranchID *primitive.ObjectID
+}
+
+// PeriodCount counts the loans and the returns of a period, labelled like 2021-02-14, 2021-W07, or 2021-02
+type PeriodCount struct {
+	Period  string `bson:"_id"`
+	Loans   int    `bson:"loans"`
+	Returns int    `bson:"returns"`
+}
+
+type BookLoanCount struct {
+	BookID primitive.ObjectID `bson:"_id"`
+	Loans  int                `bson:"loans"`
+}
+
+// CirculationSummary sums up the lendings of a report, the overdue ones are returned late or still lent past their
+// return date
+type CirculationSummary struct {
+	Loans            int     `bson:"loans"`
+	Returns          int     `bson:"returns"`
+	AverageLoanHours float64 `bson:"average_loan_hours"`
+	Overdue          int     `bson:"overdue"`
+	ActiveMembers    int     `bson:"active_members"`
+}
+
+// MemberActivity counts the loans of a member, Active are the ones not returned yet
+type MemberActivity struct {
+	UserID  primitive.ObjectID `bson:"_id"`
+	Loans   int                `bson:"loans"`
+	Active  int                `bson:"active"`
+	Overdue int                `bson:"overdue"`
+}
@@ -0,0 +1,73 @@
+// Package event records the domain events of the service in its outbox, and relays them from the outbox to a broker.
+// An event is recorded in the transaction of the change it describes, so only stored changes are published, at least
+// once.
+package event
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"lending-service/internal/domain"
+	"lending-service/pkg/logger"
+	"lending-service/pkg/proto"
+	"lending-service/pkg/tenant"
+)
+
+// Source is the service in the envelope of the events
+const Source = "lending-service"
+
+const (
+	LendingCreated  = "lending.created"
+	LendingRenewed  = "lending.renewed"
+	LendingFinished = "lending.finished"
+)
+
+// versions are the versions of the payload of each type, bumped on a breaking change of the payload message
+var versions = map[string]int32{
+	LendingCreated:  1,
+	LendingRenewed:  1,
+	LendingFinished: 1,
+}
+
+// New wraps the payload in the envelope of the event, with the tenant and the request of the context
+func New(ctx context.Context, eventType, aggregateID string, payload protobuf.Message) (*proto.Event, error) {
+	anyPayload, err := anypb.New(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.Event{
+		Id:          primitive.NewObjectID().Hex(),
+		Type:        eventType,
+		Version:     versions[eventType],
+		Source:      Source,
+		TenantId:    tenant.FromContext(ctx),
+		AggregateId: aggregateID,
+		OccurredAt:  timestamppb.Now(),
+		RequestId:   logger.RequestIDFromContext(ctx),
+		Payload:     anyPayload,
+	}, nil
+}
+
+// Record writes the event to the outbox. It runs in the transaction of the change the event describes, so the event
+// is stored if and only if the change is.
+func Record(ctx context.Context, outboxRepository domain.OutboxRepository, eventType, aggregateID string, payload protobuf.Message) error {
+	event, err := New(ctx, eventType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+
+	data, err := protobuf.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return outboxRepository.Create(ctx, &domain.OutboxEvent{
+		Type:    eventType,
+		Payload: data,
+	})
+}
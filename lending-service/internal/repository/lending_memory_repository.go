@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+	"lending-service/pkg/tenant"
+)
+
+type lendingMemoryRepository struct {
+	mu       sync.RWMutex
+	lendings []domain.Lending
+}
+
+func NewLendingMemoryRepository() domain.LendingRepository {
+	return &lendingMemoryRepository{}
+}
+
+func (r *lendingMemoryRepository) Create(ctx context.Context, lending *domain.Lending) error {
+	lending.ID = primitive.NewObjectID()
+	lending.Meta.Create()
+	lending.Meta.TenantID = tenant.FromContext(ctx)
+
+	var stored domain.Lending
+	copyDocument(lending, &stored)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lendings = append(r.lendings, stored)
+	return nil
+}
+
+func (r *lendingMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Lending, error) {
+	lendings := r.filter(ctx, param)
+
+	sort.SliceStable(lendings, func(i, j int) bool {
+		return newestFirst(lendings[i].CreatedAt, lendings[j].CreatedAt, lendings[i].ID, lendings[j].ID)
+	})
+	start, end := pageBounds(param, len(lendings))
+
+	return lendings[start:end], nil
+}
+
+func (r *lendingMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(ctx, param)), nil
+}
+
+// filter returns a copy of the lendings matching the param, like lendingMongoDBRepository.filterBy
+func (r *lendingMemoryRepository) filter(ctx context.Context, param map[string]interface{}) []domain.Lending {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	lendings := make([]domain.Lending, 0)
+	for _, lending := range r.lendings {
+		if lending.Meta.TenantID != tenantID || lending.Meta.DeletedAt != nil || !lendingMatches(lending, param) {
+			continue
+		}
+
+		var found domain.Lending
+		copyDocument(lending, &found)
+		lendings = append(lendings, found)
+	}
+
+	return lendings
+}
+
+func lendingMatches(lending domain.Lending, param map[string]interface{}) bool {
+	for key, value := range param {
+		switch key {
+		case "status":
+			// the status is filtered as a constant.LendingStatus or as the string of a request
+			if string(lending.Status) != fmt.Sprint(value) {
+				return false
+			}
+		case "user_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if lending.UserID != objectID {
+				return false
+			}
+		case "book_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if lending.BookID != objectID {
+				return false
+			}
+		case "branch_id":
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			if !equalObjectID(lending.BranchID, objectID) && !equalObjectID(lending.ReturnBranchID, objectID) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func equalObjectID(id *primitive.ObjectID, other primitive.ObjectID) bool {
+	return id != nil && *id == other
+}
+
+func (r *lendingMemoryRepository) FindByID(ctx context.Context, id string) (lending domain.Lending, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Lending{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.lendings {
+		if stored.ID == objectID && stored.Meta.TenantID == tenantID {
+			copyDocument(stored, &lending)
+			return lending, nil
+		}
+	}
+
+	return domain.Lending{}, mongo.ErrNoDocuments
+}
+
+func (r *lendingMemoryRepository) Update(ctx context.Context, lending *domain.Lending) error {
+	lending.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.lendings {
+		if stored.ID == lending.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(lending, &r.lendings[i])
+			copyDocument(r.lendings[i], lending)
+			return nil
+		}
+	}
+
+	return mongo.ErrNoDocuments
+}
+
+func (r *lendingMemoryRepository) CountActiveByTenant(_ context.Context, now time.Time) ([]domain.TenantLendingCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make([]domain.TenantLendingCount, 0)
+	indexes := make(map[string]int)
+	for _, lending := range r.lendings {
+		if lending.Meta.DeletedAt != nil || lending.Status != constant.LendingActive {
+			continue
+		}
+
+		i, ok := indexes[lending.Meta.TenantID]
+		if !ok {
+			i = len(counts)
+			indexes[lending.Meta.TenantID] = i
+			counts = append(counts, domain.TenantLendingCount{TenantID: lending.Meta.TenantID})
+		}
+		counts[i].Active++
+		if lending.ReturnDate.Before(now) {
+			counts[i].Overdue++
+		}
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].TenantID < counts[j].TenantID
+	})
+	return counts, nil
+}
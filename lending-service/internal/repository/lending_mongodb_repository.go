@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,7 +11,7 @@ import (
 
 	"lending-service/internal/domain"
 	"lending-service/internal/domain/constant"
-	"lending-service/pkg/mongodb"
+	"lending-service/pkg/tenant"
 )
 
 type lendingMongoDBRepository struct {
@@ -18,8 +19,7 @@ type lendingMongoDBRepository struct {
 	collection *mongo.Collection
 }
 
-func NewLendingMongoDBRepository() domain.LendingRepository {
-	db := mongodb.GetDatabase()
+func NewLendingMongoDBRepository(db *mongo.Database) domain.LendingRepository {
 	return &lendingMongoDBRepository{
 		db:         db,
 		collection: db.Collection(constant.LendingCollection),
@@ -29,6 +29,7 @@ func NewLendingMongoDBRepository() domain.LendingRepository {
 func (r *lendingMongoDBRepository) Create(ctx context.Context, lending *domain.Lending) error {
 	lending.ID = primitive.NewObjectID()
 	lending.Meta.Create()
+	lending.Meta.TenantID = tenant.FromContext(ctx)
 
 	result, err := r.collection.InsertOne(ctx, lending)
 	if err != nil {
@@ -40,7 +41,7 @@ func (r *lendingMongoDBRepository) Create(ctx context.Context, lending *domain.L
 }
 
 func (r *lendingMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Lending, error) {
-	cursor, err := r.collection.Find(ctx, r.filterBy(param), r.pageBy(param))
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +60,7 @@ func (r *lendingMongoDBRepository) Fetch(ctx context.Context, param map[string]i
 }
 
 func (r *lendingMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
-	count, err := r.collection.CountDocuments(ctx, r.filterBy(param))
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
 	if err != nil {
 		return 0, err
 	}
@@ -67,8 +68,8 @@ func (r *lendingMongoDBRepository) Count(ctx context.Context, param map[string]i
 	return int(count), nil
 }
 
-func (*lendingMongoDBRepository) filterBy(param map[string]interface{}) bson.D {
-	filter := bson.D{}
+func (*lendingMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
 
 	filter = append(filter, bson.E{"meta.deleted_at", nil})
 
@@ -79,6 +80,13 @@ func (*lendingMongoDBRepository) filterBy(param map[string]interface{}) bson.D {
 		case "user_id", "book_id":
 			objectID, _ := primitive.ObjectIDFromHex(value.(string))
 			filter = append(filter, bson.E{key, objectID})
+		case "branch_id":
+			// a branch sees the lendings it lends and the ones returned to it
+			objectID, _ := primitive.ObjectIDFromHex(value.(string))
+			filter = append(filter, bson.E{"$or", bson.A{
+				bson.D{{"branch_id", objectID}},
+				bson.D{{"return_branch_id", objectID}},
+			}})
 		}
 	}
 
@@ -106,7 +114,7 @@ func (*lendingMongoDBRepository) pageBy(param map[string]interface{}) *options.F
 
 func (r *lendingMongoDBRepository) FindOne(ctx context.Context, filter bson.D) (lending domain.Lending, err error) {
 	var decoded bson.M
-	err = r.collection.FindOne(ctx, filter).
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
 		Decode(&decoded)
 	if err != nil {
 		return domain.Lending{}, err
@@ -135,7 +143,7 @@ func (r *lendingMongoDBRepository) Update(ctx context.Context, lending *domain.L
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
 	var decode bson.M
-	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).
+	err := r.collection.FindOneAndUpdate(ctx, byTenant(ctx, filter), update, opts).
 		Decode(&decode)
 	if err != nil {
 		return err
@@ -145,3 +153,27 @@ func (r *lendingMongoDBRepository) Update(ctx context.Context, lending *domain.L
 	err = bson.Unmarshal(bsonBytes, lending)
 	return nil
 }
+
+func (r *lendingMongoDBRepository) CountActiveByTenant(ctx context.Context, now time.Time) ([]domain.TenantLendingCount, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"meta.deleted_at", nil}, {"status", constant.LendingActive}}}},
+		{{"$group", bson.D{
+			{"_id", "$meta.tenant_id"},
+			{"active", bson.D{{"$sum", 1}}},
+			{"overdue", bson.D{{"$sum", bson.D{{"$cond", bson.A{bson.D{{"$lt", bson.A{"$return_date", now}}}, 1, 0}}}}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := make([]domain.TenantLendingCount, 0)
+	if err = cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
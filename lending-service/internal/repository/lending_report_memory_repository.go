@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+	"lending-service/pkg/tenant"
+)
+
+// lent returns a copy of the lent lendings of the report, like lendingMongoDBRepository.lentBy
+func (r *lendingMemoryRepository) lent(ctx context.Context, filter domain.ReportFilter) []domain.Lending {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	lendings := make([]domain.Lending, 0)
+	for _, lending := range r.lendings {
+		if lending.Meta.TenantID != tenantID || lending.Meta.DeletedAt != nil {
+			continue
+		}
+		if lending.Status != constant.LendingActive && lending.Status != constant.LendingInactive {
+			continue
+		}
+		if filter.BranchID != nil && !equalObjectID(lending.BranchID, *filter.BranchID) {
+			continue
+		}
+
+		var found domain.Lending
+		copyDocument(lending, &found)
+		lendings = append(lendings, found)
+	}
+
+	return lendings
+}
+
+func inRange(t time.Time, filter domain.ReportFilter) bool {
+	return !t.Before(filter.From) && t.Before(filter.To)
+}
+
+func lentInRange(lending domain.Lending, filter domain.ReportFilter) bool {
+	return inRange(lending.Meta.CreatedAt, filter)
+}
+
+func returnedInRange(lending domain.Lending, filter domain.ReportFilter) bool {
+	return lending.ReturnedAt != nil && inRange(*lending.ReturnedAt, filter)
+}
+
+func overdue(lending domain.Lending, now time.Time) bool {
+	if lending.Status == constant.LendingActive {
+		return lending.ReturnDate.Before(now)
+	}
+	return lending.ReturnedAt != nil && lending.ReturnedAt.After(lending.ReturnDate)
+}
+
+func (r *lendingMemoryRepository) LoansPerPeriod(ctx context.Context, filter domain.ReportFilter, period constant.ReportPeriod) ([]domain.PeriodCount, error) {
+	counts := make(map[string]*domain.PeriodCount)
+	for _, lending := range r.lent(ctx, filter) {
+		if lentInRange(lending, filter) {
+			countOf(counts, period.Label(lending.Meta.CreatedAt)).Loans++
+		}
+		if returnedInRange(lending, filter) {
+			countOf(counts, period.Label(*lending.ReturnedAt)).Returns++
+		}
+	}
+
+	return sortedPeriodCounts(counts), nil
+}
+
+func (r *lendingMemoryRepository) TopBorrowedBooks(ctx context.Context, filter domain.ReportFilter, limit int) ([]domain.BookLoanCount, error) {
+	counts := make([]domain.BookLoanCount, 0)
+	indexes := make(map[primitive.ObjectID]int)
+	for _, lending := range r.lent(ctx, filter) {
+		if !lentInRange(lending, filter) {
+			continue
+		}
+
+		i, ok := indexes[lending.BookID]
+		if !ok {
+			i = len(counts)
+			indexes[lending.BookID] = i
+			counts = append(counts, domain.BookLoanCount{BookID: lending.BookID})
+		}
+		counts[i].Loans++
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		return mostLoansFirst(counts[i].Loans, counts[j].Loans, counts[i].BookID, counts[j].BookID)
+	})
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts, nil
+}
+
+func (r *lendingMemoryRepository) BorrowedBookIDs(ctx context.Context, filter domain.ReportFilter) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0)
+	borrowed := make(map[primitive.ObjectID]bool)
+	for _, lending := range r.lent(ctx, filter) {
+		if lentInRange(lending, filter) && !borrowed[lending.BookID] {
+			borrowed[lending.BookID] = true
+			ids = append(ids, lending.BookID)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+	return ids, nil
+}
+
+func (r *lendingMemoryRepository) CirculationSummary(ctx context.Context, filter domain.ReportFilter, now time.Time) (domain.CirculationSummary, error) {
+	var summary domain.CirculationSummary
+	var loanDuration time.Duration
+	members := make(map[primitive.ObjectID]bool)
+	for _, lending := range r.lent(ctx, filter) {
+		if lentInRange(lending, filter) {
+			summary.Loans++
+			members[lending.UserID] = true
+			if overdue(lending, now) {
+				summary.Overdue++
+			}
+		}
+		if returnedInRange(lending, filter) {
+			summary.Returns++
+			loanDuration += lending.ReturnedAt.Sub(lending.Meta.CreatedAt)
+		}
+	}
+
+	summary.ActiveMembers = len(members)
+	if summary.Returns > 0 {
+		summary.AverageLoanHours = loanDuration.Hours() / float64(summary.Returns)
+	}
+	return summary, nil
+}
+
+func (r *lendingMemoryRepository) ActiveMembers(ctx context.Context, filter domain.ReportFilter, now time.Time, limit int) ([]domain.MemberActivity, error) {
+	members := make([]domain.MemberActivity, 0)
+	indexes := make(map[primitive.ObjectID]int)
+	for _, lending := range r.lent(ctx, filter) {
+		if !lentInRange(lending, filter) {
+			continue
+		}
+
+		i, ok := indexes[lending.UserID]
+		if !ok {
+			i = len(members)
+			indexes[lending.UserID] = i
+			members = append(members, domain.MemberActivity{UserID: lending.UserID})
+		}
+		members[i].Loans++
+		if lending.Status == constant.LendingActive {
+			members[i].Active++
+		}
+		if overdue(lending, now) {
+			members[i].Overdue++
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return mostLoansFirst(members[i].Loans, members[j].Loans, members[i].UserID, members[j].UserID)
+	})
+	if len(members) > limit {
+		members = members[:limit]
+	}
+	return members, nil
+}
+
+// mostLoansFirst orders like the loans descending and _id ascending sort of the reports
+func mostLoansFirst(loans, otherLoans int, id, otherID primitive.ObjectID) bool {
+	if loans != otherLoans {
+		return loans > otherLoans
+	}
+	return bytes.Compare(id[:], otherID[:]) < 0
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+)
+
+// reportPeriodFormats are the $dateToString formats of the labels of constant.ReportPeriod
+var reportPeriodFormats = map[constant.ReportPeriod]string{
+	constant.ReportDay:   "%Y-%m-%d",
+	constant.ReportWeek:  "%G-W%V",
+	constant.ReportMonth: "%Y-%m",
+}
+
+// lentBy matches the lent lendings of the report, the date ranges are matched by each report
+func (*lendingMongoDBRepository) lentBy(ctx context.Context, filter domain.ReportFilter) bson.D {
+	match := byTenant(ctx, bson.D{
+		{"meta.deleted_at", nil},
+		{"status", bson.D{{"$in", bson.A{constant.LendingActive, constant.LendingInactive}}}},
+	})
+	if filter.BranchID != nil {
+		match = append(match, bson.E{"branch_id", *filter.BranchID})
+	}
+	return match
+}
+
+func lentBetween(filter domain.ReportFilter) bson.D {
+	return bson.D{{"meta.created_at", bson.D{{"$gte", filter.From}, {"$lt", filter.To}}}}
+}
+
+func returnedBetween(filter domain.ReportFilter) bson.D {
+	return bson.D{{"returned_at", bson.D{{"$gte", filter.From}, {"$lt", filter.To}}}}
+}
+
+// overdueExpression is 1 for a lending returned late or still lent past its return date
+func overdueExpression(now time.Time) bson.D {
+	return bson.D{{"$cond", bson.A{
+		bson.D{{"$or", bson.A{
+			bson.D{{"$and", bson.A{
+				bson.D{{"$eq", bson.A{"$status", constant.LendingInactive}}},
+				bson.D{{"$gt", bson.A{"$returned_at", "$return_date"}}},
+			}}},
+			bson.D{{"$and", bson.A{
+				bson.D{{"$eq", bson.A{"$status", constant.LendingActive}}},
+				bson.D{{"$lt", bson.A{"$return_date", now}}},
+			}}},
+		}}},
+		1,
+		0,
+	}}}
+}
+
+func (r *lendingMongoDBRepository) LoansPerPeriod(ctx context.Context, filter domain.ReportFilter, period constant.ReportPeriod) ([]domain.PeriodCount, error) {
+	format := reportPeriodFormats[period]
+	pipeline := mongo.Pipeline{
+		{{"$match", r.lentBy(ctx, filter)}},
+		{{"$facet", bson.D{
+			{"loans", bson.A{
+				bson.D{{"$match", lentBetween(filter)}},
+				bson.D{{"$group", bson.D{
+					{"_id", bson.D{{"$dateToString", bson.D{{"format", format}, {"date", "$meta.created_at"}}}}},
+					{"loans", bson.D{{"$sum", 1}}},
+				}}},
+			}},
+			{"returns", bson.A{
+				bson.D{{"$match", returnedBetween(filter)}},
+				bson.D{{"$group", bson.D{
+					{"_id", bson.D{{"$dateToString", bson.D{{"format", format}, {"date", "$returned_at"}}}}},
+					{"returns", bson.D{{"$sum", 1}}},
+				}}},
+			}},
+		}}},
+	}
+
+	var facets []struct {
+		Loans   []domain.PeriodCount `bson:"loans"`
+		Returns []domain.PeriodCount `bson:"returns"`
+	}
+	if err := r.aggregate(ctx, pipeline, &facets); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]*domain.PeriodCount)
+	for _, facet := range facets {
+		for _, loans := range facet.Loans {
+			countOf(counts, loans.Period).Loans = loans.Loans
+		}
+		for _, returns := range facet.Returns {
+			countOf(counts, returns.Period).Returns = returns.Returns
+		}
+	}
+
+	return sortedPeriodCounts(counts), nil
+}
+
+func (r *lendingMongoDBRepository) TopBorrowedBooks(ctx context.Context, filter domain.ReportFilter, limit int) ([]domain.BookLoanCount, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", append(r.lentBy(ctx, filter), lentBetween(filter)...)}},
+		{{"$group", bson.D{{"_id", "$book_id"}, {"loans", bson.D{{"$sum", 1}}}}}},
+		{{"$sort", bson.D{{"loans", -1}, {"_id", 1}}}},
+		{{"$limit", limit}},
+	}
+
+	counts := make([]domain.BookLoanCount, 0)
+	if err := r.aggregate(ctx, pipeline, &counts); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *lendingMongoDBRepository) BorrowedBookIDs(ctx context.Context, filter domain.ReportFilter) ([]primitive.ObjectID, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", append(r.lentBy(ctx, filter), lentBetween(filter)...)}},
+		{{"$group", bson.D{{"_id", "$book_id"}}}},
+		{{"$sort", bson.D{{"_id", 1}}}},
+	}
+
+	var books []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := r.aggregate(ctx, pipeline, &books); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(books))
+	for _, book := range books {
+		ids = append(ids, book.ID)
+	}
+	return ids, nil
+}
+
+func (r *lendingMongoDBRepository) CirculationSummary(ctx context.Context, filter domain.ReportFilter, now time.Time) (domain.CirculationSummary, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", r.lentBy(ctx, filter)}},
+		{{"$facet", bson.D{
+			{"loans", bson.A{
+				bson.D{{"$match", lentBetween(filter)}},
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"loans", bson.D{{"$sum", 1}}},
+					{"overdue", bson.D{{"$sum", overdueExpression(now)}}},
+					{"members", bson.D{{"$addToSet", "$user_id"}}},
+				}}},
+				bson.D{{"$project", bson.D{{"loans", 1}, {"overdue", 1}, {"active_members", bson.D{{"$size", "$members"}}}}}},
+			}},
+			{"returns", bson.A{
+				bson.D{{"$match", returnedBetween(filter)}},
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"returns", bson.D{{"$sum", 1}}},
+					{"average_loan_ms", bson.D{{"$avg", bson.D{{"$subtract", bson.A{"$returned_at", "$meta.created_at"}}}}}},
+				}}},
+			}},
+		}}},
+	}
+
+	var facets []struct {
+		Loans   []domain.CirculationSummary `bson:"loans"`
+		Returns []struct {
+			Returns       int     `bson:"returns"`
+			AverageLoanMS float64 `bson:"average_loan_ms"`
+		} `bson:"returns"`
+	}
+	if err := r.aggregate(ctx, pipeline, &facets); err != nil {
+		return domain.CirculationSummary{}, err
+	}
+
+	var summary domain.CirculationSummary
+	for _, facet := range facets {
+		if len(facet.Loans) > 0 {
+			summary = facet.Loans[0]
+		}
+		if len(facet.Returns) > 0 {
+			summary.Returns = facet.Returns[0].Returns
+			summary.AverageLoanHours = facet.Returns[0].AverageLoanMS / float64(time.Hour/time.Millisecond)
+		}
+	}
+	return summary, nil
+}
+
+func (r *lendingMongoDBRepository) ActiveMembers(ctx context.Context, filter domain.ReportFilter, now time.Time, limit int) ([]domain.MemberActivity, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", append(r.lentBy(ctx, filter), lentBetween(filter)...)}},
+		{{"$group", bson.D{
+			{"_id", "$user_id"},
+			{"loans", bson.D{{"$sum", 1}}},
+			{"active", bson.D{{"$sum", bson.D{{"$cond", bson.A{bson.D{{"$eq", bson.A{"$status", constant.LendingActive}}}, 1, 0}}}}}},
+			{"overdue", bson.D{{"$sum", overdueExpression(now)}}},
+		}}},
+		{{"$sort", bson.D{{"loans", -1}, {"_id", 1}}}},
+		{{"$limit", limit}},
+	}
+
+	members := make([]domain.MemberActivity, 0)
+	if err := r.aggregate(ctx, pipeline, &members); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *lendingMongoDBRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+func countOf(counts map[string]*domain.PeriodCount, period string) *domain.PeriodCount {
+	count, ok := counts[period]
+	if !ok {
+		count = &domain.PeriodCount{Period: period}
+		counts[period] = count
+	}
+	return count
+}
+
+// sortedPeriodCounts orders the counts by period, which is the time order of the labels
+func sortedPeriodCounts(counts map[string]*domain.PeriodCount) []domain.PeriodCount {
+	sorted := make([]domain.PeriodCount, 0, len(counts))
+	for _, count := range counts {
+		sorted = append(sorted, *count)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Period < sorted[j].Period
+	})
+	return sorted
+}
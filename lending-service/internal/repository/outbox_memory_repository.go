@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"lending-service/internal/domain"
+	"lending-service/pkg/tenant"
+)
+
+type outboxMemoryRepository struct {
+	mu     sync.RWMutex
+	events map[primitive.ObjectID]domain.OutboxEvent
+}
+
+func NewOutboxMemoryRepository() domain.OutboxRepository {
+	return &outboxMemoryRepository{
+		events: make(map[primitive.ObjectID]domain.OutboxEvent),
+	}
+}
+
+func (r *outboxMemoryRepository) Create(ctx context.Context, event *domain.OutboxEvent) error {
+	event.ID = primitive.NewObjectID()
+	event.Meta.Create()
+	event.Meta.TenantID = tenant.FromContext(ctx)
+
+	var stored domain.OutboxEvent
+	copyDocument(event, &stored)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[stored.ID] = stored
+	return nil
+}
+
+func (r *outboxMemoryRepository) FetchPending(_ context.Context, limit int) ([]domain.OutboxEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]domain.OutboxEvent, 0)
+	for _, event := range r.events {
+		if event.PublishedAt != nil || event.DeadAt != nil {
+			continue
+		}
+
+		var found domain.OutboxEvent
+		copyDocument(event, &found)
+		events = append(events, found)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return bytes.Compare(events[i].ID[:], events[j].ID[:]) < 0
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *outboxMemoryRepository) MarkPublished(_ context.Context, id primitive.ObjectID, publishedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[id]
+	if !ok {
+		return nil
+	}
+	event.PublishedAt = &publishedAt
+	event.Meta.Update()
+	r.events[id] = event
+	return nil
+}
+
+func (r *outboxMemoryRepository) MarkFailed(_ context.Context, id primitive.ObjectID, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[id]
+	if !ok {
+		return nil
+	}
+	event.Attempts++
+	event.LastError = reason
+	event.Meta.Update()
+	r.events[id] = event
+	return nil
+}
+
+func (r *outboxMemoryRepository) MarkDead(_ context.Context, id primitive.ObjectID, reason string, deadAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[id]
+	if !ok {
+		return nil
+	}
+	event.Attempts++
+	event.LastError = reason
+	event.DeadAt = &deadAt
+	event.Meta.Update()
+	r.events[id] = event
+	return nil
+}
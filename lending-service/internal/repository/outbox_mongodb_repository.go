@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+	"lending-service/pkg/tenant"
+)
+
+type outboxMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewOutboxMongoDBRepository(db *mongo.Database) domain.OutboxRepository {
+	return &outboxMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.OutboxCollection),
+	}
+}
+
+func (r *outboxMongoDBRepository) Create(ctx context.Context, event *domain.OutboxEvent) error {
+	event.ID = primitive.NewObjectID()
+	event.Meta.Create()
+	event.Meta.TenantID = tenant.FromContext(ctx)
+
+	_, err := r.collection.InsertOne(ctx, event)
+	return err
+}
+
+// FetchPending is not scoped to a tenant, the relay publishes the events of every tenant
+func (r *outboxMongoDBRepository) FetchPending(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	cursor, err := r.collection.Find(ctx, bson.D{{"published_at", nil}, {"dead_at", nil}},
+		options.Find().SetSort(bson.D{{"_id", 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := make([]domain.OutboxEvent, 0)
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *outboxMongoDBRepository) MarkPublished(ctx context.Context, id primitive.ObjectID, publishedAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{
+		{"$set", bson.D{{"published_at", publishedAt}, {"meta.updated_at", time.Now()}}},
+	})
+	return err
+}
+
+func (r *outboxMongoDBRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{
+		{"$set", bson.D{{"last_error", reason}, {"meta.updated_at", time.Now()}}},
+		{"$inc", bson.D{{"attempts", 1}}},
+	})
+	return err
+}
+
+func (r *outboxMongoDBRepository) MarkDead(ctx context.Context, id primitive.ObjectID, reason string, deadAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{
+		{"$set", bson.D{{"last_error", reason}, {"dead_at", deadAt}, {"meta.updated_at", time.Now()}}},
+		{"$inc", bson.D{{"attempts", 1}}},
+	})
+	return err
+}
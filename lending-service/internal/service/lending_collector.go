@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"lending-service/internal/domain"
+	"lending-service/pkg/logger"
+)
+
+const collectTimeout = 5 * time.Second
+
+// LendingCollector exports the lending gauges of every tenant, they are counted from the database on each scrape
+type LendingCollector struct {
+	lendingRepository domain.LendingRepository
+	active            *prometheus.Desc
+	overdue           *prometheus.Desc
+}
+
+func NewLendingCollector(lendingRepository domain.LendingRepository) *LendingCollector {
+	return &LendingCollector{
+		lendingRepository: lendingRepository,
+		active: prometheus.NewDesc("library_active_lendings",
+			"Number of active lendings, by tenant.", []string{"tenant"}, nil),
+		overdue: prometheus.NewDesc("library_overdue_lendings",
+			"Number of active lendings past their return date, by tenant.", []string{"tenant"}, nil),
+	}
+}
+
+func (c *LendingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.active
+	ch <- c.overdue
+}
+
+func (c *LendingCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.lendingRepository.CountActiveByTenant(ctx, time.Now())
+	if err != nil {
+		// the other metrics are still worth scraping
+		logger.Ctx(ctx).Error().Err(err).Msg("Error collecting lending metrics")
+		return
+	}
+
+	for _, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(count.Active), count.TenantID)
+		ch <- prometheus.MustNewConstMetric(c.overdue, prometheus.GaugeValue, float64(count.Overdue), count.TenantID)
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/event"
+	"lending-service/pkg/proto"
+)
+
+func (s *LendingGRPCService) recordLendingCreated(ctx context.Context, lending domain.Lending) error {
+	return event.Record(ctx, s.outboxRepository, event.LendingCreated, lending.ID.Hex(), &proto.LendingCreated{
+		LendingId:  lending.ID.Hex(),
+		BookId:     lending.BookID.Hex(),
+		UserId:     lending.UserID.Hex(),
+		BranchId:   objectIDHex(lending.BranchID),
+		ReturnDate: timestamppb.New(lending.ReturnDate),
+	})
+}
+
+func (s *LendingGRPCService) recordLendingRenewed(ctx context.Context, lending domain.Lending) error {
+	return event.Record(ctx, s.outboxRepository, event.LendingRenewed, lending.ID.Hex(), &proto.LendingRenewed{
+		LendingId:  lending.ID.Hex(),
+		BookId:     lending.BookID.Hex(),
+		UserId:     lending.UserID.Hex(),
+		ReturnDate: timestamppb.New(lending.ReturnDate),
+	})
+}
+
+// recordLendingFinished is recorded once the lending is returned, the overdue ones are returned after their return date
+func (s *LendingGRPCService) recordLendingFinished(ctx context.Context, lending domain.Lending) error {
+	var returnedAt *timestamppb.Timestamp
+	overdue := false
+	if lending.ReturnedAt != nil {
+		returnedAt = timestamppb.New(*lending.ReturnedAt)
+		overdue = lending.ReturnedAt.After(lending.ReturnDate)
+	}
+
+	return event.Record(ctx, s.outboxRepository, event.LendingFinished, lending.ID.Hex(), &proto.LendingFinished{
+		LendingId:      lending.ID.Hex(),
+		BookId:         lending.BookID.Hex(),
+		UserId:         lending.UserID.Hex(),
+		ReturnBranchId: objectIDHex(lending.ReturnBranchID),
+		ReturnedAt:     returnedAt,
+		Overdue:        overdue,
+	})
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"lending-service/internal/event"
+	"lending-service/pkg/proto"
+)
+
+func TestLendingGRPCService_RecordsEvents(t *testing.T) {
+	s, bookServiceClient := newMemoryLendingGRPCService()
+	ctx := context.Background()
+
+	book := bookServiceClient.addBook("", 1, bookServiceClient.defaultBranch)
+	lending := createTestLending(t, s, &proto.CreateLendingRequest{UserId: primitive.NewObjectID().Hex(), BookId: book.Id})
+	if _, err := s.RenewLending(ctx, &proto.RenewLendingRequest{Id: lending.Id}); err != nil {
+		t.Fatalf("RenewLending() error = %v", err)
+	}
+	if _, err := s.FinishLending(ctx, &proto.FinishLendingRequest{Id: lending.Id}); err != nil {
+		t.Fatalf("FinishLending() error = %v", err)
+	}
+
+	// a canceled lending is never active, so it is not published
+	bookServiceClient.stockErr = errors.New("book-service is unavailable")
+	stream := &createLendingServerStub{}
+	if err := s.CreateLending(&proto.CreateLendingRequest{UserId: lending.UserId, BookId: book.Id}, stream); err == nil {
+		t.Fatalf("CreateLending() error = nil, want the stock error")
+	}
+
+	outboxEvents, err := s.outboxRepository.FetchPending(ctx, 10)
+	if err != nil {
+		t.Fatalf("FetchPending() error = %v", err)
+	}
+
+	wantTypes := []string{event.LendingCreated, event.LendingRenewed, event.LendingFinished}
+	if len(outboxEvents) != len(wantTypes) {
+		t.Fatalf("events = %d, want %v", len(outboxEvents), wantTypes)
+	}
+	for i, outboxEvent := range outboxEvents {
+		var recorded proto.Event
+		if err = protobuf.Unmarshal(outboxEvent.Payload, &recorded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if recorded.Type != wantTypes[i] || recorded.AggregateId != lending.Id {
+			t.Errorf("event[%d] = %s of %s, want %s of %s", i, recorded.Type, recorded.AggregateId, wantTypes[i], lending.Id)
+		}
+
+		if recorded.Type == event.LendingFinished {
+			var finished proto.LendingFinished
+			if err = recorded.Payload.UnmarshalTo(&finished); err != nil {
+				t.Fatalf("UnmarshalTo() error = %v", err)
+			}
+			if finished.ReturnBranchId != bookServiceClient.defaultBranch.Id || finished.ReturnedAt == nil || finished.Overdue {
+				t.Errorf("lending finished = %v, want returned in time to the default branch", &finished)
+			}
+		}
+	}
+}
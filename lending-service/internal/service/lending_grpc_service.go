@@ -14,7 +14,8 @@ import (
 
 	"lending-service/internal/domain"
 	"lending-service/internal/domain/constant"
-	"lending-service/internal/repository"
+	"lending-service/pkg/detached"
+	"lending-service/pkg/mongodb"
 	"lending-service/pkg/proto"
 )
 
@@ -25,23 +26,31 @@ const (
 type LendingGRPCService struct {
 	proto.UnimplementedLendingServiceServer
 	lendingRepository domain.LendingRepository
+	outboxRepository  domain.OutboxRepository
+	txRepository      mongodb.TXRepository
 	userServiceClient proto.UserServiceClient
 	bookServiceClient proto.BookServiceClient
 }
 
 func NewLendingGRPCService(
+	lendingRepository domain.LendingRepository,
+	outboxRepository domain.OutboxRepository,
+	txRepository mongodb.TXRepository,
 	userServiceClient proto.UserServiceClient,
 	bookServiceClient proto.BookServiceClient,
 ) *LendingGRPCService {
 	return &LendingGRPCService{
-		lendingRepository: repository.NewLendingMongoDBRepository(),
+		lendingRepository: lendingRepository,
+		outboxRepository:  outboxRepository,
+		txRepository:      txRepository,
 		userServiceClient: userServiceClient,
 		bookServiceClient: bookServiceClient,
 	}
 }
 
 func (s *LendingGRPCService) CreateLending(request *proto.CreateLendingRequest, stream proto.LendingService_CreateLendingServer) error {
-	ctx := context.Background()
+	// the lending must be completed or compensated even when the client goes away
+	ctx := detached.Context(stream.Context())
 
 	userID, err := primitive.ObjectIDFromHex(request.UserId)
 	if err != nil {
@@ -55,16 +64,27 @@ func (s *LendingGRPCService) CreateLending(request *proto.CreateLendingRequest,
 		return err
 	}
 
-	if book.Stock == 0 {
-		return status.Error(codes.Aborted, "book stock is empty")
+	branch, err := s.findBranch(ctx, request.BranchId)
+	if err != nil {
+		return err
+	}
+	if branchStock(book, branch.Id) == 0 {
+		return status.Errorf(codes.Aborted, "book stock at %s branch is empty", branch.Name)
+	}
+
+	lendingDuration, err := s.lendingDuration(ctx, book)
+	if err != nil {
+		return err
 	}
 
 	bookID, _ := primitive.ObjectIDFromHex(book.Id)
+	branchID, _ := primitive.ObjectIDFromHex(branch.Id)
 	lending := domain.Lending{
 		BookID:     bookID,
 		UserID:     userID,
 		Status:     constant.LendingDraft,
-		ReturnDate: time.Now().Add(defaultLendingDuration * time.Hour),
+		ReturnDate: time.Now().Add(lendingDuration),
+		BranchID:   &branchID,
 	}
 
 	err = s.lendingRepository.Create(ctx, &lending)
@@ -72,13 +92,7 @@ func (s *LendingGRPCService) CreateLending(request *proto.CreateLendingRequest,
 		return status.Error(codes.Internal, err.Error())
 	}
 
-	err = stream.Send(&proto.Lending{
-		Id:         lending.ID.Hex(),
-		BookId:     lending.BookID.Hex(),
-		UserId:     lending.UserID.Hex(),
-		Status:     string(lending.Status),
-		ReturnDate: timestamppb.New(lending.ReturnDate),
-	})
+	err = stream.Send(toProtoLending(lending))
 	if err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
@@ -86,6 +100,10 @@ func (s *LendingGRPCService) CreateLending(request *proto.CreateLendingRequest,
 	_, err = s.bookServiceClient.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
 		Id:          book.Id,
 		StockChange: -1,
+		Reason:      string(constant.StockLending),
+		ActorId:     lending.UserID.Hex(),
+		LendingId:   lending.ID.Hex(),
+		BranchId:    branch.Id,
 	})
 	if err != nil {
 		lending.Status = constant.LendingCanceled
@@ -98,16 +116,26 @@ func (s *LendingGRPCService) CreateLending(request *proto.CreateLendingRequest,
 	}
 
 	lending.Status = constant.LendingActive
-	err = s.lendingRepository.Update(ctx, &lending)
+	err = s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.lendingRepository.Update(ctx, &lending); err != nil {
+			return err
+		}
+		return s.recordLendingCreated(ctx, lending)
+	})
 	if err != nil {
 		_, cancelBookErr := s.bookServiceClient.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
 			Id:          book.Id,
 			StockChange: 1,
+			Reason:      string(constant.StockCorrection),
+			ActorId:     lending.UserID.Hex(),
+			LendingId:   lending.ID.Hex(),
+			BranchId:    branch.Id,
 		})
 		if cancelBookErr != nil {
 			return status.Error(codes.Internal, cancelBookErr.Error())
 		}
 
+		lending.Status = constant.LendingCanceled
 		cancelErr := s.cancelCreateLending(ctx, lending, stream)
 		if cancelErr != nil {
 			return status.Error(codes.Internal, cancelErr.Error())
@@ -116,13 +144,7 @@ func (s *LendingGRPCService) CreateLending(request *proto.CreateLendingRequest,
 		return status.Error(codes.Internal, err.Error())
 	}
 
-	err = stream.Send(&proto.Lending{
-		Id:         lending.ID.Hex(),
-		BookId:     lending.BookID.Hex(),
-		UserId:     lending.UserID.Hex(),
-		Status:     string(lending.Status),
-		ReturnDate: timestamppb.New(lending.ReturnDate),
-	})
+	err = stream.Send(toProtoLending(lending))
 	if err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
@@ -130,19 +152,57 @@ func (s *LendingGRPCService) CreateLending(request *proto.CreateLendingRequest,
 	return nil
 }
 
+// withTransaction runs fn in a transaction, the repositories take part in it through the context given to fn
+func (s *LendingGRPCService) withTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := s.txRepository.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// findBranch returns the branch of the book service, or its default branch for an empty ID
+func (s *LendingGRPCService) findBranch(ctx context.Context, id string) (*proto.Branch, error) {
+	if id == "" {
+		return s.bookServiceClient.FindDefaultBranch(ctx, &proto.FindDefaultBranchRequest{})
+	}
+
+	return s.bookServiceClient.FindBranchByID(ctx, &proto.FindBranchByIDRequest{
+		Id: id,
+	})
+}
+
+// lendingDuration returns the loan period of the book category, or the default one
+func (s *LendingGRPCService) lendingDuration(ctx context.Context, book *proto.Book) (time.Duration, error) {
+	if book.CategoryId == "" {
+		return defaultLendingDuration * time.Hour, nil
+	}
+
+	category, err := s.bookServiceClient.FindCategoryByID(ctx, &proto.FindCategoryByIDRequest{
+		Id: book.CategoryId,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if category.LoanDays <= 0 {
+		return defaultLendingDuration * time.Hour, nil
+	}
+
+	return time.Duration(category.LoanDays) * 24 * time.Hour, nil
+}
+
 func (s *LendingGRPCService) cancelCreateLending(ctx context.Context, lending domain.Lending, stream proto.LendingService_CreateLendingServer) error {
 	err := s.lendingRepository.Update(ctx, &lending)
 	if err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
 
-	err = stream.Send(&proto.Lending{
-		Id:         lending.ID.Hex(),
-		BookId:     lending.BookID.Hex(),
-		UserId:     lending.UserID.Hex(),
-		Status:     string(lending.Status),
-		ReturnDate: timestamppb.New(lending.ReturnDate),
-	})
+	err = stream.Send(toProtoLending(lending))
 	if err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
@@ -165,6 +225,9 @@ func (s *LendingGRPCService) FetchLending(ctx context.Context, request *proto.Fe
 	if bookID := request.BookId; bookID != "" {
 		fetchFilter["book_id"] = bookID
 	}
+	if branchID := request.BranchId; branchID != "" {
+		fetchFilter["branch_id"] = branchID
+	}
 
 	lendings, err := s.lendingRepository.Fetch(ctx, fetchFilter)
 	if err != nil {
@@ -173,13 +236,7 @@ func (s *LendingGRPCService) FetchLending(ctx context.Context, request *proto.Fe
 
 	protoLendings := make([]*proto.Lending, 0)
 	for _, lending := range lendings {
-		protoLendings = append(protoLendings, &proto.Lending{
-			Id:         lending.ID.Hex(),
-			BookId:     lending.BookID.Hex(),
-			UserId:     lending.UserID.Hex(),
-			Status:     string(lending.Status),
-			ReturnDate: timestamppb.New(lending.ReturnDate),
-		})
+		protoLendings = append(protoLendings, toProtoLending(lending))
 	}
 
 	total, err := s.lendingRepository.Count(ctx, fetchFilter)
@@ -207,20 +264,31 @@ func (s *LendingGRPCService) RenewLending(ctx context.Context, request *proto.Re
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	lending.ReturnDate = time.Now().Add(defaultLendingDuration * time.Hour)
+	book, err := s.bookServiceClient.FindByID(ctx, &proto.FindBookByIDRequest{
+		Id: lending.BookID.Hex(),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	err = s.lendingRepository.Update(ctx, &lending)
+	lendingDuration, err := s.lendingDuration(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+
+	lending.ReturnDate = time.Now().Add(lendingDuration)
+
+	err = s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.lendingRepository.Update(ctx, &lending); err != nil {
+			return err
+		}
+		return s.recordLendingRenewed(ctx, lending)
+	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.Lending{
-		Id:         lending.ID.Hex(),
-		BookId:     lending.BookID.Hex(),
-		UserId:     lending.UserID.Hex(),
-		Status:     string(lending.Status),
-		ReturnDate: timestamppb.New(lending.ReturnDate),
-	}, nil
+	return toProtoLending(lending), nil
 }
 
 func (s *LendingGRPCService) FinishLending(ctx context.Context, request *proto.FinishLendingRequest) (*proto.Lending, error) {
@@ -232,26 +300,149 @@ func (s *LendingGRPCService) FinishLending(ctx context.Context, request *proto.F
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if err = s.finishLending(ctx, &lending, request.BranchId); err != nil {
+		return nil, err
+	}
+
+	return toProtoLending(lending), nil
+}
+
+// finishLending returns the book to the branch, or to the lending branch for an empty branch ID.
+// The lendings made before the branches have no branch, so their books are returned to the default branch.
+func (s *LendingGRPCService) finishLending(ctx context.Context, lending *domain.Lending, branchID string) error {
+	if branchID == "" {
+		branchID = objectIDHex(lending.BranchID)
+	}
+	branch, err := s.findBranch(ctx, branchID)
+	if err != nil {
+		return err
+	}
+
+	returnBranchID, _ := primitive.ObjectIDFromHex(branch.Id)
+	returnedAt := time.Now()
+	lending.ReturnBranchID = &returnBranchID
+	lending.ReturnedAt = &returnedAt
 	lending.Status = constant.LendingInactive
 
-	err = s.lendingRepository.Update(ctx, &lending)
+	err = s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.lendingRepository.Update(ctx, lending); err != nil {
+			return err
+		}
+		return s.recordLendingFinished(ctx, *lending)
+	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return status.Error(codes.Internal, err.Error())
 	}
 
 	_, err = s.bookServiceClient.UpdateBookStock(ctx, &proto.UpdateBookStockRequest{
 		Id:          lending.BookID.Hex(),
 		StockChange: 1,
+		Reason:      string(constant.StockReturn),
+		ActorId:     lending.UserID.Hex(),
+		LendingId:   lending.ID.Hex(),
+		BranchId:    branch.Id,
 	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LendingGRPCService) CountActiveLendings(ctx context.Context, request *proto.CountActiveLendingsRequest) (*proto.CountActiveLendingsResponse, error) {
+	lendings, err := s.fetchActiveLendings(ctx, request.BookId, request.UserId)
 	if err != nil {
 		return nil, err
 	}
 
-	return &proto.Lending{
-		Id:         lending.ID.Hex(),
-		BookId:     lending.BookID.Hex(),
-		UserId:     lending.UserID.Hex(),
-		Status:     string(lending.Status),
-		ReturnDate: timestamppb.New(lending.ReturnDate),
+	protoLendings := make([]*proto.Lending, 0)
+	for _, lending := range lendings {
+		protoLendings = append(protoLendings, toProtoLending(lending))
+	}
+
+	return &proto.CountActiveLendingsResponse{
+		Count:    int32(len(lendings)),
+		Lendings: protoLendings,
 	}, nil
 }
+
+func (s *LendingGRPCService) FinishActiveLendings(ctx context.Context, request *proto.FinishActiveLendingsRequest) (*proto.FinishActiveLendingsResponse, error) {
+	lendings, err := s.fetchActiveLendings(ctx, request.BookId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	protoLendings := make([]*proto.Lending, 0)
+	for _, lending := range lendings {
+		if err = s.finishLending(ctx, &lending, ""); err != nil {
+			return nil, err
+		}
+
+		protoLendings = append(protoLendings, toProtoLending(lending))
+	}
+
+	return &proto.FinishActiveLendingsResponse{
+		Lendings: protoLendings,
+	}, nil
+}
+
+// fetchActiveLendings returns every active lending of a book, a user, or both.
+func (s *LendingGRPCService) fetchActiveLendings(ctx context.Context, bookID, userID string) ([]domain.Lending, error) {
+	if bookID == "" && userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "book ID or user ID is required")
+	}
+
+	fetchFilter := map[string]interface{}{
+		"status": constant.LendingActive,
+	}
+	if bookID != "" {
+		fetchFilter["book_id"] = bookID
+	}
+	if userID != "" {
+		fetchFilter["user_id"] = userID
+	}
+
+	total, err := s.lendingRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if total == 0 {
+		return []domain.Lending{}, nil
+	}
+
+	fetchFilter["limit"] = int32(total)
+	lendings, err := s.lendingRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return lendings, nil
+}
+
+func toProtoLending(lending domain.Lending) *proto.Lending {
+	return &proto.Lending{
+		Id:             lending.ID.Hex(),
+		BookId:         lending.BookID.Hex(),
+		UserId:         lending.UserID.Hex(),
+		Status:         string(lending.Status),
+		ReturnDate:     timestamppb.New(lending.ReturnDate),
+		BranchId:       objectIDHex(lending.BranchID),
+		ReturnBranchId: objectIDHex(lending.ReturnBranchID),
+	}
+}
+
+func branchStock(book *proto.Book, branchID string) int32 {
+	for _, branch := range book.Branches {
+		if branch.BranchId == branchID {
+			return branch.Stock
+		}
+	}
+	return 0
+}
+
+func objectIDHex(id *primitive.ObjectID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Hex()
+}
@@ -0,0 +1,673 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+	"lending-service/internal/repository"
+	"lending-service/pkg/mongodb"
+	"lending-service/pkg/proto"
+)
+
+// bookServiceClientStub keeps the stock of its books by branch, every stock change is recorded
+type bookServiceClientStub struct {
+	proto.BookServiceClient
+	mu            sync.Mutex
+	books         map[string]*proto.Book
+	branches      map[string]*proto.Branch
+	defaultBranch *proto.Branch
+	categories    map[string]*proto.Category
+	stockErr      error
+	stockChanges  []*proto.UpdateBookStockRequest
+}
+
+func newBookServiceClientStub() *bookServiceClientStub {
+	defaultBranch := &proto.Branch{Id: primitive.NewObjectID().Hex(), Name: "Main", Code: "main", Default: true}
+	return &bookServiceClientStub{
+		books:         map[string]*proto.Book{},
+		branches:      map[string]*proto.Branch{defaultBranch.Id: defaultBranch},
+		defaultBranch: defaultBranch,
+		categories:    map[string]*proto.Category{},
+	}
+}
+
+func (c *bookServiceClientStub) addBook(categoryID string, stock int32, branch *proto.Branch) *proto.Book {
+	book := &proto.Book{Id: primitive.NewObjectID().Hex(), Title: "Dune", CategoryId: categoryID, Stock: stock}
+	book.Branches = []*proto.BranchStock{{BranchId: branch.Id, Stock: stock}}
+	c.books[book.Id] = book
+	return book
+}
+
+func (c *bookServiceClientStub) addBranch(code string) *proto.Branch {
+	branch := &proto.Branch{Id: primitive.NewObjectID().Hex(), Name: code, Code: code}
+	c.branches[branch.Id] = branch
+	return branch
+}
+
+func (c *bookServiceClientStub) FindByID(_ context.Context, in *proto.FindBookByIDRequest, _ ...grpc.CallOption) (*proto.Book, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	book, ok := c.books[in.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "book with %s ID is not found", in.Id)
+	}
+	return book, nil
+}
+
+func (c *bookServiceClientStub) FindBranchByID(_ context.Context, in *proto.FindBranchByIDRequest, _ ...grpc.CallOption) (*proto.Branch, error) {
+	branch, ok := c.branches[in.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "branch with %s ID is not found", in.Id)
+	}
+	return branch, nil
+}
+
+func (c *bookServiceClientStub) FindDefaultBranch(context.Context, *proto.FindDefaultBranchRequest, ...grpc.CallOption) (*proto.Branch, error) {
+	return c.defaultBranch, nil
+}
+
+func (c *bookServiceClientStub) FindCategoryByID(_ context.Context, in *proto.FindCategoryByIDRequest, _ ...grpc.CallOption) (*proto.Category, error) {
+	category, ok := c.categories[in.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "category with %s ID is not found", in.Id)
+	}
+	return category, nil
+}
+
+func (c *bookServiceClientStub) UpdateBookStock(_ context.Context, in *proto.UpdateBookStockRequest, _ ...grpc.CallOption) (*proto.Book, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stockChanges = append(c.stockChanges, in)
+	if c.stockErr != nil {
+		return nil, c.stockErr
+	}
+
+	book := c.books[in.Id]
+	for _, branchStock := range book.Branches {
+		if branchStock.BranchId == in.BranchId {
+			if branchStock.Stock+in.StockChange < 0 {
+				return nil, status.Error(codes.Aborted, "book stock is empty")
+			}
+			branchStock.Stock += in.StockChange
+			book.Stock += in.StockChange
+			return book, nil
+		}
+	}
+
+	book.Branches = append(book.Branches, &proto.BranchStock{BranchId: in.BranchId, Stock: in.StockChange})
+	book.Stock += in.StockChange
+	return book, nil
+}
+
+// createLendingServerStub collects the sent lendings
+type createLendingServerStub struct {
+	grpc.ServerStream
+	lendings []*proto.Lending
+}
+
+func (*createLendingServerStub) Context() context.Context {
+	return context.Background()
+}
+
+func (s *createLendingServerStub) Send(lending *proto.Lending) error {
+	s.lendings = append(s.lendings, lending)
+	return nil
+}
+
+// failingLendingRepository fails to save a lending changed to status
+type failingLendingRepository struct {
+	domain.LendingRepository
+	status constant.LendingStatus
+}
+
+func (r failingLendingRepository) Update(ctx context.Context, lending *domain.Lending) error {
+	if lending.Status == r.status {
+		return errors.New("lending is not updated")
+	}
+	return r.LendingRepository.Update(ctx, lending)
+}
+
+// failingOutboxRepository fails to record the events, the changes they describe must fail with them
+type failingOutboxRepository struct {
+	domain.OutboxRepository
+}
+
+func (failingOutboxRepository) Create(context.Context, *domain.OutboxEvent) error {
+	return errors.New("outbox is unavailable")
+}
+
+func newMemoryLendingGRPCService() (*LendingGRPCService, *bookServiceClientStub) {
+	bookServiceClient := newBookServiceClientStub()
+	return NewLendingGRPCService(repository.NewLendingMemoryRepository(), repository.NewOutboxMemoryRepository(), mongodb.NewNoTXRepository(), nil, bookServiceClient), bookServiceClient
+}
+
+func createTestLending(t *testing.T, s *LendingGRPCService, request *proto.CreateLendingRequest) *proto.Lending {
+	t.Helper()
+
+	stream := &createLendingServerStub{}
+	if err := s.CreateLending(request, stream); err != nil {
+		t.Fatalf("CreateLending() error = %v", err)
+	}
+	return stream.lendings[len(stream.lendings)-1]
+}
+
+func stockReasons(changes []*proto.UpdateBookStockRequest) []string {
+	reasons := make([]string, 0, len(changes))
+	for _, change := range changes {
+		reasons = append(reasons, change.Reason)
+	}
+	return reasons
+}
+
+func TestLendingGRPCService_CreateLending(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		name             string
+		stock            int32
+		loanDays         int32
+		request          func(book *proto.Book, branch *proto.Branch) *proto.CreateLendingRequest
+		stockErr         error
+		failStatus       constant.LendingStatus
+		failOutbox       bool
+		wantCode         codes.Code
+		wantStatuses     []string
+		wantStockReasons []string
+		wantLoanDays     int
+	}{
+		{
+			name:  "at the default branch",
+			stock: 1,
+			request: func(book *proto.Book, _ *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: book.Id}
+			},
+			wantStatuses:     []string{"DRAFT", "ACTIVE"},
+			wantStockReasons: []string{"lending"},
+			wantLoanDays:     14,
+		},
+		{
+			name:     "for the loan days of the category",
+			stock:    1,
+			loanDays: 7,
+			request: func(book *proto.Book, branch *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: book.Id, BranchId: branch.Id}
+			},
+			wantStatuses:     []string{"DRAFT", "ACTIVE"},
+			wantStockReasons: []string{"lending"},
+			wantLoanDays:     7,
+		},
+		{
+			name:  "empty stock",
+			stock: 0,
+			request: func(book *proto.Book, _ *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: book.Id}
+			},
+			wantCode: codes.Aborted,
+		},
+		{
+			name:  "invalid user ID",
+			stock: 1,
+			request: func(book *proto.Book, _ *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: "user", BookId: book.Id}
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:  "unknown book",
+			stock: 1,
+			request: func(*proto.Book, *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: primitive.NewObjectID().Hex()}
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:  "unknown branch",
+			stock: 1,
+			request: func(book *proto.Book, _ *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: book.Id, BranchId: primitive.NewObjectID().Hex()}
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:  "canceled when the stock is not updated",
+			stock: 1,
+			request: func(book *proto.Book, _ *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: book.Id}
+			},
+			stockErr:         status.Error(codes.Unavailable, "book service is unavailable"),
+			wantCode:         codes.Internal,
+			wantStatuses:     []string{"DRAFT", "CANCELED"},
+			wantStockReasons: []string{"lending"},
+		},
+		{
+			name:  "stock corrected and canceled when the lending is not activated",
+			stock: 1,
+			request: func(book *proto.Book, _ *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: book.Id}
+			},
+			failStatus:       constant.LendingActive,
+			wantCode:         codes.Internal,
+			wantStatuses:     []string{"DRAFT", "CANCELED"},
+			wantStockReasons: []string{"lending", "correction"},
+		},
+		{
+			name:  "stock corrected and canceled when the event is not recorded",
+			stock: 1,
+			request: func(book *proto.Book, _ *proto.Branch) *proto.CreateLendingRequest {
+				return &proto.CreateLendingRequest{UserId: userID, BookId: book.Id}
+			},
+			failOutbox:       true,
+			wantCode:         codes.Internal,
+			wantStatuses:     []string{"DRAFT", "CANCELED"},
+			wantStockReasons: []string{"lending", "correction"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, bookServiceClient := newMemoryLendingGRPCService()
+			if tt.failStatus != "" {
+				s.lendingRepository = failingLendingRepository{LendingRepository: s.lendingRepository, status: tt.failStatus}
+			}
+			if tt.failOutbox {
+				s.outboxRepository = failingOutboxRepository{OutboxRepository: s.outboxRepository}
+			}
+
+			branch := bookServiceClient.defaultBranch
+			categoryID := ""
+			if tt.loanDays > 0 {
+				branch = bookServiceClient.addBranch("east")
+				categoryID = primitive.NewObjectID().Hex()
+				bookServiceClient.categories[categoryID] = &proto.Category{Id: categoryID, LoanDays: tt.loanDays}
+			}
+			book := bookServiceClient.addBook(categoryID, tt.stock, branch)
+			bookServiceClient.stockErr = tt.stockErr
+
+			stream := &createLendingServerStub{}
+			err := s.CreateLending(tt.request(book, branch), stream)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateLending() error = %v, want %v", err, tt.wantCode)
+			}
+
+			statuses := make([]string, 0, len(stream.lendings))
+			for _, lending := range stream.lendings {
+				statuses = append(statuses, lending.Status)
+			}
+			if !equalStrings(statuses, tt.wantStatuses) {
+				t.Errorf("CreateLending() statuses = %v, want %v", statuses, tt.wantStatuses)
+			}
+			if reasons := stockReasons(bookServiceClient.stockChanges); !equalStrings(reasons, tt.wantStockReasons) {
+				t.Errorf("CreateLending() stock changes = %v, want %v", reasons, tt.wantStockReasons)
+			}
+			if len(stream.lendings) == 0 {
+				return
+			}
+
+			// the stock is taken only by an active lending
+			lending := stream.lendings[len(stream.lendings)-1]
+			wantStock := tt.stock
+			if lending.Status == string(constant.LendingActive) {
+				wantStock--
+			}
+			if book.Branches[0].Stock != wantStock {
+				t.Errorf("CreateLending() stock = %d, want %d", book.Branches[0].Stock, wantStock)
+			}
+
+			stored, err := s.lendingRepository.FindByID(context.Background(), lending.Id)
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if string(stored.Status) != lending.Status || objectIDHex(stored.BranchID) != branch.Id {
+				t.Errorf("stored lending = %+v, want %s at %s", stored, lending.Status, branch.Id)
+			}
+			if tt.wantLoanDays > 0 {
+				wantReturnDate := time.Now().Add(time.Duration(tt.wantLoanDays) * 24 * time.Hour)
+				if diff := wantReturnDate.Sub(lending.ReturnDate.AsTime()); diff < 0 || diff > time.Minute {
+					t.Errorf("CreateLending() return date = %v, want %v", lending.ReturnDate.AsTime(), wantReturnDate)
+				}
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_FetchLending(t *testing.T) {
+	s, bookServiceClient := newMemoryLendingGRPCService()
+	ctx := context.Background()
+
+	east := bookServiceClient.addBranch("east")
+	book := bookServiceClient.addBook("", 2, bookServiceClient.defaultBranch)
+	other := bookServiceClient.addBook("", 1, bookServiceClient.defaultBranch)
+	user := primitive.NewObjectID().Hex()
+
+	first := createTestLending(t, s, &proto.CreateLendingRequest{UserId: user, BookId: book.Id})
+	second := createTestLending(t, s, &proto.CreateLendingRequest{UserId: primitive.NewObjectID().Hex(), BookId: book.Id})
+	third := createTestLending(t, s, &proto.CreateLendingRequest{UserId: user, BookId: other.Id})
+	if _, err := s.FinishLending(ctx, &proto.FinishLendingRequest{Id: first.Id, BranchId: east.Id}); err != nil {
+		t.Fatalf("FinishLending() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		request   *proto.FetchLendingRequest
+		wantIDs   []string
+		wantTotal int32
+	}{
+		{
+			name:      "newest first",
+			request:   &proto.FetchLendingRequest{Pagination: &proto.LendingPaginationRequest{Limit: 2, Page: 1}},
+			wantIDs:   []string{third.Id, second.Id},
+			wantTotal: 3,
+		},
+		{
+			name:      "by page",
+			request:   &proto.FetchLendingRequest{Pagination: &proto.LendingPaginationRequest{Limit: 2, Page: 2}},
+			wantIDs:   []string{first.Id},
+			wantTotal: 3,
+		},
+		{
+			name:      "by status",
+			request:   &proto.FetchLendingRequest{Pagination: &proto.LendingPaginationRequest{Limit: 10, Page: 1}, Status: string(constant.LendingActive)},
+			wantIDs:   []string{third.Id, second.Id},
+			wantTotal: 2,
+		},
+		{
+			name:      "by user",
+			request:   &proto.FetchLendingRequest{Pagination: &proto.LendingPaginationRequest{Limit: 10, Page: 1}, UserId: user},
+			wantIDs:   []string{third.Id, first.Id},
+			wantTotal: 2,
+		},
+		{
+			name:      "by book",
+			request:   &proto.FetchLendingRequest{Pagination: &proto.LendingPaginationRequest{Limit: 10, Page: 1}, BookId: book.Id},
+			wantIDs:   []string{second.Id, first.Id},
+			wantTotal: 2,
+		},
+		{
+			name:      "by return branch",
+			request:   &proto.FetchLendingRequest{Pagination: &proto.LendingPaginationRequest{Limit: 10, Page: 1}, BranchId: east.Id},
+			wantIDs:   []string{first.Id},
+			wantTotal: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FetchLending(ctx, tt.request)
+			if err != nil {
+				t.Fatalf("FetchLending() error = %v", err)
+			}
+
+			ids := make([]string, 0, len(got.Lendings))
+			for _, lending := range got.Lendings {
+				ids = append(ids, lending.Id)
+			}
+			if !equalStrings(ids, tt.wantIDs) || got.Pagination.Total != tt.wantTotal {
+				t.Errorf("FetchLending() IDs = %v, total = %d, want %v, %d", ids, got.Pagination.Total, tt.wantIDs, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_RenewLending(t *testing.T) {
+	s, bookServiceClient := newMemoryLendingGRPCService()
+	ctx := context.Background()
+
+	book := bookServiceClient.addBook("", 1, bookServiceClient.defaultBranch)
+	lending := createTestLending(t, s, &proto.CreateLendingRequest{UserId: primitive.NewObjectID().Hex(), BookId: book.Id})
+	stored, err := s.lendingRepository.FindByID(ctx, lending.Id)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	// the lending is due tomorrow
+	stored.ReturnDate = time.Now().Add(24 * time.Hour)
+	if err = s.lendingRepository.Update(ctx, &stored); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode codes.Code
+	}{
+		{
+			name: "for another lending duration",
+			id:   lending.Id,
+		},
+		{
+			name:     "not found",
+			id:       primitive.NewObjectID().Hex(),
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.RenewLending(ctx, &proto.RenewLendingRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("RenewLending() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			wantReturnDate := time.Now().Add(defaultLendingDuration * time.Hour)
+			if diff := wantReturnDate.Sub(got.ReturnDate.AsTime()); diff < 0 || diff > time.Minute {
+				t.Errorf("RenewLending() return date = %v, want %v", got.ReturnDate.AsTime(), wantReturnDate)
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_FinishLending(t *testing.T) {
+	tests := []struct {
+		name           string
+		returnBranch   bool
+		withoutBranch  bool
+		missing        bool
+		wantCode       codes.Code
+		wantReturnedAt string
+	}{
+		{
+			name:           "to the lending branch",
+			wantReturnedAt: "lending",
+		},
+		{
+			name:           "to another branch",
+			returnBranch:   true,
+			wantReturnedAt: "return",
+		},
+		{
+			name:           "lent before the branches",
+			withoutBranch:  true,
+			wantReturnedAt: "default",
+		},
+		{
+			name:     "not found",
+			missing:  true,
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, bookServiceClient := newMemoryLendingGRPCService()
+			ctx := context.Background()
+
+			lendingBranch := bookServiceClient.addBranch("east")
+			returnBranch := bookServiceClient.addBranch("west")
+			book := bookServiceClient.addBook("", 1, lendingBranch)
+			lending := createTestLending(t, s, &proto.CreateLendingRequest{
+				UserId: primitive.NewObjectID().Hex(), BookId: book.Id, BranchId: lendingBranch.Id,
+			})
+			if tt.withoutBranch {
+				stored, _ := s.lendingRepository.FindByID(ctx, lending.Id)
+				stored.BranchID = nil
+				if err := s.lendingRepository.Update(ctx, &stored); err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+			}
+
+			request := &proto.FinishLendingRequest{Id: lending.Id}
+			if tt.returnBranch {
+				request.BranchId = returnBranch.Id
+			}
+			if tt.missing {
+				request.Id = primitive.NewObjectID().Hex()
+			}
+			got, err := s.FinishLending(ctx, request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FinishLending() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			wantBranch := map[string]*proto.Branch{
+				"lending": lendingBranch,
+				"return":  returnBranch,
+				"default": bookServiceClient.defaultBranch,
+			}[tt.wantReturnedAt]
+			if got.Status != string(constant.LendingInactive) || got.ReturnBranchId != wantBranch.Id {
+				t.Errorf("FinishLending() = %+v, want inactive returned to %s", got, wantBranch.Code)
+			}
+
+			returned := bookServiceClient.stockChanges[len(bookServiceClient.stockChanges)-1]
+			if returned.Reason != string(constant.StockReturn) || returned.StockChange != 1 || returned.BranchId != wantBranch.Id {
+				t.Errorf("FinishLending() stock change = %+v, want a return to %s", returned, wantBranch.Code)
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_CountActiveLendings(t *testing.T) {
+	s, bookServiceClient := newMemoryLendingGRPCService()
+	ctx := context.Background()
+
+	book := bookServiceClient.addBook("", 3, bookServiceClient.defaultBranch)
+	user := primitive.NewObjectID().Hex()
+	createTestLending(t, s, &proto.CreateLendingRequest{UserId: user, BookId: book.Id})
+	createTestLending(t, s, &proto.CreateLendingRequest{UserId: primitive.NewObjectID().Hex(), BookId: book.Id})
+	finished := createTestLending(t, s, &proto.CreateLendingRequest{UserId: user, BookId: book.Id})
+	if _, err := s.FinishLending(ctx, &proto.FinishLendingRequest{Id: finished.Id}); err != nil {
+		t.Fatalf("FinishLending() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		request   *proto.CountActiveLendingsRequest
+		wantCode  codes.Code
+		wantCount int32
+	}{
+		{
+			name:      "of a book",
+			request:   &proto.CountActiveLendingsRequest{BookId: book.Id},
+			wantCount: 2,
+		},
+		{
+			name:      "of a user",
+			request:   &proto.CountActiveLendingsRequest{UserId: user},
+			wantCount: 1,
+		},
+		{
+			name:    "of a user without lendings",
+			request: &proto.CountActiveLendingsRequest{UserId: primitive.NewObjectID().Hex()},
+		},
+		{
+			name:     "without book or user",
+			request:  &proto.CountActiveLendingsRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CountActiveLendings(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CountActiveLendings() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && (got.Count != tt.wantCount || len(got.Lendings) != int(tt.wantCount)) {
+				t.Errorf("CountActiveLendings() = %+v, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_FinishActiveLendings(t *testing.T) {
+	tests := []struct {
+		name         string
+		byUser       bool
+		stockErr     error
+		wantCode     codes.Code
+		wantFinished int
+	}{
+		{
+			name:         "of a book",
+			wantFinished: 2,
+		},
+		{
+			name:         "of a user",
+			byUser:       true,
+			wantFinished: 1,
+		},
+		{
+			name:     "stock not returned",
+			stockErr: status.Error(codes.Unavailable, "book service is unavailable"),
+			wantCode: codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, bookServiceClient := newMemoryLendingGRPCService()
+			ctx := context.Background()
+
+			book := bookServiceClient.addBook("", 2, bookServiceClient.defaultBranch)
+			user := primitive.NewObjectID().Hex()
+			createTestLending(t, s, &proto.CreateLendingRequest{UserId: user, BookId: book.Id})
+			createTestLending(t, s, &proto.CreateLendingRequest{UserId: primitive.NewObjectID().Hex(), BookId: book.Id})
+			bookServiceClient.stockErr = tt.stockErr
+
+			request := &proto.FinishActiveLendingsRequest{BookId: book.Id}
+			if tt.byUser {
+				request = &proto.FinishActiveLendingsRequest{UserId: user}
+			}
+			got, err := s.FinishActiveLendings(ctx, request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FinishActiveLendings() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(got.Lendings) != tt.wantFinished {
+				t.Errorf("FinishActiveLendings() = %d lendings, want %d", len(got.Lendings), tt.wantFinished)
+			}
+			for _, lending := range got.Lendings {
+				if lending.Status != string(constant.LendingInactive) {
+					t.Errorf("FinishActiveLendings() lending = %+v, want inactive", lending)
+				}
+			}
+			if book.Stock != int32(tt.wantFinished) {
+				t.Errorf("FinishActiveLendings() stock = %d, want %d", book.Stock, tt.wantFinished)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
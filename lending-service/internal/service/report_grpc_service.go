@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+	"lending-service/pkg/proto"
+)
+
+const (
+	defaultReportLimit = 10
+	maxReportLimit     = 100
+	// maxReportPeriods is a bit more than 3 years of days
+	maxReportPeriods = 1200
+)
+
+func (s *LendingGRPCService) LoansPerPeriod(ctx context.Context, request *proto.ReportRequest) (*proto.LoansPerPeriodResponse, error) {
+	filter, err := s.reportFilter(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	period := constant.ReportPeriod(request.Period)
+	if period == "" {
+		period = constant.ReportDay
+	}
+	if period != constant.ReportDay && period != constant.ReportWeek && period != constant.ReportMonth {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid report period: %s", request.Period)
+	}
+
+	// every period of the range is reported, the ones without lendings with zero. The first one is the period of the
+	// start of the range, so a range starting within a period still reports it.
+	labels := make([]string, 0)
+	for t := period.Start(filter.From); t.Before(filter.To); t = period.Next(t) {
+		if len(labels) == maxReportPeriods {
+			return nil, status.Errorf(codes.InvalidArgument, "report range must have at most %d periods of a %s", maxReportPeriods, period)
+		}
+		labels = append(labels, period.Label(t))
+	}
+
+	counts, err := s.lendingRepository.LoansPerPeriod(ctx, filter, period)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	countsByPeriod := make(map[string]domain.PeriodCount, len(counts))
+	for _, count := range counts {
+		countsByPeriod[count.Period] = count
+	}
+
+	periods := make([]*proto.PeriodCount, 0, len(labels))
+	for _, label := range labels {
+		count := countsByPeriod[label]
+		periods = append(periods, &proto.PeriodCount{
+			Period:  label,
+			Loans:   int32(count.Loans),
+			Returns: int32(count.Returns),
+		})
+	}
+
+	return &proto.LoansPerPeriodResponse{Periods: periods}, nil
+}
+
+func (s *LendingGRPCService) TopBorrowedBooks(ctx context.Context, request *proto.ReportRequest) (*proto.TopBorrowedBooksResponse, error) {
+	filter, err := s.reportFilter(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := reportLimit(request.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.lendingRepository.TopBorrowedBooks(ctx, filter, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	books := make([]*proto.BookLoanCount, 0, len(counts))
+	for _, count := range counts {
+		books = append(books, &proto.BookLoanCount{BookId: count.BookID.Hex(), Loans: int32(count.Loans)})
+	}
+
+	return &proto.TopBorrowedBooksResponse{Books: books}, nil
+}
+
+// BorrowedBooks returns the IDs of the books lent in the range, the catalog without them are the never borrowed books
+func (s *LendingGRPCService) BorrowedBooks(ctx context.Context, request *proto.ReportRequest) (*proto.BorrowedBooksResponse, error) {
+	filter, err := s.reportFilter(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.lendingRepository.BorrowedBookIDs(ctx, filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	bookIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		bookIDs = append(bookIDs, id.Hex())
+	}
+
+	return &proto.BorrowedBooksResponse{BookIds: bookIDs}, nil
+}
+
+func (s *LendingGRPCService) CirculationSummary(ctx context.Context, request *proto.ReportRequest) (*proto.CirculationSummaryResponse, error) {
+	filter, err := s.reportFilter(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.lendingRepository.CirculationSummary(ctx, filter, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var overdueRate float64
+	if summary.Loans > 0 {
+		overdueRate = float64(summary.Overdue) / float64(summary.Loans)
+	}
+
+	return &proto.CirculationSummaryResponse{
+		Loans:           int32(summary.Loans),
+		Returns:         int32(summary.Returns),
+		AverageLoanDays: summary.AverageLoanHours / 24,
+		Overdue:         int32(summary.Overdue),
+		OverdueRate:     overdueRate,
+		ActiveMembers:   int32(summary.ActiveMembers),
+	}, nil
+}
+
+func (s *LendingGRPCService) ActiveMembers(ctx context.Context, request *proto.ReportRequest) (*proto.ActiveMembersResponse, error) {
+	filter, err := s.reportFilter(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := reportLimit(request.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := s.lendingRepository.ActiveMembers(ctx, filter, time.Now(), limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	members := make([]*proto.MemberActivity, 0, len(activities))
+	for _, activity := range activities {
+		members = append(members, &proto.MemberActivity{
+			UserId:  activity.UserID.Hex(),
+			Loans:   int32(activity.Loans),
+			Active:  int32(activity.Active),
+			Overdue: int32(activity.Overdue),
+		})
+	}
+
+	return &proto.ActiveMembersResponse{Members: members}, nil
+}
+
+// reportFilter checks the range and the branch of the request, an empty branch is every branch
+func (s *LendingGRPCService) reportFilter(ctx context.Context, request *proto.ReportRequest) (domain.ReportFilter, error) {
+	if request.From == nil || request.To == nil {
+		return domain.ReportFilter{}, status.Error(codes.InvalidArgument, "report range is required")
+	}
+
+	filter := domain.ReportFilter{From: request.From.AsTime(), To: request.To.AsTime()}
+	if !filter.From.Before(filter.To) {
+		return domain.ReportFilter{}, status.Error(codes.InvalidArgument, "report range must end after it starts")
+	}
+
+	if request.BranchId != "" {
+		branch, err := s.findBranch(ctx, request.BranchId)
+		if err != nil {
+			return domain.ReportFilter{}, err
+		}
+		branchID, _ := primitive.ObjectIDFromHex(branch.Id)
+		filter.BranchID = &branchID
+	}
+
+	return filter, nil
+}
+
+func reportLimit(limit int32) (int, error) {
+	if limit <= 0 {
+		return defaultReportLimit, nil
+	}
+	if limit > maxReportLimit {
+		return 0, status.Errorf(codes.InvalidArgument, "report limit must be at most %d", maxReportLimit)
+	}
+	return int(limit), nil
+}
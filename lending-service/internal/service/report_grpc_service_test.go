@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"lending-service/internal/domain"
+	"lending-service/internal/domain/constant"
+	"lending-service/pkg/proto"
+)
+
+func reportDate(month time.Month, day int) time.Time {
+	return time.Date(2021, month, day, 10, 0, 0, 0, time.UTC)
+}
+
+// reportTestLendings are lent and returned at known dates of February 2021
+type reportTestLendings struct {
+	bookA, bookB      primitive.ObjectID
+	member1, member2  primitive.ObjectID
+	eastBranch        *proto.Branch
+	defaultBranchID   primitive.ObjectID
+	lendingRepository domain.LendingRepository
+}
+
+func (l *reportTestLendings) add(t *testing.T, lending domain.Lending, lentAt time.Time, returnedAt *time.Time) {
+	t.Helper()
+
+	ctx := context.Background()
+	if lending.BranchID == nil {
+		lending.BranchID = &l.defaultBranchID
+	}
+	if err := l.lendingRepository.Create(ctx, &lending); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	lending.Meta.CreatedAt = lentAt
+	lending.ReturnedAt = returnedAt
+	if err := l.lendingRepository.Update(ctx, &lending); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+}
+
+func newReportTestService(t *testing.T) (*LendingGRPCService, *reportTestLendings) {
+	t.Helper()
+
+	s, bookServiceClient := newMemoryLendingGRPCService()
+	l := &reportTestLendings{
+		bookA:             primitive.NewObjectID(),
+		bookB:             primitive.NewObjectID(),
+		member1:           primitive.NewObjectID(),
+		member2:           primitive.NewObjectID(),
+		eastBranch:        bookServiceClient.addBranch("east"),
+		lendingRepository: s.lendingRepository,
+	}
+	l.defaultBranchID, _ = primitive.ObjectIDFromHex(bookServiceClient.defaultBranch.Id)
+	eastBranchID, _ := primitive.ObjectIDFromHex(l.eastBranch.Id)
+
+	returned := func(month time.Month, day int) *time.Time {
+		returnedAt := reportDate(month, day)
+		return &returnedAt
+	}
+
+	// returned on time
+	l.add(t, domain.Lending{BookID: l.bookA, UserID: l.member1, Status: constant.LendingInactive, ReturnDate: reportDate(2, 15)},
+		reportDate(2, 1), returned(2, 3))
+	// returned late
+	l.add(t, domain.Lending{BookID: l.bookA, UserID: l.member2, Status: constant.LendingInactive, ReturnDate: reportDate(2, 16)},
+		reportDate(2, 2), returned(2, 20))
+	// still lent past its return date
+	l.add(t, domain.Lending{BookID: l.bookB, UserID: l.member1, Status: constant.LendingActive, ReturnDate: reportDate(2, 17)},
+		reportDate(2, 3), nil)
+	// lent at the east branch, not due yet
+	l.add(t, domain.Lending{BookID: l.bookA, UserID: l.member1, Status: constant.LendingActive, ReturnDate: time.Now().AddDate(0, 0, 7), BranchID: &eastBranchID},
+		reportDate(2, 10), nil)
+	// lent before the range, returned in it
+	l.add(t, domain.Lending{BookID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: constant.LendingInactive, ReturnDate: reportDate(2, 3)},
+		reportDate(1, 20), returned(2, 2))
+	// never lent
+	l.add(t, domain.Lending{BookID: primitive.NewObjectID(), UserID: l.member2, Status: constant.LendingCanceled, ReturnDate: reportDate(2, 18)},
+		reportDate(2, 4), nil)
+
+	return s, l
+}
+
+func reportRequest(from, to time.Time) *proto.ReportRequest {
+	return &proto.ReportRequest{From: timestamppb.New(from), To: timestamppb.New(to)}
+}
+
+func TestLendingGRPCService_LoansPerPeriod(t *testing.T) {
+	s, _ := newReportTestService(t)
+	ctx := context.Background()
+
+	february := func(period constant.ReportPeriod, to time.Time) *proto.ReportRequest {
+		request := reportRequest(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), to)
+		request.Period = string(period)
+		return request
+	}
+
+	tests := []struct {
+		name        string
+		request     *proto.ReportRequest
+		wantCode    codes.Code
+		wantPeriods []string
+		wantLoans   []int32
+		wantReturns []int32
+	}{
+		{
+			name:        "by day, with the empty days",
+			request:     february("", time.Date(2021, 2, 5, 0, 0, 0, 0, time.UTC)),
+			wantPeriods: []string{"2021-02-01", "2021-02-02", "2021-02-03", "2021-02-04"},
+			wantLoans:   []int32{1, 1, 1, 0},
+			wantReturns: []int32{0, 1, 1, 0},
+		},
+		{
+			name:        "by ISO week",
+			request:     february(constant.ReportWeek, time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC)),
+			wantPeriods: []string{"2021-W05", "2021-W06"},
+			wantLoans:   []int32{3, 1},
+			wantReturns: []int32{2, 0},
+		},
+		{
+			name:        "by month",
+			request:     february(constant.ReportMonth, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)),
+			wantPeriods: []string{"2021-02"},
+			wantLoans:   []int32{4},
+			wantReturns: []int32{3},
+		},
+		{
+			name:        "unaligned days",
+			request:     &proto.ReportRequest{From: timestamppb.New(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)), To: timestamppb.New(time.Date(2021, 1, 3, 6, 0, 0, 0, time.UTC))},
+			wantPeriods: []string{"2021-01-01", "2021-01-02", "2021-01-03"},
+		},
+		{
+			name:        "unaligned weeks, from a Sunday to the next Tuesday",
+			request:     &proto.ReportRequest{From: timestamppb.New(reportDate(2, 7)), To: timestamppb.New(reportDate(2, 9)), Period: string(constant.ReportWeek)},
+			wantPeriods: []string{"2021-W05", "2021-W06"},
+			wantLoans:   []int32{0, 0},
+			wantReturns: []int32{0, 0},
+		},
+		{
+			name:        "unaligned months",
+			request:     &proto.ReportRequest{From: timestamppb.New(reportDate(1, 31)), To: timestamppb.New(reportDate(3, 1)), Period: string(constant.ReportMonth)},
+			wantPeriods: []string{"2021-01", "2021-02", "2021-03"},
+		},
+		{
+			name:     "too many periods",
+			request:  reportRequest(time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), reportDate(2, 1)),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid period",
+			request:  february("year", time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "range ending before it starts",
+			request:  reportRequest(reportDate(2, 10), reportDate(2, 1)),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "without range",
+			request:  &proto.ReportRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.LoansPerPeriod(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("LoansPerPeriod() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			periods := make([]string, 0, len(got.Periods))
+			for i, count := range got.Periods {
+				periods = append(periods, count.Period)
+				if i < len(tt.wantLoans) && (count.Loans != tt.wantLoans[i] || count.Returns != tt.wantReturns[i]) {
+					t.Errorf("LoansPerPeriod() %s = %d loans, %d returns, want %d, %d",
+						count.Period, count.Loans, count.Returns, tt.wantLoans[i], tt.wantReturns[i])
+				}
+			}
+			if !equalStrings(periods, tt.wantPeriods) {
+				t.Errorf("LoansPerPeriod() periods = %v, want %v", periods, tt.wantPeriods)
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_TopBorrowedBooks(t *testing.T) {
+	s, l := newReportTestService(t)
+	ctx := context.Background()
+
+	february := reportRequest(reportDate(2, 1), reportDate(3, 1))
+	limited := reportRequest(reportDate(2, 1), reportDate(3, 1))
+	limited.Limit = 1
+	east := reportRequest(reportDate(2, 1), reportDate(3, 1))
+	east.BranchId = l.eastBranch.Id
+	unknownBranch := reportRequest(reportDate(2, 1), reportDate(3, 1))
+	unknownBranch.BranchId = primitive.NewObjectID().Hex()
+	overLimit := reportRequest(reportDate(2, 1), reportDate(3, 1))
+	overLimit.Limit = maxReportLimit + 1
+
+	tests := []struct {
+		name      string
+		request   *proto.ReportRequest
+		wantCode  codes.Code
+		wantBooks []string
+		wantLoans []int32
+	}{
+		{
+			name:      "most borrowed first",
+			request:   february,
+			wantBooks: []string{l.bookA.Hex(), l.bookB.Hex()},
+			wantLoans: []int32{3, 1},
+		},
+		{
+			name:      "limited",
+			request:   limited,
+			wantBooks: []string{l.bookA.Hex()},
+			wantLoans: []int32{3},
+		},
+		{
+			name:      "by branch",
+			request:   east,
+			wantBooks: []string{l.bookA.Hex()},
+			wantLoans: []int32{1},
+		},
+		{
+			name:     "unknown branch",
+			request:  unknownBranch,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "over the limit",
+			request:  overLimit,
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.TopBorrowedBooks(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("TopBorrowedBooks() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			books := make([]string, 0, len(got.Books))
+			for i, book := range got.Books {
+				books = append(books, book.BookId)
+				if i < len(tt.wantLoans) && book.Loans != tt.wantLoans[i] {
+					t.Errorf("TopBorrowedBooks() %s = %d loans, want %d", book.BookId, book.Loans, tt.wantLoans[i])
+				}
+			}
+			if !equalStrings(books, tt.wantBooks) {
+				t.Errorf("TopBorrowedBooks() = %v, want %v", books, tt.wantBooks)
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_BorrowedBooks(t *testing.T) {
+	s, l := newReportTestService(t)
+
+	got, err := s.BorrowedBooks(context.Background(), reportRequest(reportDate(2, 1), reportDate(3, 1)))
+	if err != nil {
+		t.Fatalf("BorrowedBooks() error = %v", err)
+	}
+
+	want := []string{l.bookA.Hex(), l.bookB.Hex()}
+	if l.bookB.Hex() < l.bookA.Hex() {
+		want = []string{l.bookB.Hex(), l.bookA.Hex()}
+	}
+	if !equalStrings(got.BookIds, want) {
+		t.Errorf("BorrowedBooks() = %v, want %v", got.BookIds, want)
+	}
+}
+
+func TestLendingGRPCService_CirculationSummary(t *testing.T) {
+	s, l := newReportTestService(t)
+	ctx := context.Background()
+
+	east := reportRequest(reportDate(2, 1), reportDate(3, 1))
+	east.BranchId = l.eastBranch.Id
+
+	tests := []struct {
+		name    string
+		request *proto.ReportRequest
+		want    *proto.CirculationSummaryResponse
+	}{
+		{
+			name:    "every branch",
+			request: reportRequest(reportDate(2, 1), reportDate(3, 1)),
+			// the returns took 2, 18 and 13 days
+			want: &proto.CirculationSummaryResponse{Loans: 4, Returns: 3, AverageLoanDays: 11, Overdue: 2, OverdueRate: 0.5, ActiveMembers: 2},
+		},
+		{
+			name:    "by branch",
+			request: east,
+			want:    &proto.CirculationSummaryResponse{Loans: 1, ActiveMembers: 1},
+		},
+		{
+			name:    "without lendings",
+			request: reportRequest(reportDate(6, 1), reportDate(7, 1)),
+			want:    &proto.CirculationSummaryResponse{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CirculationSummary(ctx, tt.request)
+			if err != nil {
+				t.Fatalf("CirculationSummary() error = %v", err)
+			}
+
+			if got.Loans != tt.want.Loans || got.Returns != tt.want.Returns || got.AverageLoanDays != tt.want.AverageLoanDays ||
+				got.Overdue != tt.want.Overdue || got.OverdueRate != tt.want.OverdueRate || got.ActiveMembers != tt.want.ActiveMembers {
+				t.Errorf("CirculationSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLendingGRPCService_ActiveMembers(t *testing.T) {
+	s, l := newReportTestService(t)
+
+	got, err := s.ActiveMembers(context.Background(), reportRequest(reportDate(2, 1), reportDate(3, 1)))
+	if err != nil {
+		t.Fatalf("ActiveMembers() error = %v", err)
+	}
+
+	want := []*proto.MemberActivity{
+		{UserId: l.member1.Hex(), Loans: 3, Active: 2, Overdue: 1},
+		{UserId: l.member2.Hex(), Loans: 1, Active: 0, Overdue: 1},
+	}
+	if len(got.Members) != len(want) {
+		t.Fatalf("ActiveMembers() = %v, want %v", got.Members, want)
+	}
+	for i, member := range got.Members {
+		if member.UserId != want[i].UserId || member.Loans != want[i].Loans || member.Active != want[i].Active || member.Overdue != want[i].Overdue {
+			t.Errorf("ActiveMembers()[%d] = %+v, want %+v", i, member, want[i])
+		}
+	}
+}
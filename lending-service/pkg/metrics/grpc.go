@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	serverHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Total number of RPCs completed on the server, by method and code.",
+	}, []string{"grpc_method", "grpc_code"})
+	serverHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Latency of the RPCs handled by the server, by method and code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_method", "grpc_code"})
+	serverInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_server_in_flight_requests",
+		Help: "Number of RPCs being handled by the server, by method.",
+	}, []string{"grpc_method"})
+
+	clientHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_handled_total",
+		Help: "Total number of RPCs completed by the client, by method and code.",
+	}, []string{"grpc_method", "grpc_code"})
+	clientHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_handling_seconds",
+		Help:    "Latency of the RPCs until completed by the client, by method and code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_method", "grpc_code"})
+	clientInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_client_in_flight_requests",
+		Help: "Number of RPCs sent by the client and not completed yet, by method.",
+	}, []string{"grpc_method"})
+)
+
+// rpcObserver records a single RPC, finish is safe to call more than once
+type rpcObserver struct {
+	method        string
+	start         time.Time
+	once          sync.Once
+	handled       *prometheus.CounterVec
+	handlingTimes *prometheus.HistogramVec
+	inFlight      *prometheus.GaugeVec
+}
+
+func newServerObserver(method string) *rpcObserver {
+	return newObserver(method, serverHandled, serverHandlingSeconds, serverInFlight)
+}
+
+func newClientObserver(method string) *rpcObserver {
+	return newObserver(method, clientHandled, clientHandlingSeconds, clientInFlight)
+}
+
+func newObserver(method string, handled *prometheus.CounterVec, handlingTimes *prometheus.HistogramVec, inFlight *prometheus.GaugeVec) *rpcObserver {
+	inFlight.WithLabelValues(method).Inc()
+	return &rpcObserver{
+		method:        method,
+		start:         time.Now(),
+		handled:       handled,
+		handlingTimes: handlingTimes,
+		inFlight:      inFlight,
+	}
+}
+
+func (o *rpcObserver) finish(err error) {
+	o.once.Do(func() {
+		code := status.Code(err).String()
+		o.inFlight.WithLabelValues(o.method).Dec()
+		o.handled.WithLabelValues(o.method, code).Inc()
+		o.handlingTimes.WithLabelValues(o.method, code).Observe(time.Since(o.start).Seconds())
+	})
+}
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		observer := newServerObserver(info.FullMethod)
+		resp, err := handler(ctx, req)
+		observer.finish(err)
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		observer := newServerObserver(info.FullMethod)
+		err := handler(srv, stream)
+		observer.finish(err)
+		return err
+	}
+}
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		observer := newClientObserver(method)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observer.finish(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor records a stream when it is completed, that is when the last response is received
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		observer := newClientObserver(method)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			observer.finish(err)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: stream, desc: desc, observer: observer}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	desc     *grpc.StreamDesc
+	observer *rpcObserver
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.observer.finish(nil)
+	case err != nil:
+		s.observer.finish(err)
+	case !s.desc.ServerStreams:
+		// a client streaming RPC has a single response
+		s.observer.finish(nil)
+	}
+	return err
+}
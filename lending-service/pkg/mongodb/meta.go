@@ -5,6 +5,7 @@ import (
 )
 
 type Meta struct {
+	TenantID  string     `json:"tenant_id" bson:"tenant_id"`
 	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at" bson:"deleted_at"`
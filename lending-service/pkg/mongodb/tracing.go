@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "mongodb"
+
+// commandMonitor traces every command as a child span of the operation context, the commands outside a traced
+// operation, like the health checks, are not traced. The command itself is not recorded since it holds the user data.
+func commandMonitor() *event.CommandMonitor {
+	var spans sync.Map
+
+	spanKey := func(connectionID string, requestID int64) string {
+		return fmt.Sprintf("%s/%d", connectionID, requestID)
+	}
+	end := func(connectionID string, requestID int64, err error) {
+		value, ok := spans.LoadAndDelete(spanKey(connectionID, requestID))
+		if !ok {
+			return
+		}
+
+		span := value.(trace.Span)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, startedEvent *event.CommandStartedEvent) {
+			if !trace.SpanContextFromContext(ctx).IsValid() {
+				return
+			}
+
+			attributes := []trace.SpanStartOption{
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongoDB,
+					semconv.DBNameKey.String(startedEvent.DatabaseName),
+					semconv.DBOperationKey.String(startedEvent.CommandName),
+				),
+			}
+			// the first element of a command is its name with the collection as value
+			if element, err := startedEvent.Command.IndexErr(0); err == nil {
+				if collection, ok := element.Value().StringValueOK(); ok {
+					attributes = append(attributes, trace.WithAttributes(semconv.DBMongoDBCollectionKey.String(collection)))
+				}
+			}
+
+			_, span := otel.Tracer(tracerName).Start(ctx, "mongodb."+startedEvent.CommandName, attributes...)
+			spans.Store(spanKey(startedEvent.ConnectionID, startedEvent.RequestID), span)
+		},
+		Succeeded: func(_ context.Context, succeededEvent *event.CommandSucceededEvent) {
+			end(succeededEvent.ConnectionID, succeededEvent.RequestID, nil)
+		},
+		Failed: func(_ context.Context, failedEvent *event.CommandFailedEvent) {
+			end(failedEvent.ConnectionID, failedEvent.RequestID, errors.New(failedEvent.Failure))
+		},
+	}
+}
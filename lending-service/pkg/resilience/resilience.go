@@ -0,0 +1,180 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"lending-service/pkg/logger"
+)
+
+const (
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+// Policy is the resilience of the calls to a method
+type Policy struct {
+	// Timeout of every attempt, unless the caller gives an earlier deadline. A stream has no timeout by default.
+	Timeout time.Duration
+	// Retries of a call failing because the backend is unavailable, only for the idempotent methods
+	Retries int
+}
+
+// Client applies the policies of its methods to the calls sent to a backend, and breaks the circuit to the backend
+// when it fails.
+type Client struct {
+	backend  string
+	policy   Policy
+	policies map[string]Policy
+	breaker  *Breaker
+}
+
+// NewClient applies the given policy to the unary calls of a method without its own policy, the policies are keyed by
+// the full method name like /book.BookService/FindByID.
+func NewClient(backend string, policy Policy, policies map[string]Policy) *Client {
+	return &Client{
+		backend:  backend,
+		policy:   policy,
+		policies: policies,
+		breaker:  NewBreaker(backend, breakerThreshold, breakerCooldown),
+	}
+}
+
+func (c *Client) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		policy, ok := c.policies[method]
+		if !ok {
+			policy = c.policy
+		}
+
+		var err error
+		for attempt := 0; ; attempt++ {
+			if !c.breaker.Allow() {
+				return c.unavailable()
+			}
+
+			err = invokeWithTimeout(ctx, policy.Timeout, func(ctx context.Context) error {
+				return invoker(ctx, method, req, reply, cc, opts...)
+			})
+			c.breaker.Record(err)
+
+			if attempt >= policy.Retries || !isBackendFailure(err) || !sleep(ctx, retryDelay(attempt)) {
+				break
+			}
+		}
+
+		return c.normalize(ctx, method, err)
+	}
+}
+
+func (c *Client) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !c.breaker.Allow() {
+			return nil, c.unavailable()
+		}
+
+		cancel := context.CancelFunc(func() {})
+		if policy := c.policies[method]; policy.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		}
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+			c.breaker.Record(err)
+			return nil, c.normalize(ctx, method, err)
+		}
+
+		return &clientStream{ClientStream: stream, ctx: ctx, client: c, desc: desc, method: method, cancel: cancel}, nil
+	}
+}
+
+func invokeWithTimeout(ctx context.Context, timeout time.Duration, invoke func(ctx context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return invoke(ctx)
+}
+
+// retryDelay is an exponential backoff with full jitter
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// sleep waits for the delay, unless the context is done before
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (c *Client) unavailable() error {
+	return status.Errorf(codes.Unavailable, "%s is temporarily unavailable", c.backend)
+}
+
+// normalize replaces the transport errors, which tell nothing to the user, with a clean message of the same code
+func (c *Client) normalize(ctx context.Context, method string, err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		logger.Ctx(ctx).Warn().Err(err).Str("backend", c.backend).Str("grpc_method", method).Msg("Backend unavailable")
+		return c.unavailable()
+	case codes.DeadlineExceeded:
+		if ctx.Err() == nil || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return status.Errorf(codes.DeadlineExceeded, "%s did not respond in time", c.backend)
+		}
+	}
+	return err
+}
+
+// clientStream records the result of a stream in the breaker when it is completed
+type clientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	client *Client
+	desc   *grpc.StreamDesc
+	method string
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+		return s.client.normalize(s.ctx, s.method, err)
+	case !s.desc.ServerStreams:
+		// a client streaming RPC has a single response
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		s.client.breaker.Record(err)
+		s.cancel()
+	})
+}
@@ -0,0 +1,107 @@
+// Package testserver serves the lending service backed by an in-memory repository, so the tests spanning the services
+// run in one process without MongoDB.
+package testserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"lending-service/internal/event"
+	"lending-service/internal/repository"
+	"lending-service/internal/service"
+	"lending-service/pkg/logger"
+	"lending-service/pkg/mongodb"
+	"lending-service/pkg/proto"
+	"lending-service/pkg/tenant"
+)
+
+// the events are relayed soon after the requests
+const (
+	relayInterval    = 10 * time.Millisecond
+	relayMaxAttempts = 10
+	eventBufferSize  = 100
+)
+
+// Server is the lending service, its repositories live as long as it
+type Server struct {
+	*grpc.Server
+	clientConns []*grpc.ClientConn
+	publisher   *event.ChannelPublisher
+	stopRelay   context.CancelFunc
+	relayDone   chan struct{}
+}
+
+// New returns the lending service calling the user and book services through their dialers. The connections are not
+// blocking, so the services can be created in any order.
+func New(userServiceDialer, bookServiceDialer func(context.Context, string) (net.Conn, error)) (*Server, error) {
+	userGRPCClientConn, err := dial("user-service", userServiceDialer)
+	if err != nil {
+		return nil, err
+	}
+	bookGRPCClientConn, err := dial("book-service", bookServiceDialer)
+	if err != nil {
+		_ = userGRPCClientConn.Close()
+		return nil, err
+	}
+
+	outboxRepository := repository.NewOutboxMemoryRepository()
+	lendingService := service.NewLendingGRPCService(
+		repository.NewLendingMemoryRepository(),
+		outboxRepository,
+		mongodb.NewNoTXRepository(),
+		proto.NewUserServiceClient(userGRPCClientConn),
+		proto.NewBookServiceClient(bookGRPCClientConn),
+	)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(tenant.UnaryServerInterceptor(), logger.UnaryServerInterceptor(), logger.RecoveryUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(tenant.StreamServerInterceptor(), logger.StreamServerInterceptor(), logger.RecoveryStreamServerInterceptor()),
+	)
+	proto.RegisterLendingServiceServer(server, lendingService)
+
+	s := &Server{
+		Server:      server,
+		clientConns: []*grpc.ClientConn{userGRPCClientConn, bookGRPCClientConn},
+		publisher:   event.NewChannelPublisher(eventBufferSize),
+		relayDone:   make(chan struct{}),
+	}
+
+	var relayCtx context.Context
+	relayCtx, s.stopRelay = context.WithCancel(context.Background())
+	go func() {
+		defer close(s.relayDone)
+		event.NewRelay(outboxRepository, s.publisher, relayInterval, relayMaxAttempts).Run(relayCtx)
+	}()
+
+	return s, nil
+}
+
+// Events receives the events published by the service. The events wait in the outbox while the channel is full,
+// so a test not reading them does not block the service.
+func (s *Server) Events() <-chan *proto.Event {
+	return s.publisher.Events()
+}
+
+// Stop stops serving and relaying the events, and closes the connections to the other services
+func (s *Server) Stop() {
+	s.Server.Stop()
+	s.stopRelay()
+	<-s.relayDone
+	for _, clientConn := range s.clientConns {
+		_ = clientConn.Close()
+	}
+}
+
+func dial(target string, dialer func(context.Context, string) (net.Conn, error)) (*grpc.ClientConn, error) {
+	return grpc.Dial(
+		target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer),
+		grpc.WithChainUnaryInterceptor(tenant.UnaryClientInterceptor(), logger.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tenant.StreamClientInterceptor(), logger.StreamClientInterceptor()),
+	)
+}
@@ -0,0 +1,58 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"lending-service/pkg/config"
+)
+
+// ServerOption returns the credentials of the gRPC server: TLS when the certificate is set, and mutual TLS
+// when the client certificates are required too. Without certificate the server accepts plaintext connections.
+func ServerOption(cfg config.TLS) (grpc.ServerOption, error) {
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		if cfg.ClientAuth {
+			return nil, errors.New("TLS_CLIENT_AUTH requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		return grpc.Creds(insecure.NewCredentials()), nil
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE are set together")
+	}
+
+	caFile := ""
+	if cfg.ClientAuth {
+		if cfg.CAFile == "" {
+			return nil, errors.New("TLS_CLIENT_AUTH requires TLS_CA_FILE to verify the client certificates")
+		}
+		caFile = cfg.CAFile
+	}
+
+	r, err := newReloader(cfg.CertFile, cfg.KeyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.Creds(credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// every handshake takes the current files, so a rotation applies to the new connections
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+
+			tlsConfig := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2"},
+			}
+			if cfg.ClientAuth {
+				tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+				tlsConfig.ClientCAs = pool
+			}
+			return tlsConfig, nil
+		},
+	})), nil
+}
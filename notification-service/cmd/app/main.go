@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	_ "notification-service/cmd/migration/script" // registers the migrations checked at startup
+	"notification-service/internal/event"
+	"notification-service/internal/notification"
+	"notification-service/internal/repository"
+	"notification-service/internal/service"
+	"notification-service/internal/webhook"
+	"notification-service/pkg/config"
+	"notification-service/pkg/healthcheck"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/metrics"
+	"notification-service/pkg/migration"
+	"notification-service/pkg/mongodb"
+	"notification-service/pkg/proto"
+	"notification-service/pkg/tenant"
+	"notification-service/pkg/tlsconfig"
+	"notification-service/pkg/tracing"
+)
+
+const serviceName = "notification-service"
+
+func init() {
+	_ = godotenv.Load()
+}
+
+func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file, the environment variables override it")
+	printConfig := flag.Bool("print-config", false, "print the configuration with the secrets redacted, then exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if *printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatal().Err(err).Msg("Error printing configuration")
+		}
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading configuration")
+	}
+	if *printConfig {
+		return
+	}
+
+	logger.Init(serviceName, cfg.Log.Level)
+	mongodb.Configure(cfg.MongoDB.URI, cfg.MongoDB.Database)
+
+	shutdownTracing, err := tracing.Init(context.Background(), serviceName, cfg.Tracing.Exporter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error initializing tracing")
+	}
+
+	pprofServer := new(http.Server)
+	if cfg.PProf.Enable {
+		profileDirPath := cfg.PProf.FolderPath
+		if _, err := os.Stat(profileDirPath); os.IsNotExist(err) {
+			if err := os.Mkdir(profileDirPath, os.ModePerm); err != nil {
+				log.Error().Err(err).Send()
+			}
+		}
+
+		if cpuProfile := cfg.PProf.CPUFileName; cpuProfile != "" {
+			f, err := os.Create(fmt.Sprintf("%s/%s", profileDirPath, cpuProfile))
+			if err != nil {
+				log.Error().Err(err).Send()
+			} else {
+				defer func() {
+					if err := f.Close(); err != nil {
+						log.Error().Err(err).Msg("Error closing cpu profile file")
+					}
+				}()
+
+				_ = pprof.StartCPUProfile(f)
+			}
+		}
+		if memProfile := cfg.PProf.MemoryFileName; memProfile != "" {
+			f, err := os.Create(fmt.Sprintf("%s/%s", profileDirPath, memProfile))
+			if err != nil {
+				log.Error().Err(err).Send()
+			} else {
+				defer func() {
+					if err := f.Close(); err != nil {
+						log.Error().Err(err).Msg("Error closing memory profile file")
+					}
+				}()
+
+				_ = pprof.WriteHeapProfile(f)
+			}
+		}
+
+		pprofServer = &http.Server{
+			Addr:    cfg.PProf.HTTPPort,
+			Handler: nil,
+		}
+
+		go func() {
+			if err := pprofServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error().Err(err).Msg("Error starting pprof server")
+			}
+		}()
+	}
+
+	db := mongodb.GetDatabase()
+
+	pendingMigrations := migration.NewChecker(db, cfg.AllowPendingMigrations)
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 5*time.Second)
+	err = pendingMigrations.Check(checkCtx)
+	cancelCheck()
+	if errors.Is(err, migration.ErrUnreachable) {
+		// the health check is not serving until the migrations are checked
+		log.Warn().Err(err).Msg("Starting before checking the pending migrations")
+	} else if err != nil {
+		log.Fatal().Err(err).Msg("Error starting with pending migrations")
+	}
+
+	metricsServer := &http.Server{
+		Addr:    cfg.MetricsHTTPPort,
+		Handler: metrics.Handler(),
+	}
+
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("Error starting metrics server")
+		}
+	}()
+
+	notificationRepository := repository.NewNotificationMongoDBRepository(db)
+	preferenceRepository := repository.NewPreferenceMongoDBRepository(db)
+	bookRepository := repository.NewBookMongoDBRepository(db)
+	lendingRepository := repository.NewLendingMongoDBRepository(db)
+	webhookRepository := repository.NewWebhookMongoDBRepository(db)
+	webhookDeliveryRepository := repository.NewWebhookDeliveryMongoDBRepository(db)
+
+	senders, closeSenders, err := newSenders(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error creating senders")
+	}
+
+	notifier := notification.NewNotifier(notificationRepository, preferenceRepository)
+	handler := notification.NewHandler(notifier, preferenceRepository, bookRepository, lendingRepository)
+	scheduler := notification.NewScheduler(lendingRepository, bookRepository, notifier, cfg.Scheduler.Interval, cfg.Scheduler.DueSoon)
+	dispatcher := notification.NewDispatcher(notificationRepository, senders, cfg.Dispatcher.Interval, cfg.Dispatcher.MaxAttempts,
+		cfg.Dispatcher.Backoff, cfg.Senders.Timeout)
+	webhookHandler := webhook.NewHandler(webhookRepository, webhookDeliveryRepository)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepository, webhookDeliveryRepository, &http.Client{Timeout: cfg.Webhooks.Timeout},
+		cfg.Webhooks.Interval, cfg.Webhooks.MaxAttempts, cfg.Webhooks.Backoff)
+
+	notificationGRPCService := service.NewNotificationGRPCService(notificationRepository, preferenceRepository)
+	webhookGRPCService := service.NewWebhookGRPCService(webhookRepository, webhookDeliveryRepository, webhookDispatcher)
+
+	tlsServerOption, err := tlsconfig.ServerOption(cfg.TLS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading TLS server certificates")
+	}
+
+	server := grpc.NewServer(
+		tlsServerOption,
+		grpc.ChainUnaryInterceptor(otelgrpc.UnaryServerInterceptor(), metrics.UnaryServerInterceptor(), tenant.UnaryServerInterceptor(), logger.UnaryServerInterceptor(), logger.RecoveryUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(otelgrpc.StreamServerInterceptor(), metrics.StreamServerInterceptor(), tenant.StreamServerInterceptor(), logger.StreamServerInterceptor(), logger.RecoveryStreamServerInterceptor()),
+	)
+	proto.RegisterNotificationServiceServer(server, notificationGRPCService)
+	proto.RegisterWebhookServiceServer(server, webhookGRPCService)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	listener, err := net.Listen("tcp", cfg.GRPCPort)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error listening on gRPC port")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go healthcheck.Watch(ctx, healthServer, func(ctx context.Context) error {
+		if err := mongodb.Ping(ctx); err != nil {
+			return err
+		}
+		return pendingMigrations.Check(ctx)
+	}, "notification.NotificationService", "notification.WebhookService")
+
+	// without a broker no event is received, the scheduler still notifies the lendings already known
+	var subscriber *event.NATSSubscriber
+	if cfg.Events.Broker == "nats" {
+		subscriber, err = event.SubscribeNATS(ctx, cfg.Events.NATSURL, cfg.Events.SubjectPrefix, cfg.Events.Stream,
+			cfg.Events.Durable, event.Handlers(handler.Handle, webhookHandler.Handle))
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error subscribing to NATS")
+		}
+	}
+
+	wg := new(sync.WaitGroup)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		scheduler.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		webhookDispatcher.Run(ctx)
+	}()
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		healthServer.Shutdown()
+		server.GracefulStop()
+		if subscriber != nil {
+			if err := subscriber.Close(); err != nil {
+				log.Error().Err(err).Send()
+			}
+		}
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Send()
+		}
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error().Err(err).Send()
+		}
+		if cfg.PProf.Enable {
+			pprof.StopCPUProfile()
+			if err := pprofServer.Shutdown(ctx); err != nil {
+				log.Error().Err(err).Send()
+			}
+		}
+	}()
+
+	log.Info().Str("port", cfg.GRPCPort).Msg("starting to serve")
+	if err = server.Serve(listener); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	wg.Wait()
+	if err := closeSenders(); err != nil {
+		log.Error().Err(err).Send()
+	}
+	log.Info().Msg("service is gracefully shutdown")
+}
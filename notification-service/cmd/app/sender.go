@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"os"
+
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/sender"
+	"notification-service/pkg/config"
+)
+
+// newSenders returns the sender of every channel, and the closing of the log file
+func newSenders(cfg config.Config) (sender.Router, func() error, error) {
+	closeSenders := func() error { return nil }
+
+	var logSender *sender.LogSender
+	if cfg.Senders.LogFile == "" {
+		logSender = sender.NewLogSender(os.Stdout)
+	} else {
+		var logFile io.Closer
+		var err error
+		logSender, logFile, err = sender.OpenLogSender(cfg.Senders.LogFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		closeSenders = logFile.Close
+	}
+
+	senders := sender.Router{
+		constant.ChannelEmail:   logSender,
+		constant.ChannelSMS:     logSender,
+		constant.ChannelWebhook: logSender,
+	}
+	if cfg.Senders.Email == "smtp" {
+		senders[constant.ChannelEmail] = sender.NewSMTPSender(cfg.SMTP.Addr, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	}
+	if cfg.Senders.Webhook == "http" {
+		senders[constant.ChannelWebhook] = sender.NewWebhookSender(&http.Client{Timeout: cfg.Senders.Timeout})
+	}
+
+	return senders, closeSenders, nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	_ "notification-service/cmd/migration/script" // migration script
+	"notification-service/pkg/config"
+	"notification-service/pkg/migration"
+	"notification-service/pkg/mongodb"
+)
+
+func init() {
+	_ = godotenv.Load()
+}
+
+func main() {
+	cfg, err := config.LoadCommand()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mongodb.Configure(cfg.MongoDB.URI, cfg.MongoDB.Database)
+
+	if err = migration.Run(mongodb.GetDatabase(), os.Args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,53 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.NotificationCollection)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.NotificationCollection).Indexes().
+			CreateMany(context.TODO(), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{"meta.tenant_id", 1}, {"key", 1}, {"channel", 1}},
+					Options: options.Index().SetName(constant.NotificationKeyUniqueIndex).SetUnique(true),
+				},
+				{
+					// the dispatcher reads the pending notifications of every tenant
+					Keys:    bson.D{{"status", 1}, {"next_attempt_at", 1}},
+					Options: options.Index().SetName(constant.NotificationDueIndex),
+				},
+				{
+					Keys:    bson.D{{"meta.tenant_id", 1}, {"user_id", 1}, {"meta.created_at", -1}},
+					Options: options.Index().SetName(constant.NotificationUserIndex),
+				},
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create notification collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.NotificationCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop notification collection")
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.PreferenceCollection)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.PreferenceCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"meta.tenant_id", 1}, {"user_id", 1}},
+				Options: options.Index().SetName(constant.PreferenceUserUniqueIndex).SetUnique(true),
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create preference collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.PreferenceCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop preference collection")
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.BookCollection)
+		if err != nil {
+			return err
+		}
+		_, err = db.Collection(constant.BookCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"meta.tenant_id", 1}, {"book_id", 1}},
+				Options: options.Index().SetName(constant.BookUniqueIndex).SetUnique(true),
+			})
+		if err != nil {
+			return err
+		}
+
+		err = db.CreateCollection(context.TODO(), constant.LendingCollection)
+		if err != nil {
+			return err
+		}
+		_, err = db.Collection(constant.LendingCollection).Indexes().
+			CreateMany(context.TODO(), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{"meta.tenant_id", 1}, {"lending_id", 1}},
+					Options: options.Index().SetName(constant.LendingUniqueIndex).SetUnique(true),
+				},
+				{
+					// the scheduler reads the lendings of every tenant by their return date
+					Keys:    bson.D{{"return_date", 1}},
+					Options: options.Index().SetName(constant.LendingReturnDateIndex),
+				},
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create book and lending collections")
+		return nil
+	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.LendingCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+		err = db.Collection(constant.BookCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop book and lending collections")
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain/constant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		for _, collection := range []string{constant.WebhookCollection, constant.WebhookDeliveryCollection} {
+			if err := db.CreateCollection(context.TODO(), collection); err != nil {
+				return err
+			}
+		}
+
+		_, err := db.Collection(constant.WebhookCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				// the handler reads the active webhooks of the tenant for every event
+				Keys:    bson.D{{"meta.tenant_id", 1}, {"active", 1}, {"event_types", 1}},
+				Options: options.Index().SetName(constant.WebhookTenantIndex),
+			})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.WebhookDeliveryCollection).Indexes().
+			CreateMany(context.TODO(), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{"meta.tenant_id", 1}, {"webhook_id", 1}, {"event_id", 1}},
+					Options: options.Index().SetName(constant.WebhookDeliveryEventUniqueIndex).SetUnique(true),
+				},
+				{
+					// the dispatcher reads the pending deliveries of every tenant
+					Keys:    bson.D{{"status", 1}, {"next_attempt_at", 1}},
+					Options: options.Index().SetName(constant.WebhookDeliveryDueIndex),
+				},
+				{
+					Keys:    bson.D{{"meta.tenant_id", 1}, {"webhook_id", 1}, {"meta.created_at", -1}},
+					Options: options.Index().SetName(constant.WebhookDeliveryWebhookIndex),
+				},
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create webhook and webhook delivery collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		for _, collection := range []string{constant.WebhookDeliveryCollection, constant.WebhookCollection} {
+			if err := db.Collection(collection).Drop(context.TODO()); err != nil {
+				return err
+			}
+		}
+
+		log.Println("success drop webhook and webhook delivery collection")
+		return nil
+	})
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"notification-service/pkg/mongodb"
+)
+
+// Book is a book of the book service, for its title in the notifications
+type Book struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	BookID       string `json:"book_id" bson:"book_id"`
+	Title        string `json:"title" bson:"title"`
+}
+
+type BookRepository interface {
+	// Save creates the book or replaces the one with the same book ID
+	Save(ctx context.Context, book *Book) error
+	FindByBookID(ctx context.Context, bookID string) (Book, error)
+	Delete(ctx context.Context, bookID string) error
+}
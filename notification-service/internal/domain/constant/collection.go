@@ -0,0 +1,28 @@
+package constant
+
+const (
+	NotificationCollection = "notification"
+	PreferenceCollection   = "preference"
+	// BookCollection and LendingCollection are the books and the active lendings known from the events of the
+	// other services, only what the notifications need is kept
+	BookCollection    = "book"
+	LendingCollection = "lending"
+	// WebhookCollection is the webhooks of the admins, WebhookDeliveryCollection the events sent to each of them
+	WebhookCollection         = "webhook"
+	WebhookDeliveryCollection = "webhook_delivery"
+
+	// NotificationKeyUniqueIndex makes a notification of a source sent once to each channel
+	NotificationKeyUniqueIndex = "notification-tenant-key-channel-unique-index"
+	// NotificationDueIndex finds the pending notifications to send
+	NotificationDueIndex      = "notification-due-index"
+	NotificationUserIndex     = "notification-tenant-user-index"
+	PreferenceUserUniqueIndex = "preference-tenant-user-unique-index"
+	BookUniqueIndex           = "book-tenant-book-unique-index"
+	LendingUniqueIndex        = "lending-tenant-lending-unique-index"
+	LendingReturnDateIndex    = "lending-return-date-index"
+	WebhookTenantIndex        = "webhook-tenant-index"
+	// WebhookDeliveryEventUniqueIndex makes an event delivered once to each webhook
+	WebhookDeliveryEventUniqueIndex = "webhook-delivery-tenant-webhook-event-unique-index"
+	WebhookDeliveryDueIndex         = "webhook-delivery-due-index"
+	WebhookDeliveryWebhookIndex     = "webhook-delivery-tenant-webhook-index"
+)
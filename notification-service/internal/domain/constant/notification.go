@@ -0,0 +1,31 @@
+package constant
+
+type NotificationKind string
+
+const (
+	NotificationDueSoon        NotificationKind = "due_soon"
+	NotificationOverdue        NotificationKind = "overdue"
+	NotificationHoldReady      NotificationKind = "hold_ready"
+	NotificationAccountCreated NotificationKind = "account_created"
+	NotificationAccountDeleted NotificationKind = "account_deleted"
+)
+
+type Channel string
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelSMS     Channel = "sms"
+	ChannelWebhook Channel = "webhook"
+)
+
+// Channels are every channel, in the order the notifications are sent
+var Channels = []Channel{ChannelEmail, ChannelSMS, ChannelWebhook}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "PENDING"
+	DeliverySent    DeliveryStatus = "SENT"
+	// DeliveryFailed is a notification out of attempts, it is only sent again on request
+	DeliveryFailed DeliveryStatus = "FAILED"
+)
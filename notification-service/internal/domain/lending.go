@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"notification-service/pkg/mongodb"
+)
+
+// Lending is an active lending of the lending service, removed once it is finished. RemindedAt and OverdueNotifiedAt
+// are when its notifications are created for the current return date, a renewal clears them.
+type Lending struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta      `json:"meta" bson:"meta"`
+	LendingID         string     `json:"lending_id" bson:"lending_id"`
+	UserID            string     `json:"user_id" bson:"user_id"`
+	BookID            string     `json:"book_id" bson:"book_id"`
+	ReturnDate        time.Time  `json:"return_date" bson:"return_date"`
+	RemindedAt        *time.Time `json:"reminded_at" bson:"reminded_at"`
+	OverdueNotifiedAt *time.Time `json:"overdue_notified_at" bson:"overdue_notified_at"`
+}
+
+type LendingRepository interface {
+	// Save creates the lending or replaces the one with the same lending ID
+	Save(ctx context.Context, lending *Lending) error
+	Delete(ctx context.Context, lendingID string) error
+
+	// the scheduler reads the lendings of every tenant, the tenant of a lending is in its meta
+
+	// FetchDueSoon returns the lendings due after now and until, not reminded yet
+	FetchDueSoon(ctx context.Context, now, until time.Time, limit int) ([]Lending, error)
+	// FetchOverdue returns the lendings due at now or before, not notified yet
+	FetchOverdue(ctx context.Context, now time.Time, limit int) ([]Lending, error)
+	MarkReminded(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	MarkOverdueNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/mongodb"
+)
+
+// Notification is a message to a user on one channel, rendered when it is created and sent by the dispatcher
+type Notification struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	UserID       string                    `json:"user_id" bson:"user_id"`
+	Kind         constant.NotificationKind `json:"kind" bson:"kind"`
+	Channel      constant.Channel          `json:"channel" bson:"channel"`
+	// Recipient is the address of the channel, an email, a phone number, or a webhook URL
+	Recipient string `json:"recipient" bson:"recipient"`
+	Subject   string `json:"subject" bson:"subject"`
+	Body      string `json:"body" bson:"body"`
+	// Key is the source of the notification, like an event ID, so it is created once even when the source is
+	// received again
+	Key           string                  `json:"key" bson:"key"`
+	Status        constant.DeliveryStatus `json:"status" bson:"status"`
+	Attempts      int                     `json:"attempts" bson:"attempts"`
+	LastError     string                  `json:"last_error" bson:"last_error"`
+	NextAttemptAt time.Time               `json:"next_attempt_at" bson:"next_attempt_at"`
+	SentAt        *time.Time              `json:"sent_at" bson:"sent_at"`
+}
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *Notification) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]Notification, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FindByID(ctx context.Context, id string) (Notification, error)
+	Update(ctx context.Context, notification *Notification) error
+	// FetchDue is not scoped to a tenant, it returns the pending notifications of every tenant to send at now,
+	// the longest waiting first
+	FetchDue(ctx context.Context, now time.Time, limit int) ([]Notification, error)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/mongodb"
+)
+
+// Preference is created with the email channel when the user registers, the email comes from the user service
+type Preference struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	UserID       string             `json:"user_id" bson:"user_id"`
+	Email        string             `json:"email" bson:"email"`
+	Phone        string             `json:"phone" bson:"phone"`
+	WebhookURL   string             `json:"webhook_url" bson:"webhook_url"`
+	Channels     []constant.Channel `json:"channels" bson:"channels"`
+}
+
+// Recipient returns the address of the channel, empty when the user has none
+func (p Preference) Recipient(channel constant.Channel) string {
+	switch channel {
+	case constant.ChannelEmail:
+		return p.Email
+	case constant.ChannelSMS:
+		return p.Phone
+	case constant.ChannelWebhook:
+		return p.WebhookURL
+	}
+	return ""
+}
+
+// Enabled tells whether the user receives the notifications on the channel
+func (p Preference) Enabled(channel constant.Channel) bool {
+	for _, enabled := range p.Channels {
+		if enabled == channel {
+			return true
+		}
+	}
+	return false
+}
+
+type PreferenceRepository interface {
+	// Create keeps the existing preference of the user, so a user registered event received again changes nothing
+	Create(ctx context.Context, preference *Preference) error
+	FindByUserID(ctx context.Context, userID string) (Preference, error)
+	Update(ctx context.Context, preference *Preference) error
+	Delete(ctx context.Context, userID string) error
+}
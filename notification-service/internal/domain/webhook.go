@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/mongodb"
+)
+
+// Webhook is an endpoint of an admin receiving the events of its types, signed with its secret
+type Webhook struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	URL          string `json:"url" bson:"url"`
+	// Secret signs the payloads with HMAC-SHA256, it is only shown when it is created or rotated
+	Secret     string   `json:"-" bson:"secret"`
+	EventTypes []string `json:"event_types" bson:"event_types"`
+	// Active is false for a paused webhook, it receives no event until it is active again
+	Active bool `json:"active" bson:"active"`
+}
+
+// Subscribed tells whether the webhook receives the events of the type
+func (w Webhook) Subscribed(eventType string) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]Webhook, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FindByID(ctx context.Context, id string) (Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, webhook *Webhook) error
+	// FetchSubscribed returns the active webhooks of the tenant receiving the events of the type
+	FetchSubscribed(ctx context.Context, eventType string) ([]Webhook, error)
+}
+
+// WebhookDelivery is an event to send to a webhook. A delivery out of attempts is failed, the failed deliveries are
+// the dead letters and are only sent again on request.
+type WebhookDelivery struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	WebhookID    string `json:"webhook_id" bson:"webhook_id"`
+	EventID      string `json:"event_id" bson:"event_id"`
+	EventType    string `json:"event_type" bson:"event_type"`
+	// Payload is the JSON body, kept as sent so its signature can be checked again
+	Payload string                  `json:"payload" bson:"payload"`
+	Status  constant.DeliveryStatus `json:"status" bson:"status"`
+	// Attempts are counted from the last time the delivery was queued, Log keeps every attempt
+	Attempts      int              `json:"attempts" bson:"attempts"`
+	Log           []WebhookAttempt `json:"log" bson:"log"`
+	LastError     string           `json:"last_error" bson:"last_error"`
+	NextAttemptAt time.Time        `json:"next_attempt_at" bson:"next_attempt_at"`
+	DeliveredAt   *time.Time       `json:"delivered_at" bson:"delivered_at"`
+}
+
+// WebhookAttempt is one request of a delivery, StatusCode is 0 when no response was received
+type WebhookAttempt struct {
+	AttemptedAt time.Time     `json:"attempted_at" bson:"attempted_at"`
+	StatusCode  int           `json:"status_code" bson:"status_code"`
+	Error       string        `json:"error" bson:"error"`
+	Duration    time.Duration `json:"duration" bson:"duration"`
+}
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]WebhookDelivery, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FindByID(ctx context.Context, id string) (WebhookDelivery, error)
+	Update(ctx context.Context, delivery *WebhookDelivery) error
+	// FetchDue is not scoped to a tenant, it returns the pending deliveries of every tenant to send at now,
+	// the longest waiting first
+	FetchDue(ctx context.Context, now time.Time, limit int) ([]WebhookDelivery, error)
+}
@@ -0,0 +1,72 @@
+// Package event receives the domain events of the other services. The events are delivered at least once, so every
+// handler is idempotent.
+package event
+
+import (
+	"context"
+
+	"notification-service/pkg/logger"
+	"notification-service/pkg/proto"
+	"notification-service/pkg/tenant"
+)
+
+const (
+	UserRegistered   = "user.registered"
+	UserDeleted      = "user.deleted"
+	BookCreated      = "book.created"
+	BookDeleted      = "book.deleted"
+	BookStockChanged = "book.stock_changed"
+	LendingCreated   = "lending.created"
+	LendingRenewed   = "lending.renewed"
+	LendingFinished  = "lending.finished"
+)
+
+// Types are the types of every event the other services publish
+var Types = []string{
+	UserRegistered, UserDeleted, BookCreated, BookDeleted, BookStockChanged, LendingCreated, LendingRenewed,
+	LendingFinished,
+}
+
+// SupportedVersion is the version of the payloads the handlers read, a later version is skipped
+const SupportedVersion = 1
+
+// Handler handles an event, the event is delivered again when it returns an error
+type Handler func(ctx context.Context, event *proto.Event) error
+
+// Handlers calls every handler with the event, in order. A handler returning an error stops the others, so the
+// event is delivered again to all of them.
+func Handlers(handlers ...Handler) Handler {
+	return func(ctx context.Context, event *proto.Event) error {
+		for _, handler := range handlers {
+			if err := handler(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// handle calls the handler in the tenant and the request of the event
+func handle(ctx context.Context, handler Handler, event *proto.Event) error {
+	ctx = tenant.NewContext(ctx, event.TenantId)
+	ctx = logger.NewRequestIDContext(ctx, event.RequestId)
+
+	err := handler(ctx, event)
+	if err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("event_id", event.Id).Str("event_type", event.Type).Msg("Error handling event")
+	}
+	return err
+}
+
+// Consume handles the events of the channel until the context is done, for the tests and the publishers linked in
+// the same binary. An event failing to be handled is only logged.
+func Consume(ctx context.Context, events <-chan *proto.Event, handler Handler) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			_ = handle(ctx, handler, event)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package event
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"notification-service/pkg/logger"
+	"notification-service/pkg/proto"
+)
+
+// Source is the name of the connection to NATS
+const Source = "notification-service"
+
+// NATSSubscriber receives the events from a durable JetStream consumer, an event is acknowledged once it is handled
+// and delivered again otherwise
+type NATSSubscriber struct {
+	conn *nats.Conn
+}
+
+// SubscribeNATS connects to the comma separated NATS URLs and handles the events published on <prefix>.>. The
+// stream over the subject is created when it does not exist, so it keeps the events published while the service is
+// down.
+func SubscribeNATS(ctx context.Context, url, subjectPrefix, stream, durable string, handler Handler) (*NATSSubscriber, error) {
+	conn, err := nats.Connect(url, nats.Name(Source), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = subscribe(ctx, conn, subjectPrefix, stream, durable, handler); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NATSSubscriber{conn: conn}, nil
+}
+
+func subscribe(ctx context.Context, conn *nats.Conn, subjectPrefix, stream, durable string, handler Handler) error {
+	js, err := conn.JetStream()
+	if err != nil {
+		return err
+	}
+
+	subject := subjectPrefix + ".>"
+	if _, err = js.StreamInfo(stream); err != nil {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     stream,
+			Subjects: []string{subject},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = js.Subscribe(subject, func(msg *nats.Msg) {
+		var event proto.Event
+		if err := protobuf.Unmarshal(msg.Data, &event); err != nil {
+			// an event never read is acknowledged, it would be delivered forever otherwise
+			logger.Ctx(ctx).Error().Err(err).Str("subject", msg.Subject).Msg("Error reading event")
+			_ = msg.Ack()
+			return
+		}
+
+		if err := handle(ctx, handler, &event); err != nil {
+			_ = msg.Nak()
+			return
+		}
+		_ = msg.Ack()
+	}, nats.Durable(durable), nats.ManualAck(), nats.DeliverAll())
+	return err
+}
+
+// Close handles the events already received then closes the connection, the durable consumer keeps the next ones
+// for the next start
+func (s *NATSSubscriber) Close() error {
+	return s.conn.Drain()
+}
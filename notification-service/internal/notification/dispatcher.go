@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/sender"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/tenant"
+)
+
+const (
+	// dispatchBatchSize is the number of notifications read at once
+	dispatchBatchSize = 100
+	// maxBackoff caps the doubled backoff, so many attempts neither overflow it nor retry right away
+	maxBackoff = 24 * time.Hour
+)
+
+// Dispatcher sends the pending notifications. A failed delivery is retried after a backoff doubled on every attempt
+// up to maxBackoff, the notification is failed once it is out of attempts.
+type Dispatcher struct {
+	notificationRepository domain.NotificationRepository
+	sender                 sender.Sender
+	interval               time.Duration
+	maxAttempts            int
+	backoff                time.Duration
+	timeout                time.Duration
+}
+
+func NewDispatcher(notificationRepository domain.NotificationRepository, sender sender.Sender, interval time.Duration, maxAttempts int, backoff, timeout time.Duration) *Dispatcher {
+	return &Dispatcher{
+		notificationRepository: notificationRepository,
+		sender:                 sender,
+		interval:               interval,
+		maxAttempts:            maxAttempts,
+		backoff:                backoff,
+		timeout:                timeout,
+	}
+}
+
+// Run dispatches the due notifications at every interval until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Ctx(ctx).Warn().Err(err).Msg("Error dispatching notifications")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends the notifications due at now and returns the number of sent notifications. Every sent or
+// retried notification leaves the due ones, so it runs until none is due.
+func (d *Dispatcher) DispatchDue(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	for {
+		notifications, err := d.notificationRepository.FetchDue(ctx, now, dispatchBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for i := range notifications {
+			ok, err := d.dispatch(ctx, &notifications[i], now)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if len(notifications) < dispatchBatchSize || ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+	}
+}
+
+// dispatch sends the notification and stores the result of the attempt, it tells whether the notification is sent
+func (d *Dispatcher) dispatch(ctx context.Context, notification *domain.Notification, now time.Time) (bool, error) {
+	ctx = tenant.NewContext(ctx, notification.Meta.TenantID)
+
+	sendCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	err := d.sender.Send(sendCtx, sender.Message{
+		NotificationID: notification.ID.Hex(),
+		TenantID:       notification.Meta.TenantID,
+		UserID:         notification.UserID,
+		Kind:           string(notification.Kind),
+		Channel:        notification.Channel,
+		Recipient:      notification.Recipient,
+		Subject:        notification.Subject,
+		Body:           notification.Body,
+	})
+	cancel()
+
+	notification.Attempts++
+	switch {
+	case err == nil:
+		sentAt := time.Now()
+		notification.Status = constant.DeliverySent
+		notification.SentAt = &sentAt
+		notification.LastError = ""
+	case notification.Attempts >= d.maxAttempts:
+		notification.Status = constant.DeliveryFailed
+		notification.LastError = err.Error()
+	default:
+		notification.LastError = err.Error()
+		notification.NextAttemptAt = now.Add(retryDelay(d.backoff, notification.Attempts))
+	}
+
+	if err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("notification_id", notification.ID.Hex()).Int("attempts", notification.Attempts).
+			Str("status", string(notification.Status)).Msg("Error sending notification")
+	}
+
+	return err == nil, d.notificationRepository.Update(ctx, notification)
+}
+
+// Retry makes a failed notification pending again with all its attempts, it is sent on the next dispatch
+func Retry(notification *domain.Notification, now time.Time) {
+	notification.Status = constant.DeliveryPending
+	notification.Attempts = 0
+	notification.NextAttemptAt = now
+}
+
+// retryDelay is the backoff doubled for every attempt after the first one, up to maxBackoff
+func retryDelay(backoff time.Duration, attempts int) time.Duration {
+	delay := backoff
+	for i := 1; i < attempts && delay > 0 && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
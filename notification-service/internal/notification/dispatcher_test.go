@@ -0,0 +1,150 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/repository"
+	"notification-service/internal/sender"
+	"notification-service/pkg/tenant"
+)
+
+type failingSender struct {
+	failures int
+	sent     []sender.Message
+}
+
+func (s *failingSender) Send(_ context.Context, message sender.Message) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("connection refused")
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		wantStatus   constant.DeliveryStatus
+		wantAttempts int
+		wantSent     int
+	}{
+		{name: "sent at first attempt", failures: 0, wantStatus: constant.DeliverySent, wantAttempts: 1, wantSent: 1},
+		{name: "sent after retries", failures: 2, wantStatus: constant.DeliverySent, wantAttempts: 3, wantSent: 1},
+		{name: "failed out of attempts", failures: 3, wantStatus: constant.DeliveryFailed, wantAttempts: 3, wantSent: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepository := repository.NewNotificationMemoryRepository()
+			ctx := tenant.NewContext(context.Background(), "tenant-1")
+			now := time.Now()
+
+			created := domain.Notification{
+				UserID:        "user-1",
+				Kind:          constant.NotificationOverdue,
+				Channel:       constant.ChannelEmail,
+				Recipient:     "member@example.com",
+				Subject:       "Dune is overdue",
+				Key:           "overdue:lending-1",
+				Status:        constant.DeliveryPending,
+				NextAttemptAt: now,
+			}
+			if err := notificationRepository.Create(ctx, &created); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			s := &failingSender{failures: tt.failures}
+			dispatcher := NewDispatcher(notificationRepository, s, time.Second, 3, time.Minute, time.Second)
+
+			// every attempt is dispatched once its backoff is over, the dispatcher runs from another context
+			at := now
+			for attempt := 0; attempt < 5; attempt++ {
+				if _, err := dispatcher.DispatchDue(context.Background(), at); err != nil {
+					t.Fatalf("DispatchDue() error = %v", err)
+				}
+				at = at.Add(time.Hour)
+			}
+
+			n, err := notificationRepository.FindByID(ctx, created.ID.Hex())
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if n.Status != tt.wantStatus || n.Attempts != tt.wantAttempts {
+				t.Errorf("notification = %s after %d attempts, want %s after %d", n.Status, n.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			if (n.Status == constant.DeliverySent) != (n.SentAt != nil) || (n.Status == constant.DeliveryFailed) != (n.LastError != "") {
+				t.Errorf("notification sent at %v with last error %q, want them to match the %s status", n.SentAt, n.LastError, n.Status)
+			}
+			if len(s.sent) != tt.wantSent {
+				t.Fatalf("sent %d messages, want %d", len(s.sent), tt.wantSent)
+			}
+			if tt.wantSent > 0 && (s.sent[0].TenantID != "tenant-1" || s.sent[0].NotificationID != created.ID.Hex()) {
+				t.Errorf("message = %v, want the notification of tenant-1", s.sent[0])
+			}
+		})
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	notificationRepository := repository.NewNotificationMemoryRepository()
+	ctx := context.Background()
+	// the times are stored in milliseconds, like in MongoDB
+	now := time.Now().Truncate(time.Millisecond)
+
+	created := domain.Notification{Channel: constant.ChannelSMS, Key: "key", Status: constant.DeliveryPending, NextAttemptAt: now}
+	if err := notificationRepository.Create(ctx, &created); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	dispatcher := NewDispatcher(notificationRepository, &failingSender{failures: 5}, time.Second, 5, time.Minute, time.Second)
+	steps := []struct {
+		at           time.Time
+		wantAttempts int
+		wantNext     time.Time
+	}{
+		{at: now, wantAttempts: 1, wantNext: now.Add(time.Minute)},
+		{at: now.Add(30 * time.Second), wantAttempts: 1, wantNext: now.Add(time.Minute)},
+		{at: now.Add(time.Minute), wantAttempts: 2, wantNext: now.Add(3 * time.Minute)},
+		{at: now.Add(3 * time.Minute), wantAttempts: 3, wantNext: now.Add(7 * time.Minute)},
+	}
+	for _, step := range steps {
+		if _, err := dispatcher.DispatchDue(ctx, step.at); err != nil {
+			t.Fatalf("DispatchDue() error = %v", err)
+		}
+
+		n, _ := notificationRepository.FindByID(ctx, created.ID.Hex())
+		if n.Attempts != step.wantAttempts || !n.NextAttemptAt.Equal(step.wantNext) {
+			t.Errorf("at %v: %d attempts, next at %v, want %d attempts, next at %v", step.at.Sub(now), n.Attempts,
+				n.NextAttemptAt.Sub(now), step.wantAttempts, step.wantNext.Sub(now))
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		backoff  time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first attempt", backoff: 30 * time.Second, attempts: 1, want: 30 * time.Second},
+		{name: "doubled", backoff: 30 * time.Second, attempts: 4, want: 4 * time.Minute},
+		{name: "capped", backoff: 30 * time.Second, attempts: 20, want: maxBackoff},
+		{name: "capped past the bits of a duration", backoff: 30 * time.Second, attempts: 100, want: maxBackoff},
+		{name: "backoff over the cap", backoff: 48 * time.Hour, attempts: 1, want: maxBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.backoff, tt.attempts); got != tt.want {
+				t.Errorf("retryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
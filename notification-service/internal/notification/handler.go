@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"context"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/event"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/proto"
+)
+
+// Handler keeps the users, the books and the active lendings of the events, and notifies the users of the changes
+// of their account
+type Handler struct {
+	notifier             *Notifier
+	preferenceRepository domain.PreferenceRepository
+	bookRepository       domain.BookRepository
+	lendingRepository    domain.LendingRepository
+}
+
+func NewHandler(notifier *Notifier, preferenceRepository domain.PreferenceRepository, bookRepository domain.BookRepository, lendingRepository domain.LendingRepository) *Handler {
+	return &Handler{
+		notifier:             notifier,
+		preferenceRepository: preferenceRepository,
+		bookRepository:       bookRepository,
+		lendingRepository:    lendingRepository,
+	}
+}
+
+// Handle is an event.Handler, the events of the other types are skipped
+func (h *Handler) Handle(ctx context.Context, e *proto.Event) error {
+	if e.Version > event.SupportedVersion {
+		logger.Ctx(ctx).Warn().Str("event_id", e.Id).Str("event_type", e.Type).Int32("version", e.Version).
+			Msg("Skipping event of unsupported version")
+		return nil
+	}
+
+	switch e.Type {
+	case event.UserRegistered:
+		var payload proto.UserRegistered
+		if err := e.Payload.UnmarshalTo(&payload); err != nil {
+			return err
+		}
+		return h.userRegistered(ctx, e.Id, &payload)
+	case event.UserDeleted:
+		var payload proto.UserDeleted
+		if err := e.Payload.UnmarshalTo(&payload); err != nil {
+			return err
+		}
+		return h.userDeleted(ctx, e.Id, &payload)
+	case event.BookCreated:
+		var payload proto.BookCreated
+		if err := e.Payload.UnmarshalTo(&payload); err != nil {
+			return err
+		}
+		return h.bookRepository.Save(ctx, &domain.Book{BookID: payload.BookId, Title: payload.Title})
+	case event.BookDeleted:
+		var payload proto.BookDeleted
+		if err := e.Payload.UnmarshalTo(&payload); err != nil {
+			return err
+		}
+		return h.bookRepository.Delete(ctx, payload.BookId)
+	case event.LendingCreated:
+		var payload proto.LendingCreated
+		if err := e.Payload.UnmarshalTo(&payload); err != nil {
+			return err
+		}
+		return h.lendingRepository.Save(ctx, &domain.Lending{
+			LendingID:  payload.LendingId,
+			UserID:     payload.UserId,
+			BookID:     payload.BookId,
+			ReturnDate: payload.ReturnDate.AsTime(),
+		})
+	case event.LendingRenewed:
+		var payload proto.LendingRenewed
+		if err := e.Payload.UnmarshalTo(&payload); err != nil {
+			return err
+		}
+		// the notifications of the previous return date are cleared by the replacement
+		return h.lendingRepository.Save(ctx, &domain.Lending{
+			LendingID:  payload.LendingId,
+			UserID:     payload.UserId,
+			BookID:     payload.BookId,
+			ReturnDate: payload.ReturnDate.AsTime(),
+		})
+	case event.LendingFinished:
+		var payload proto.LendingFinished
+		if err := e.Payload.UnmarshalTo(&payload); err != nil {
+			return err
+		}
+		return h.lendingRepository.Delete(ctx, payload.LendingId)
+	}
+
+	return nil
+}
+
+// userRegistered creates the preference of the user with the email channel, then welcomes them
+func (h *Handler) userRegistered(ctx context.Context, eventID string, payload *proto.UserRegistered) error {
+	err := h.preferenceRepository.Create(ctx, &domain.Preference{
+		UserID:   payload.UserId,
+		Email:    payload.Email,
+		Channels: []constant.Channel{constant.ChannelEmail},
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.notifier.Notify(ctx, payload.UserId, constant.NotificationAccountCreated, eventID, Data{Email: payload.Email})
+}
+
+// userDeleted notifies the user a last time, then forgets their preference. The notification is created first, so
+// an event delivered again after a failure still finds the preference.
+func (h *Handler) userDeleted(ctx context.Context, eventID string, payload *proto.UserDeleted) error {
+	err := h.notifier.Notify(ctx, payload.UserId, constant.NotificationAccountDeleted, eventID, Data{Email: payload.Email})
+	if err != nil {
+		return err
+	}
+
+	return h.preferenceRepository.Delete(ctx, payload.UserId)
+}
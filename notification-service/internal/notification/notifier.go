@@ -0,0 +1,76 @@
+// Package notification creates the notifications of the users and delivers them. The notifications are created
+// from the domain events and from the return dates of the lendings, then the dispatcher sends them with retries.
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/logger"
+)
+
+// Notifier creates the notifications of a user on the channels of their preference
+type Notifier struct {
+	notificationRepository domain.NotificationRepository
+	preferenceRepository   domain.PreferenceRepository
+}
+
+func NewNotifier(notificationRepository domain.NotificationRepository, preferenceRepository domain.PreferenceRepository) *Notifier {
+	return &Notifier{
+		notificationRepository: notificationRepository,
+		preferenceRepository:   preferenceRepository,
+	}
+}
+
+// Notify renders the notification and creates it, pending, on every enabled channel the user has an address for.
+// The key is the source of the notification, a notification already created for the key on a channel is kept, so
+// a source handled again notifies the user once. A user without a preference is not notified.
+func (n *Notifier) Notify(ctx context.Context, userID string, kind constant.NotificationKind, key string, data Data) error {
+	preference, err := n.preferenceRepository.FindByUserID(ctx, userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Ctx(ctx).Debug().Str("user_id", userID).Str("kind", string(kind)).Msg("Skipping notification of unknown user")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if data.Email == "" {
+		data.Email = preference.Email
+	}
+
+	subject, body, err := Render(kind, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, channel := range constant.Channels {
+		recipient := preference.Recipient(channel)
+		if !preference.Enabled(channel) || recipient == "" {
+			continue
+		}
+
+		err = n.notificationRepository.Create(ctx, &domain.Notification{
+			UserID:        userID,
+			Kind:          kind,
+			Channel:       channel,
+			Recipient:     recipient,
+			Subject:       subject,
+			Body:          body,
+			Key:           key,
+			Status:        constant.DeliveryPending,
+			NextAttemptAt: now,
+		})
+		if err != nil && !strings.Contains(err.Error(), constant.NotificationKeyUniqueIndex) {
+			return err
+		}
+	}
+
+	return nil
+}
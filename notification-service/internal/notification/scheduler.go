@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/tenant"
+)
+
+// scheduleBatchSize is the number of lendings read at once
+const scheduleBatchSize = 100
+
+// unknownBookTitle names a book lent before the service received its book.created event
+const unknownBookTitle = "Your book"
+
+// Scheduler reminds the users of the lendings due soon, then notifies them once they are overdue. Each is sent once
+// for a return date, a renewal sends them again for the new return date.
+type Scheduler struct {
+	lendingRepository domain.LendingRepository
+	bookRepository    domain.BookRepository
+	notifier          *Notifier
+	interval          time.Duration
+	dueSoon           time.Duration
+}
+
+func NewScheduler(lendingRepository domain.LendingRepository, bookRepository domain.BookRepository, notifier *Notifier, interval, dueSoon time.Duration) *Scheduler {
+	return &Scheduler{
+		lendingRepository: lendingRepository,
+		bookRepository:    bookRepository,
+		notifier:          notifier,
+		interval:          interval,
+		dueSoon:           dueSoon,
+	}
+}
+
+// Run schedules the notifications at every interval until the context is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Schedule(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Ctx(ctx).Warn().Err(err).Msg("Error scheduling notifications")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Schedule creates the notifications of the lendings due soon and overdue at now, and returns the number of
+// notified lendings. A lending failing to be notified is logged and tried again on the next run.
+func (s *Scheduler) Schedule(ctx context.Context, now time.Time) (int, error) {
+	dueSoon, err := s.lendingRepository.FetchDueSoon(ctx, now, now.Add(s.dueSoon), scheduleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	notified := s.notify(ctx, dueSoon, constant.NotificationDueSoon, now, s.lendingRepository.MarkReminded)
+
+	overdue, err := s.lendingRepository.FetchOverdue(ctx, now, scheduleBatchSize)
+	if err != nil {
+		return notified, err
+	}
+	notified += s.notify(ctx, overdue, constant.NotificationOverdue, now, s.lendingRepository.MarkOverdueNotified)
+
+	return notified, nil
+}
+
+type markFunc func(ctx context.Context, id primitive.ObjectID, at time.Time) error
+
+func (s *Scheduler) notify(ctx context.Context, lendings []domain.Lending, kind constant.NotificationKind, now time.Time, mark markFunc) int {
+	notified := 0
+	for _, lending := range lendings {
+		lendingCtx := tenant.NewContext(ctx, lending.Meta.TenantID)
+
+		// the key changes with the return date, so a renewed lending is notified again
+		key := fmt.Sprintf("%s:%s:%d", kind, lending.LendingID, lending.ReturnDate.Unix())
+		err := s.notifier.Notify(lendingCtx, lending.UserID, kind, key, Data{
+			BookTitle:  s.bookTitle(lendingCtx, lending.BookID),
+			ReturnDate: lending.ReturnDate,
+		})
+		if err == nil {
+			err = mark(lendingCtx, lending.ID, now)
+		}
+		if err != nil {
+			logger.Ctx(lendingCtx).Error().Err(err).Str("lending_id", lending.LendingID).Str("kind", string(kind)).
+				Msg("Error notifying lending")
+			continue
+		}
+		notified++
+	}
+
+	return notified
+}
+
+func (s *Scheduler) bookTitle(ctx context.Context, bookID string) string {
+	book, err := s.bookRepository.FindByBookID(ctx, bookID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Ctx(ctx).Warn().Err(err).Str("book_id", bookID).Msg("Error finding book title")
+		}
+		return unknownBookTitle
+	}
+	return book.Title
+}
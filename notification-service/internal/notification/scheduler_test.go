@@ -0,0 +1,174 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/event"
+	"notification-service/internal/repository"
+	"notification-service/pkg/proto"
+	"notification-service/pkg/tenant"
+)
+
+type fixture struct {
+	notificationRepository domain.NotificationRepository
+	handler                *Handler
+	scheduler              *Scheduler
+}
+
+func newFixture() *fixture {
+	notificationRepository := repository.NewNotificationMemoryRepository()
+	preferenceRepository := repository.NewPreferenceMemoryRepository()
+	bookRepository := repository.NewBookMemoryRepository()
+	lendingRepository := repository.NewLendingMemoryRepository()
+	notifier := NewNotifier(notificationRepository, preferenceRepository)
+
+	return &fixture{
+		notificationRepository: notificationRepository,
+		handler:                NewHandler(notifier, preferenceRepository, bookRepository, lendingRepository),
+		scheduler:              NewScheduler(lendingRepository, bookRepository, notifier, time.Second, 3*24*time.Hour),
+	}
+}
+
+func (f *fixture) handle(t *testing.T, ctx context.Context, id, eventType string, payload protobuf.Message) {
+	t.Helper()
+
+	anyPayload, err := anypb.New(payload)
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	e := &proto.Event{Id: id, Type: eventType, Version: 1, TenantId: tenant.FromContext(ctx), Payload: anyPayload}
+	if err = f.handler.Handle(ctx, e); err != nil {
+		t.Fatalf("Handle(%s) error = %v", eventType, err)
+	}
+}
+
+func (f *fixture) kinds(t *testing.T, ctx context.Context) []constant.NotificationKind {
+	t.Helper()
+
+	notifications, err := f.notificationRepository.Fetch(ctx, map[string]interface{}{"limit": int32(100)})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	kinds := make([]constant.NotificationKind, 0)
+	for i := len(notifications) - 1; i >= 0; i-- {
+		kinds = append(kinds, notifications[i].Kind)
+	}
+	return kinds
+}
+
+func TestHandler_UserRegistered(t *testing.T) {
+	f := newFixture()
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+
+	registered := &proto.UserRegistered{UserId: "user-1", Email: "member@example.com"}
+	f.handle(t, ctx, "event-1", event.UserRegistered, registered)
+	// the event is delivered again
+	f.handle(t, ctx, "event-1", event.UserRegistered, registered)
+
+	notifications, err := f.notificationRepository.Fetch(ctx, map[string]interface{}{"user_id": "user-1"})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("got %d notifications, want the account_created one", len(notifications))
+	}
+	n := notifications[0]
+	if n.Kind != constant.NotificationAccountCreated || n.Channel != constant.ChannelEmail || n.Recipient != "member@example.com" ||
+		n.Status != constant.DeliveryPending || n.Subject != "Welcome to the library" {
+		t.Errorf("notification = %+v, want a pending welcome email", n)
+	}
+
+	f.handle(t, ctx, "event-2", event.UserDeleted, &proto.UserDeleted{UserId: "user-1", Email: "member@example.com"})
+	f.handle(t, ctx, "event-3", event.UserRegistered, &proto.UserRegistered{UserId: "user-2"})
+
+	got := f.kinds(t, ctx)
+	want := []constant.NotificationKind{constant.NotificationAccountCreated, constant.NotificationAccountDeleted}
+	if !equalKinds(got, want) {
+		t.Errorf("kinds = %v, want %v, the user without an email is not notified", got, want)
+	}
+}
+
+func TestScheduler_Schedule(t *testing.T) {
+	f := newFixture()
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	returnDate := now.Add(5 * 24 * time.Hour)
+
+	f.handle(t, ctx, "event-1", event.UserRegistered, &proto.UserRegistered{UserId: "user-1", Email: "member@example.com"})
+	f.handle(t, ctx, "event-2", event.BookCreated, &proto.BookCreated{BookId: "book-1", Title: "Dune"})
+	f.handle(t, ctx, "event-3", event.LendingCreated, &proto.LendingCreated{
+		LendingId: "lending-1", BookId: "book-1", UserId: "user-1", ReturnDate: timestamppb.New(returnDate),
+	})
+
+	steps := []struct {
+		name         string
+		at           time.Time
+		renewTo      time.Time
+		wantNotified int
+	}{
+		{name: "not due soon", at: now, wantNotified: 0},
+		{name: "due in 3 days", at: returnDate.Add(-3 * 24 * time.Hour), wantNotified: 1},
+		{name: "already reminded", at: returnDate.Add(-24 * time.Hour), wantNotified: 0},
+		{name: "overdue", at: returnDate, wantNotified: 1},
+		{name: "already overdue", at: returnDate.Add(24 * time.Hour), wantNotified: 0},
+		{name: "renewed and due again", at: returnDate.Add(24 * time.Hour), renewTo: returnDate.Add(3 * 24 * time.Hour), wantNotified: 1},
+	}
+	for _, step := range steps {
+		if !step.renewTo.IsZero() {
+			f.handle(t, ctx, "event-4", event.LendingRenewed, &proto.LendingRenewed{
+				LendingId: "lending-1", BookId: "book-1", UserId: "user-1", ReturnDate: timestamppb.New(step.renewTo),
+			})
+		}
+
+		notified, err := f.scheduler.Schedule(context.Background(), step.at)
+		if err != nil {
+			t.Fatalf("%s: Schedule() error = %v", step.name, err)
+		}
+		if notified != step.wantNotified {
+			t.Errorf("%s: notified %d lendings, want %d", step.name, notified, step.wantNotified)
+		}
+	}
+
+	got := f.kinds(t, ctx)
+	want := []constant.NotificationKind{
+		constant.NotificationAccountCreated,
+		constant.NotificationDueSoon,
+		constant.NotificationOverdue,
+		constant.NotificationDueSoon,
+	}
+	if !equalKinds(got, want) {
+		t.Fatalf("kinds = %v, want %v", got, want)
+	}
+
+	notifications, _ := f.notificationRepository.Fetch(ctx, map[string]interface{}{"limit": int32(100)})
+	overdue := notifications[1]
+	if overdue.Subject != "Dune is overdue" || overdue.Body != "Hello,\n\nDune was due on Sunday, June 6, 2021. Please return it as soon as possible." {
+		t.Errorf("overdue notification = %q %q, want the rendered template", overdue.Subject, overdue.Body)
+	}
+
+	f.handle(t, ctx, "event-5", event.LendingFinished, &proto.LendingFinished{LendingId: "lending-1"})
+	if notified, _ := f.scheduler.Schedule(context.Background(), returnDate.Add(30*24*time.Hour)); notified != 0 {
+		t.Errorf("notified %d lendings, want none once the lending is finished", notified)
+	}
+}
+
+func equalKinds(kinds, other []constant.NotificationKind) bool {
+	if len(kinds) != len(other) {
+		return false
+	}
+	for i := range kinds {
+		if kinds[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"notification-service/internal/domain/constant"
+)
+
+// Data is what the templates show, each kind uses a part of it
+type Data struct {
+	Email      string
+	BookTitle  string
+	ReturnDate time.Time
+	// PickupBy is the last day to pick up a held book
+	PickupBy time.Time
+}
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var functions = template.FuncMap{
+	"date": func(t time.Time) string {
+		return t.Format("Monday, January 2, 2006")
+	},
+}
+
+var templates = map[constant.NotificationKind]messageTemplate{
+	constant.NotificationDueSoon: newTemplate(
+		`{{.BookTitle}} is due on {{date .ReturnDate}}`,
+		`Hello,
+
+{{.BookTitle}} is due on {{date .ReturnDate}}. Please return or renew it before then.`),
+	constant.NotificationOverdue: newTemplate(
+		`{{.BookTitle}} is overdue`,
+		`Hello,
+
+{{.BookTitle}} was due on {{date .ReturnDate}}. Please return it as soon as possible.`),
+	constant.NotificationHoldReady: newTemplate(
+		`{{.BookTitle}} is ready for pickup`,
+		`Hello,
+
+{{.BookTitle}} is held for you until {{date .PickupBy}}. Please pick it up before then.`),
+	constant.NotificationAccountCreated: newTemplate(
+		`Welcome to the library`,
+		`Hello,
+
+Your library account {{.Email}} is created.`),
+	constant.NotificationAccountDeleted: newTemplate(
+		`Your library account is deleted`,
+		`Hello,
+
+Your library account {{.Email}} is deleted, you will not receive any more notifications.`),
+}
+
+func newTemplate(subject, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New("subject").Funcs(functions).Option("missingkey=error").Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(functions).Option("missingkey=error").Parse(body)),
+	}
+}
+
+// Render returns the subject and the body of a notification of the kind
+func Render(kind constant.NotificationKind, data Data) (subject, body string, err error) {
+	t, ok := templates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for the %s notification", kind)
+	}
+
+	var b strings.Builder
+	if err = t.subject.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	subject = b.String()
+
+	b.Reset()
+	if err = t.body.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+
+	return subject, b.String(), nil
+}
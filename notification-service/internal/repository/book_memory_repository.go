@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/pkg/tenant"
+)
+
+type bookMemoryRepository struct {
+	mu    sync.RWMutex
+	books []domain.Book
+}
+
+func NewBookMemoryRepository() domain.BookRepository {
+	return &bookMemoryRepository{}
+}
+
+func (r *bookMemoryRepository) Save(ctx context.Context, book *domain.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.books {
+		if stored.BookID == book.BookID && stored.Meta.TenantID == tenantID {
+			r.books[i].Title = book.Title
+			r.books[i].Meta.Update()
+			return nil
+		}
+	}
+
+	book.ID = primitive.NewObjectID()
+	book.Meta.Create()
+	book.Meta.TenantID = tenantID
+
+	var stored domain.Book
+	copyDocument(book, &stored)
+	r.books = append(r.books, stored)
+	return nil
+}
+
+func (r *bookMemoryRepository) FindByBookID(ctx context.Context, bookID string) (book domain.Book, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.books {
+		if stored.BookID == bookID && stored.Meta.TenantID == tenantID {
+			copyDocument(stored, &book)
+			return book, nil
+		}
+	}
+
+	return domain.Book{}, mongo.ErrNoDocuments
+}
+
+func (r *bookMemoryRepository) Delete(ctx context.Context, bookID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.books {
+		if stored.BookID == bookID && stored.Meta.TenantID == tenantID {
+			r.books = append(r.books[:i], r.books[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
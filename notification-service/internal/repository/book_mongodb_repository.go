@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type bookMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewBookMongoDBRepository(db *mongo.Database) domain.BookRepository {
+	return &bookMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.BookCollection),
+	}
+}
+
+func (r *bookMongoDBRepository) Save(ctx context.Context, book *domain.Book) error {
+	book.ID = primitive.NewObjectID()
+	book.Meta.Create()
+	book.Meta.TenantID = tenant.FromContext(ctx)
+
+	// the replacement keeps the ID of the saved book
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, bson.D{{"book_id", book.BookID}}),
+		bson.D{
+			{"$set", bson.D{{"title", book.Title}, {"meta.updated_at", book.Meta.UpdatedAt}}},
+			{"$setOnInsert", bson.D{{"_id", book.ID}, {"meta.created_at", book.Meta.CreatedAt}}},
+		},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *bookMongoDBRepository) FindByBookID(ctx context.Context, bookID string) (domain.Book, error) {
+	var book domain.Book
+	err := r.collection.FindOne(ctx, byTenant(ctx, bson.D{{"book_id", bookID}})).Decode(&book)
+	return book, err
+}
+
+func (r *bookMongoDBRepository) Delete(ctx context.Context, bookID string) error {
+	_, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.D{{"book_id", bookID}}))
+	return err
+}
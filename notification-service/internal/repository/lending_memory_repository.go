@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/pkg/tenant"
+)
+
+type lendingMemoryRepository struct {
+	mu       sync.RWMutex
+	lendings []domain.Lending
+}
+
+func NewLendingMemoryRepository() domain.LendingRepository {
+	return &lendingMemoryRepository{}
+}
+
+func (r *lendingMemoryRepository) Save(ctx context.Context, lending *domain.Lending) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.lendings {
+		if stored.LendingID == lending.LendingID && stored.Meta.TenantID == tenantID {
+			lending.ID = stored.ID
+			lending.Meta = stored.Meta
+			lending.Meta.Update()
+			copyDocument(lending, &r.lendings[i])
+			return nil
+		}
+	}
+
+	lending.ID = primitive.NewObjectID()
+	lending.Meta.Create()
+	lending.Meta.TenantID = tenantID
+
+	var stored domain.Lending
+	copyDocument(lending, &stored)
+	r.lendings = append(r.lendings, stored)
+	return nil
+}
+
+func (r *lendingMemoryRepository) Delete(ctx context.Context, lendingID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.lendings {
+		if stored.LendingID == lendingID && stored.Meta.TenantID == tenantID {
+			r.lendings = append(r.lendings[:i], r.lendings[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (r *lendingMemoryRepository) FetchDueSoon(_ context.Context, now, until time.Time, limit int) ([]domain.Lending, error) {
+	return r.fetch(func(lending domain.Lending) bool {
+		return lending.RemindedAt == nil && lending.ReturnDate.After(now) && !lending.ReturnDate.After(until)
+	}, limit), nil
+}
+
+func (r *lendingMemoryRepository) FetchOverdue(_ context.Context, now time.Time, limit int) ([]domain.Lending, error) {
+	return r.fetch(func(lending domain.Lending) bool {
+		return lending.OverdueNotifiedAt == nil && !lending.ReturnDate.After(now)
+	}, limit), nil
+}
+
+// fetch returns a copy of the lendings of every tenant matching, the earliest return date first
+func (r *lendingMemoryRepository) fetch(matches func(domain.Lending) bool, limit int) []domain.Lending {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lendings := make([]domain.Lending, 0)
+	for _, stored := range r.lendings {
+		if !matches(stored) {
+			continue
+		}
+
+		var found domain.Lending
+		copyDocument(stored, &found)
+		lendings = append(lendings, found)
+	}
+
+	sort.SliceStable(lendings, func(i, j int) bool {
+		return lendings[i].ReturnDate.Before(lendings[j].ReturnDate)
+	})
+	if len(lendings) > limit {
+		lendings = lendings[:limit]
+	}
+	return lendings
+}
+
+func (r *lendingMemoryRepository) MarkReminded(_ context.Context, id primitive.ObjectID, at time.Time) error {
+	return r.mark(id, func(lending *domain.Lending) { lending.RemindedAt = &at })
+}
+
+func (r *lendingMemoryRepository) MarkOverdueNotified(_ context.Context, id primitive.ObjectID, at time.Time) error {
+	return r.mark(id, func(lending *domain.Lending) { lending.OverdueNotifiedAt = &at })
+}
+
+func (r *lendingMemoryRepository) mark(id primitive.ObjectID, set func(*domain.Lending)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.lendings {
+		if r.lendings[i].ID == id {
+			set(&r.lendings[i])
+			r.lendings[i].Meta.Update()
+			return nil
+		}
+	}
+
+	return mongo.ErrNoDocuments
+}
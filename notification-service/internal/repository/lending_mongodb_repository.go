@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type lendingMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewLendingMongoDBRepository(db *mongo.Database) domain.LendingRepository {
+	return &lendingMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.LendingCollection),
+	}
+}
+
+func (r *lendingMongoDBRepository) Save(ctx context.Context, lending *domain.Lending) error {
+	lending.ID = primitive.NewObjectID()
+	lending.Meta.Create()
+	lending.Meta.TenantID = tenant.FromContext(ctx)
+
+	// a renewal replaces the return date and clears the notifications of the previous one
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, bson.D{{"lending_id", lending.LendingID}}),
+		bson.D{
+			{"$set", bson.D{
+				{"user_id", lending.UserID},
+				{"book_id", lending.BookID},
+				{"return_date", lending.ReturnDate},
+				{"reminded_at", lending.RemindedAt},
+				{"overdue_notified_at", lending.OverdueNotifiedAt},
+				{"meta.updated_at", lending.Meta.UpdatedAt},
+			}},
+			{"$setOnInsert", bson.D{{"_id", lending.ID}, {"meta.created_at", lending.Meta.CreatedAt}}},
+		},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *lendingMongoDBRepository) Delete(ctx context.Context, lendingID string) error {
+	_, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.D{{"lending_id", lendingID}}))
+	return err
+}
+
+func (r *lendingMongoDBRepository) FetchDueSoon(ctx context.Context, now, until time.Time, limit int) ([]domain.Lending, error) {
+	return r.fetch(ctx, bson.D{
+		{"return_date", bson.D{{"$gt", now}, {"$lte", until}}},
+		{"reminded_at", nil},
+	}, limit)
+}
+
+func (r *lendingMongoDBRepository) FetchOverdue(ctx context.Context, now time.Time, limit int) ([]domain.Lending, error) {
+	return r.fetch(ctx, bson.D{
+		{"return_date", bson.D{{"$lte", now}}},
+		{"overdue_notified_at", nil},
+	}, limit)
+}
+
+// fetch is not scoped to a tenant, the scheduler notifies the lendings of every tenant
+func (r *lendingMongoDBRepository) fetch(ctx context.Context, filter bson.D, limit int) ([]domain.Lending, error) {
+	cursor, err := r.collection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"return_date", 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lendings := make([]domain.Lending, 0)
+	if err = cursor.All(ctx, &lendings); err != nil {
+		return nil, err
+	}
+
+	return lendings, nil
+}
+
+func (r *lendingMongoDBRepository) MarkReminded(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{
+		{"$set", bson.D{{"reminded_at", at}, {"meta.updated_at", time.Now()}}},
+	})
+	return err
+}
+
+func (r *lendingMongoDBRepository) MarkOverdueNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{
+		{"$set", bson.D{{"overdue_notified_at", at}, {"meta.updated_at", time.Now()}}},
+	})
+	return err
+}
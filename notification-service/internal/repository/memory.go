@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// copyDocument copies src to dst through BSON like a document written to and read back from MongoDB,
+// so the in-memory repositories never share a slice or a pointer with their callers
+func copyDocument(src, dst interface{}) {
+	value := reflect.ValueOf(dst).Elem()
+	value.Set(reflect.Zero(value.Type()))
+
+	bsonBytes, _ := bson.Marshal(src)
+	_ = bson.Unmarshal(bsonBytes, dst)
+}
+
+// pageBounds returns the bounds of the requested page in total documents, with the defaults of pageBy
+func pageBounds(param map[string]interface{}, total int) (start, end int) {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+
+	start = int((page - 1) * limit)
+	if start > total {
+		start = total
+	}
+	end = start + int(limit)
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// newestFirst orders like the meta.created_at descending sort, the ties by the newest ID
+func newestFirst(createdAt, otherCreatedAt time.Time, id, otherID primitive.ObjectID) bool {
+	if !createdAt.Equal(otherCreatedAt) {
+		return createdAt.After(otherCreatedAt)
+	}
+	return bytes.Compare(id[:], otherID[:]) > 0
+}
+
+// duplicateKeyError is the error of MongoDB for a document violating a unique index
+func duplicateKeyError(collection, index string) error {
+	return mongo.WriteException{
+		WriteErrors: mongo.WriteErrors{{
+			Code:    11000,
+			Message: fmt.Sprintf("E11000 duplicate key error collection: %s index: %s dup key", collection, index),
+		}},
+	}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type notificationMemoryRepository struct {
+	mu            sync.RWMutex
+	notifications []domain.Notification
+}
+
+func NewNotificationMemoryRepository() domain.NotificationRepository {
+	return &notificationMemoryRepository{}
+}
+
+func (r *notificationMemoryRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.notifications {
+		if stored.Meta.TenantID == tenantID && stored.Key == notification.Key && stored.Channel == notification.Channel {
+			return duplicateKeyError(constant.NotificationCollection, constant.NotificationKeyUniqueIndex)
+		}
+	}
+
+	notification.ID = primitive.NewObjectID()
+	notification.Meta.Create()
+	notification.Meta.TenantID = tenantID
+
+	var stored domain.Notification
+	copyDocument(notification, &stored)
+	r.notifications = append(r.notifications, stored)
+	return nil
+}
+
+func (r *notificationMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Notification, error) {
+	notifications := r.filter(ctx, param)
+
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return newestFirst(notifications[i].CreatedAt, notifications[j].CreatedAt, notifications[i].ID, notifications[j].ID)
+	})
+	start, end := pageBounds(param, len(notifications))
+
+	return notifications[start:end], nil
+}
+
+func (r *notificationMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(ctx, param)), nil
+}
+
+// filter returns a copy of the notifications matching the param, like notificationMongoDBRepository.filterBy
+func (r *notificationMemoryRepository) filter(ctx context.Context, param map[string]interface{}) []domain.Notification {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	notifications := make([]domain.Notification, 0)
+	for _, notification := range r.notifications {
+		if notification.Meta.TenantID != tenantID || !notificationMatches(notification, param) {
+			continue
+		}
+
+		var found domain.Notification
+		copyDocument(notification, &found)
+		notifications = append(notifications, found)
+	}
+
+	return notifications
+}
+
+func notificationMatches(notification domain.Notification, param map[string]interface{}) bool {
+	for key, value := range param {
+		switch key {
+		case "user_id":
+			if notification.UserID != value.(string) {
+				return false
+			}
+		case "status":
+			// the status is filtered as a constant.DeliveryStatus or as the string of a request
+			if string(notification.Status) != fmt.Sprint(value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (r *notificationMemoryRepository) FindByID(ctx context.Context, id string) (notification domain.Notification, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Notification{}, mongo.ErrNoDocuments
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.notifications {
+		if stored.ID == objectID && stored.Meta.TenantID == tenantID {
+			copyDocument(stored, &notification)
+			return notification, nil
+		}
+	}
+
+	return domain.Notification{}, mongo.ErrNoDocuments
+}
+
+func (r *notificationMemoryRepository) Update(ctx context.Context, notification *domain.Notification) error {
+	notification.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.notifications {
+		if stored.ID == notification.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(notification, &r.notifications[i])
+			copyDocument(r.notifications[i], notification)
+			return nil
+		}
+	}
+
+	return mongo.ErrNoDocuments
+}
+
+func (r *notificationMemoryRepository) FetchDue(_ context.Context, now time.Time, limit int) ([]domain.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := make([]domain.Notification, 0)
+	for _, stored := range r.notifications {
+		if stored.Status != constant.DeliveryPending || stored.NextAttemptAt.After(now) {
+			continue
+		}
+
+		var found domain.Notification
+		copyDocument(stored, &found)
+		notifications = append(notifications, found)
+	}
+
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].NextAttemptAt.Before(notifications[j].NextAttemptAt)
+	})
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
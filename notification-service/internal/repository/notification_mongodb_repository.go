@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type notificationMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewNotificationMongoDBRepository(db *mongo.Database) domain.NotificationRepository {
+	return &notificationMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.NotificationCollection),
+	}
+}
+
+func (r *notificationMongoDBRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	notification.ID = primitive.NewObjectID()
+	notification.Meta.Create()
+	notification.Meta.TenantID = tenant.FromContext(ctx)
+
+	_, err := r.collection.InsertOne(ctx, notification)
+	return err
+}
+
+func (r *notificationMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Notification, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := make([]domain.Notification, 0)
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*notificationMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
+
+	for key, value := range param {
+		switch key {
+		case "user_id", "status":
+			filter = append(filter, bson.E{key, value})
+		}
+	}
+
+	return filter
+}
+
+func (*notificationMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.D{{"meta.created_at", -1}, {"_id", -1}})
+
+	return findOptions
+}
+
+func (r *notificationMongoDBRepository) FindByID(ctx context.Context, id string) (domain.Notification, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Notification{}, mongo.ErrNoDocuments
+	}
+
+	var notification domain.Notification
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.D{{"_id", objectID}})).Decode(&notification)
+	return notification, err
+}
+
+func (r *notificationMongoDBRepository) Update(ctx context.Context, notification *domain.Notification) error {
+	notification.Meta.Update()
+
+	_, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.D{{"_id", notification.ID}}), notification)
+	return err
+}
+
+// FetchDue is not scoped to a tenant, the dispatcher sends the notifications of every tenant
+func (r *notificationMongoDBRepository) FetchDue(ctx context.Context, now time.Time, limit int) ([]domain.Notification, error) {
+	filter := bson.D{
+		{"status", constant.DeliveryPending},
+		{"next_attempt_at", bson.D{{"$lte", now}}},
+	}
+	cursor, err := r.collection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"next_attempt_at", 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := make([]domain.Notification, 0)
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
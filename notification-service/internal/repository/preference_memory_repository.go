@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/pkg/tenant"
+)
+
+type preferenceMemoryRepository struct {
+	mu          sync.RWMutex
+	preferences []domain.Preference
+}
+
+func NewPreferenceMemoryRepository() domain.PreferenceRepository {
+	return &preferenceMemoryRepository{}
+}
+
+func (r *preferenceMemoryRepository) Create(ctx context.Context, preference *domain.Preference) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.preferences {
+		if stored.Meta.TenantID == tenantID && stored.UserID == preference.UserID {
+			return nil
+		}
+	}
+
+	preference.ID = primitive.NewObjectID()
+	preference.Meta.Create()
+	preference.Meta.TenantID = tenantID
+
+	var stored domain.Preference
+	copyDocument(preference, &stored)
+	r.preferences = append(r.preferences, stored)
+	return nil
+}
+
+func (r *preferenceMemoryRepository) FindByUserID(ctx context.Context, userID string) (preference domain.Preference, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.preferences {
+		if stored.UserID == userID && stored.Meta.TenantID == tenantID {
+			copyDocument(stored, &preference)
+			return preference, nil
+		}
+	}
+
+	return domain.Preference{}, mongo.ErrNoDocuments
+}
+
+func (r *preferenceMemoryRepository) Update(ctx context.Context, preference *domain.Preference) error {
+	preference.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.preferences {
+		if stored.ID == preference.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(preference, &r.preferences[i])
+			copyDocument(r.preferences[i], preference)
+			return nil
+		}
+	}
+
+	return mongo.ErrNoDocuments
+}
+
+func (r *preferenceMemoryRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.preferences {
+		if stored.UserID == userID && stored.Meta.TenantID == tenantID {
+			r.preferences = append(r.preferences[:i], r.preferences[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type preferenceMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewPreferenceMongoDBRepository(db *mongo.Database) domain.PreferenceRepository {
+	return &preferenceMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.PreferenceCollection),
+	}
+}
+
+func (r *preferenceMongoDBRepository) Create(ctx context.Context, preference *domain.Preference) error {
+	preference.ID = primitive.NewObjectID()
+	preference.Meta.Create()
+	preference.Meta.TenantID = tenant.FromContext(ctx)
+
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, bson.D{{"user_id", preference.UserID}}),
+		bson.D{{"$setOnInsert", preference}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *preferenceMongoDBRepository) FindByUserID(ctx context.Context, userID string) (domain.Preference, error) {
+	var preference domain.Preference
+	err := r.collection.FindOne(ctx, byTenant(ctx, bson.D{{"user_id", userID}})).Decode(&preference)
+	return preference, err
+}
+
+func (r *preferenceMongoDBRepository) Update(ctx context.Context, preference *domain.Preference) error {
+	preference.Meta.Update()
+
+	_, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.D{{"_id", preference.ID}}), preference)
+	return err
+}
+
+func (r *preferenceMongoDBRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.D{{"user_id", userID}}))
+	return err
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type webhookDeliveryMemoryRepository struct {
+	mu         sync.RWMutex
+	deliveries []domain.WebhookDelivery
+}
+
+func NewWebhookDeliveryMemoryRepository() domain.WebhookDeliveryRepository {
+	return &webhookDeliveryMemoryRepository{}
+}
+
+func (r *webhookDeliveryMemoryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.deliveries {
+		if stored.Meta.TenantID == tenantID && stored.WebhookID == delivery.WebhookID && stored.EventID == delivery.EventID {
+			return duplicateKeyError(constant.WebhookDeliveryCollection, constant.WebhookDeliveryEventUniqueIndex)
+		}
+	}
+
+	delivery.ID = primitive.NewObjectID()
+	delivery.Meta.Create()
+	delivery.Meta.TenantID = tenantID
+
+	var stored domain.WebhookDelivery
+	copyDocument(delivery, &stored)
+	r.deliveries = append(r.deliveries, stored)
+	return nil
+}
+
+func (r *webhookDeliveryMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.WebhookDelivery, error) {
+	deliveries := r.filter(ctx, param)
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return newestFirst(deliveries[i].CreatedAt, deliveries[j].CreatedAt, deliveries[i].ID, deliveries[j].ID)
+	})
+	start, end := pageBounds(param, len(deliveries))
+
+	return deliveries[start:end], nil
+}
+
+func (r *webhookDeliveryMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(ctx, param)), nil
+}
+
+// filter returns a copy of the deliveries matching the param, like webhookDeliveryMongoDBRepository.filterBy
+func (r *webhookDeliveryMemoryRepository) filter(ctx context.Context, param map[string]interface{}) []domain.WebhookDelivery {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.Meta.TenantID != tenantID || !webhookDeliveryMatches(delivery, param) {
+			continue
+		}
+
+		var found domain.WebhookDelivery
+		copyDocument(delivery, &found)
+		deliveries = append(deliveries, found)
+	}
+
+	return deliveries
+}
+
+func webhookDeliveryMatches(delivery domain.WebhookDelivery, param map[string]interface{}) bool {
+	for key, value := range param {
+		switch key {
+		case "webhook_id":
+			if delivery.WebhookID != value.(string) {
+				return false
+			}
+		case "event_type":
+			if delivery.EventType != value.(string) {
+				return false
+			}
+		case "status":
+			// the status is filtered as a constant.DeliveryStatus or as the string of a request
+			if string(delivery.Status) != fmt.Sprint(value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (r *webhookDeliveryMemoryRepository) FindByID(ctx context.Context, id string) (delivery domain.WebhookDelivery, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.WebhookDelivery{}, mongo.ErrNoDocuments
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.deliveries {
+		if stored.ID == objectID && stored.Meta.TenantID == tenantID {
+			copyDocument(stored, &delivery)
+			return delivery, nil
+		}
+	}
+
+	return domain.WebhookDelivery{}, mongo.ErrNoDocuments
+}
+
+func (r *webhookDeliveryMemoryRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	delivery.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.deliveries {
+		if stored.ID == delivery.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(delivery, &r.deliveries[i])
+			copyDocument(r.deliveries[i], delivery)
+			return nil
+		}
+	}
+
+	return mongo.ErrNoDocuments
+}
+
+func (r *webhookDeliveryMemoryRepository) FetchDue(_ context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, stored := range r.deliveries {
+		if stored.Status != constant.DeliveryPending || stored.NextAttemptAt.After(now) {
+			continue
+		}
+
+		var found domain.WebhookDelivery
+		copyDocument(stored, &found)
+		deliveries = append(deliveries, found)
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
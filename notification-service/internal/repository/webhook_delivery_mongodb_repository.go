@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type webhookDeliveryMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewWebhookDeliveryMongoDBRepository(db *mongo.Database) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.WebhookDeliveryCollection),
+	}
+}
+
+func (r *webhookDeliveryMongoDBRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	delivery.ID = primitive.NewObjectID()
+	delivery.Meta.Create()
+	delivery.Meta.TenantID = tenant.FromContext(ctx)
+
+	_, err := r.collection.InsertOne(ctx, delivery)
+	return err
+}
+
+func (r *webhookDeliveryMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.WebhookDelivery, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*webhookDeliveryMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
+
+	for key, value := range param {
+		switch key {
+		case "webhook_id", "event_type", "status":
+			filter = append(filter, bson.E{key, value})
+		}
+	}
+
+	return filter
+}
+
+func (*webhookDeliveryMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.D{{"meta.created_at", -1}, {"_id", -1}})
+
+	return findOptions
+}
+
+func (r *webhookDeliveryMongoDBRepository) FindByID(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.WebhookDelivery{}, mongo.ErrNoDocuments
+	}
+
+	var delivery domain.WebhookDelivery
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.D{{"_id", objectID}})).Decode(&delivery)
+	return delivery, err
+}
+
+func (r *webhookDeliveryMongoDBRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	delivery.Meta.Update()
+
+	_, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.D{{"_id", delivery.ID}}), delivery)
+	return err
+}
+
+// FetchDue is not scoped to a tenant, the dispatcher sends the deliveries of every tenant
+func (r *webhookDeliveryMongoDBRepository) FetchDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	filter := bson.D{
+		{"status", constant.DeliveryPending},
+		{"next_attempt_at", bson.D{{"$lte", now}}},
+	}
+	cursor, err := r.collection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"next_attempt_at", 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
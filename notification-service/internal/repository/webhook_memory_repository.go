@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/pkg/tenant"
+)
+
+type webhookMemoryRepository struct {
+	mu       sync.RWMutex
+	webhooks []domain.Webhook
+}
+
+func NewWebhookMemoryRepository() domain.WebhookRepository {
+	return &webhookMemoryRepository{}
+}
+
+func (r *webhookMemoryRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.ID = primitive.NewObjectID()
+	webhook.Meta.Create()
+	webhook.Meta.TenantID = tenant.FromContext(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stored domain.Webhook
+	copyDocument(webhook, &stored)
+	r.webhooks = append(r.webhooks, stored)
+	return nil
+}
+
+func (r *webhookMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Webhook, error) {
+	webhooks := r.filter(ctx, param)
+
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		return newestFirst(webhooks[i].CreatedAt, webhooks[j].CreatedAt, webhooks[i].ID, webhooks[j].ID)
+	})
+	start, end := pageBounds(param, len(webhooks))
+
+	return webhooks[start:end], nil
+}
+
+func (r *webhookMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(ctx, param)), nil
+}
+
+// filter returns a copy of the webhooks matching the param, like webhookMongoDBRepository.filterBy
+func (r *webhookMemoryRepository) filter(ctx context.Context, param map[string]interface{}) []domain.Webhook {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	webhooks := make([]domain.Webhook, 0)
+	for _, webhook := range r.webhooks {
+		if webhook.Meta.TenantID != tenantID || webhook.DeletedAt != nil || !webhookMatches(webhook, param) {
+			continue
+		}
+
+		var found domain.Webhook
+		copyDocument(webhook, &found)
+		webhooks = append(webhooks, found)
+	}
+
+	return webhooks
+}
+
+func webhookMatches(webhook domain.Webhook, param map[string]interface{}) bool {
+	for key, value := range param {
+		switch key {
+		case "active":
+			if webhook.Active != value.(bool) {
+				return false
+			}
+		case "event_type":
+			if !webhook.Subscribed(value.(string)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (r *webhookMemoryRepository) FindByID(ctx context.Context, id string) (webhook domain.Webhook, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Webhook{}, mongo.ErrNoDocuments
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.webhooks {
+		if stored.ID == objectID && stored.Meta.TenantID == tenantID && stored.DeletedAt == nil {
+			copyDocument(stored, &webhook)
+			return webhook, nil
+		}
+	}
+
+	return domain.Webhook{}, mongo.ErrNoDocuments
+}
+
+func (r *webhookMemoryRepository) Update(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.Meta.Update()
+	return r.replace(ctx, webhook)
+}
+
+func (r *webhookMemoryRepository) Delete(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.Meta.Delete()
+	return r.replace(ctx, webhook)
+}
+
+func (r *webhookMemoryRepository) replace(ctx context.Context, webhook *domain.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.webhooks {
+		if stored.ID == webhook.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(webhook, &r.webhooks[i])
+			copyDocument(r.webhooks[i], webhook)
+			return nil
+		}
+	}
+
+	return mongo.ErrNoDocuments
+}
+
+func (r *webhookMemoryRepository) FetchSubscribed(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	return r.filter(ctx, map[string]interface{}{"active": true, "event_type": eventType}), nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/tenant"
+)
+
+type webhookMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewWebhookMongoDBRepository(db *mongo.Database) domain.WebhookRepository {
+	return &webhookMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.WebhookCollection),
+	}
+}
+
+func (r *webhookMongoDBRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.ID = primitive.NewObjectID()
+	webhook.Meta.Create()
+	webhook.Meta.TenantID = tenant.FromContext(ctx)
+
+	_, err := r.collection.InsertOne(ctx, webhook)
+	return err
+}
+
+func (r *webhookMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Webhook, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := make([]domain.Webhook, 0)
+	if err = cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhookMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*webhookMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{{"meta.deleted_at", nil}})
+
+	for key, value := range param {
+		switch key {
+		case "active":
+			filter = append(filter, bson.E{key, value})
+		case "event_type":
+			filter = append(filter, bson.E{"event_types", value})
+		}
+	}
+
+	return filter
+}
+
+func (*webhookMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.D{{"meta.created_at", -1}, {"_id", -1}})
+
+	return findOptions
+}
+
+func (r *webhookMongoDBRepository) FindByID(ctx context.Context, id string) (domain.Webhook, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Webhook{}, mongo.ErrNoDocuments
+	}
+
+	var webhook domain.Webhook
+	filter := byTenant(ctx, bson.D{{"_id", objectID}, {"meta.deleted_at", nil}})
+	err = r.collection.FindOne(ctx, filter).Decode(&webhook)
+	return webhook, err
+}
+
+func (r *webhookMongoDBRepository) Update(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.Meta.Update()
+
+	_, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.D{{"_id", webhook.ID}}), webhook)
+	return err
+}
+
+// Delete keeps the webhook for its delivery log, it receives no more event
+func (r *webhookMongoDBRepository) Delete(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.Meta.Delete()
+
+	_, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.D{{"_id", webhook.ID}}), webhook)
+	return err
+}
+
+func (r *webhookMongoDBRepository) FetchSubscribed(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	filter := byTenant(ctx, bson.D{{"meta.deleted_at", nil}, {"active", true}, {"event_types", eventType}})
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := make([]domain.Webhook, 0)
+	if err = cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
@@ -0,0 +1,38 @@
+// Package sender delivers the notifications on their channel. A sender returns an error when the message is not
+// delivered, the dispatcher retries it later.
+package sender
+
+import (
+	"context"
+	"fmt"
+
+	"notification-service/internal/domain/constant"
+)
+
+// Message is a rendered notification to one recipient
+type Message struct {
+	NotificationID string           `json:"notification_id"`
+	TenantID       string           `json:"tenant_id"`
+	UserID         string           `json:"user_id"`
+	Kind           string           `json:"kind"`
+	Channel        constant.Channel `json:"channel"`
+	Recipient      string           `json:"recipient"`
+	Subject        string           `json:"subject"`
+	Body           string           `json:"body"`
+}
+
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Router sends every message with the sender of its channel
+type Router map[constant.Channel]Sender
+
+func (r Router) Send(ctx context.Context, message Message) error {
+	s, ok := r[message.Channel]
+	if !ok {
+		return fmt.Errorf("no sender for the %s channel", message.Channel)
+	}
+
+	return s.Send(ctx, message)
+}
@@ -0,0 +1,136 @@
+package sender
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"notification-service/internal/domain/constant"
+)
+
+// fakeSMTPServer accepts the mails of one connection, replying with rcptCode to RCPT
+type fakeSMTPServer struct {
+	listener net.Listener
+	rcptCode string
+	mails    chan fakeMail
+}
+
+type fakeMail struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T, rcptCode string) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &fakeSMTPServer{listener: listener, rcptCode: rcptCode, mails: make(chan fakeMail, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	_ = text.PrintfLine("220 localhost fake SMTP")
+
+	var mail fakeMail
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			_ = text.PrintfLine("250 localhost")
+		case "MAIL":
+			mail.from = line[len("MAIL FROM:"):]
+			_ = text.PrintfLine("250 OK")
+		case "RCPT":
+			mail.to = append(mail.to, line[len("RCPT TO:"):])
+			_ = text.PrintfLine("%s recipient", s.rcptCode)
+		case "DATA":
+			_ = text.PrintfLine("354 end with <CRLF>.<CRLF>")
+			data, err := bufio.NewReader(text.DotReader()).ReadString(0)
+			if err != nil && data == "" {
+				return
+			}
+			mail.data = data
+			_ = text.PrintfLine("250 OK")
+			s.mails <- mail
+		case "QUIT":
+			_ = text.PrintfLine("221 bye")
+			return
+		default:
+			_ = text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	server := newFakeSMTPServer(t, "250")
+	sender := NewSMTPSender(server.Addr(), "", "", "library@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := sender.Send(ctx, Message{
+		NotificationID: "notification-1",
+		Channel:        constant.ChannelEmail,
+		Recipient:      "member@example.com",
+		Subject:        "Dune is due in 3 days",
+		Body:           "Hello,\nDune is due on 2021-06-04.",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	mail := <-server.mails
+	if mail.from != "<library@example.com>" || len(mail.to) != 1 || mail.to[0] != "<member@example.com>" {
+		t.Errorf("envelope = %s to %v, want library@example.com to member@example.com", mail.from, mail.to)
+	}
+	for _, want := range []string{
+		"To: member@example.com\n",
+		"Subject: Dune is due in 3 days\n",
+		"Message-ID: <notification-1@example.com>\n",
+		"\nHello,\nDune is due on 2021-06-04.\n",
+	} {
+		if !strings.Contains(mail.data, want) {
+			t.Errorf("data = %q, want it to contain %q", mail.data, want)
+		}
+	}
+}
+
+func TestSMTPSender_SendRejected(t *testing.T) {
+	server := newFakeSMTPServer(t, "550")
+	sender := NewSMTPSender(server.Addr(), "", "", "library@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := sender.Send(ctx, Message{Channel: constant.ChannelEmail, Recipient: "unknown@example.com", Subject: "Hello"})
+	if err == nil || !strings.Contains(err.Error(), "550") {
+		t.Errorf("Send() error = %v, want the rejection of the recipient", err)
+	}
+}
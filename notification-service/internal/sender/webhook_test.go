@@ -0,0 +1,39 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"notification-service/internal/domain/constant"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	var received Message
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(server.Client())
+	message := Message{NotificationID: "notification-1", Channel: constant.ChannelWebhook, Recipient: server.URL, Subject: "Overdue"}
+
+	if err := sender.Send(context.Background(), message); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if received != message {
+		t.Errorf("received = %v, want %v", received, message)
+	}
+
+	status = http.StatusInternalServerError
+	if err := sender.Send(context.Background(), message); err == nil {
+		t.Error("Send() error = nil, want the error of the 500 response")
+	}
+}
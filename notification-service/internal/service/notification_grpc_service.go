@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/notification"
+	"notification-service/pkg/proto"
+)
+
+type NotificationGRPCService struct {
+	proto.UnimplementedNotificationServiceServer
+	notificationRepository domain.NotificationRepository
+	preferenceRepository   domain.PreferenceRepository
+}
+
+func NewNotificationGRPCService(
+	notificationRepository domain.NotificationRepository,
+	preferenceRepository domain.PreferenceRepository,
+) *NotificationGRPCService {
+	return &NotificationGRPCService{
+		notificationRepository: notificationRepository,
+		preferenceRepository:   preferenceRepository,
+	}
+}
+
+func (s *NotificationGRPCService) FindPreference(ctx context.Context, request *proto.FindPreferenceRequest) (*proto.Preference, error) {
+	preference, err := s.findPreference(ctx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoPreference(preference), nil
+}
+
+// UpdatePreference replaces the phone, the webhook URL and the channels of the user, every channel needs an address
+func (s *NotificationGRPCService) UpdatePreference(ctx context.Context, request *proto.UpdatePreferenceRequest) (*proto.Preference, error) {
+	preference, err := s.findPreference(ctx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.WebhookUrl != "" && !validWebhookURL(request.WebhookUrl) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook URL: %s", request.WebhookUrl)
+	}
+	preference.Phone = request.Phone
+	preference.WebhookURL = request.WebhookUrl
+
+	channels := make([]constant.Channel, 0)
+	for _, requestChannel := range request.Channels {
+		channel := constant.Channel(requestChannel)
+		if !validChannel(channel) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown channel: %s", requestChannel)
+		}
+		if preference.Recipient(channel) == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "%s channel has no address", requestChannel)
+		}
+		if !containsChannel(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	preference.Channels = channels
+
+	if err = s.preferenceRepository.Update(ctx, &preference); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoPreference(preference), nil
+}
+
+func (s *NotificationGRPCService) findPreference(ctx context.Context, userID string) (domain.Preference, error) {
+	preference, err := s.preferenceRepository.FindByUserID(ctx, userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Preference{}, status.Errorf(codes.NotFound, "preference of user with %s ID not found", userID)
+	}
+	if err != nil {
+		return domain.Preference{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return preference, nil
+}
+
+func (s *NotificationGRPCService) FetchNotification(ctx context.Context, request *proto.FetchNotificationRequest) (*proto.FetchNotificationResponse, error) {
+	page, limit := request.GetPagination().GetPage(), request.GetPagination().GetLimit()
+	fetchFilter := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+	if userID := request.UserId; userID != "" {
+		fetchFilter["user_id"] = userID
+	}
+	if requestStatus := request.Status; requestStatus != "" {
+		fetchFilter["status"] = requestStatus
+	}
+
+	notifications, err := s.notificationRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoNotifications := make([]*proto.Notification, 0)
+	for _, n := range notifications {
+		protoNotifications = append(protoNotifications, toProtoNotification(n))
+	}
+
+	total, err := s.notificationRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.FetchNotificationResponse{
+		Pagination:    paginationResponse(page, limit, total),
+		Notifications: protoNotifications,
+	}, nil
+}
+
+func (s *NotificationGRPCService) RetryNotification(ctx context.Context, request *proto.RetryNotificationRequest) (*proto.Notification, error) {
+	n, err := s.notificationRepository.FindByID(ctx, request.Id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Errorf(codes.NotFound, "notification with %s ID not found", request.Id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if n.Status != constant.DeliveryFailed {
+		return nil, status.Errorf(codes.FailedPrecondition, "notification is %s, only a failed notification is retried", n.Status)
+	}
+
+	notification.Retry(&n, time.Now())
+	if err = s.notificationRepository.Update(ctx, &n); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoNotification(n), nil
+}
+
+// validWebhookURL tells whether the URL is an absolute http or https URL
+func validWebhookURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validChannel(channel constant.Channel) bool {
+	return containsChannel(constant.Channels, channel)
+}
+
+func containsChannel(channels []constant.Channel, channel constant.Channel) bool {
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func toProtoPreference(preference domain.Preference) *proto.Preference {
+	channels := make([]string, 0, len(preference.Channels))
+	for _, channel := range preference.Channels {
+		channels = append(channels, string(channel))
+	}
+
+	return &proto.Preference{
+		UserId:     preference.UserID,
+		Email:      preference.Email,
+		Phone:      preference.Phone,
+		WebhookUrl: preference.WebhookURL,
+		Channels:   channels,
+	}
+}
+
+func toProtoNotification(n domain.Notification) *proto.Notification {
+	protoNotification := &proto.Notification{
+		Id:        n.ID.Hex(),
+		UserId:    n.UserID,
+		Kind:      string(n.Kind),
+		Channel:   string(n.Channel),
+		Recipient: n.Recipient,
+		Subject:   n.Subject,
+		Body:      n.Body,
+		Status:    string(n.Status),
+		Attempts:  int32(n.Attempts),
+		LastError: n.LastError,
+		CreatedAt: timestamppb.New(n.CreatedAt),
+	}
+	if n.SentAt != nil {
+		protoNotification.SentAt = timestamppb.New(*n.SentAt)
+	}
+	return protoNotification
+}
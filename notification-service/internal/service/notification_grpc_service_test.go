@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/repository"
+	"notification-service/pkg/proto"
+	"notification-service/pkg/tenant"
+)
+
+func newMemoryNotificationGRPCService(t *testing.T, ctx context.Context) *NotificationGRPCService {
+	t.Helper()
+
+	preferenceRepository := repository.NewPreferenceMemoryRepository()
+	err := preferenceRepository.Create(ctx, &domain.Preference{
+		UserID:   "user-1",
+		Email:    "member@example.com",
+		Channels: []constant.Channel{constant.ChannelEmail},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	return NewNotificationGRPCService(repository.NewNotificationMemoryRepository(), preferenceRepository)
+}
+
+func TestNotificationGRPCService_UpdatePreference(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *proto.UpdatePreferenceRequest
+		wantChannels []string
+		wantCode     codes.Code
+	}{
+		{
+			name:         "every channel",
+			request:      &proto.UpdatePreferenceRequest{UserId: "user-1", Phone: "+33600000000", WebhookUrl: "https://example.com/hook", Channels: []string{"sms", "email", "sms", "webhook"}},
+			wantChannels: []string{"sms", "email", "webhook"},
+		},
+		{
+			name:         "no channel",
+			request:      &proto.UpdatePreferenceRequest{UserId: "user-1"},
+			wantChannels: []string{},
+		},
+		{
+			name:     "channel without address",
+			request:  &proto.UpdatePreferenceRequest{UserId: "user-1", Channels: []string{"sms"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown channel",
+			request:  &proto.UpdatePreferenceRequest{UserId: "user-1", Channels: []string{"pigeon"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid webhook URL",
+			request:  &proto.UpdatePreferenceRequest{UserId: "user-1", WebhookUrl: "ftp://example.com", Channels: []string{"webhook"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown user",
+			request:  &proto.UpdatePreferenceRequest{UserId: "user-2"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tenant.NewContext(context.Background(), "tenant-1")
+			s := newMemoryNotificationGRPCService(t, ctx)
+
+			_, err := s.UpdatePreference(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdatePreference() error = %v, want %v", err, tt.wantCode)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+
+			got, err := s.FindPreference(ctx, &proto.FindPreferenceRequest{UserId: "user-1"})
+			if err != nil {
+				t.Fatalf("FindPreference() error = %v", err)
+			}
+			if got.Email != "member@example.com" || got.Phone != tt.request.Phone || len(got.Channels) != len(tt.wantChannels) {
+				t.Fatalf("preference = %v, want the channels %v", got, tt.wantChannels)
+			}
+			for i := range got.Channels {
+				if got.Channels[i] != tt.wantChannels[i] {
+					t.Errorf("channels = %v, want %v", got.Channels, tt.wantChannels)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationGRPCService_RetryNotification(t *testing.T) {
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+	s := newMemoryNotificationGRPCService(t, ctx)
+
+	failed := domain.Notification{UserID: "user-1", Channel: constant.ChannelEmail, Key: "failed", Status: constant.DeliveryFailed, Attempts: 5, LastError: "timeout"}
+	sent := domain.Notification{UserID: "user-1", Channel: constant.ChannelEmail, Key: "sent", Status: constant.DeliverySent, Attempts: 1}
+	for _, n := range []*domain.Notification{&failed, &sent} {
+		if err := s.notificationRepository.Create(ctx, n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	got, err := s.RetryNotification(ctx, &proto.RetryNotificationRequest{Id: failed.ID.Hex()})
+	if err != nil {
+		t.Fatalf("RetryNotification() error = %v", err)
+	}
+	if got.Status != string(constant.DeliveryPending) || got.Attempts != 0 {
+		t.Errorf("notification = %s after %d attempts, want pending without attempts", got.Status, got.Attempts)
+	}
+
+	due, _ := s.notificationRepository.FetchDue(ctx, time.Now(), 10)
+	if len(due) != 1 || due[0].ID != failed.ID {
+		t.Errorf("due notifications = %v, want the retried one", due)
+	}
+
+	if _, err = s.RetryNotification(ctx, &proto.RetryNotificationRequest{Id: sent.ID.Hex()}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("RetryNotification() of a sent notification error = %v, want FailedPrecondition", err)
+	}
+	otherCtx := tenant.NewContext(context.Background(), "tenant-2")
+	if _, err = s.RetryNotification(otherCtx, &proto.RetryNotificationRequest{Id: failed.ID.Hex()}); status.Code(err) != codes.NotFound {
+		t.Errorf("RetryNotification() of another tenant error = %v, want NotFound", err)
+	}
+}
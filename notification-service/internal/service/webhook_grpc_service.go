@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/event"
+	"notification-service/internal/webhook"
+	"notification-service/pkg/proto"
+)
+
+// minSecretLength is the shortest secret given by an admin, a generated one is longer
+const minSecretLength = 16
+
+type WebhookGRPCService struct {
+	proto.UnimplementedWebhookServiceServer
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	dispatcher         *webhook.Dispatcher
+}
+
+func NewWebhookGRPCService(
+	webhookRepository domain.WebhookRepository,
+	deliveryRepository domain.WebhookDeliveryRepository,
+	dispatcher *webhook.Dispatcher,
+) *WebhookGRPCService {
+	return &WebhookGRPCService{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		dispatcher:         dispatcher,
+	}
+}
+
+// CreateWebhook creates an active webhook, it is the only response with the secret besides a rotation
+func (s *WebhookGRPCService) CreateWebhook(ctx context.Context, request *proto.CreateWebhookRequest) (*proto.Webhook, error) {
+	eventTypes, err := checkWebhook(request.Url, request.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		if secret, err = webhook.NewSecret(); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	} else if len(secret) < minSecretLength {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook secret must have at least %d characters", minSecretLength)
+	}
+
+	w := domain.Webhook{
+		URL:        request.Url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+	}
+	if err = s.webhookRepository.Create(ctx, &w); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoWebhook := toProtoWebhook(w)
+	protoWebhook.Secret = w.Secret
+	return protoWebhook, nil
+}
+
+// UpdateWebhook replaces the URL, the event types and the state of the webhook, and rotates its secret on request
+func (s *WebhookGRPCService) UpdateWebhook(ctx context.Context, request *proto.UpdateWebhookRequest) (*proto.Webhook, error) {
+	w, err := s.findWebhook(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := checkWebhook(request.Url, request.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	w.URL = request.Url
+	w.EventTypes = eventTypes
+	w.Active = request.Active
+	if request.RotateSecret {
+		if w.Secret, err = webhook.NewSecret(); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	if err = s.webhookRepository.Update(ctx, &w); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoWebhook := toProtoWebhook(w)
+	if request.RotateSecret {
+		protoWebhook.Secret = w.Secret
+	}
+	return protoWebhook, nil
+}
+
+// DeleteWebhook stops the deliveries of the webhook, its delivery log is kept
+func (s *WebhookGRPCService) DeleteWebhook(ctx context.Context, request *proto.DeleteWebhookRequest) (*proto.Webhook, error) {
+	w, err := s.findWebhook(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.webhookRepository.Delete(ctx, &w); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoWebhook(w), nil
+}
+
+func (s *WebhookGRPCService) FetchWebhook(ctx context.Context, request *proto.FetchWebhookRequest) (*proto.FetchWebhookResponse, error) {
+	page, limit := request.GetPagination().GetPage(), request.GetPagination().GetLimit()
+	fetchFilter := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+
+	webhooks, err := s.webhookRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoWebhooks := make([]*proto.Webhook, 0)
+	for _, w := range webhooks {
+		protoWebhooks = append(protoWebhooks, toProtoWebhook(w))
+	}
+
+	total, err := s.webhookRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.FetchWebhookResponse{
+		Pagination: paginationResponse(page, limit, total),
+		Webhooks:   protoWebhooks,
+	}, nil
+}
+
+// FetchWebhookDelivery returns the delivery log, the newest first. The failed deliveries are the dead letters.
+func (s *WebhookGRPCService) FetchWebhookDelivery(ctx context.Context, request *proto.FetchWebhookDeliveryRequest) (*proto.FetchWebhookDeliveryResponse, error) {
+	page, limit := request.GetPagination().GetPage(), request.GetPagination().GetLimit()
+	fetchFilter := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+	if webhookID := request.WebhookId; webhookID != "" {
+		fetchFilter["webhook_id"] = webhookID
+	}
+	if eventType := request.EventType; eventType != "" {
+		fetchFilter["event_type"] = eventType
+	}
+	if requestStatus := request.Status; requestStatus != "" {
+		fetchFilter["status"] = requestStatus
+	}
+
+	deliveries, err := s.deliveryRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoDeliveries := make([]*proto.WebhookDelivery, 0)
+	for _, delivery := range deliveries {
+		protoDeliveries = append(protoDeliveries, toProtoWebhookDelivery(delivery))
+	}
+
+	total, err := s.deliveryRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.FetchWebhookDeliveryResponse{
+		Pagination: paginationResponse(page, limit, total),
+		Deliveries: protoDeliveries,
+	}, nil
+}
+
+func (s *WebhookGRPCService) RedeliverWebhookDelivery(ctx context.Context, request *proto.RedeliverWebhookDeliveryRequest) (*proto.WebhookDelivery, error) {
+	delivery, err := s.deliveryRepository.FindByID(ctx, request.Id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Errorf(codes.NotFound, "webhook delivery with %s ID not found", request.Id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if delivery.Status != constant.DeliveryFailed {
+		return nil, status.Errorf(codes.FailedPrecondition, "webhook delivery is %s, only a failed delivery is sent again", delivery.Status)
+	}
+
+	webhook.Redeliver(&delivery, time.Now())
+	if err = s.deliveryRepository.Update(ctx, &delivery); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoWebhookDelivery(delivery), nil
+}
+
+// SendTestWebhookEvent returns the delivery of the test event after its first attempt, so its log tells whether the
+// webhook accepted it
+func (s *WebhookGRPCService) SendTestWebhookEvent(ctx context.Context, request *proto.SendTestWebhookEventRequest) (*proto.WebhookDelivery, error) {
+	w, err := s.findWebhook(ctx, request.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+	if !w.Active {
+		return nil, status.Error(codes.FailedPrecondition, "webhook is paused")
+	}
+
+	delivery, err := s.dispatcher.SendTest(ctx, w, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProtoWebhookDelivery(delivery), nil
+}
+
+func (s *WebhookGRPCService) findWebhook(ctx context.Context, id string) (domain.Webhook, error) {
+	w, err := s.webhookRepository.FindByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Webhook{}, status.Errorf(codes.NotFound, "webhook with %s ID not found", id)
+	}
+	if err != nil {
+		return domain.Webhook{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return w, nil
+}
+
+// checkWebhook checks the URL and the event types of a webhook, and returns the event types without duplicates
+func checkWebhook(rawURL string, requestEventTypes []string) ([]string, error) {
+	if !validWebhookURL(rawURL) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook URL: %s", rawURL)
+	}
+	if len(requestEventTypes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "webhook has no event type")
+	}
+
+	eventTypes := make([]string, 0, len(requestEventTypes))
+	for _, eventType := range requestEventTypes {
+		if !containsString(event.Types, eventType) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type: %s", eventType)
+		}
+		if !containsString(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	return eventTypes, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func paginationResponse(page, limit int32, total int) *proto.NotificationPaginationResponse {
+	lastPage := int32(0)
+	if limit > 0 {
+		lastPage = int32(math.Ceil(float64(total) / float64(limit)))
+	}
+
+	return &proto.NotificationPaginationResponse{
+		Limit:    limit,
+		Page:     page,
+		LastPage: lastPage,
+		Total:    int32(total),
+	}
+}
+
+// toProtoWebhook leaves the secret out, it is only set by the responses of a creation or a rotation
+func toProtoWebhook(w domain.Webhook) *proto.Webhook {
+	return &proto.Webhook{
+		Id:         w.ID.Hex(),
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		Active:     w.Active,
+		CreatedAt:  timestamppb.New(w.CreatedAt),
+		UpdatedAt:  timestamppb.New(w.UpdatedAt),
+	}
+}
+
+func toProtoWebhookDelivery(delivery domain.WebhookDelivery) *proto.WebhookDelivery {
+	log := make([]*proto.WebhookAttempt, 0, len(delivery.Log))
+	for _, attempt := range delivery.Log {
+		log = append(log, &proto.WebhookAttempt{
+			AttemptedAt: timestamppb.New(attempt.AttemptedAt),
+			StatusCode:  int32(attempt.StatusCode),
+			Error:       attempt.Error,
+			DurationMs:  attempt.Duration.Milliseconds(),
+		})
+	}
+
+	protoDelivery := &proto.WebhookDelivery{
+		Id:            delivery.ID.Hex(),
+		WebhookId:     delivery.WebhookID,
+		EventId:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        string(delivery.Status),
+		Attempts:      int32(delivery.Attempts),
+		Log:           log,
+		LastError:     delivery.LastError,
+		NextAttemptAt: timestamppb.New(delivery.NextAttemptAt),
+		CreatedAt:     timestamppb.New(delivery.CreatedAt),
+	}
+	if delivery.DeliveredAt != nil {
+		protoDelivery.DeliveredAt = timestamppb.New(*delivery.DeliveredAt)
+	}
+	return protoDelivery
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"notification-service/internal/repository"
+	"notification-service/internal/webhook"
+	"notification-service/pkg/proto"
+	"notification-service/pkg/tenant"
+)
+
+func newMemoryWebhookGRPCService() *WebhookGRPCService {
+	webhookRepository := repository.NewWebhookMemoryRepository()
+	deliveryRepository := repository.NewWebhookDeliveryMemoryRepository()
+	dispatcher := webhook.NewDispatcher(webhookRepository, deliveryRepository, http.DefaultClient, time.Second, 1, time.Minute)
+
+	return NewWebhookGRPCService(webhookRepository, deliveryRepository, dispatcher)
+}
+
+func TestWebhookGRPCService_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        *proto.CreateWebhookRequest
+		wantEventTypes []string
+		wantCode       codes.Code
+	}{
+		{
+			name:           "generated secret",
+			request:        &proto.CreateWebhookRequest{Url: "https://portal.example.com/hook", EventTypes: []string{"lending.created", "lending.finished", "lending.created"}},
+			wantEventTypes: []string{"lending.created", "lending.finished"},
+		},
+		{
+			name:           "given secret",
+			request:        &proto.CreateWebhookRequest{Url: "http://portal.example.com/hook", Secret: "a-long-enough-secret", EventTypes: []string{"book.created"}},
+			wantEventTypes: []string{"book.created"},
+		},
+		{
+			name:     "short secret",
+			request:  &proto.CreateWebhookRequest{Url: "https://portal.example.com/hook", Secret: "short", EventTypes: []string{"book.created"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid URL",
+			request:  &proto.CreateWebhookRequest{Url: "portal.example.com/hook", EventTypes: []string{"book.created"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "no event type",
+			request:  &proto.CreateWebhookRequest{Url: "https://portal.example.com/hook"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown event type",
+			request:  &proto.CreateWebhookRequest{Url: "https://portal.example.com/hook", EventTypes: []string{"lending.lost"}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tenant.NewContext(context.Background(), "tenant-1")
+			s := newMemoryWebhookGRPCService()
+
+			got, err := s.CreateWebhook(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateWebhook() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if !equalStrings(got.EventTypes, tt.wantEventTypes) || !got.Active {
+				t.Errorf("CreateWebhook() event types = %v, active %v, want %v active", got.EventTypes, got.Active, tt.wantEventTypes)
+			}
+			if got.Secret == "" || (tt.request.Secret != "" && got.Secret != tt.request.Secret) {
+				t.Errorf("CreateWebhook() secret = %q, want the given or a generated one", got.Secret)
+			}
+
+			// the secret is only shown once
+			fetched, err := s.FetchWebhook(ctx, &proto.FetchWebhookRequest{})
+			if err != nil {
+				t.Fatalf("FetchWebhook() error = %v", err)
+			}
+			if len(fetched.Webhooks) != 1 || fetched.Webhooks[0].Secret != "" {
+				t.Errorf("FetchWebhook() = %v, want the webhook without its secret", fetched.Webhooks)
+			}
+		})
+	}
+}
+
+func TestWebhookGRPCService_UpdateWebhook(t *testing.T) {
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+	s := newMemoryWebhookGRPCService()
+
+	created, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{Url: "https://portal.example.com/hook", EventTypes: []string{"book.created"}})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+
+	paused, err := s.UpdateWebhook(ctx, &proto.UpdateWebhookRequest{Id: created.Id, Url: created.Url, EventTypes: []string{"user.deleted"}})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+	if paused.Active || paused.Secret != "" || !equalStrings(paused.EventTypes, []string{"user.deleted"}) {
+		t.Errorf("UpdateWebhook() = %v, want paused for user.deleted without secret", paused)
+	}
+	if _, err = s.SendTestWebhookEvent(ctx, &proto.SendTestWebhookEventRequest{WebhookId: created.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("SendTestWebhookEvent() of a paused webhook error = %v, want FailedPrecondition", err)
+	}
+
+	rotated, err := s.UpdateWebhook(ctx, &proto.UpdateWebhookRequest{Id: created.Id, Url: created.Url, EventTypes: created.EventTypes, Active: true, RotateSecret: true})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+	if !rotated.Active || rotated.Secret == "" || rotated.Secret == created.Secret {
+		t.Errorf("UpdateWebhook() = %v, want active with a new secret", rotated)
+	}
+
+	if _, err = s.DeleteWebhook(ctx, &proto.DeleteWebhookRequest{Id: created.Id}); err != nil {
+		t.Fatalf("DeleteWebhook() error = %v", err)
+	}
+	if _, err = s.UpdateWebhook(ctx, &proto.UpdateWebhookRequest{Id: created.Id, Url: created.Url, EventTypes: created.EventTypes}); status.Code(err) != codes.NotFound {
+		t.Errorf("UpdateWebhook() of a deleted webhook error = %v, want NotFound", err)
+	}
+}
+
+func TestWebhookGRPCService_RedeliverWebhookDelivery(t *testing.T) {
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+	s := newMemoryWebhookGRPCService()
+
+	responses := []int{http.StatusInternalServerError, http.StatusNoContent}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(responses[0])
+		responses = responses[1:]
+	}))
+	defer server.Close()
+
+	created, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{Url: server.URL, EventTypes: []string{"book.created"}})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+
+	// the dispatcher has one attempt, so the failed test event is a dead letter at once
+	delivery, err := s.SendTestWebhookEvent(ctx, &proto.SendTestWebhookEventRequest{WebhookId: created.Id})
+	if err != nil {
+		t.Fatalf("SendTestWebhookEvent() error = %v", err)
+	}
+	if delivery.Status != "FAILED" || len(delivery.Log) != 1 || delivery.Log[0].StatusCode != http.StatusInternalServerError {
+		t.Fatalf("SendTestWebhookEvent() = %v, want failed with a 500 response", delivery)
+	}
+
+	deadLetters, err := s.FetchWebhookDelivery(ctx, &proto.FetchWebhookDeliveryRequest{WebhookId: created.Id, Status: "FAILED"})
+	if err != nil {
+		t.Fatalf("FetchWebhookDelivery() error = %v", err)
+	}
+	if len(deadLetters.Deliveries) != 1 || deadLetters.Deliveries[0].Id != delivery.Id {
+		t.Fatalf("FetchWebhookDelivery() = %v, want the test event", deadLetters.Deliveries)
+	}
+
+	redelivered, err := s.RedeliverWebhookDelivery(ctx, &proto.RedeliverWebhookDeliveryRequest{Id: delivery.Id})
+	if err != nil {
+		t.Fatalf("RedeliverWebhookDelivery() error = %v", err)
+	}
+	if redelivered.Status != "PENDING" || redelivered.Attempts != 0 || len(redelivered.Log) != 1 {
+		t.Errorf("RedeliverWebhookDelivery() = %v, want pending with its log", redelivered)
+	}
+	if _, err = s.RedeliverWebhookDelivery(ctx, &proto.RedeliverWebhookDeliveryRequest{Id: delivery.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("RedeliverWebhookDelivery() of a pending delivery error = %v, want FailedPrecondition", err)
+	}
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/tenant"
+)
+
+const (
+	// dispatchBatchSize is the number of deliveries read at once
+	dispatchBatchSize = 100
+	// maxResponseSize is the part of a response body read, so the connection is reused
+	maxResponseSize = 64 << 10
+	// maxBackoff caps the doubled backoff, so many attempts neither overflow it nor retry right away
+	maxBackoff = 24 * time.Hour
+)
+
+// errWebhookGone fails the deliveries of a deleted webhook without a request
+var errWebhookGone = errors.New("webhook is deleted")
+
+// errWebhookPaused fails the deliveries of a paused webhook, they are sent again on request once it is active
+var errWebhookPaused = errors.New("webhook is paused")
+
+// Dispatcher sends the pending deliveries. A failed delivery is retried after a backoff doubled on every attempt up
+// to maxBackoff, the delivery is failed once it is out of attempts.
+type Dispatcher struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	client             *http.Client
+	interval           time.Duration
+	maxAttempts        int
+	backoff            time.Duration
+}
+
+func NewDispatcher(webhookRepository domain.WebhookRepository, deliveryRepository domain.WebhookDeliveryRepository, client *http.Client, interval time.Duration, maxAttempts int, backoff time.Duration) *Dispatcher {
+	return &Dispatcher{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		client:             client,
+		interval:           interval,
+		maxAttempts:        maxAttempts,
+		backoff:            backoff,
+	}
+}
+
+// Run dispatches the due deliveries at every interval until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Ctx(ctx).Warn().Err(err).Msg("Error dispatching webhook deliveries")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends the deliveries due at now and returns the number of accepted deliveries. Every sent or retried
+// delivery leaves the due ones, so it runs until none is due.
+func (d *Dispatcher) DispatchDue(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	for {
+		deliveries, err := d.deliveryRepository.FetchDue(ctx, now, dispatchBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for i := range deliveries {
+			deliveryCtx := tenant.NewContext(ctx, deliveries[i].Meta.TenantID)
+			ok, err := d.Deliver(deliveryCtx, &deliveries[i], now)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if len(deliveries) < dispatchBatchSize || ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+	}
+}
+
+// Deliver sends the delivery to its webhook once and stores the attempt, it tells whether the webhook accepted it.
+// The context is in the tenant of the delivery.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery *domain.WebhookDelivery, now time.Time) (bool, error) {
+	webhook, err := d.webhookRepository.FindByID(ctx, delivery.WebhookID)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		err = errWebhookGone
+	case err != nil:
+		return false, err
+	case !webhook.Active:
+		err = errWebhookPaused
+	default:
+		err = d.post(ctx, webhook, delivery)
+	}
+	if errors.Is(err, errWebhookGone) || errors.Is(err, errWebhookPaused) {
+		delivery.Log = append(delivery.Log, domain.WebhookAttempt{AttemptedAt: time.Now(), Error: err.Error()})
+	}
+
+	delivery.Attempts++
+	switch {
+	case err == nil:
+		deliveredAt := time.Now()
+		delivery.Status = constant.DeliverySent
+		delivery.DeliveredAt = &deliveredAt
+		delivery.LastError = ""
+	case delivery.Attempts >= d.maxAttempts || errors.Is(err, errWebhookGone) || errors.Is(err, errWebhookPaused):
+		delivery.Status = constant.DeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(retryDelay(d.backoff, delivery.Attempts))
+	}
+
+	if err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("webhook_delivery_id", delivery.ID.Hex()).Int("attempts", delivery.Attempts).
+			Str("status", string(delivery.Status)).Msg("Error delivering webhook")
+	}
+
+	return err == nil, d.deliveryRepository.Update(ctx, delivery)
+}
+
+// retryDelay is the backoff doubled for every attempt after the first one, up to maxBackoff
+func retryDelay(backoff time.Duration, attempts int) time.Duration {
+	delay := backoff
+	for i := 1; i < attempts && delay > 0 && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// post sends the signed payload and logs the attempt in the delivery, a webhook accepts it with a 2xx status
+func (d *Dispatcher) post(ctx context.Context, webhook domain.Webhook, delivery *domain.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	start := time.Now()
+	attempt := domain.WebhookAttempt{AttemptedAt: start}
+	defer func() {
+		attempt.Duration = time.Since(start)
+		delivery.Log = append(delivery.Log, attempt)
+	}()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return err
+	}
+	timestamp := start.Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		attempt.Error = err.Error()
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(response.Body, maxResponseSize))
+
+	attempt.StatusCode = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		err = fmt.Errorf("webhook responded %s", response.Status)
+		attempt.Error = err.Error()
+		return err
+	}
+	return nil
+}
+
+// SendTest delivers a test event to the webhook at once, a failed test event is then retried like the others.
+// The context is in the tenant of the webhook.
+func (d *Dispatcher) SendTest(ctx context.Context, webhook domain.Webhook, now time.Time) (domain.WebhookDelivery, error) {
+	eventID := primitive.NewObjectID().Hex()
+	payload, err := newTestPayload(eventID, tenant.FromContext(ctx), webhook.ID.Hex(), now)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	delivery := domain.WebhookDelivery{
+		WebhookID:     webhook.ID.Hex(),
+		EventID:       eventID,
+		EventType:     TestEventType,
+		Payload:       string(payload),
+		Status:        constant.DeliveryPending,
+		NextAttemptAt: now,
+	}
+	if err = d.deliveryRepository.Create(ctx, &delivery); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	_, err = d.Deliver(ctx, &delivery, now)
+	return delivery, err
+}
+
+// Redeliver makes a failed delivery pending again with all its attempts, it is sent on the next dispatch.
+// Its log is kept.
+func Redeliver(delivery *domain.WebhookDelivery, now time.Time) {
+	delivery.Status = constant.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+}
@@ -0,0 +1,334 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/event"
+	"notification-service/internal/repository"
+	"notification-service/pkg/proto"
+	"notification-service/pkg/tenant"
+)
+
+const secret = "webhook-secret"
+
+// receiver is a webhook endpoint failing its first requests, it keeps the bodies of the requests with a valid
+// signature
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	bodies   []Payload
+	invalid  int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(request.Body)
+	timestamp, _ := strconv.ParseInt(request.Header.Get(HeaderTimestamp), 10, 64)
+	if !Verify(secret, request.Header.Get(HeaderSignature), timestamp, body) {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload Payload
+	_ = json.Unmarshal(body, &payload)
+	if request.Header.Get(HeaderEvent) != payload.Type {
+		r.invalid++
+	}
+	r.bodies = append(r.bodies, payload)
+}
+
+type fixture struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	handler            *Handler
+	dispatcher         *Dispatcher
+}
+
+func newFixture(maxAttempts int) *fixture {
+	webhookRepository := repository.NewWebhookMemoryRepository()
+	deliveryRepository := repository.NewWebhookDeliveryMemoryRepository()
+
+	return &fixture{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		handler:            NewHandler(webhookRepository, deliveryRepository),
+		dispatcher:         NewDispatcher(webhookRepository, deliveryRepository, http.DefaultClient, time.Second, maxAttempts, time.Minute),
+	}
+}
+
+func (f *fixture) createWebhook(t *testing.T, ctx context.Context, url string, eventTypes ...string) domain.Webhook {
+	t.Helper()
+
+	webhook := domain.Webhook{URL: url, Secret: secret, EventTypes: eventTypes, Active: true}
+	if err := f.webhookRepository.Create(ctx, &webhook); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return webhook
+}
+
+func (f *fixture) deliveries(t *testing.T, ctx context.Context) []domain.WebhookDelivery {
+	t.Helper()
+
+	deliveries, err := f.deliveryRepository.Fetch(ctx, map[string]interface{}{"limit": int32(100)})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	return deliveries
+}
+
+func lendingCreated(t *testing.T, ctx context.Context, id string) *proto.Event {
+	t.Helper()
+
+	payload, err := anypb.New(&proto.LendingCreated{LendingId: "lending-1", UserId: "user-1", BookId: "book-1"})
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	return &proto.Event{
+		Id:          id,
+		Type:        event.LendingCreated,
+		Version:     1,
+		Source:      "lending-service",
+		TenantId:    tenant.FromContext(ctx),
+		AggregateId: "lending-1",
+		OccurredAt:  timestamppb.Now(),
+		Payload:     payload,
+	}
+}
+
+func TestHandler_Handle(t *testing.T) {
+	f := newFixture(3)
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+
+	lendings := f.createWebhook(t, ctx, "http://portal.test/lendings", event.LendingCreated, event.LendingFinished)
+	f.createWebhook(t, ctx, "http://portal.test/books", event.BookCreated)
+	paused := f.createWebhook(t, ctx, "http://portal.test/paused", event.LendingCreated)
+	paused.Active = false
+	if err := f.webhookRepository.Update(ctx, &paused); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	// the webhooks of the other tenants never receive the events of the tenant
+	f.createWebhook(t, tenant.NewContext(context.Background(), "tenant-2"), "http://other.test", event.LendingCreated)
+
+	// the event is received twice, like after a redelivery of the broker
+	e := lendingCreated(t, ctx, "event-1")
+	for i := 0; i < 2; i++ {
+		if err := f.handler.Handle(ctx, e); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+
+	deliveries := f.deliveries(t, ctx)
+	if len(deliveries) != 1 {
+		t.Fatalf("deliveries = %+v, want one to %s", deliveries, lendings.URL)
+	}
+	if got := deliveries[0]; got.WebhookID != lendings.ID.Hex() || got.EventID != "event-1" || got.Status != constant.DeliveryPending {
+		t.Errorf("delivery = %+v, want event-1 pending for %s", got, lendings.ID.Hex())
+	}
+
+	var payload Payload
+	if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(payload.Data, &data); err != nil {
+		t.Fatalf("Unmarshal() data error = %v", err)
+	}
+	if payload.Type != event.LendingCreated || payload.TenantID != "tenant-1" || data["lending_id"] != "lending-1" {
+		t.Errorf("payload = %+v with data %v, want lending.created of lending-1", payload, data)
+	}
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		wantStatus   constant.DeliveryStatus
+		wantAttempts int
+		wantBodies   int
+	}{
+		{name: "accepted at first attempt", failures: 0, wantStatus: constant.DeliverySent, wantAttempts: 1, wantBodies: 1},
+		{name: "accepted after retries", failures: 2, wantStatus: constant.DeliverySent, wantAttempts: 3, wantBodies: 1},
+		{name: "dead letter out of attempts", failures: 3, wantStatus: constant.DeliveryFailed, wantAttempts: 3, wantBodies: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(3)
+			ctx := tenant.NewContext(context.Background(), "tenant-1")
+			r := &receiver{failures: tt.failures}
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			f.createWebhook(t, ctx, server.URL, event.LendingCreated)
+			if err := f.handler.Handle(ctx, lendingCreated(t, ctx, "event-1")); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			// every attempt is dispatched once its backoff is over, the dispatcher runs from another context
+			at := time.Now()
+			for attempt := 0; attempt < 5; attempt++ {
+				if _, err := f.dispatcher.DispatchDue(context.Background(), at); err != nil {
+					t.Fatalf("DispatchDue() error = %v", err)
+				}
+				at = at.Add(time.Hour)
+			}
+
+			got := f.deliveries(t, ctx)[0]
+			if got.Status != tt.wantStatus || got.Attempts != tt.wantAttempts || len(got.Log) != tt.wantAttempts {
+				t.Errorf("delivery = %s after %d attempts with %d logged, want %s after %d", got.Status, got.Attempts,
+					len(got.Log), tt.wantStatus, tt.wantAttempts)
+			}
+			for i, attempt := range got.Log {
+				wantCode := http.StatusOK
+				if i < tt.failures {
+					wantCode = http.StatusServiceUnavailable
+				}
+				if attempt.StatusCode != wantCode || (attempt.Error == "") == (i < tt.failures) {
+					t.Errorf("attempt %d = %+v, want status %d", i, attempt, wantCode)
+				}
+			}
+			if len(r.bodies) != tt.wantBodies || r.invalid != 0 {
+				t.Fatalf("received %d payloads and %d invalid ones, want %d", len(r.bodies), r.invalid, tt.wantBodies)
+			}
+			if tt.wantBodies > 0 && r.bodies[0].ID != "event-1" {
+				t.Errorf("payload = %+v, want event-1", r.bodies[0])
+			}
+		})
+	}
+}
+
+func TestDispatcher_BackoffCapped(t *testing.T) {
+	const attempts = 70
+
+	f := newFixture(attempts + 30)
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+	server := httptest.NewServer(&receiver{failures: attempts})
+	defer server.Close()
+
+	f.createWebhook(t, ctx, server.URL, event.LendingCreated)
+	if err := f.handler.Handle(ctx, lendingCreated(t, ctx, "event-1")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	// past the bits of a duration the backoff stays at its cap, every run retries the delivery once
+	// the times are stored in milliseconds, like in MongoDB
+	at := time.Now().Truncate(time.Millisecond)
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if _, err := f.dispatcher.DispatchDue(context.Background(), at); err != nil {
+			t.Fatalf("DispatchDue() error = %v", err)
+		}
+
+		got := f.deliveries(t, ctx)[0]
+		if got.Attempts != attempt || got.Status != constant.DeliveryPending {
+			t.Fatalf("delivery = %s after %d attempts, want pending after %d", got.Status, got.Attempts, attempt)
+		}
+		if delay := got.NextAttemptAt.Sub(at); delay <= 0 || delay > maxBackoff {
+			t.Fatalf("attempt %d: next attempt in %v, want at most %v", attempt, delay, maxBackoff)
+		}
+		at = at.Add(maxBackoff)
+	}
+}
+
+func TestDispatcher_Redeliver(t *testing.T) {
+	f := newFixture(1)
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+	r := &receiver{failures: 1}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	webhook := f.createWebhook(t, ctx, server.URL)
+	delivery, err := f.dispatcher.SendTest(ctx, webhook, time.Now())
+	if err != nil {
+		t.Fatalf("SendTest() error = %v", err)
+	}
+	if delivery.Status != constant.DeliveryFailed || delivery.EventType != TestEventType {
+		t.Fatalf("SendTest() = %s %s, want a failed test event", delivery.EventType, delivery.Status)
+	}
+
+	Redeliver(&delivery, time.Now())
+	if err = f.deliveryRepository.Update(ctx, &delivery); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err = f.dispatcher.DispatchDue(context.Background(), time.Now()); err != nil {
+		t.Fatalf("DispatchDue() error = %v", err)
+	}
+
+	got, _ := f.deliveryRepository.FindByID(ctx, delivery.ID.Hex())
+	if got.Status != constant.DeliverySent || got.Attempts != 1 || len(got.Log) != 2 {
+		t.Errorf("redelivered = %s after %d attempts with %d logged, want sent at the first attempt with 2 logged",
+			got.Status, got.Attempts, len(got.Log))
+	}
+	if len(r.bodies) != 1 || r.bodies[0].Type != TestEventType || r.bodies[0].AggregateID != webhook.ID.Hex() {
+		t.Errorf("received %+v, want the test event of the webhook", r.bodies)
+	}
+}
+
+func TestDispatcher_DeletedWebhook(t *testing.T) {
+	f := newFixture(5)
+	ctx := tenant.NewContext(context.Background(), "tenant-1")
+
+	webhook := f.createWebhook(t, ctx, "http://portal.test", event.LendingCreated)
+	if err := f.handler.Handle(ctx, lendingCreated(t, ctx, "event-1")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if err := f.webhookRepository.Delete(ctx, &webhook); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := f.dispatcher.DispatchDue(context.Background(), time.Now()); err != nil {
+		t.Fatalf("DispatchDue() error = %v", err)
+	}
+
+	// the deliveries of a deleted webhook are dead letters at once, without a request
+	got := f.deliveries(t, ctx)[0]
+	if got.Status != constant.DeliveryFailed || got.LastError != errWebhookGone.Error() || got.Log[0].StatusCode != 0 {
+		t.Errorf("delivery = %s with error %q, want failed for the deleted webhook", got.Status, got.LastError)
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"event-1"}`)
+	signature := Sign(secret, 1612137600, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      bool
+	}{
+		{name: "same request", secret: secret, timestamp: 1612137600, body: body, want: true},
+		{name: "other secret", secret: "other", timestamp: 1612137600, body: body, want: false},
+		{name: "replayed later", secret: secret, timestamp: 1612137601, body: body, want: false},
+		{name: "changed body", secret: secret, timestamp: 1612137600, body: []byte(`{"id":"event-2"}`), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, signature, tt.timestamp, tt.body); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
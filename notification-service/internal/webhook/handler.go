@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/event"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/proto"
+)
+
+// Handler queues a delivery of every event to the webhooks subscribed to its type
+type Handler struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+}
+
+func NewHandler(webhookRepository domain.WebhookRepository, deliveryRepository domain.WebhookDeliveryRepository) *Handler {
+	return &Handler{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+	}
+}
+
+// Handle is an event.Handler. An event received again is not queued twice for a webhook.
+func (h *Handler) Handle(ctx context.Context, e *proto.Event) error {
+	if e.Version > event.SupportedVersion {
+		logger.Ctx(ctx).Warn().Str("event_id", e.Id).Str("event_type", e.Type).Int32("version", e.Version).
+			Msg("Skipping webhook deliveries of event of unsupported version")
+		return nil
+	}
+
+	webhooks, err := h.webhookRepository.FetchSubscribed(ctx, e.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := NewPayload(e)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, webhook := range webhooks {
+		err = h.deliveryRepository.Create(ctx, &domain.WebhookDelivery{
+			WebhookID:     webhook.ID.Hex(),
+			EventID:       e.Id,
+			EventType:     e.Type,
+			Payload:       string(payload),
+			Status:        constant.DeliveryPending,
+			NextAttemptAt: now,
+		})
+		if err != nil && !strings.Contains(err.Error(), constant.WebhookDeliveryEventUniqueIndex) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"notification-service/pkg/proto"
+)
+
+// TestEventType is the type of the test events sent on request to check a webhook
+const TestEventType = "webhook.test"
+
+// Payload is the JSON body of a delivery, Data is the payload of the event type with the field names of its proto
+type Payload struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Version     int32           `json:"version"`
+	Source      string          `json:"source"`
+	TenantID    string          `json:"tenant_id"`
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// NewPayload returns the body of the deliveries of an event
+func NewPayload(e *proto.Event) ([]byte, error) {
+	message, err := e.Payload.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Payload{
+		ID:          e.Id,
+		Type:        e.Type,
+		Version:     e.Version,
+		Source:      e.Source,
+		TenantID:    e.TenantId,
+		AggregateID: e.AggregateId,
+		OccurredAt:  e.OccurredAt.AsTime(),
+		Data:        data,
+	})
+}
+
+// newTestPayload returns the body of a test event of the webhook
+func newTestPayload(eventID, tenantID, webhookID string, now time.Time) ([]byte, error) {
+	data, err := json.Marshal(map[string]string{"webhook_id": webhookID})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Payload{
+		ID:          eventID,
+		Type:        TestEventType,
+		Version:     1,
+		Source:      "notification-service",
+		TenantID:    tenantID,
+		AggregateID: webhookID,
+		OccurredAt:  now,
+		Data:        data,
+	})
+}
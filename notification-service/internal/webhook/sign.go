@@ -0,0 +1,49 @@
+// Package webhook delivers the events to the webhooks of the admins. Every delivery is a signed JSON POST, retried
+// with an exponential backoff until it is accepted or out of attempts.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// the headers of a delivery request
+const (
+	HeaderEvent     = "X-Library-Event"
+	HeaderDelivery  = "X-Library-Delivery"
+	HeaderTimestamp = "X-Library-Timestamp"
+	HeaderSignature = "X-Library-Signature"
+)
+
+// signaturePrefix names the algorithm of the signature, so another one can be added without breaking the receivers
+const signaturePrefix = "sha256="
+
+// secretSize is the number of random bytes of a generated secret
+const secretSize = 32
+
+// Sign returns the signature of a body sent at timestamp, in Unix seconds: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed by the secret. The receivers reject the old timestamps, so a delivery cannot be replayed.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether the signature is the one of the body sent at timestamp, in constant time
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// NewSecret returns a random secret for a webhook created without one
+func NewSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Config is the configuration of the app
+type Config struct {
+	GRPCPort        string `yaml:"grpc_port" env:"GRPC_PORT" default:":8000" validate:"required,addr"`
+	MetricsHTTPPort string `yaml:"metrics_http_port" env:"METRICS_HTTP_PORT" default:":9100" validate:"required,addr"`
+	// AllowPendingMigrations starts the app even when some migrations have not run
+	AllowPendingMigrations bool `yaml:"allow_pending_migrations" env:"ALLOW_PENDING_MIGRATIONS"`
+
+	MongoDB    MongoDB    `yaml:"mongodb" env:"MONGODB_"`
+	TLS        TLS        `yaml:"tls" env:"TLS_"`
+	Events     Events     `yaml:"events" env:"EVENT_"`
+	Senders    Senders    `yaml:"senders" env:"SENDER_"`
+	SMTP       SMTP       `yaml:"smtp" env:"SMTP_"`
+	Scheduler  Scheduler  `yaml:"scheduler" env:"SCHEDULER_"`
+	Dispatcher Dispatcher `yaml:"dispatcher" env:"DISPATCHER_"`
+	Webhooks   Webhooks   `yaml:"webhooks" env:"WEBHOOK_"`
+	Log        Log        `yaml:"log"`
+	Tracing    Tracing    `yaml:"tracing"`
+	PProf      PProf      `yaml:"pprof"`
+}
+
+// CommandConfig is the configuration of the migration command
+type CommandConfig struct {
+	MongoDB MongoDB `yaml:"mongodb" env:"MONGODB_"`
+}
+
+type MongoDB struct {
+	URI      string `yaml:"uri" env:"URI" validate:"required,url" secret:"userinfo"`
+	Database string `yaml:"database" env:"DATABASE" validate:"required"`
+}
+
+// TLS secures the gRPC server, the certificate is the identity of the service.
+// The files are read again when they change, so they are rotated without restart.
+type TLS struct {
+	CertFile string `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"KEY_FILE"`
+	// CAFile verifies the client certificates
+	CAFile string `yaml:"ca_file" env:"CA_FILE"`
+	// ClientAuth makes the server require a client certificate signed by the CA, which is mutual TLS
+	ClientAuth bool `yaml:"client_auth" env:"CLIENT_AUTH"`
+}
+
+// Events consumes the domain events of the other services from a durable JetStream consumer, so the events
+// published while the service is down are received when it starts. With the none broker no event is received.
+type Events struct {
+	Broker        string `yaml:"broker" env:"BROKER" default:"none" validate:"oneof=none nats"`
+	NATSURL       string `yaml:"nats_url" env:"NATS_URL" default:"nats://127.0.0.1:4222" validate:"url"`
+	SubjectPrefix string `yaml:"subject_prefix" env:"SUBJECT_PREFIX" default:"library" validate:"required"`
+	// Stream is created over every subject of the prefix when it does not exist
+	Stream  string `yaml:"stream" env:"STREAM" default:"LIBRARY" validate:"required"`
+	Durable string `yaml:"durable" env:"DURABLE" default:"notification-service" validate:"required"`
+}
+
+// Senders selects how each channel is delivered. The log sender appends the messages as JSON lines to LogFile,
+// or to the standard output without it, for development and tests.
+type Senders struct {
+	Email   string `yaml:"email" env:"EMAIL" default:"log" validate:"oneof=log smtp"`
+	SMS     string `yaml:"sms" env:"SMS" default:"log" validate:"oneof=log"`
+	Webhook string `yaml:"webhook" env:"WEBHOOK" default:"log" validate:"oneof=log http"`
+	LogFile string `yaml:"log_file" env:"LOG_FILE"`
+	// Timeout is the time to deliver one message
+	Timeout time.Duration `yaml:"timeout" env:"TIMEOUT" default:"10s"`
+}
+
+// SMTP is the server of the smtp email sender, it authenticates with PLAIN when Username is set
+type SMTP struct {
+	Addr     string `yaml:"addr" env:"ADDR" default:"127.0.0.1:1025" validate:"addr"`
+	Username string `yaml:"username" env:"USERNAME"`
+	Password string `yaml:"password" env:"PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"FROM" default:"library@localhost" validate:"required"`
+}
+
+// Scheduler creates the due date reminders and the overdue notices of the lendings
+type Scheduler struct {
+	Interval time.Duration `yaml:"interval" env:"INTERVAL" default:"1m"`
+	// DueSoon is how long before the return date the reminder is sent
+	DueSoon time.Duration `yaml:"due_soon" env:"DUE_SOON" default:"72h"`
+}
+
+// Dispatcher sends the pending notifications, a failed delivery is retried after Backoff, doubled for every
+// attempt up to a day, until MaxAttempts
+type Dispatcher struct {
+	Interval    time.Duration `yaml:"interval" env:"INTERVAL" default:"5s"`
+	MaxAttempts int           `yaml:"max_attempts" env:"MAX_ATTEMPTS" default:"5"`
+	Backoff     time.Duration `yaml:"backoff" env:"BACKOFF" default:"30s"`
+}
+
+// Webhooks sends the events to the webhooks of the admins, a failed delivery is retried after Backoff, doubled for
+// every attempt up to a day, until MaxAttempts, then it is a dead letter
+type Webhooks struct {
+	Interval    time.Duration `yaml:"interval" env:"INTERVAL" default:"5s"`
+	MaxAttempts int           `yaml:"max_attempts" env:"MAX_ATTEMPTS" default:"8"`
+	Backoff     time.Duration `yaml:"backoff" env:"BACKOFF" default:"30s"`
+	// Timeout is the time of one request to a webhook
+	Timeout time.Duration `yaml:"timeout" env:"TIMEOUT" default:"10s"`
+}
+
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info" validate:"oneof=trace debug info warn error"`
+}
+
+// Tracing selects the exporter of the traces, the OTLP exporter is configured by the standard variables
+// like OTEL_EXPORTER_OTLP_ENDPOINT
+type Tracing struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" default:"none" validate:"oneof=none otlp stdout"`
+}
+
+type PProf struct {
+	Enable         bool   `yaml:"enable" env:"ENABLE_PPROF"`
+	HTTPPort       string `yaml:"http_port" env:"PPROF_HTTP_PORT" default:":6060" validate:"addr"`
+	FolderPath     string `yaml:"folder_path" env:"PPROF_FOLDER_PATH" default:"profile"`
+	CPUFileName    string `yaml:"cpu_file_name" env:"CPU_PPROF_FILE_NAME"`
+	MemoryFileName string `yaml:"memory_file_name" env:"MEMORY_PPROF_FILE_NAME"`
+}
+
+// Load reads the configuration of the app, see load
+func Load(path string) (Config, error) {
+	var cfg Config
+	err := load(path, &cfg)
+	return cfg, err
+}
+
+// LoadCommand reads the configuration of the command from the environment and the CONFIG_FILE
+func LoadCommand() (CommandConfig, error) {
+	var cfg CommandConfig
+	err := load(os.Getenv("CONFIG_FILE"), &cfg)
+	return cfg, err
+}
@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// level of a completed RPC, the errors caused by the request are warnings
+func level(err error, okLevel zerolog.Level) zerolog.Level {
+	switch status.Code(err) {
+	case codes.OK:
+		return okLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unauthenticated:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+func logRPC(ctx context.Context, message, method string, start time.Time, err error, okLevel zerolog.Level) {
+	Ctx(ctx).WithLevel(level(err, okLevel)).
+		Str("grpc_method", method).
+		Str("grpc_code", status.Code(err).String()).
+		Dur("duration_ms", time.Since(start)).
+		Err(err).
+		Msg(message)
+}
+
+// UnaryServerInterceptor reads the request ID and user sent by the caller, and logs the handled request.
+// It must run after the tenant interceptor to log the tenant of the request.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = fromIncomingContext(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		okLevel := zerolog.InfoLevel
+		// the health checks are polled, so they are not worth an info line each
+		if strings.HasPrefix(info.FullMethod, "/grpc.health.v1.Health/") {
+			okLevel = zerolog.DebugLevel
+		}
+		logRPC(ctx, "gRPC request handled", info.FullMethod, start, err, okLevel)
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := fromIncomingContext(stream.Context())
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+		logRPC(ctx, "gRPC request handled", info.FullMethod, start, err, zerolog.InfoLevel)
+		return err
+	}
+}
+
+// RecoveryUnaryServerInterceptor turns a panic of the handler into an Internal error, so a bad request does not
+// crash the service. It must run after UnaryServerInterceptor to log the panic with the request ID.
+func RecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamServerInterceptor is RecoveryUnaryServerInterceptor for the streams
+func RecoveryStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(stream.Context(), info.FullMethod, r)
+			}
+		}()
+		return handler(srv, stream)
+	}
+}
+
+func recovered(ctx context.Context, method string, r interface{}) error {
+	Ctx(ctx).Error().
+		Str("grpc_method", method).
+		Interface("panic", r).
+		Bytes("stack", debug.Stack()).
+		Msg("gRPC handler panicked")
+	return status.Error(codes.Internal, "internal error")
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryClientInterceptor forwards the request ID and user to the called service, and logs the completed call
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(toOutgoingContext(ctx), method, req, reply, cc, opts...)
+		logRPC(ctx, "gRPC call completed", method, start, err, zerolog.DebugLevel)
+		return err
+	}
+}
+
+// StreamClientInterceptor logs a stream when it is completed, that is when the last response is received
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(toOutgoingContext(ctx), desc, cc, method, opts...)
+		if err != nil {
+			logRPC(ctx, "gRPC call completed", method, start, err, zerolog.DebugLevel)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: stream, ctx: ctx, desc: desc, method: method, start: start}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	desc   *grpc.StreamDesc
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+	case !s.desc.ServerStreams:
+		// a client streaming RPC has a single response
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		logRPC(s.ctx, "gRPC call completed", s.method, s.start, err, zerolog.DebugLevel)
+	})
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	stdlog "log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"notification-service/pkg/tenant"
+)
+
+// Init sets the global logger to write JSON lines at the level, info when it is not a valid level.
+// The standard log package, still used by some libraries, writes through it too.
+func Init(serviceName, levelName string) {
+	level, err := zerolog.ParseLevel(strings.ToLower(levelName))
+	if err != nil || level == zerolog.NoLevel {
+		level = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(level)
+	zerolog.TimeFieldFormat = time.RFC3339Nano
+	zerolog.DurationFieldUnit = time.Millisecond
+
+	log.Logger = zerolog.New(redactWriter{w: os.Stdout}).With().
+		Timestamp().
+		Str("service", serviceName).
+		Logger()
+
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(log.Logger)
+}
+
+// Ctx returns the global logger with the request ID, user, tenant, and trace of the request
+func Ctx(ctx context.Context) *zerolog.Logger {
+	fields := log.With().Str("tenant_id", tenant.FromContext(ctx))
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = fields.Str("request_id", requestID)
+	}
+	if userID := UserIDFromContext(ctx); userID != "" {
+		fields = fields.Str("user_id", userID)
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		fields = fields.Str("trace_id", spanCtx.TraceID().String())
+	}
+
+	logger := fields.Logger()
+	return &logger
+}
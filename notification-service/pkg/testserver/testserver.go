@@ -0,0 +1,120 @@
+// Package testserver serves the notification service backed by in-memory repositories, so the tests spanning the
+// services run in one process without MongoDB and NATS.
+package testserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"notification-service/internal/domain/constant"
+	"notification-service/internal/event"
+	"notification-service/internal/notification"
+	"notification-service/internal/repository"
+	"notification-service/internal/sender"
+	"notification-service/internal/service"
+	"notification-service/internal/webhook"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/proto"
+	"notification-service/pkg/tenant"
+)
+
+// the notifications are created and sent soon after the events
+const (
+	schedulerInterval  = 10 * time.Millisecond
+	dispatcherInterval = 10 * time.Millisecond
+	dueSoon            = 3 * 24 * time.Hour
+	maxAttempts        = 3
+	backoff            = 10 * time.Millisecond
+	sendTimeout        = time.Second
+	eventBufferSize    = 100
+)
+
+// Server is the notification service, its repositories live as long as it
+type Server struct {
+	*grpc.Server
+	events chan *proto.Event
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns the notification service writing the messages of every channel as JSON lines to w. The events are
+// posted to the webhooks of the admins over HTTP, like a test server of net/http/httptest.
+func New(w io.Writer) *Server {
+	notificationRepository := repository.NewNotificationMemoryRepository()
+	preferenceRepository := repository.NewPreferenceMemoryRepository()
+	bookRepository := repository.NewBookMemoryRepository()
+	lendingRepository := repository.NewLendingMemoryRepository()
+	webhookRepository := repository.NewWebhookMemoryRepository()
+	webhookDeliveryRepository := repository.NewWebhookDeliveryMemoryRepository()
+
+	logSender := sender.NewLogSender(w)
+	senders := sender.Router{
+		constant.ChannelEmail:   logSender,
+		constant.ChannelSMS:     logSender,
+		constant.ChannelWebhook: logSender,
+	}
+
+	notifier := notification.NewNotifier(notificationRepository, preferenceRepository)
+	handler := notification.NewHandler(notifier, preferenceRepository, bookRepository, lendingRepository)
+	scheduler := notification.NewScheduler(lendingRepository, bookRepository, notifier, schedulerInterval, dueSoon)
+	dispatcher := notification.NewDispatcher(notificationRepository, senders, dispatcherInterval, maxAttempts, backoff, sendTimeout)
+	webhookHandler := webhook.NewHandler(webhookRepository, webhookDeliveryRepository)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepository, webhookDeliveryRepository, &http.Client{Timeout: sendTimeout},
+		dispatcherInterval, maxAttempts, backoff)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(tenant.UnaryServerInterceptor(), logger.UnaryServerInterceptor(), logger.RecoveryUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(tenant.StreamServerInterceptor(), logger.StreamServerInterceptor(), logger.RecoveryStreamServerInterceptor()),
+	)
+	proto.RegisterNotificationServiceServer(server, service.NewNotificationGRPCService(notificationRepository, preferenceRepository))
+	proto.RegisterWebhookServiceServer(server, service.NewWebhookGRPCService(webhookRepository, webhookDeliveryRepository, webhookDispatcher))
+
+	s := &Server{
+		Server: server,
+		events: make(chan *proto.Event, eventBufferSize),
+	}
+
+	s.ctx, s.stop = context.WithCancel(context.Background())
+	ctx := s.ctx
+	s.wg.Add(4)
+	go func() {
+		defer s.wg.Done()
+		event.Consume(ctx, s.events, event.Handlers(handler.Handle, webhookHandler.Handle))
+	}()
+	go func() {
+		defer s.wg.Done()
+		scheduler.Run(ctx)
+	}()
+	go func() {
+		defer s.wg.Done()
+		dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer s.wg.Done()
+		webhookDispatcher.Run(ctx)
+	}()
+
+	return s
+}
+
+// Receive delivers an event of the other services to the service, like the broker does. The event is dropped once
+// the service is stopped.
+func (s *Server) Receive(e *proto.Event) {
+	select {
+	case s.events <- e:
+	case <-s.ctx.Done():
+	}
+}
+
+// Stop stops serving, handling the events and sending the notifications and the webhook deliveries
+func (s *Server) Stop() {
+	s.Server.Stop()
+	s.stop()
+	s.wg.Wait()
+}
@@ -0,0 +1,38 @@
+#!/bin/sh
+# Generates a development CA and a certificate per service, signed by the CA, into the folder of the first argument.
+# A service certificate is valid as server and as client certificate, for mutual TLS, on its docker host name,
+# localhost, and 127.0.0.1. Running it again keeps the CA and renews the service certificates, which the running
+# services reload without restart.
+set -eu
+
+dir="${1:-certs}"
+mkdir -p "$dir"
+
+if [ ! -f "$dir/ca.pem" ]; then
+  openssl ecparam -name prime256v1 -genkey -noout -out "$dir/ca-key.pem"
+  openssl req -x509 -new -sha256 -days 3650 -key "$dir/ca-key.pem" \
+    -subj "/CN=Book Library Development CA" \
+    -addext "basicConstraints=critical,CA:TRUE" \
+    -addext "keyUsage=critical,keyCertSign,cRLSign" \
+    -out "$dir/ca.pem"
+fi
+
+for service in api-gateway user-service book-service lending-service notification-service; do
+  openssl ecparam -name prime256v1 -genkey -noout -out "$dir/$service-key.pem"
+  openssl req -new -sha256 -key "$dir/$service-key.pem" -subj "/CN=$service" -out "$dir/$service.csr"
+
+  cat > "$dir/$service.ext" <<EXT
+basicConstraints=CA:FALSE
+keyUsage=critical,digitalSignature
+extendedKeyUsage=serverAuth,clientAuth
+subjectAltName=DNS:$service,DNS:localhost,IP:127.0.0.1
+EXT
+  openssl x509 -req -sha256 -days 365 -in "$dir/$service.csr" -CA "$dir/ca.pem" -CAkey "$dir/ca-key.pem" \
+    -CAcreateserial -extfile "$dir/$service.ext" -out "$dir/$service.pem" 2>/dev/null
+
+  rm "$dir/$service.csr" "$dir/$service.ext"
+  # the services of docker-compose read the keys as another user than the host one
+  chmod 644 "$dir/$service-key.pem"
+done
+
+echo "certificates generated in $dir"
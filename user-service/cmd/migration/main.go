@@ -2,11 +2,13 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/joho/godotenv"
-	migrate "github.com/xakep666/mongo-migrate"
 
 	_ "user-service/cmd/migration/script" // migration script
+	"user-service/pkg/config"
+	"user-service/pkg/migration"
 	"user-service/pkg/mongodb"
 )
 
@@ -15,10 +17,13 @@ func init() {
 }
 
 func main() {
-	db := mongodb.GetDatabase()
-	migrate.SetDatabase(db)
+	cfg, err := config.LoadCommand()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mongodb.Configure(cfg.MongoDB.URI, cfg.MongoDB.Database)
 
-	if err := migrate.Up(migrate.AllAvailable); err != nil {
+	if err = migration.Run(mongodb.GetDatabase(), os.Args[1:], os.Stdout); err != nil {
 		log.Fatal(err)
 	}
 }
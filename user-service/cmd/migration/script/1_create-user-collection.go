@@ -20,6 +20,12 @@ func init() {
 		log.Println("success create user collection")
 		return nil
 	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.UserCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop user collection")
 		return nil
 	})
 }
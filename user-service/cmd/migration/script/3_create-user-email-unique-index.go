@@ -28,6 +28,12 @@ func init() {
 		log.Printf("success create %s\n", idx)
 		return nil
 	}, func(db *mongo.Database) error {
+		_, err := db.Collection(constant.UserCollection).Indexes().DropOne(context.TODO(), constant.UserEmailUniqueIndex)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("success drop %s\n", constant.UserEmailUniqueIndex)
 		return nil
 	})
 }
@@ -0,0 +1,117 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+	"user-service/pkg/tenant"
+)
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		ctx := context.TODO()
+
+		err := db.CreateCollection(ctx, constant.TenantCollection)
+		if err != nil {
+			return err
+		}
+
+		opt := options.Index().SetName(constant.TenantCodeUniqueIndex).
+			SetUnique(true)
+		model := mongo.IndexModel{Keys: bson.D{{"code", 1}}, Options: opt}
+		if _, err = db.Collection(constant.TenantCollection).Indexes().CreateOne(ctx, model); err != nil {
+			return err
+		}
+
+		defaultTenant := domain.Tenant{
+			ID:   primitive.NewObjectID(),
+			Code: tenant.Default,
+			Name: "Default",
+		}
+		defaultTenant.Meta.Create()
+		defaultTenant.Meta.TenantID = tenant.Default
+		if _, err = db.Collection(constant.TenantCollection).InsertOne(ctx, &defaultTenant); err != nil {
+			return err
+		}
+
+		// the existing users belong to the default tenant
+		_, err = db.Collection(constant.UserCollection).UpdateMany(ctx,
+			bson.D{{"meta.tenant_id", bson.D{{"$in", bson.A{nil, ""}}}}},
+			bson.D{{"$set", bson.D{{"meta.tenant_id", tenant.Default}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		// an email is unique inside its tenant only
+		if _, err = db.Collection(constant.UserCollection).Indexes().DropOne(ctx, constant.UserEmailUniqueIndex); err != nil {
+			return err
+		}
+		opt = options.Index().SetName(constant.UserTenantEmailUniqueIndex).
+			SetUnique(true)
+		model = mongo.IndexModel{Keys: bson.D{{"meta.tenant_id", 1}, {"email", 1}}, Options: opt}
+		if _, err = db.Collection(constant.UserCollection).Indexes().CreateOne(ctx, model); err != nil {
+			return err
+		}
+
+		log.Println("success create tenant collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		ctx := context.TODO()
+
+		// the users of other tenants would be merged into a single library
+		otherTenant := bson.D{{"meta.tenant_id", bson.D{{"$nin", bson.A{nil, "", tenant.Default}}}}}
+		count, err := db.Collection(constant.UserCollection).CountDocuments(ctx, otherTenant)
+		if err != nil {
+			return err
+		}
+		tenants, err := db.Collection(constant.TenantCollection).
+			CountDocuments(ctx, bson.D{{"code", bson.D{{"$ne", tenant.Default}}}})
+		if err != nil {
+			return err
+		}
+		if count > 0 || tenants > 0 {
+			return fmt.Errorf("other tenants than %s exist, remove them first", tenant.Default)
+		}
+
+		// the super admins provision the tenants, they have no role without them
+		_, err = db.Collection(constant.UserCollection).DeleteMany(ctx, bson.D{{"role", constant.SuperAdminRole}})
+		if err != nil {
+			return err
+		}
+
+		if _, err = db.Collection(constant.UserCollection).Indexes().DropOne(ctx, constant.UserTenantEmailUniqueIndex); err != nil {
+			return err
+		}
+		opt := options.Index().SetName(constant.UserEmailUniqueIndex).
+			SetUnique(true)
+		model := mongo.IndexModel{Keys: bson.D{{"email", 1}}, Options: opt}
+		if _, err = db.Collection(constant.UserCollection).Indexes().CreateOne(ctx, model); err != nil {
+			return err
+		}
+
+		_, err = db.Collection(constant.UserCollection).UpdateMany(ctx,
+			bson.D{{"meta.tenant_id", tenant.Default}},
+			bson.D{{"$unset", bson.D{{"meta.tenant_id", ""}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if err = db.Collection(constant.TenantCollection).Drop(ctx); err != nil {
+			return err
+		}
+
+		log.Println("success drop tenant collection")
+		return nil
+	})
+}
@@ -0,0 +1,46 @@
+package script
+
+import (
+	"context"
+	"log"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"user-service/internal/domain/constant"
+)
+
+// outboxRetentionSeconds keeps the published events for a week, to look into what the consumers received
+const outboxRetentionSeconds = 7 * 24 * 60 * 60
+
+func init() {
+	migrate.Register(func(db *mongo.Database) error {
+		err := db.CreateCollection(context.TODO(), constant.OutboxCollection)
+		if err != nil {
+			return err
+		}
+
+		// the relay reads the pending events, which have no published time, and MongoDB never expires them
+		_, err = db.Collection(constant.OutboxCollection).Indexes().
+			CreateOne(context.TODO(), mongo.IndexModel{
+				Keys:    bson.D{{"published_at", 1}},
+				Options: options.Index().SetName(constant.OutboxPublishedIndex).SetExpireAfterSeconds(outboxRetentionSeconds),
+			})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success create outbox collection")
+		return nil
+	}, func(db *mongo.Database) error {
+		err := db.Collection(constant.OutboxCollection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		log.Println("success drop outbox collection")
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+	"user-service/pkg/config"
+	"user-service/pkg/mongodb"
+	"user-service/pkg/password"
+	"user-service/pkg/seed"
+	"user-service/pkg/tenant"
+)
+
+// configUser is a user seeded with an email of the configuration
+type configUser struct {
+	email string
+	role  string
+}
+
+func init() {
+	_ = godotenv.Load()
+}
+
+func main() {
+	cfg, err := config.LoadCommand()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mongodb.Configure(cfg.MongoDB.URI, cfg.MongoDB.Database)
+
+	options, err := seed.ParseOptions(os.Args[1:], os.Stdout, cfg.SeedProfile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = run(context.Background(), mongodb.GetDatabase(), options, cfg.SeedUsers, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run seeds the users of the profile, the ones of the configuration emails and the members of the lendings
+func run(ctx context.Context, db *mongo.Database, options seed.Options, seedUsers config.SeedUsers, out io.Writer) error {
+	if options.Profile.Name == "" {
+		_, _ = fmt.Fprintln(out, "no seed profile, nothing to seed")
+		return nil
+	}
+
+	err := db.Collection(constant.TenantCollection).
+		FindOne(ctx, bson.D{{"code", options.TenantID}}).Err()
+	if err != nil {
+		return fmt.Errorf("finding tenant %s: %w", options.TenantID, err)
+	}
+
+	if seedUsers.Password == "" {
+		return errors.New("DEFAULT_USER_PASSWORD is required to seed the users")
+	}
+	// every seeded user has the same password, hashing it once keeps the load-test profile fast
+	hashedPassword, err := password.Hash(seedUsers.Password)
+	if err != nil {
+		return err
+	}
+
+	configUsers := []configUser{{seedUsers.AdminEmail, constant.AdminRole}}
+	if options.TenantID == tenant.Default {
+		configUsers = append(configUsers, configUser{seedUsers.SuperAdminEmail, constant.SuperAdminRole})
+	}
+	if options.Profile.Name != seed.Minimal {
+		configUsers = append(configUsers,
+			configUser{seedUsers.LibrarianEmail, constant.LibrarianRole},
+			configUser{seedUsers.MemberEmail, constant.MemberRole},
+		)
+	}
+
+	users := make([]seed.Document, 0, len(configUsers)+options.Profile.Members)
+	for _, configUser := range configUsers {
+		if configUser.email == "" {
+			continue
+		}
+
+		user := newUser(options, options.ID("user-"+configUser.role, 0), configUser.email, configUser.role, hashedPassword)
+		users = append(users, seed.Document{
+			Filter: bson.D{{"meta.tenant_id", options.TenantID}, {"email", configUser.email}},
+			Value:  user,
+		})
+	}
+
+	// the IDs of the members are the ones the lending service seeds the lendings with
+	for i := 0; i < options.Profile.Members; i++ {
+		email := fmt.Sprintf("member%d@seed.lib", i+1)
+		user := newUser(options, options.ID("member", i), email, constant.MemberRole, hashedPassword)
+		users = append(users, seed.Document{Filter: bson.D{{"_id", user.ID}}, Value: user})
+	}
+
+	inserted, err := seed.InsertMissing(ctx, db.Collection(constant.UserCollection), users)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(out, "seeded %d of %d users of the %s profile\n", inserted, len(users), options.Profile.Name)
+	return err
+}
+
+func newUser(options seed.Options, id primitive.ObjectID, email, role, hashedPassword string) domain.User {
+	user := domain.User{
+		ID:             id,
+		Email:          email,
+		HashedPassword: hashedPassword,
+		Role:           role,
+	}
+	user.Meta.Create()
+	user.Meta.TenantID = options.TenantID
+	return user
+}
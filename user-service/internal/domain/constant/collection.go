@@ -1,7 +1,14 @@
 package constant
 
 const (
-	UserCollection = "user"
+	UserCollection   = "user"
+	TenantCollection = "tenant"
+	OutboxCollection = "outbox"
 
 	UserEmailUniqueIndex = "user-email-unique-index"
+	// UserTenantEmailUniqueIndex replaces UserEmailUniqueIndex, an email is unique inside its tenant only
+	UserTenantEmailUniqueIndex = "user-tenant-email-unique-index"
+	TenantCodeUniqueIndex      = "tenant-code-unique-index"
+	// OutboxPublishedIndex expires the published events, the pending ones have no published time
+	OutboxPublishedIndex = "outbox-published-index"
 )
@@ -1,7 +1,9 @@
 package constant
 
 const (
-	AdminRole     = "admin"
-	LibrarianRole = "librarian"
-	MemberRole    = "member"
+	// SuperAdminRole provisions the tenants, the admin role manages a single tenant
+	SuperAdminRole = "super_admin"
+	AdminRole      = "admin"
+	LibrarianRole  = "librarian"
+	MemberRole     = "member"
 )
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"user-service/pkg/mongodb"
+)
+
+// OutboxEvent is a domain event waiting in the outbox until the relay publishes it,
+// the payload is the marshaled proto.Event envelope
+type OutboxEvent struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	Type         string     `json:"type" bson:"type"`
+	Payload      []byte     `json:"payload" bson:"payload"`
+	PublishedAt  *time.Time `json:"published_at" bson:"published_at"`
+	Attempts     int        `json:"attempts" bson:"attempts"`
+	LastError    string     `json:"last_error" bson:"last_error"`
+	// DeadAt is when the relay moved the event aside, after failing to publish it too many times, it is kept in the
+	// outbox to look into its last error but never published
+	DeadAt *time.Time `json:"dead_at" bson:"dead_at"`
+}
+
+// OutboxRepository is read by the relay across the tenants, the tenant of an event is in its envelope
+type OutboxRepository interface {
+	Create(ctx context.Context, event *OutboxEvent) error
+	// FetchPending returns the oldest events neither published nor dead yet, in the order they were created
+	FetchPending(ctx context.Context, limit int) ([]OutboxEvent, error)
+	MarkPublished(ctx context.Context, id primitive.ObjectID, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error
+	// MarkDead records the last failure of the event and moves it aside, FetchPending never returns it again
+	MarkDead(ctx context.Context, id primitive.ObjectID, reason string, deadAt time.Time) error
+}
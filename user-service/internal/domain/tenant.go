@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"user-service/pkg/mongodb"
+)
+
+// Tenant is a library sharing the deployment, its code is the tenant ID of the tokens and of every document
+type Tenant struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	mongodb.Meta `json:"meta" bson:"meta"`
+	Code         string `json:"code" bson:"code"`
+	Name         string `json:"name" bson:"name"`
+}
+
+type TenantRepository interface {
+	Create(ctx context.Context, tenant *Tenant) error
+	Fetch(ctx context.Context, filter map[string]interface{}) ([]Tenant, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	FindByCode(ctx context.Context, code string) (Tenant, error)
+	Delete(ctx context.Context, tenant *Tenant) error
+}
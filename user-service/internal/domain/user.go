@@ -14,6 +14,7 @@ type User struct {
 	Email          string `json:"email" bson:"email"`
 	HashedPassword string `json:"hashed_password" bson:"hashed_password"`
 	Role           string `json:"role" bson:"role"`
+	BranchID       string `json:"branch_id" bson:"branch_id"`
 }
 
 type UserRepository interface {
@@ -0,0 +1,43 @@
+package event
+
+import (
+	"context"
+
+	"user-service/pkg/proto"
+)
+
+// Publisher sends the events to a broker, an event is published once Publish returns nil
+type Publisher interface {
+	Publish(ctx context.Context, event *proto.Event) error
+	Close() error
+}
+
+// ChannelPublisher delivers the events in process, to the tests and to the consumers linked in the same binary
+type ChannelPublisher struct {
+	events chan *proto.Event
+}
+
+func NewChannelPublisher(size int) *ChannelPublisher {
+	return &ChannelPublisher{
+		events: make(chan *proto.Event, size),
+	}
+}
+
+// Publish waits while the channel is full, the event stays in the outbox until it is received
+func (p *ChannelPublisher) Publish(ctx context.Context, event *proto.Event) error {
+	select {
+	case p.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Events receives the published events, the channel is never closed
+func (p *ChannelPublisher) Events() <-chan *proto.Event {
+	return p.events
+}
+
+func (p *ChannelPublisher) Close() error {
+	return nil
+}
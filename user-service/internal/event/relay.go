@@ -0,0 +1,109 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	protobuf "google.golang.org/protobuf/proto"
+
+	"user-service/internal/domain"
+	"user-service/pkg/logger"
+	"user-service/pkg/proto"
+)
+
+// relayBatchSize is the number of events read from the outbox at once
+const relayBatchSize = 100
+
+// Relay publishes the events of the outbox in the order they were recorded
+type Relay struct {
+	outboxRepository domain.OutboxRepository
+	publisher        Publisher
+	interval         time.Duration
+	maxAttempts      int
+}
+
+// NewRelay returns a relay moving an event aside after maxAttempts failed attempts to publish it, so the events
+// after it are still published
+func NewRelay(outboxRepository domain.OutboxRepository, publisher Publisher, interval time.Duration, maxAttempts int) *Relay {
+	return &Relay{
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+		interval:         interval,
+		maxAttempts:      maxAttempts,
+	}
+}
+
+// Run relays the pending events at every interval until the context is done
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			logger.Ctx(ctx).Warn().Err(err).Msg("Error relaying events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes the pending events until the outbox is drained and returns the number of published events.
+// It stops at the first event failing to be published, which is retried first on the next run, so the events of the
+// service are never published out of order. An event failing maxAttempts times, or whose payload cannot be read,
+// is moved aside instead, so it does not block the events after it.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		outboxEvents, err := r.outboxRepository.FetchPending(ctx, relayBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for _, outboxEvent := range outboxEvents {
+			var event proto.Event
+			if err = protobuf.Unmarshal(outboxEvent.Payload, &event); err != nil {
+				// a payload which cannot be read never will be
+				if err = r.moveAside(ctx, outboxEvent, err); err != nil {
+					return published, err
+				}
+				continue
+			}
+
+			if err = r.publisher.Publish(ctx, &event); err != nil {
+				// the relay is stopping, the event did not fail
+				if ctx.Err() != nil {
+					return published, err
+				}
+				if outboxEvent.Attempts+1 >= r.maxAttempts {
+					if err = r.moveAside(ctx, outboxEvent, err); err != nil {
+						return published, err
+					}
+					continue
+				}
+				if markErr := r.outboxRepository.MarkFailed(ctx, outboxEvent.ID, err.Error()); markErr != nil {
+					logger.Ctx(ctx).Error().Err(markErr).Str("event_id", outboxEvent.ID.Hex()).Msg("Error marking event as failed")
+				}
+				return published, err
+			}
+
+			if err = r.outboxRepository.MarkPublished(ctx, outboxEvent.ID, time.Now()); err != nil {
+				return published, err
+			}
+			published++
+		}
+
+		if len(outboxEvents) < relayBatchSize {
+			return published, nil
+		}
+	}
+}
+
+func (r *Relay) moveAside(ctx context.Context, outboxEvent domain.OutboxEvent, reason error) error {
+	logger.Ctx(ctx).Error().Err(reason).Str("event_id", outboxEvent.ID.Hex()).Str("event_type", outboxEvent.Type).
+		Int("attempts", outboxEvent.Attempts+1).Msg("Moving event aside, it is not published")
+	return r.outboxRepository.MarkDead(ctx, outboxEvent.ID, reason.Error(), time.Now())
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+)
+
+// tenantMemoryRepository keeps the unique tenant code like constant.TenantCodeUniqueIndex
+type tenantMemoryRepository struct {
+	mu      sync.RWMutex
+	tenants []domain.Tenant
+}
+
+func NewTenantMemoryRepository() domain.TenantRepository {
+	return &tenantMemoryRepository{}
+}
+
+func (r *tenantMemoryRepository) Create(_ context.Context, tenant *domain.Tenant) error {
+	tenant.ID = primitive.NewObjectID()
+	tenant.Meta.Create()
+	tenant.Meta.TenantID = tenant.Code
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.tenants {
+		if stored.Code == tenant.Code {
+			return duplicateKeyError(constant.TenantCollection, constant.TenantCodeUniqueIndex)
+		}
+	}
+
+	var stored domain.Tenant
+	copyDocument(tenant, &stored)
+	r.tenants = append(r.tenants, stored)
+	return nil
+}
+
+func (r *tenantMemoryRepository) Fetch(_ context.Context, param map[string]interface{}) ([]domain.Tenant, error) {
+	tenants := r.filter(param)
+
+	sort.SliceStable(tenants, func(i, j int) bool {
+		return tenants[i].Code < tenants[j].Code
+	})
+	start, end := pageBounds(param, len(tenants))
+
+	return tenants[start:end], nil
+}
+
+func (r *tenantMemoryRepository) Count(_ context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(param)), nil
+}
+
+// filter returns a copy of the tenants matching the param, like tenantMongoDBRepository.filterBy
+func (r *tenantMemoryRepository) filter(param map[string]interface{}) []domain.Tenant {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	code, byCode := param["code"].(string)
+	tenants := make([]domain.Tenant, 0)
+	for _, tenant := range r.tenants {
+		if tenant.Meta.DeletedAt != nil || (byCode && tenant.Code != code) {
+			continue
+		}
+
+		var found domain.Tenant
+		copyDocument(tenant, &found)
+		tenants = append(tenants, found)
+	}
+
+	return tenants
+}
+
+func (r *tenantMemoryRepository) FindByCode(_ context.Context, code string) (tenant domain.Tenant, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, stored := range r.tenants {
+		if stored.Code == code && stored.Meta.DeletedAt == nil {
+			copyDocument(stored, &tenant)
+			return tenant, nil
+		}
+	}
+
+	return domain.Tenant{}, mongo.ErrNoDocuments
+}
+
+// Delete removes the tenant for good like tenantMongoDBRepository.Delete
+func (r *tenantMemoryRepository) Delete(_ context.Context, tenant *domain.Tenant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, stored := range r.tenants {
+		if stored.ID == tenant.ID {
+			r.tenants = append(r.tenants[:i], r.tenants[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
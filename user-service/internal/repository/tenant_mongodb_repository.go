@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+)
+
+// tenantMongoDBRepository is the only repository not scoped by byTenant, the tenants are shared by the deployment
+type tenantMongoDBRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewTenantMongoDBRepository(db *mongo.Database) domain.TenantRepository {
+	return &tenantMongoDBRepository{
+		db:         db,
+		collection: db.Collection(constant.TenantCollection),
+	}
+}
+
+func (r *tenantMongoDBRepository) Create(ctx context.Context, tenant *domain.Tenant) error {
+	tenant.ID = primitive.NewObjectID()
+	tenant.Meta.Create()
+	tenant.Meta.TenantID = tenant.Code
+
+	result, err := r.collection.InsertOne(ctx, tenant)
+	if err != nil {
+		return err
+	}
+
+	tenant.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *tenantMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.Tenant, error) {
+	cursor, err := r.collection.Find(ctx, r.filterBy(param), r.pageBy(param))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tenants := make([]domain.Tenant, 0)
+	for cursor.Next(ctx) {
+		var tenant domain.Tenant
+		if err = cursor.Decode(&tenant); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants, nil
+}
+
+func (r *tenantMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(param))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (*tenantMongoDBRepository) filterBy(param map[string]interface{}) bson.D {
+	filter := bson.D{}
+
+	filter = append(filter, bson.E{"meta.deleted_at", nil})
+
+	for key, value := range param {
+		switch key {
+		case "code":
+			filter = append(filter, bson.E{key, value})
+		}
+	}
+
+	return filter
+}
+
+func (*tenantMongoDBRepository) pageBy(param map[string]interface{}) *options.FindOptions {
+	limit, ok := param["limit"].(int32)
+	if !ok || limit <= 0 {
+		limit = 10
+	}
+	page, ok := param["page"].(int32)
+	if !ok || page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * limit
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetSort(bson.M{"code": 1})
+
+	return findOptions
+}
+
+func (r *tenantMongoDBRepository) FindByCode(ctx context.Context, code string) (tenant domain.Tenant, err error) {
+	filter := bson.D{{"code", code}, {"meta.deleted_at", nil}}
+	err = r.collection.FindOne(ctx, filter).
+		Decode(&tenant)
+	return
+}
+
+// Delete removes the tenant for good, it only undoes a failed provisioning so the code can be used again
+func (r *tenantMongoDBRepository) Delete(ctx context.Context, tenant *domain.Tenant) error {
+	_, err := r.collection.DeleteOne(ctx, bson.D{{"_id", tenant.ID}})
+	return err
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+	"user-service/pkg/tenant"
+)
+
+// userMemoryRepository keeps the unique email of a tenant like constant.UserTenantEmailUniqueIndex
+type userMemoryRepository struct {
+	mu    sync.RWMutex
+	users []domain.User
+}
+
+func NewUserMemoryRepository() domain.UserRepository {
+	return &userMemoryRepository{}
+}
+
+func (r *userMemoryRepository) Create(ctx context.Context, user *domain.User) error {
+	user.ID = primitive.NewObjectID()
+	user.Meta.Create()
+	user.Meta.TenantID = tenant.FromContext(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(*user) {
+		return duplicateKeyError(constant.UserCollection, constant.UserTenantEmailUniqueIndex)
+	}
+
+	var stored domain.User
+	copyDocument(user, &stored)
+	r.users = append(r.users, stored)
+	return nil
+}
+
+// emailTaken checks the unique index, the deleted users included. The caller holds the lock.
+func (r *userMemoryRepository) emailTaken(user domain.User) bool {
+	for _, stored := range r.users {
+		if stored.ID != user.ID && stored.Meta.TenantID == user.Meta.TenantID && stored.Email == user.Email {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *userMemoryRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.User, error) {
+	users := r.filter(ctx, param)
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return newestFirst(users[i].CreatedAt, users[j].CreatedAt, users[i].ID, users[j].ID)
+	})
+	start, end := pageBounds(param, len(users))
+
+	return users[start:end], nil
+}
+
+func (r *userMemoryRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
+	return len(r.filter(ctx, param)), nil
+}
+
+// filter returns a copy of the users matching the param, like userMongoDBRepository.filterBy
+func (r *userMemoryRepository) filter(ctx context.Context, param map[string]interface{}) []domain.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	users := make([]domain.User, 0)
+	for _, user := range r.users {
+		if user.Meta.TenantID != tenantID || user.Meta.DeletedAt != nil || !userMatches(user, param) {
+			continue
+		}
+
+		var found domain.User
+		copyDocument(user, &found)
+		users = append(users, found)
+	}
+
+	return users
+}
+
+func userMatches(user domain.User, param map[string]interface{}) bool {
+	for key, value := range param {
+		switch key {
+		case "role":
+			if user.Role != value.(string) {
+				return false
+			}
+		case "email":
+			if user.Email != value.(string) {
+				return false
+			}
+		case "branch_id":
+			if user.BranchID != value.(string) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// find returns the first user of the tenant matching fn, the deleted users included like userMongoDBRepository.FindOne
+func (r *userMemoryRepository) find(ctx context.Context, fn func(domain.User) bool) (user domain.User, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, stored := range r.users {
+		if stored.Meta.TenantID == tenantID && fn(stored) {
+			copyDocument(stored, &user)
+			return user, nil
+		}
+	}
+
+	return domain.User{}, mongo.ErrNoDocuments
+}
+
+func (r *userMemoryRepository) FindByID(ctx context.Context, id string) (domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return r.find(ctx, func(user domain.User) bool {
+		return user.ID == objectID
+	})
+}
+
+func (r *userMemoryRepository) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	return r.find(ctx, func(user domain.User) bool {
+		return user.Email == email
+	})
+}
+
+func (r *userMemoryRepository) Update(ctx context.Context, user *domain.User) error {
+	user.Meta.Update()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.users {
+		if stored.ID != user.ID || stored.Meta.TenantID != tenantID {
+			continue
+		}
+		if r.emailTaken(*user) {
+			return duplicateKeyError(constant.UserCollection, constant.UserTenantEmailUniqueIndex)
+		}
+
+		copyDocument(user, &r.users[i])
+		copyDocument(r.users[i], user)
+		return nil
+	}
+
+	return mongo.ErrNoDocuments
+}
+
+// Delete marks the user as deleted, a missing one is not an error like the update of userMongoDBRepository
+func (r *userMemoryRepository) Delete(ctx context.Context, user *domain.User) error {
+	user.Meta.Delete()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, stored := range r.users {
+		if stored.ID == user.ID && stored.Meta.TenantID == tenantID {
+			copyDocument(user, &r.users[i])
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 
 	"user-service/internal/domain"
 	"user-service/internal/domain/constant"
-	"user-service/pkg/mongodb"
+	"user-service/pkg/tenant"
 )
 
 type userMongoDBRepository struct {
@@ -18,8 +18,7 @@ type userMongoDBRepository struct {
 	collection *mongo.Collection
 }
 
-func NewUserMongoDBRepository() domain.UserRepository {
-	db := mongodb.GetDatabase()
+func NewUserMongoDBRepository(db *mongo.Database) domain.UserRepository {
 	return &userMongoDBRepository{
 		db:         db,
 		collection: db.Collection(constant.UserCollection),
@@ -29,6 +28,7 @@ func NewUserMongoDBRepository() domain.UserRepository {
 func (r *userMongoDBRepository) Create(ctx context.Context, user *domain.User) error {
 	user.ID = primitive.NewObjectID()
 	user.Meta.Create()
+	user.Meta.TenantID = tenant.FromContext(ctx)
 
 	result, err := r.collection.InsertOne(ctx, user)
 	if err != nil {
@@ -40,7 +40,7 @@ func (r *userMongoDBRepository) Create(ctx context.Context, user *domain.User) e
 }
 
 func (r *userMongoDBRepository) Fetch(ctx context.Context, param map[string]interface{}) ([]domain.User, error) {
-	cursor, err := r.collection.Find(ctx, r.filterBy(param), r.pageBy(param))
+	cursor, err := r.collection.Find(ctx, r.filterBy(ctx, param), r.pageBy(param))
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +59,7 @@ func (r *userMongoDBRepository) Fetch(ctx context.Context, param map[string]inte
 }
 
 func (r *userMongoDBRepository) Count(ctx context.Context, param map[string]interface{}) (int, error) {
-	count, err := r.collection.CountDocuments(ctx, r.filterBy(param))
+	count, err := r.collection.CountDocuments(ctx, r.filterBy(ctx, param))
 	if err != nil {
 		return 0, err
 	}
@@ -67,14 +67,14 @@ func (r *userMongoDBRepository) Count(ctx context.Context, param map[string]inte
 	return int(count), nil
 }
 
-func (*userMongoDBRepository) filterBy(param map[string]interface{}) bson.D {
-	filter := bson.D{}
+func (*userMongoDBRepository) filterBy(ctx context.Context, param map[string]interface{}) bson.D {
+	filter := byTenant(ctx, bson.D{})
 
 	filter = append(filter, bson.E{"meta.deleted_at", nil})
 
 	for key, value := range param {
 		switch key {
-		case "role", "email":
+		case "role", "email", "branch_id":
 			filter = append(filter, bson.E{key, value})
 		}
 	}
@@ -103,7 +103,7 @@ func (*userMongoDBRepository) pageBy(param map[string]interface{}) *options.Find
 
 func (r *userMongoDBRepository) FindOne(ctx context.Context, filter bson.D) (user domain.User, err error) {
 	var decoded bson.M
-	err = r.collection.FindOne(ctx, filter).
+	err = r.collection.FindOne(ctx, byTenant(ctx, filter)).
 		Decode(&decoded)
 	if err != nil {
 		return domain.User{}, err
@@ -137,7 +137,7 @@ func (r *userMongoDBRepository) Update(ctx context.Context, user *domain.User) e
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
 	var decode bson.M
-	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).
+	err := r.collection.FindOneAndUpdate(ctx, byTenant(ctx, filter), update, opts).
 		Decode(&decode)
 	if err != nil {
 		return err
@@ -154,7 +154,7 @@ func (r *userMongoDBRepository) Delete(ctx context.Context, user *domain.User) e
 	filter := bson.D{{"_id", user.ID}}
 	update := bson.D{{"$set", user}}
 
-	_, err := r.collection.UpdateOne(ctx, filter, update)
+	_, err := r.collection.UpdateOne(ctx, byTenant(ctx, filter), update)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+	"user-service/pkg/proto"
+	"user-service/pkg/tenant"
+)
+
+// tenantCodePattern keeps the tenant code usable in a header, a token and a URL
+var tenantCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// CreateTenant provisions a tenant with its first admin, who manages the users of the tenant
+func (s *UserGRPCService) CreateTenant(ctx context.Context, request *proto.CreateTenantRequest) (*proto.Tenant, error) {
+	code := strings.ToLower(strings.TrimSpace(request.Code))
+	if !tenantCodePattern.MatchString(code) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid tenant code: %s", request.Code)
+	}
+	if request.AdminEmail == "" || request.AdminPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant admin email and password are required")
+	}
+
+	newTenant := domain.Tenant{
+		Code: code,
+		Name: request.Name,
+	}
+	if err := s.tenantRepository.Create(ctx, &newTenant); err != nil {
+		if strings.Contains(err.Error(), constant.TenantCodeUniqueIndex) {
+			return nil, status.Errorf(codes.AlreadyExists, "tenant with %s code already exists", code)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	_, err := s.CreateUser(tenant.NewContext(ctx, code), &proto.CreateUserRequest{
+		Email:    request.AdminEmail,
+		Password: request.AdminPassword,
+		Role:     constant.AdminRole,
+	})
+	if err != nil {
+		if deleteErr := s.tenantRepository.Delete(ctx, &newTenant); deleteErr != nil {
+			return nil, status.Error(codes.Internal, deleteErr.Error())
+		}
+		return nil, err
+	}
+
+	return toProtoTenant(newTenant), nil
+}
+
+func (s *UserGRPCService) FetchTenant(ctx context.Context, request *proto.FetchTenantRequest) (*proto.FetchTenantResponse, error) {
+	page, limit := request.Pagination.Page, request.Pagination.Limit
+	fetchFilter := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+
+	tenants, err := s.tenantRepository.Fetch(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoTenants := make([]*proto.Tenant, 0, len(tenants))
+	for _, fetchedTenant := range tenants {
+		protoTenants = append(protoTenants, toProtoTenant(fetchedTenant))
+	}
+
+	total, err := s.tenantRepository.Count(ctx, fetchFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.FetchTenantResponse{
+		Pagination: &proto.PaginationResponse{
+			Limit:    limit,
+			Page:     page,
+			LastPage: int32(math.Ceil(float64(total) / float64(limit))),
+			Total:    int32(total),
+		},
+		Tenants: protoTenants,
+	}, nil
+}
+
+func (s *UserGRPCService) findTenant(ctx context.Context, code string) (domain.Tenant, error) {
+	foundTenant, err := s.tenantRepository.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Tenant{}, status.Errorf(codes.NotFound, "tenant with %s code is not found", code)
+		}
+		return domain.Tenant{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return foundTenant, nil
+}
+
+func toProtoTenant(tenant domain.Tenant) *proto.Tenant {
+	return &proto.Tenant{
+		Id:   tenant.ID.Hex(),
+		Code: tenant.Code,
+		Name: tenant.Name,
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+	"user-service/pkg/proto"
+	"user-service/pkg/tenant"
+)
+
+// failingUserRepository fails to create every user
+type failingUserRepository struct {
+	domain.UserRepository
+}
+
+func (failingUserRepository) Create(context.Context, *domain.User) error {
+	return errors.New("user is not created")
+}
+
+func TestUserGRPCService_CreateTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *proto.CreateTenantRequest
+		failUser bool
+		wantCode codes.Code
+	}{
+		{
+			name:    "with its admin",
+			request: &proto.CreateTenantRequest{Code: " North-Library ", Name: "North", AdminEmail: "admin@north.com", AdminPassword: "password"},
+		},
+		{
+			name:     "code already exists",
+			request:  &proto.CreateTenantRequest{Code: tenant.Default, Name: "Default", AdminEmail: "admin@mail.com", AdminPassword: "password"},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "invalid code",
+			request:  &proto.CreateTenantRequest{Code: "north library", AdminEmail: "admin@north.com", AdminPassword: "password"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "without admin",
+			request:  &proto.CreateTenantRequest{Code: "north"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "admin not created",
+			request:  &proto.CreateTenantRequest{Code: "north", AdminEmail: "admin@north.com", AdminPassword: "password"},
+			failUser: true,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newMemoryUserGRPCService(t)
+			if tt.failUser {
+				s.userRepository = failingUserRepository{s.userRepository}
+			}
+			ctx := context.Background()
+
+			got, err := s.CreateTenant(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateTenant() error = %v, want %v", err, tt.wantCode)
+			}
+
+			tenants, err := s.FetchTenant(ctx, &proto.FetchTenantRequest{Pagination: &proto.PaginationRequest{Limit: 10, Page: 1}})
+			if err != nil {
+				t.Fatalf("FetchTenant() error = %v", err)
+			}
+			// a failed provisioning leaves only the default tenant
+			wantTenants := int32(1)
+			if tt.wantCode == codes.OK {
+				wantTenants = 2
+			}
+			if tenants.Pagination.Total != wantTenants {
+				t.Errorf("FetchTenant() total = %d, want %d", tenants.Pagination.Total, wantTenants)
+			}
+			if err != nil || tt.wantCode != codes.OK {
+				return
+			}
+
+			if got.Code != "north-library" {
+				t.Errorf("CreateTenant() = %+v, want north-library code", got)
+			}
+			admin, err := s.FindByEmail(tenant.NewContext(ctx, got.Code), &proto.FindByEmailRequest{Email: tt.request.AdminEmail})
+			if err != nil || admin.Role != constant.AdminRole {
+				t.Errorf("FindByEmail() admin = %+v, %v", admin, err)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_FetchTenant(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	ctx := context.Background()
+	for _, code := range []string{"west", "east"} {
+		_, err := s.CreateTenant(ctx, &proto.CreateTenantRequest{Code: code, AdminEmail: "admin@" + code + ".com", AdminPassword: "password"})
+		if err != nil {
+			t.Fatalf("CreateTenant() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		request   *proto.FetchTenantRequest
+		wantCodes []string
+	}{
+		{
+			name:      "by code",
+			request:   &proto.FetchTenantRequest{Pagination: &proto.PaginationRequest{Limit: 2, Page: 1}},
+			wantCodes: []string{tenant.Default, "east"},
+		},
+		{
+			name:      "by page",
+			request:   &proto.FetchTenantRequest{Pagination: &proto.PaginationRequest{Limit: 2, Page: 2}},
+			wantCodes: []string{"west"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FetchTenant(ctx, tt.request)
+			if err != nil {
+				t.Fatalf("FetchTenant() error = %v", err)
+			}
+
+			tenantCodes := make([]string, 0, len(got.Tenants))
+			for _, fetchedTenant := range got.Tenants {
+				tenantCodes = append(tenantCodes, fetchedTenant.Code)
+			}
+			if !equalStrings(tenantCodes, tt.wantCodes) || got.Pagination.Total != 3 || got.Pagination.LastPage != 2 {
+				t.Errorf("FetchTenant() codes = %v, pagination = %+v, want %v", tenantCodes, got.Pagination, tt.wantCodes)
+			}
+		})
+	}
+}
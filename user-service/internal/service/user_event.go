@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"user-service/internal/domain"
+	"user-service/internal/event"
+	"user-service/pkg/proto"
+)
+
+func (s *UserGRPCService) recordUserRegistered(ctx context.Context, user domain.User) error {
+	return event.Record(ctx, s.outboxRepository, event.UserRegistered, user.ID.Hex(), &proto.UserRegistered{
+		UserId:   user.ID.Hex(),
+		Email:    user.Email,
+		Role:     user.Role,
+		BranchId: user.BranchID,
+	})
+}
+
+func (s *UserGRPCService) recordUserDeleted(ctx context.Context, user domain.User) error {
+	return event.Record(ctx, s.outboxRepository, event.UserDeleted, user.ID.Hex(), &proto.UserDeleted{
+		UserId: user.ID.Hex(),
+		Email:  user.Email,
+	})
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+	"user-service/internal/event"
+	"user-service/pkg/proto"
+)
+
+func TestUserGRPCService_RecordsEvents(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, s, "member@example.com", constant.MemberRole)
+	if _, err := s.DeleteUser(ctx, &proto.DeleteUserRequest{Email: user.Email}); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+
+	outboxEvents, err := s.outboxRepository.FetchPending(ctx, 10)
+	if err != nil {
+		t.Fatalf("FetchPending() error = %v", err)
+	}
+
+	wantTypes := []string{event.UserRegistered, event.UserDeleted}
+	if len(outboxEvents) != len(wantTypes) {
+		t.Fatalf("events = %d, want %v", len(outboxEvents), wantTypes)
+	}
+	for i, outboxEvent := range outboxEvents {
+		var recorded proto.Event
+		if err = protobuf.Unmarshal(outboxEvent.Payload, &recorded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if recorded.Type != wantTypes[i] || recorded.AggregateId != user.Id {
+			t.Errorf("event[%d] = %s of %s, want %s of %s", i, recorded.Type, recorded.AggregateId, wantTypes[i], user.Id)
+		}
+
+		if recorded.Type == event.UserRegistered {
+			var registered proto.UserRegistered
+			if err = recorded.Payload.UnmarshalTo(&registered); err != nil {
+				t.Fatalf("UnmarshalTo() error = %v", err)
+			}
+			if registered.Email != user.Email || registered.Role != constant.MemberRole {
+				t.Errorf("user registered = %v, want the member %s", &registered, user.Email)
+			}
+		}
+	}
+}
+
+// failingOutboxRepository fails to record the events, the changes they describe must fail with them
+type failingOutboxRepository struct {
+	domain.OutboxRepository
+}
+
+func (failingOutboxRepository) Create(context.Context, *domain.OutboxEvent) error {
+	return errors.New("outbox is unavailable")
+}
+
+func TestUserGRPCService_OutboxFailure(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, s, "member@example.com", constant.MemberRole)
+	s.outboxRepository = failingOutboxRepository{}
+
+	_, err := s.CreateUser(ctx, &proto.CreateUserRequest{
+		Email:    "other@example.com",
+		Password: "password",
+		Role:     constant.MemberRole,
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("CreateUser() error = %v, want Internal", err)
+	}
+	if _, err = s.DeleteUser(ctx, &proto.DeleteUserRequest{Email: user.Email}); status.Code(err) != codes.Internal {
+		t.Errorf("DeleteUser() error = %v, want Internal", err)
+	}
+}
@@ -12,46 +12,78 @@ import (
 
 	"user-service/internal/domain"
 	"user-service/internal/domain/constant"
-	"user-service/internal/repository"
 	"user-service/pkg/jwt"
+	"user-service/pkg/mongodb"
 	"user-service/pkg/password"
 	"user-service/pkg/proto"
+	"user-service/pkg/tenant"
 )
 
 type UserGRPCService struct {
 	proto.UnimplementedUserServiceServer
-	userRepository domain.UserRepository
-	jwtService     jwt.Service
+	userRepository       domain.UserRepository
+	tenantRepository     domain.TenantRepository
+	outboxRepository     domain.OutboxRepository
+	txRepository         mongodb.TXRepository
+	jwtService           jwt.Service
+	lendingServiceClient proto.LendingServiceClient
+	bookServiceClient    proto.BookServiceClient
 }
 
-func NewUserGRPCService() *UserGRPCService {
+func NewUserGRPCService(
+	userRepository domain.UserRepository,
+	tenantRepository domain.TenantRepository,
+	outboxRepository domain.OutboxRepository,
+	txRepository mongodb.TXRepository,
+	lendingServiceClient proto.LendingServiceClient,
+	bookServiceClient proto.BookServiceClient,
+	jwtService jwt.Service,
+) *UserGRPCService {
 	return &UserGRPCService{
-		userRepository: repository.NewUserMongoDBRepository(),
-		jwtService:     jwt.New(),
+		userRepository:       userRepository,
+		tenantRepository:     tenantRepository,
+		outboxRepository:     outboxRepository,
+		txRepository:         txRepository,
+		jwtService:           jwtService,
+		lendingServiceClient: lendingServiceClient,
+		bookServiceClient:    bookServiceClient,
 	}
 }
 
 func (s *UserGRPCService) CreateUser(ctx context.Context, request *proto.CreateUserRequest) (
 	*proto.User, error) {
+	if request.BranchId != "" && request.Role != constant.LibrarianRole {
+		return nil, status.Error(codes.InvalidArgument, "only a librarian can be assigned to a branch")
+	}
+	if _, err := s.findTenant(ctx, tenant.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	if err := s.checkBranch(ctx, request.BranchId); err != nil {
+		return nil, err
+	}
+
 	hashedPassword, _ := password.Hash(request.Password)
 	user := domain.User{
 		Email:          request.Email,
 		HashedPassword: hashedPassword,
 		Role:           request.Role,
+		BranchID:       request.BranchId,
 	}
 
-	if err := s.userRepository.Create(ctx, &user); err != nil {
-		if strings.Contains(err.Error(), constant.UserEmailUniqueIndex) {
+	err := s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepository.Create(ctx, &user); err != nil {
+			return err
+		}
+		return s.recordUserRegistered(ctx, user)
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), constant.UserTenantEmailUniqueIndex) {
 			return nil, status.Errorf(codes.AlreadyExists, "email %s already registered", user.Email)
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.User{
-		Id:    user.ID.Hex(),
-		Email: user.Email,
-		Role:  user.Role,
-	}, nil
+	return toProtoUser(user), nil
 }
 
 func (s *UserGRPCService) Login(ctx context.Context, request *proto.LoginRequest) (*proto.LoginResponse, error) {
@@ -70,7 +102,7 @@ func (s *UserGRPCService) Login(ctx context.Context, request *proto.LoginRequest
 		return nil, status.Error(codes.Unauthenticated, "wrong password")
 	}
 
-	token, err := s.jwtService.GenerateToken(user.ID.Hex(), user.Email, user.Role)
+	token, err := s.jwtService.GenerateToken(user.ID.Hex(), user.Email, user.Role, user.BranchID, user.Meta.TenantID)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -92,6 +124,9 @@ func (s *UserGRPCService) FetchUser(ctx context.Context, request *proto.FetchUse
 	if role := request.Role; role != "" {
 		fetchFilter["role"] = role
 	}
+	if branchID := request.BranchId; branchID != "" {
+		fetchFilter["branch_id"] = branchID
+	}
 
 	users, err := s.userRepository.Fetch(ctx, fetchFilter)
 	if err != nil {
@@ -100,11 +135,7 @@ func (s *UserGRPCService) FetchUser(ctx context.Context, request *proto.FetchUse
 
 	protoUsers := make([]*proto.User, 0)
 	for _, user := range users {
-		protoUsers = append(protoUsers, &proto.User{
-			Id:    user.ID.Hex(),
-			Email: user.Email,
-			Role:  user.Role,
-		})
+		protoUsers = append(protoUsers, toProtoUser(user))
 	}
 
 	total, err := s.userRepository.Count(ctx, fetchFilter)
@@ -132,11 +163,7 @@ func (s *UserGRPCService) FindByID(ctx context.Context, request *proto.FindByIDR
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.User{
-		Id:    user.ID.Hex(),
-		Email: user.Email,
-		Role:  user.Role,
-	}, nil
+	return toProtoUser(user), nil
 }
 
 func (s *UserGRPCService) FindByEmail(ctx context.Context, request *proto.FindByEmailRequest) (*proto.User, error) {
@@ -148,11 +175,7 @@ func (s *UserGRPCService) FindByEmail(ctx context.Context, request *proto.FindBy
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.User{
-		Id:    user.ID.Hex(),
-		Email: user.Email,
-		Role:  user.Role,
-	}, nil
+	return toProtoUser(user), nil
 }
 
 func (s *UserGRPCService) UpdateUser(ctx context.Context, request *proto.UpdateUserRequest) (*proto.User, error) {
@@ -165,20 +188,25 @@ func (s *UserGRPCService) UpdateUser(ctx context.Context, request *proto.UpdateU
 	}
 
 	user.Email = request.Email
+	if request.BranchId != "" {
+		if user.Role != constant.LibrarianRole {
+			return nil, status.Error(codes.InvalidArgument, "only a librarian can be assigned to a branch")
+		}
+		if err = s.checkBranch(ctx, request.BranchId); err != nil {
+			return nil, err
+		}
+		user.BranchID = request.BranchId
+	}
 
 	err = s.userRepository.Update(ctx, &user)
 	if err != nil {
-		if strings.Contains(err.Error(), constant.UserEmailUniqueIndex) {
+		if strings.Contains(err.Error(), constant.UserTenantEmailUniqueIndex) {
 			return nil, status.Errorf(codes.AlreadyExists, "email %s already registered", user.Email)
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.User{
-		Id:    user.ID.Hex(),
-		Email: user.Email,
-		Role:  user.Role,
-	}, nil
+	return toProtoUser(user), nil
 }
 
 func (s *UserGRPCService) UpdateSelf(ctx context.Context, request *proto.UpdateSelfRequest) (*proto.User, error) {
@@ -197,17 +225,13 @@ func (s *UserGRPCService) UpdateSelf(ctx context.Context, request *proto.UpdateS
 
 	err = s.userRepository.Update(ctx, &user)
 	if err != nil {
-		if strings.Contains(err.Error(), constant.UserEmailUniqueIndex) {
+		if strings.Contains(err.Error(), constant.UserTenantEmailUniqueIndex) {
 			return nil, status.Errorf(codes.AlreadyExists, "email %s already registered", user.Email)
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &proto.User{
-		Id:    user.ID.Hex(),
-		Email: user.Email,
-		Role:  user.Role,
-	}, nil
+	return toProtoUser(user), nil
 }
 
 func (s *UserGRPCService) DeleteUser(ctx context.Context, request *proto.DeleteUserRequest) (*proto.DeleteUserResponse, error) {
@@ -219,10 +243,78 @@ func (s *UserGRPCService) DeleteUser(ctx context.Context, request *proto.DeleteU
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	err = s.userRepository.Delete(ctx, &user)
+	activeLendings, err := s.lendingServiceClient.CountActiveLendings(ctx, &proto.CountActiveLendingsRequest{
+		UserId: user.ID.Hex(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if activeLendings.Count > 0 {
+		if !request.Force {
+			return nil, status.Errorf(codes.FailedPrecondition, "account with %s email still has %d active lending(s): %s",
+				request.Email, activeLendings.Count, joinLendingIDs(activeLendings.Lendings))
+		}
+
+		_, err = s.lendingServiceClient.FinishActiveLendings(ctx, &proto.FinishActiveLendingsRequest{
+			UserId: user.ID.Hex(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.withTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepository.Delete(ctx, &user); err != nil {
+			return err
+		}
+		return s.recordUserDeleted(ctx, user)
+	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	return &proto.DeleteUserResponse{}, nil
 }
+
+// checkBranch returns the NotFound error of the book service for a branch it does not know in the tenant of the
+// request, the branch of a librarian scopes their requests in the gateway
+func (s *UserGRPCService) checkBranch(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+
+	_, err := s.bookServiceClient.FindBranchByID(ctx, &proto.FindBranchByIDRequest{Id: id})
+	return err
+}
+
+// withTransaction runs fn in a transaction, the repositories take part in it through the context given to fn
+func (s *UserGRPCService) withTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := s.txRepository.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+func joinLendingIDs(lendings []*proto.Lending) string {
+	ids := make([]string, 0, len(lendings))
+	for _, lending := range lendings {
+		ids = append(ids, lending.GetId())
+	}
+
+	return strings.Join(ids, ", ")
+}
+
+func toProtoUser(user domain.User) *proto.User {
+	return &proto.User{
+		Id:       user.ID.Hex(),
+		Email:    user.Email,
+		Role:     user.Role,
+		BranchId: user.BranchID,
+	}
+}
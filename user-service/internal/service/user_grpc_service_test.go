@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-service/internal/domain"
+	"user-service/internal/domain/constant"
+	"user-service/internal/repository"
+	"user-service/pkg/jwt"
+	"user-service/pkg/mongodb"
+	"user-service/pkg/proto"
+	"user-service/pkg/tenant"
+)
+
+// newMemoryUserGRPCService runs the service against the in-memory repositories with the default tenant provisioned
+func newMemoryUserGRPCService(t *testing.T) (*UserGRPCService, *lendingServiceClientStub) {
+	t.Helper()
+
+	tenantRepository := repository.NewTenantMemoryRepository()
+	if err := tenantRepository.Create(context.Background(), &domain.Tenant{Code: tenant.Default, Name: "Default"}); err != nil {
+		t.Fatalf("Create() tenant error = %v", err)
+	}
+
+	lendingServiceClient := &lendingServiceClientStub{activeLendings: map[string][]*proto.Lending{}}
+	s := NewUserGRPCService(repository.NewUserMemoryRepository(), tenantRepository, repository.NewOutboxMemoryRepository(), mongodb.NewNoTXRepository(), lendingServiceClient, bookServiceClientStub{}, jwt.New("secret"))
+	return s, lendingServiceClient
+}
+
+// lendingServiceClientStub answers with the active lendings of each user ID, the finished users are recorded
+type lendingServiceClientStub struct {
+	proto.LendingServiceClient
+	activeLendings map[string][]*proto.Lending
+	finished       []string
+	err            error
+}
+
+func (c *lendingServiceClientStub) CountActiveLendings(_ context.Context, in *proto.CountActiveLendingsRequest, _ ...grpc.CallOption) (*proto.CountActiveLendingsResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	lendings := c.activeLendings[in.UserId]
+	return &proto.CountActiveLendingsResponse{Count: int32(len(lendings)), Lendings: lendings}, nil
+}
+
+func (c *lendingServiceClientStub) FinishActiveLendings(_ context.Context, in *proto.FinishActiveLendingsRequest, _ ...grpc.CallOption) (*proto.FinishActiveLendingsResponse, error) {
+	c.finished = append(c.finished, in.UserId)
+	return &proto.FinishActiveLendingsResponse{Lendings: c.activeLendings[in.UserId]}, nil
+}
+
+// bookServiceClientStub knows the east branch only
+type bookServiceClientStub struct {
+	proto.BookServiceClient
+}
+
+func (bookServiceClientStub) FindBranchByID(_ context.Context, in *proto.FindBranchByIDRequest, _ ...grpc.CallOption) (*proto.Branch, error) {
+	if in.Id != "east" {
+		return nil, status.Errorf(codes.NotFound, "branch with %s ID is not found", in.Id)
+	}
+	return &proto.Branch{Id: in.Id, Name: "East"}, nil
+}
+
+func createTestUser(t *testing.T, s *UserGRPCService, email, role string) *proto.User {
+	t.Helper()
+
+	user, err := s.CreateUser(context.Background(), &proto.CreateUserRequest{Email: email, Password: "password", Role: role})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	return user
+}
+
+func TestUserGRPCService_CreateUser(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	createTestUser(t, s, "taken@mail.com", constant.MemberRole)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		request  *proto.CreateUserRequest
+		wantCode codes.Code
+	}{
+		{
+			name:    "member",
+			ctx:     context.Background(),
+			request: &proto.CreateUserRequest{Email: "member@mail.com", Password: "password", Role: constant.MemberRole},
+		},
+		{
+			name:    "librarian of a branch",
+			ctx:     context.Background(),
+			request: &proto.CreateUserRequest{Email: "librarian@mail.com", Password: "password", Role: constant.LibrarianRole, BranchId: "east"},
+		},
+		{
+			name:     "member of a branch",
+			ctx:      context.Background(),
+			request:  &proto.CreateUserRequest{Email: "branch@mail.com", Password: "password", Role: constant.MemberRole, BranchId: "east"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "librarian of an unknown branch",
+			ctx:      context.Background(),
+			request:  &proto.CreateUserRequest{Email: "unknown@mail.com", Password: "password", Role: constant.LibrarianRole, BranchId: "west"},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "email already registered",
+			ctx:      context.Background(),
+			request:  &proto.CreateUserRequest{Email: "taken@mail.com", Password: "password", Role: constant.MemberRole},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "unknown tenant",
+			ctx:      tenant.NewContext(context.Background(), "unknown"),
+			request:  &proto.CreateUserRequest{Email: "member@mail.com", Password: "password", Role: constant.MemberRole},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CreateUser(tt.ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateUser() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && (got.Id == "" || got.Email != tt.request.Email || got.Role != tt.request.Role || got.BranchId != tt.request.BranchId) {
+				t.Errorf("CreateUser() = %+v, want %+v", got, tt.request)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_Login(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	createTestUser(t, s, "member@mail.com", constant.MemberRole)
+	deleted := createTestUser(t, s, "deleted@mail.com", constant.MemberRole)
+	if _, err := s.DeleteUser(context.Background(), &proto.DeleteUserRequest{Email: deleted.Email}); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		request  *proto.LoginRequest
+		wantCode codes.Code
+	}{
+		{
+			name:    "valid password",
+			request: &proto.LoginRequest{Email: "member@mail.com", Password: "password"},
+		},
+		{
+			name:     "wrong password",
+			request:  &proto.LoginRequest{Email: "member@mail.com", Password: "wrong"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "unknown email",
+			request:  &proto.LoginRequest{Email: "unknown@mail.com", Password: "password"},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "deleted account",
+			request:  &proto.LoginRequest{Email: "deleted@mail.com", Password: "password"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Login(context.Background(), tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Login() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			token, err := s.jwtService.ValidateToken(got.Token)
+			if err != nil || !token.Valid {
+				t.Fatalf("ValidateToken() = %v, %v", token, err)
+			}
+			claims := token.Claims.(jwt.MapClaims)
+			if claims["email"] != tt.request.Email || claims["tenant_id"] != tenant.Default {
+				t.Errorf("Login() claims = %v", claims)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_FetchUser(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	ctx := context.Background()
+
+	first := createTestUser(t, s, "first@mail.com", constant.MemberRole)
+	second := createTestUser(t, s, "second@mail.com", constant.MemberRole)
+	librarian, err := s.CreateUser(ctx, &proto.CreateUserRequest{Email: "librarian@mail.com", Password: "password", Role: constant.LibrarianRole, BranchId: "east"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	deleted := createTestUser(t, s, "deleted@mail.com", constant.MemberRole)
+	if _, err = s.DeleteUser(ctx, &proto.DeleteUserRequest{Email: deleted.Email}); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		request   *proto.FetchUserRequest
+		wantIDs   []string
+		wantTotal int32
+	}{
+		{
+			name:      "newest first without the deleted",
+			request:   &proto.FetchUserRequest{Pagination: &proto.PaginationRequest{Limit: 2, Page: 1}},
+			wantIDs:   []string{librarian.Id, second.Id},
+			wantTotal: 3,
+		},
+		{
+			name:      "by page",
+			request:   &proto.FetchUserRequest{Pagination: &proto.PaginationRequest{Limit: 2, Page: 2}},
+			wantIDs:   []string{first.Id},
+			wantTotal: 3,
+		},
+		{
+			name:      "by role",
+			request:   &proto.FetchUserRequest{Pagination: &proto.PaginationRequest{Limit: 10, Page: 1}, Role: constant.MemberRole},
+			wantIDs:   []string{second.Id, first.Id},
+			wantTotal: 2,
+		},
+		{
+			name:      "by email",
+			request:   &proto.FetchUserRequest{Pagination: &proto.PaginationRequest{Limit: 10, Page: 1}, Email: "first@mail.com"},
+			wantIDs:   []string{first.Id},
+			wantTotal: 1,
+		},
+		{
+			name:      "by branch",
+			request:   &proto.FetchUserRequest{Pagination: &proto.PaginationRequest{Limit: 10, Page: 1}, BranchId: "east"},
+			wantIDs:   []string{librarian.Id},
+			wantTotal: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FetchUser(ctx, tt.request)
+			if err != nil {
+				t.Fatalf("FetchUser() error = %v", err)
+			}
+
+			ids := make([]string, 0, len(got.Users))
+			for _, user := range got.Users {
+				ids = append(ids, user.Id)
+			}
+			if !equalStrings(ids, tt.wantIDs) || got.Pagination.Total != tt.wantTotal {
+				t.Errorf("FetchUser() IDs = %v, total = %d, want %v, %d", ids, got.Pagination.Total, tt.wantIDs, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_FindByID(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	user := createTestUser(t, s, "member@mail.com", constant.MemberRole)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		id       string
+		wantCode codes.Code
+	}{
+		{
+			name: "found",
+			ctx:  context.Background(),
+			id:   user.Id,
+		},
+		{
+			name:     "not found",
+			ctx:      context.Background(),
+			id:       primitive.NewObjectID().Hex(),
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "user of another tenant",
+			ctx:      tenant.NewContext(context.Background(), "other"),
+			id:       user.Id,
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindByID(tt.ctx, &proto.FindByIDRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FindByID() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && got.Email != user.Email {
+				t.Errorf("FindByID() = %+v, want %+v", got, user)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_FindByEmail(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	user := createTestUser(t, s, "member@mail.com", constant.MemberRole)
+
+	tests := []struct {
+		name     string
+		email    string
+		wantCode codes.Code
+	}{
+		{
+			name:  "found",
+			email: user.Email,
+		},
+		{
+			name:     "not found",
+			email:    "unknown@mail.com",
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindByEmail(context.Background(), &proto.FindByEmailRequest{Email: tt.email})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("FindByEmail() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && got.Id != user.Id {
+				t.Errorf("FindByEmail() = %+v, want %+v", got, user)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_UpdateUser(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	member := createTestUser(t, s, "member@mail.com", constant.MemberRole)
+	librarian := createTestUser(t, s, "librarian@mail.com", constant.LibrarianRole)
+	createTestUser(t, s, "taken@mail.com", constant.MemberRole)
+
+	tests := []struct {
+		name         string
+		request      *proto.UpdateUserRequest
+		wantCode     codes.Code
+		wantBranchID string
+	}{
+		{
+			name:    "email",
+			request: &proto.UpdateUserRequest{Id: member.Id, Email: "new@mail.com"},
+		},
+		{
+			name:         "branch of a librarian",
+			request:      &proto.UpdateUserRequest{Id: librarian.Id, Email: librarian.Email, BranchId: "east"},
+			wantBranchID: "east",
+		},
+		{
+			name:     "branch of a member",
+			request:  &proto.UpdateUserRequest{Id: member.Id, Email: "new@mail.com", BranchId: "east"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown branch",
+			request:  &proto.UpdateUserRequest{Id: librarian.Id, Email: librarian.Email, BranchId: "west"},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "email already registered",
+			request:  &proto.UpdateUserRequest{Id: member.Id, Email: "taken@mail.com"},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "not found",
+			request:  &proto.UpdateUserRequest{Id: primitive.NewObjectID().Hex(), Email: "new@mail.com"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.UpdateUser(context.Background(), tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateUser() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindByID(context.Background(), &proto.FindByIDRequest{Id: tt.request.Id})
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if got.Email != tt.request.Email || got.BranchId != tt.wantBranchID {
+				t.Errorf("UpdateUser() = %+v, want %+v", got, tt.request)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_UpdateSelf(t *testing.T) {
+	s, _ := newMemoryUserGRPCService(t)
+	member := createTestUser(t, s, "member@mail.com", constant.MemberRole)
+	other := createTestUser(t, s, "other@mail.com", constant.MemberRole)
+
+	tests := []struct {
+		name     string
+		request  *proto.UpdateSelfRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "another user",
+			request:  &proto.UpdateSelfRequest{Id: other.Id, SelfEmail: member.Email, Email: "new@mail.com"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "email already registered",
+			request:  &proto.UpdateSelfRequest{Id: member.Id, SelfEmail: member.Email, Email: other.Email},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:    "own email",
+			request: &proto.UpdateSelfRequest{Id: member.Id, SelfEmail: member.Email, Email: "new@mail.com"},
+		},
+		{
+			name:     "not found",
+			request:  &proto.UpdateSelfRequest{Id: primitive.NewObjectID().Hex(), SelfEmail: member.Email, Email: "new@mail.com"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.UpdateSelf(context.Background(), tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UpdateSelf() error = %v, want %v", err, tt.wantCode)
+			}
+			if err == nil && got.Email != tt.request.Email {
+				t.Errorf("UpdateSelf() = %+v, want %+v", got, tt.request)
+			}
+		})
+	}
+}
+
+func TestUserGRPCService_DeleteUser(t *testing.T) {
+	lending := &proto.Lending{Id: primitive.NewObjectID().Hex()}
+
+	tests := []struct {
+		name         string
+		email        string
+		lendings     int
+		force        bool
+		lendingErr   error
+		wantCode     codes.Code
+		wantFinished bool
+	}{
+		{
+			name:  "without lendings",
+			email: "member@mail.com",
+		},
+		{
+			name:     "with active lendings",
+			email:    "member@mail.com",
+			lendings: 2,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "forced with active lendings",
+			email:        "member@mail.com",
+			lendings:     2,
+			force:        true,
+			wantFinished: true,
+		},
+		{
+			name:       "lending service unavailable",
+			email:      "member@mail.com",
+			lendingErr: status.Error(codes.Unavailable, "lending service is unavailable"),
+			wantCode:   codes.Unavailable,
+		},
+		{
+			name:     "not found",
+			email:    "unknown@mail.com",
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, lendingServiceClient := newMemoryUserGRPCService(t)
+			ctx := context.Background()
+
+			user := createTestUser(t, s, "member@mail.com", constant.MemberRole)
+			for i := 0; i < tt.lendings; i++ {
+				lendingServiceClient.activeLendings[user.Id] = append(lendingServiceClient.activeLendings[user.Id], lending)
+			}
+			lendingServiceClient.err = tt.lendingErr
+
+			_, err := s.DeleteUser(ctx, &proto.DeleteUserRequest{Email: tt.email, Force: tt.force})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("DeleteUser() error = %v, want %v", err, tt.wantCode)
+			}
+			if finished := len(lendingServiceClient.finished) > 0; finished != tt.wantFinished {
+				t.Errorf("DeleteUser() finished lendings = %v, want %v", finished, tt.wantFinished)
+			}
+
+			_, err = s.Login(ctx, &proto.LoginRequest{Email: user.Email, Password: "password"})
+			if deleted := status.Code(err) == codes.NotFound; deleted != (tt.wantCode == codes.OK) {
+				t.Errorf("DeleteUser() deleted = %v, want %v", deleted, tt.wantCode == codes.OK)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,316 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const usage = `Usage: migration [--dry-run] <command>
+
+Commands:
+  up [N]        run the N next migrations, all of them by default
+  down [N]      revert the N last migrations, the last one by default
+  to VERSION    run or revert the migrations to reach the version, 0 reverts all of them
+  status        list the migrations and whether they have run
+`
+
+// Step runs or reverts a migration, then records the version of the database
+type Step struct {
+	Migration   migrate.Migration
+	Down        bool
+	Version     uint64
+	Description string
+}
+
+func (s Step) String() string {
+	direction := "up"
+	if s.Down {
+		direction = "down"
+	}
+	return fmt.Sprintf("%s %d_%s", direction, s.Migration.Version, s.Migration.Description)
+}
+
+// Run runs the migration command given by the command line arguments, see usage.
+// Without command, all the pending migrations are run.
+func Run(db *mongo.Database, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migration", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(out, usage)
+	}
+	dryRun := flags.Bool("dry-run", false, "print the migrations without running them")
+
+	// the flag is accepted after the command too
+	positional := make([]string, 0, 2)
+	for {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	migrations := registeredMigrations()
+	m := migrate.NewMigrate(db, migrations...)
+	current, _, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	command := "up"
+	if len(positional) > 0 {
+		command = positional[0]
+	}
+
+	var target uint64
+	switch command {
+	case "status":
+		return printStatus(out, migrations, current)
+	case "up":
+		n, err := count(positional, 0)
+		if err != nil {
+			return err
+		}
+		target = UpTarget(migrations, current, n)
+	case "down":
+		n, err := count(positional, 1)
+		if err != nil {
+			return err
+		}
+		target = DownTarget(migrations, current, n)
+	case "to":
+		if len(positional) != 2 {
+			return errors.New("the target version is required")
+		}
+		if target, err = strconv.ParseUint(positional[1], 10, 64); err != nil {
+			return fmt.Errorf("invalid version: %s", positional[1])
+		}
+		if target != 0 && !registered(migrations, target) {
+			return fmt.Errorf("migration with version %d is not found", target)
+		}
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command: %s", command)
+	}
+
+	steps, err := Plan(migrations, current, target)
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		_, _ = fmt.Fprintf(out, "database is at version %d, no migration to run\n", current)
+		return nil
+	}
+
+	for _, step := range steps {
+		if *dryRun {
+			_, _ = fmt.Fprintf(out, "would run %s\n", step)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(out, "running %s\n", step)
+		run := step.Migration.Up
+		if step.Down {
+			run = step.Migration.Down
+		}
+		if err = run(db); err != nil {
+			return fmt.Errorf("%s: %w", step, err)
+		}
+		if err = m.SetVersion(step.Version, step.Description); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Plan returns the steps from the current version to the target version, in the order they must run
+func Plan(migrations []migrate.Migration, current, target uint64) ([]Step, error) {
+	steps := make([]Step, 0)
+	if target >= current {
+		for _, migration := range migrations {
+			if migration.Version > current && migration.Version <= target {
+				steps = append(steps, Step{
+					Migration:   migration,
+					Version:     migration.Version,
+					Description: migration.Description,
+				})
+			}
+		}
+		return steps, nil
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if migration.Version > current || migration.Version <= target {
+			continue
+		}
+		if migration.Down == nil {
+			return nil, fmt.Errorf("migration %d_%s can't be reverted", migration.Version, migration.Description)
+		}
+
+		var previous migrate.Migration
+		if i > 0 {
+			previous = migrations[i-1]
+		}
+		steps = append(steps, Step{
+			Migration:   migration,
+			Down:        true,
+			Version:     previous.Version,
+			Description: previous.Description,
+		})
+	}
+	return steps, nil
+}
+
+// UpTarget is the version after running the n next migrations, all of them when n is 0
+func UpTarget(migrations []migrate.Migration, current uint64, n int) uint64 {
+	target := current
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+		target = migration.Version
+		if n--; n == 0 {
+			break
+		}
+	}
+	return target
+}
+
+// DownTarget is the version after reverting the n last migrations
+func DownTarget(migrations []migrate.Migration, current uint64, n int) uint64 {
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if migrations[i].Version > current {
+			continue
+		}
+		if n == 0 {
+			return migrations[i].Version
+		}
+		n--
+	}
+	return 0
+}
+
+// ErrUnreachable is returned by CheckPending when the database does not answer, the pending migrations are unknown
+var ErrUnreachable = errors.New("database is unreachable")
+
+// CheckPending returns an error when some migrations have not run, so the app does not start on an outdated database.
+// allowPending lets it start anyway.
+func CheckPending(ctx context.Context, db *mongo.Database, allowPending bool) error {
+	if err := db.Client().Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+
+	migrations := registeredMigrations()
+	current, _, err := migrate.NewMigrate(db, migrations...).Version()
+	if err != nil {
+		return err
+	}
+
+	pending := make([]string, 0)
+	for _, migration := range migrations {
+		if migration.Version > current {
+			pending = append(pending, fmt.Sprintf("%d_%s", migration.Version, migration.Description))
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if allowPending {
+		log.Warn().Strs("migrations", pending).Msg("Starting with pending migrations")
+		return nil
+	}
+	return fmt.Errorf("pending migrations %v, run them or set ALLOW_PENDING_MIGRATIONS to start anyway", pending)
+}
+
+// Checker checks the pending migrations until the check passes once. An app started while the database was
+// unreachable uses it in its health check, so it is not serving before its database is known to be up to date.
+type Checker struct {
+	db           *mongo.Database
+	allowPending bool
+	passed       int32
+}
+
+func NewChecker(db *mongo.Database, allowPending bool) *Checker {
+	return &Checker{
+		db:           db,
+		allowPending: allowPending,
+	}
+}
+
+func (c *Checker) Check(ctx context.Context) error {
+	if atomic.LoadInt32(&c.passed) == 1 {
+		return nil
+	}
+	if err := CheckPending(ctx, c.db, c.allowPending); err != nil {
+		return err
+	}
+
+	atomic.StoreInt32(&c.passed, 1)
+	return nil
+}
+
+func registeredMigrations() []migrate.Migration {
+	migrations := migrate.RegisteredMigrations()
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+func registered(migrations []migrate.Migration, version uint64) bool {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// count reads the optional number of migrations of the up and down commands
+func count(positional []string, defaultCount int) (int, error) {
+	if len(positional) < 2 {
+		return defaultCount, nil
+	}
+
+	n, err := strconv.Atoi(positional[1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid number of migrations: %s", positional[1])
+	}
+	return n, nil
+}
+
+func printStatus(out io.Writer, migrations []migrate.Migration, current uint64) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "VERSION\tDESCRIPTION\tSTATUS")
+	for _, migration := range migrations {
+		status := "pending"
+		if migration.Version <= current {
+			status = "applied"
+		}
+		_, _ = fmt.Fprintf(writer, "%d\t%s\t%s\n", migration.Version, migration.Description, status)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "\ndatabase is at version %d\n", current)
+	return err
+}
@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// the pool metrics are registered with the first connection, so several services can be linked in one test binary
+var (
+	poolConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mongodb_pool_connections",
+		Help: "Number of open connections of the MongoDB pool.",
+	})
+	poolConnectionsInUse = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mongodb_pool_connections_in_use",
+		Help: "Number of connections of the MongoDB pool checked out by an operation.",
+	})
+	poolCheckOutFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mongodb_pool_check_out_failures_total",
+		Help: "Total number of failed connection check outs of the MongoDB pool.",
+	})
+)
+
+// poolMonitor keeps the pool metrics up to date from the pool events of the driver
+func poolMonitor() *event.PoolMonitor {
+	prometheus.MustRegister(poolConnections, poolConnectionsInUse, poolCheckOutFailures)
+
+	return &event.PoolMonitor{
+		Event: func(poolEvent *event.PoolEvent) {
+			switch poolEvent.Type {
+			case event.ConnectionCreated:
+				poolConnections.Inc()
+			case event.ConnectionClosed:
+				poolConnections.Dec()
+			case event.GetSucceeded:
+				poolConnectionsInUse.Inc()
+			case event.ConnectionReturned:
+				poolConnectionsInUse.Dec()
+			case event.GetFailed:
+				poolCheckOutFailures.Inc()
+			}
+		},
+	}
+}
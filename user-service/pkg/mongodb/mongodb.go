@@ -2,42 +2,52 @@ package mongodb
 
 import (
 	"context"
-	"log"
-	"os"
 	"sync"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 var (
+	uri          string
+	databaseName string
+
 	client      *mongo.Client
 	database    *mongo.Database
 	connectOnce sync.Once
 )
 
+// Configure sets the connection of GetClient and GetDatabase, it is called before their first use
+func Configure(connectionURI, name string) {
+	uri, databaseName = connectionURI, name
+}
+
 func connectDatabase() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(os.Getenv("MONGODB_URI"))
-	clientOptions = clientOptions.SetMaxPoolSize(50)
+	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions = clientOptions.SetMaxPoolSize(50).
+		SetPoolMonitor(poolMonitor()).
+		SetMonitor(commandMonitor())
 
 	var err error
 	client, err = mongo.NewClient(clientOptions)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal().Err(err).Msg("Error creating MongoDB client")
 	}
 	if err = client.Connect(ctx); err != nil {
-		log.Fatal(err)
+		log.Fatal().Err(err).Msg("Error connecting to MongoDB")
 	}
+	// the service starts while MongoDB is down and reports it in its health, the driver reconnects by itself
 	if err = client.Ping(ctx, readpref.Primary()); err != nil {
-		log.Fatal(err)
+		log.Warn().Err(err).Msg("Error pinging MongoDB")
 	}
 
-	database = client.Database(os.Getenv("MONGODB_DATABASE"))
+	database = client.Database(databaseName)
 }
 
 func GetClient() *mongo.Client {
@@ -49,3 +59,8 @@ func GetDatabase() *mongo.Database {
 	connectOnce.Do(connectDatabase)
 	return database
 }
+
+// Ping checks that the primary is reachable
+func Ping(ctx context.Context) error {
+	return GetClient().Ping(ctx, readpref.Primary())
+}
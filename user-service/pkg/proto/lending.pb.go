@@ -0,0 +1,2354 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.6.1
+// source: lending.proto
+
+package proto
+
+import (
+	context "context"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateLendingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookId string `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// empty for the default branch
+	BranchId string `protobuf:"bytes,3,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+}
+
+func (x *CreateLendingRequest) Reset() {
+	*x = CreateLendingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLendingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLendingRequest) ProtoMessage() {}
+
+func (x *CreateLendingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLendingRequest.ProtoReflect.Descriptor instead.
+func (*CreateLendingRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateLendingRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *CreateLendingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateLendingRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+type Lending struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BookId         string               `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	UserId         string               `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status         string               `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	ReturnDate     *timestamp.Timestamp `protobuf:"bytes,5,opt,name=return_date,json=returnDate,proto3" json:"return_date,omitempty"`
+	BranchId       string               `protobuf:"bytes,6,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	ReturnBranchId string               `protobuf:"bytes,7,opt,name=return_branch_id,json=returnBranchId,proto3" json:"return_branch_id,omitempty"`
+}
+
+func (x *Lending) Reset() {
+	*x = Lending{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Lending) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lending) ProtoMessage() {}
+
+func (x *Lending) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lending.ProtoReflect.Descriptor instead.
+func (*Lending) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Lending) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Lending) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *Lending) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Lending) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Lending) GetReturnDate() *timestamp.Timestamp {
+	if x != nil {
+		return x.ReturnDate
+	}
+	return nil
+}
+
+func (x *Lending) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+func (x *Lending) GetReturnBranchId() string {
+	if x != nil {
+		return x.ReturnBranchId
+	}
+	return ""
+}
+
+type FetchLendingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pagination     *LendingPaginationRequest `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	BookId         string                    `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	UserId         string                    `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status         string                    `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	PassReturnDate bool                      `protobuf:"varint,5,opt,name=pass_return_date,json=passReturnDate,proto3" json:"pass_return_date,omitempty"`
+	BranchId       string                    `protobuf:"bytes,6,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+}
+
+func (x *FetchLendingRequest) Reset() {
+	*x = FetchLendingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchLendingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchLendingRequest) ProtoMessage() {}
+
+func (x *FetchLendingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchLendingRequest.ProtoReflect.Descriptor instead.
+func (*FetchLendingRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FetchLendingRequest) GetPagination() *LendingPaginationRequest {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *FetchLendingRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *FetchLendingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *FetchLendingRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FetchLendingRequest) GetPassReturnDate() bool {
+	if x != nil {
+		return x.PassReturnDate
+	}
+	return false
+}
+
+func (x *FetchLendingRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+type FetchLendingResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pagination *LendingPaginationResponse `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	Lendings   []*Lending                 `protobuf:"bytes,2,rep,name=lendings,proto3" json:"lendings,omitempty"`
+}
+
+func (x *FetchLendingResponse) Reset() {
+	*x = FetchLendingResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchLendingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchLendingResponse) ProtoMessage() {}
+
+func (x *FetchLendingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchLendingResponse.ProtoReflect.Descriptor instead.
+func (*FetchLendingResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FetchLendingResponse) GetPagination() *LendingPaginationResponse {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *FetchLendingResponse) GetLendings() []*Lending {
+	if x != nil {
+		return x.Lendings
+	}
+	return nil
+}
+
+type LendingPaginationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Page  int32 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+}
+
+func (x *LendingPaginationRequest) Reset() {
+	*x = LendingPaginationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LendingPaginationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LendingPaginationRequest) ProtoMessage() {}
+
+func (x *LendingPaginationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LendingPaginationRequest.ProtoReflect.Descriptor instead.
+func (*LendingPaginationRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LendingPaginationRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *LendingPaginationRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+type LendingPaginationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit    int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Page     int32 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	LastPage int32 `protobuf:"varint,3,opt,name=last_page,json=lastPage,proto3" json:"last_page,omitempty"`
+	Total    int32 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *LendingPaginationResponse) Reset() {
+	*x = LendingPaginationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LendingPaginationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LendingPaginationResponse) ProtoMessage() {}
+
+func (x *LendingPaginationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LendingPaginationResponse.ProtoReflect.Descriptor instead.
+func (*LendingPaginationResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LendingPaginationResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *LendingPaginationResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *LendingPaginationResponse) GetLastPage() int32 {
+	if x != nil {
+		return x.LastPage
+	}
+	return 0
+}
+
+func (x *LendingPaginationResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type RenewLendingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *RenewLendingRequest) Reset() {
+	*x = RenewLendingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenewLendingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewLendingRequest) ProtoMessage() {}
+
+func (x *RenewLendingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewLendingRequest.ProtoReflect.Descriptor instead.
+func (*RenewLendingRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RenewLendingRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type FinishLendingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// the branch the book is returned to, empty for the lending branch
+	BranchId string `protobuf:"bytes,2,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+}
+
+func (x *FinishLendingRequest) Reset() {
+	*x = FinishLendingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinishLendingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishLendingRequest) ProtoMessage() {}
+
+func (x *FinishLendingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishLendingRequest.ProtoReflect.Descriptor instead.
+func (*FinishLendingRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FinishLendingRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FinishLendingRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+type CountActiveLendingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookId string `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (x *CountActiveLendingsRequest) Reset() {
+	*x = CountActiveLendingsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountActiveLendingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountActiveLendingsRequest) ProtoMessage() {}
+
+func (x *CountActiveLendingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountActiveLendingsRequest.ProtoReflect.Descriptor instead.
+func (*CountActiveLendingsRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CountActiveLendingsRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *CountActiveLendingsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CountActiveLendingsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count    int32      `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Lendings []*Lending `protobuf:"bytes,2,rep,name=lendings,proto3" json:"lendings,omitempty"`
+}
+
+func (x *CountActiveLendingsResponse) Reset() {
+	*x = CountActiveLendingsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountActiveLendingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountActiveLendingsResponse) ProtoMessage() {}
+
+func (x *CountActiveLendingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountActiveLendingsResponse.ProtoReflect.Descriptor instead.
+func (*CountActiveLendingsResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CountActiveLendingsResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CountActiveLendingsResponse) GetLendings() []*Lending {
+	if x != nil {
+		return x.Lendings
+	}
+	return nil
+}
+
+type FinishActiveLendingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookId string `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (x *FinishActiveLendingsRequest) Reset() {
+	*x = FinishActiveLendingsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinishActiveLendingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishActiveLendingsRequest) ProtoMessage() {}
+
+func (x *FinishActiveLendingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishActiveLendingsRequest.ProtoReflect.Descriptor instead.
+func (*FinishActiveLendingsRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FinishActiveLendingsRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *FinishActiveLendingsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type FinishActiveLendingsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lendings []*Lending `protobuf:"bytes,1,rep,name=lendings,proto3" json:"lendings,omitempty"`
+}
+
+func (x *FinishActiveLendingsResponse) Reset() {
+	*x = FinishActiveLendingsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinishActiveLendingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishActiveLendingsResponse) ProtoMessage() {}
+
+func (x *FinishActiveLendingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishActiveLendingsResponse.ProtoReflect.Descriptor instead.
+func (*FinishActiveLendingsResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FinishActiveLendingsResponse) GetLendings() []*Lending {
+	if x != nil {
+		return x.Lendings
+	}
+	return nil
+}
+
+// ReportRequest bounds a report to the loans and the returns from from until to
+type ReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From *timestamp.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// the branch the books are lent at, empty for every branch
+	BranchId string `protobuf:"bytes,3,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"`
+	// day, week or month, for the loans per period
+	Period string `protobuf:"bytes,4,opt,name=period,proto3" json:"period,omitempty"`
+	// the number of books or members of the rankings, 10 by default
+	Limit int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *ReportRequest) Reset() {
+	*x = ReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportRequest) ProtoMessage() {}
+
+func (x *ReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportRequest.ProtoReflect.Descriptor instead.
+func (*ReportRequest) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReportRequest) GetFrom() *timestamp.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReportRequest) GetTo() *timestamp.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ReportRequest) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type PeriodCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// like 2021-02-14, 2021-W07, or 2021-02
+	Period  string `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	Loans   int32  `protobuf:"varint,2,opt,name=loans,proto3" json:"loans,omitempty"`
+	Returns int32  `protobuf:"varint,3,opt,name=returns,proto3" json:"returns,omitempty"`
+}
+
+func (x *PeriodCount) Reset() {
+	*x = PeriodCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeriodCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeriodCount) ProtoMessage() {}
+
+func (x *PeriodCount) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeriodCount.ProtoReflect.Descriptor instead.
+func (*PeriodCount) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PeriodCount) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *PeriodCount) GetLoans() int32 {
+	if x != nil {
+		return x.Loans
+	}
+	return 0
+}
+
+func (x *PeriodCount) GetReturns() int32 {
+	if x != nil {
+		return x.Returns
+	}
+	return 0
+}
+
+type LoansPerPeriodResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Periods []*PeriodCount `protobuf:"bytes,1,rep,name=periods,proto3" json:"periods,omitempty"`
+}
+
+func (x *LoansPerPeriodResponse) Reset() {
+	*x = LoansPerPeriodResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoansPerPeriodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoansPerPeriodResponse) ProtoMessage() {}
+
+func (x *LoansPerPeriodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoansPerPeriodResponse.ProtoReflect.Descriptor instead.
+func (*LoansPerPeriodResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LoansPerPeriodResponse) GetPeriods() []*PeriodCount {
+	if x != nil {
+		return x.Periods
+	}
+	return nil
+}
+
+type BookLoanCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookId string `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Loans  int32  `protobuf:"varint,2,opt,name=loans,proto3" json:"loans,omitempty"`
+}
+
+func (x *BookLoanCount) Reset() {
+	*x = BookLoanCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BookLoanCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookLoanCount) ProtoMessage() {}
+
+func (x *BookLoanCount) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookLoanCount.ProtoReflect.Descriptor instead.
+func (*BookLoanCount) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BookLoanCount) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *BookLoanCount) GetLoans() int32 {
+	if x != nil {
+		return x.Loans
+	}
+	return 0
+}
+
+type TopBorrowedBooksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Books []*BookLoanCount `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+}
+
+func (x *TopBorrowedBooksResponse) Reset() {
+	*x = TopBorrowedBooksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TopBorrowedBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopBorrowedBooksResponse) ProtoMessage() {}
+
+func (x *TopBorrowedBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopBorrowedBooksResponse.ProtoReflect.Descriptor instead.
+func (*TopBorrowedBooksResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TopBorrowedBooksResponse) GetBooks() []*BookLoanCount {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+type BorrowedBooksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookIds []string `protobuf:"bytes,1,rep,name=book_ids,json=bookIds,proto3" json:"book_ids,omitempty"`
+}
+
+func (x *BorrowedBooksResponse) Reset() {
+	*x = BorrowedBooksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BorrowedBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BorrowedBooksResponse) ProtoMessage() {}
+
+func (x *BorrowedBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BorrowedBooksResponse.ProtoReflect.Descriptor instead.
+func (*BorrowedBooksResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BorrowedBooksResponse) GetBookIds() []string {
+	if x != nil {
+		return x.BookIds
+	}
+	return nil
+}
+
+type CirculationSummaryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Loans           int32   `protobuf:"varint,1,opt,name=loans,proto3" json:"loans,omitempty"`
+	Returns         int32   `protobuf:"varint,2,opt,name=returns,proto3" json:"returns,omitempty"`
+	AverageLoanDays float64 `protobuf:"fixed64,3,opt,name=average_loan_days,json=averageLoanDays,proto3" json:"average_loan_days,omitempty"`
+	// the loans returned late or still lent past their return date
+	Overdue       int32   `protobuf:"varint,4,opt,name=overdue,proto3" json:"overdue,omitempty"`
+	OverdueRate   float64 `protobuf:"fixed64,5,opt,name=overdue_rate,json=overdueRate,proto3" json:"overdue_rate,omitempty"`
+	ActiveMembers int32   `protobuf:"varint,6,opt,name=active_members,json=activeMembers,proto3" json:"active_members,omitempty"`
+}
+
+func (x *CirculationSummaryResponse) Reset() {
+	*x = CirculationSummaryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CirculationSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CirculationSummaryResponse) ProtoMessage() {}
+
+func (x *CirculationSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CirculationSummaryResponse.ProtoReflect.Descriptor instead.
+func (*CirculationSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CirculationSummaryResponse) GetLoans() int32 {
+	if x != nil {
+		return x.Loans
+	}
+	return 0
+}
+
+func (x *CirculationSummaryResponse) GetReturns() int32 {
+	if x != nil {
+		return x.Returns
+	}
+	return 0
+}
+
+func (x *CirculationSummaryResponse) GetAverageLoanDays() float64 {
+	if x != nil {
+		return x.AverageLoanDays
+	}
+	return 0
+}
+
+func (x *CirculationSummaryResponse) GetOverdue() int32 {
+	if x != nil {
+		return x.Overdue
+	}
+	return 0
+}
+
+func (x *CirculationSummaryResponse) GetOverdueRate() float64 {
+	if x != nil {
+		return x.OverdueRate
+	}
+	return 0
+}
+
+func (x *CirculationSummaryResponse) GetActiveMembers() int32 {
+	if x != nil {
+		return x.ActiveMembers
+	}
+	return 0
+}
+
+type MemberActivity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId  string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Loans   int32  `protobuf:"varint,2,opt,name=loans,proto3" json:"loans,omitempty"`
+	Active  int32  `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	Overdue int32  `protobuf:"varint,4,opt,name=overdue,proto3" json:"overdue,omitempty"`
+}
+
+func (x *MemberActivity) Reset() {
+	*x = MemberActivity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MemberActivity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberActivity) ProtoMessage() {}
+
+func (x *MemberActivity) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberActivity.ProtoReflect.Descriptor instead.
+func (*MemberActivity) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MemberActivity) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MemberActivity) GetLoans() int32 {
+	if x != nil {
+		return x.Loans
+	}
+	return 0
+}
+
+func (x *MemberActivity) GetActive() int32 {
+	if x != nil {
+		return x.Active
+	}
+	return 0
+}
+
+func (x *MemberActivity) GetOverdue() int32 {
+	if x != nil {
+		return x.Overdue
+	}
+	return 0
+}
+
+type ActiveMembersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Members []*MemberActivity `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+}
+
+func (x *ActiveMembersResponse) Reset() {
+	*x = ActiveMembersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lending_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActiveMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActiveMembersResponse) ProtoMessage() {}
+
+func (x *ActiveMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lending_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActiveMembersResponse.ProtoReflect.Descriptor instead.
+func (*ActiveMembersResponse) Descriptor() ([]byte, []int) {
+	return file_lending_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ActiveMembersResponse) GetMembers() []*MemberActivity {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+var File_lending_proto protoreflect.FileDescriptor
+
+var file_lending_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x07, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x65, 0x0a, 0x14, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x5f, 0x69, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x49, 0x64,
+	0x22, 0xe7, 0x01, 0x0a, 0x07, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07,
+	0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62,
+	0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e,
+	0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x44,
+	0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x5f, 0x69, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x49, 0x64,
+	0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x62, 0x72, 0x61, 0x6e, 0x63,
+	0x68, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x74, 0x75,
+	0x72, 0x6e, 0x42, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x49, 0x64, 0x22, 0xe9, 0x01, 0x0a, 0x13, 0x46,
+	0x65, 0x74, 0x63, 0x68, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x41, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x2e, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x17,
+	0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x28, 0x0a, 0x10, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x70, 0x61, 0x73, 0x73, 0x52,
+	0x65, 0x74, 0x75, 0x72, 0x6e, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x72, 0x61,
+	0x6e, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x72,
+	0x61, 0x6e, 0x63, 0x68, 0x49, 0x64, 0x22, 0x88, 0x01, 0x0a, 0x14, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x42, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x4c, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x08, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e,
+	0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x73, 0x22, 0x44, 0x0a, 0x18, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x67, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x22, 0x78, 0x0a, 0x19, 0x4c, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x22, 0x25, 0x0a, 0x13, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x43, 0x0a, 0x14, 0x46, 0x69, 0x6e, 0x69,
+	0x73, 0x68, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x49, 0x64, 0x22, 0x4e, 0x0a,
+	0x1a, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x62,
+	0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6f,
+	0x6f, 0x6b, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x61, 0x0a,
+	0x1b, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x08, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x4c,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73,
+	0x22, 0x4f, 0x0a, 0x1b, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x22, 0x4c, 0x0a, 0x1c, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2c, 0x0a, 0x08, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x4c, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22,
+	0xb6, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x1b, 0x0a,
+	0x09, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x55, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x22,
+	0x48, 0x0a, 0x16, 0x4c, 0x6f, 0x61, 0x6e, 0x73, 0x50, 0x65, 0x72, 0x50, 0x65, 0x72, 0x69, 0x6f,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x70, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x65, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x52, 0x07, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x73, 0x22, 0x3e, 0x0a, 0x0d, 0x42, 0x6f, 0x6f,
+	0x6b, 0x4c, 0x6f, 0x61, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6f,
+	0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x6f,
+	0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x22, 0x48, 0x0a, 0x18, 0x54, 0x6f, 0x70,
+	0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x62, 0x6f, 0x6f, 0x6b, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x42,
+	0x6f, 0x6f, 0x6b, 0x4c, 0x6f, 0x61, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x05, 0x62, 0x6f,
+	0x6f, 0x6b, 0x73, 0x22, 0x32, 0x0a, 0x15, 0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x65, 0x64, 0x42,
+	0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x73, 0x22, 0xdc, 0x01, 0x0a, 0x1a, 0x43, 0x69, 0x72, 0x63,
+	0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72,
+	0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x5f, 0x6c, 0x6f, 0x61, 0x6e, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x4c, 0x6f, 0x61, 0x6e, 0x44, 0x61,
+	0x79, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x6f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0b, 0x6f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12,
+	0x25, 0x0a, 0x0e, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x22, 0x71, 0x0a, 0x0e, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x6c, 0x6f, 0x61, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x64, 0x75, 0x65, 0x22, 0x4a, 0x0a, 0x15, 0x41, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x52, 0x07, 0x6d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x32, 0xff, 0x06, 0x0a, 0x0e, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1d, 0x2e, 0x6c, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x2e, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4d,
+	0x0a, 0x0c, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1c,
+	0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6c,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a,
+	0x0c, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x2e,
+	0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x4c, 0x65, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x6c, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x00, 0x12,
+	0x42, 0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x1d, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x46, 0x69, 0x6e, 0x69, 0x73,
+	0x68, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x10, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x13, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x23, 0x2e, 0x6c, 0x65, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x24, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x65, 0x0a, 0x14, 0x46, 0x69, 0x6e, 0x69, 0x73,
+	0x68, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12,
+	0x24, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e,
+	0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b,
+	0x0a, 0x0e, 0x4c, 0x6f, 0x61, 0x6e, 0x73, 0x50, 0x65, 0x72, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x12, 0x16, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x2e, 0x4c, 0x6f, 0x61, 0x6e, 0x73, 0x50, 0x65, 0x72, 0x50, 0x65, 0x72, 0x69, 0x6f,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x10, 0x54,
+	0x6f, 0x70, 0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x12,
+	0x16, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x2e, 0x54, 0x6f, 0x70, 0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x6f, 0x6f,
+	0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0d,
+	0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x16, 0x2e,
+	0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e,
+	0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x12, 0x43, 0x69, 0x72, 0x63, 0x75,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x16, 0x2e,
+	0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e,
+	0x43, 0x69, 0x72, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0d,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e,
+	0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6c, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x0b, 0x5a, 0x09, 0x70, 0x6b, 0x67, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_lending_proto_rawDescOnce sync.Once
+	file_lending_proto_rawDescData = file_lending_proto_rawDesc
+)
+
+func file_lending_proto_rawDescGZIP() []byte {
+	file_lending_proto_rawDescOnce.Do(func() {
+		file_lending_proto_rawDescData = protoimpl.X.CompressGZIP(file_lending_proto_rawDescData)
+	})
+	return file_lending_proto_rawDescData
+}
+
+var file_lending_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_lending_proto_goTypes = []interface{}{
+	(*CreateLendingRequest)(nil),         // 0: lending.CreateLendingRequest
+	(*Lending)(nil),                      // 1: lending.Lending
+	(*FetchLendingRequest)(nil),          // 2: lending.FetchLendingRequest
+	(*FetchLendingResponse)(nil),         // 3: lending.FetchLendingResponse
+	(*LendingPaginationRequest)(nil),     // 4: lending.LendingPaginationRequest
+	(*LendingPaginationResponse)(nil),    // 5: lending.LendingPaginationResponse
+	(*RenewLendingRequest)(nil),          // 6: lending.RenewLendingRequest
+	(*FinishLendingRequest)(nil),         // 7: lending.FinishLendingRequest
+	(*CountActiveLendingsRequest)(nil),   // 8: lending.CountActiveLendingsRequest
+	(*CountActiveLendingsResponse)(nil),  // 9: lending.CountActiveLendingsResponse
+	(*FinishActiveLendingsRequest)(nil),  // 10: lending.FinishActiveLendingsRequest
+	(*FinishActiveLendingsResponse)(nil), // 11: lending.FinishActiveLendingsResponse
+	(*ReportRequest)(nil),                // 12: lending.ReportRequest
+	(*PeriodCount)(nil),                  // 13: lending.PeriodCount
+	(*LoansPerPeriodResponse)(nil),       // 14: lending.LoansPerPeriodResponse
+	(*BookLoanCount)(nil),                // 15: lending.BookLoanCount
+	(*TopBorrowedBooksResponse)(nil),     // 16: lending.TopBorrowedBooksResponse
+	(*BorrowedBooksResponse)(nil),        // 17: lending.BorrowedBooksResponse
+	(*CirculationSummaryResponse)(nil),   // 18: lending.CirculationSummaryResponse
+	(*MemberActivity)(nil),               // 19: lending.MemberActivity
+	(*ActiveMembersResponse)(nil),        // 20: lending.ActiveMembersResponse
+	(*timestamp.Timestamp)(nil),          // 21: google.protobuf.Timestamp
+}
+var file_lending_proto_depIdxs = []int32{
+	21, // 0: lending.Lending.return_date:type_name -> google.protobuf.Timestamp
+	4,  // 1: lending.FetchLendingRequest.pagination:type_name -> lending.LendingPaginationRequest
+	5,  // 2: lending.FetchLendingResponse.pagination:type_name -> lending.LendingPaginationResponse
+	1,  // 3: lending.FetchLendingResponse.lendings:type_name -> lending.Lending
+	1,  // 4: lending.CountActiveLendingsResponse.lendings:type_name -> lending.Lending
+	1,  // 5: lending.FinishActiveLendingsResponse.lendings:type_name -> lending.Lending
+	21, // 6: lending.ReportRequest.from:type_name -> google.protobuf.Timestamp
+	21, // 7: lending.ReportRequest.to:type_name -> google.protobuf.Timestamp
+	13, // 8: lending.LoansPerPeriodResponse.periods:type_name -> lending.PeriodCount
+	15, // 9: lending.TopBorrowedBooksResponse.books:type_name -> lending.BookLoanCount
+	19, // 10: lending.ActiveMembersResponse.members:type_name -> lending.MemberActivity
+	0,  // 11: lending.LendingService.CreateLending:input_type -> lending.CreateLendingRequest
+	2,  // 12: lending.LendingService.FetchLending:input_type -> lending.FetchLendingRequest
+	6,  // 13: lending.LendingService.RenewLending:input_type -> lending.RenewLendingRequest
+	7,  // 14: lending.LendingService.FinishLending:input_type -> lending.FinishLendingRequest
+	8,  // 15: lending.LendingService.CountActiveLendings:input_type -> lending.CountActiveLendingsRequest
+	10, // 16: lending.LendingService.FinishActiveLendings:input_type -> lending.FinishActiveLendingsRequest
+	12, // 17: lending.LendingService.LoansPerPeriod:input_type -> lending.ReportRequest
+	12, // 18: lending.LendingService.TopBorrowedBooks:input_type -> lending.ReportRequest
+	12, // 19: lending.LendingService.BorrowedBooks:input_type -> lending.ReportRequest
+	12, // 20: lending.LendingService.CirculationSummary:input_type -> lending.ReportRequest
+	12, // 21: lending.LendingService.ActiveMembers:input_type -> lending.ReportRequest
+	1,  // 22: lending.LendingService.CreateLending:output_type -> lending.Lending
+	3,  // 23: lending.LendingService.FetchLending:output_type -> lending.FetchLendingResponse
+	1,  // 24: lending.LendingService.RenewLending:output_type -> lending.Lending
+	1,  // 25: lending.LendingService.FinishLending:output_type -> lending.Lending
+	9,  // 26: lending.LendingService.CountActiveLendings:output_type -> lending.CountActiveLendingsResponse
+	11, // 27: lending.LendingService.FinishActiveLendings:output_type -> lending.FinishActiveLendingsResponse
+	14, // 28: lending.LendingService.LoansPerPeriod:output_type -> lending.LoansPerPeriodResponse
+	16, // 29: lending.LendingService.TopBorrowedBooks:output_type -> lending.TopBorrowedBooksResponse
+	17, // 30: lending.LendingService.BorrowedBooks:output_type -> lending.BorrowedBooksResponse
+	18, // 31: lending.LendingService.CirculationSummary:output_type -> lending.CirculationSummaryResponse
+	20, // 32: lending.LendingService.ActiveMembers:output_type -> lending.ActiveMembersResponse
+	22, // [22:33] is the sub-list for method output_type
+	11, // [11:22] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_lending_proto_init() }
+func file_lending_proto_init() {
+	if File_lending_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_lending_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateLendingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Lending); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchLendingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchLendingResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LendingPaginationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LendingPaginationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenewLendingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinishLendingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountActiveLendingsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountActiveLendingsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinishActiveLendingsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinishActiveLendingsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeriodCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoansPerPeriodResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BookLoanCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TopBorrowedBooksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BorrowedBooksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CirculationSummaryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MemberActivity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lending_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActiveMembersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_lending_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lending_proto_goTypes,
+		DependencyIndexes: file_lending_proto_depIdxs,
+		MessageInfos:      file_lending_proto_msgTypes,
+	}.Build()
+	File_lending_proto = out.File
+	file_lending_proto_rawDesc = nil
+	file_lending_proto_goTypes = nil
+	file_lending_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// LendingServiceClient is the client API for LendingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LendingServiceClient interface {
+	CreateLending(ctx context.Context, in *CreateLendingRequest, opts ...grpc.CallOption) (LendingService_CreateLendingClient, error)
+	FetchLending(ctx context.Context, in *FetchLendingRequest, opts ...grpc.CallOption) (*FetchLendingResponse, error)
+	RenewLending(ctx context.Context, in *RenewLendingRequest, opts ...grpc.CallOption) (*Lending, error)
+	FinishLending(ctx context.Context, in *FinishLendingRequest, opts ...grpc.CallOption) (*Lending, error)
+	CountActiveLendings(ctx context.Context, in *CountActiveLendingsRequest, opts ...grpc.CallOption) (*CountActiveLendingsResponse, error)
+	FinishActiveLendings(ctx context.Context, in *FinishActiveLendingsRequest, opts ...grpc.CallOption) (*FinishActiveLendingsResponse, error)
+	// the circulation reports of the lent lendings
+	LoansPerPeriod(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*LoansPerPeriodResponse, error)
+	TopBorrowedBooks(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*TopBorrowedBooksResponse, error)
+	BorrowedBooks(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*BorrowedBooksResponse, error)
+	CirculationSummary(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*CirculationSummaryResponse, error)
+	ActiveMembers(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*ActiveMembersResponse, error)
+}
+
+type lendingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLendingServiceClient(cc grpc.ClientConnInterface) LendingServiceClient {
+	return &lendingServiceClient{cc}
+}
+
+func (c *lendingServiceClient) CreateLending(ctx context.Context, in *CreateLendingRequest, opts ...grpc.CallOption) (LendingService_CreateLendingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LendingService_serviceDesc.Streams[0], "/lending.LendingService/CreateLending", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lendingServiceCreateLendingClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LendingService_CreateLendingClient interface {
+	Recv() (*Lending, error)
+	grpc.ClientStream
+}
+
+type lendingServiceCreateLendingClient struct {
+	grpc.ClientStream
+}
+
+func (x *lendingServiceCreateLendingClient) Recv() (*Lending, error) {
+	m := new(Lending)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lendingServiceClient) FetchLending(ctx context.Context, in *FetchLendingRequest, opts ...grpc.CallOption) (*FetchLendingResponse, error) {
+	out := new(FetchLendingResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/FetchLending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) RenewLending(ctx context.Context, in *RenewLendingRequest, opts ...grpc.CallOption) (*Lending, error) {
+	out := new(Lending)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/RenewLending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) FinishLending(ctx context.Context, in *FinishLendingRequest, opts ...grpc.CallOption) (*Lending, error) {
+	out := new(Lending)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/FinishLending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) CountActiveLendings(ctx context.Context, in *CountActiveLendingsRequest, opts ...grpc.CallOption) (*CountActiveLendingsResponse, error) {
+	out := new(CountActiveLendingsResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/CountActiveLendings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) FinishActiveLendings(ctx context.Context, in *FinishActiveLendingsRequest, opts ...grpc.CallOption) (*FinishActiveLendingsResponse, error) {
+	out := new(FinishActiveLendingsResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/FinishActiveLendings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) LoansPerPeriod(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*LoansPerPeriodResponse, error) {
+	out := new(LoansPerPeriodResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/LoansPerPeriod", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) TopBorrowedBooks(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*TopBorrowedBooksResponse, error) {
+	out := new(TopBorrowedBooksResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/TopBorrowedBooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) BorrowedBooks(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*BorrowedBooksResponse, error) {
+	out := new(BorrowedBooksResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/BorrowedBooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) CirculationSummary(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*CirculationSummaryResponse, error) {
+	out := new(CirculationSummaryResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/CirculationSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lendingServiceClient) ActiveMembers(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*ActiveMembersResponse, error) {
+	out := new(ActiveMembersResponse)
+	err := c.cc.Invoke(ctx, "/lending.LendingService/ActiveMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LendingServiceServer is the server API for LendingService service.
+type LendingServiceServer interface {
+	CreateLending(*CreateLendingRequest, LendingService_CreateLendingServer) error
+	FetchLending(context.Context, *FetchLendingRequest) (*FetchLendingResponse, error)
+	RenewLending(context.Context, *RenewLendingRequest) (*Lending, error)
+	FinishLending(context.Context, *FinishLendingRequest) (*Lending, error)
+	CountActiveLendings(context.Context, *CountActiveLendingsRequest) (*CountActiveLendingsResponse, error)
+	FinishActiveLendings(context.Context, *FinishActiveLendingsRequest) (*FinishActiveLendingsResponse, error)
+	// the circulation reports of the lent lendings
+	LoansPerPeriod(context.Context, *ReportRequest) (*LoansPerPeriodResponse, error)
+	TopBorrowedBooks(context.Context, *ReportRequest) (*TopBorrowedBooksResponse, error)
+	BorrowedBooks(context.Context, *ReportRequest) (*BorrowedBooksResponse, error)
+	CirculationSummary(context.Context, *ReportRequest) (*CirculationSummaryResponse, error)
+	ActiveMembers(context.Context, *ReportRequest) (*ActiveMembersResponse, error)
+}
+
+// UnimplementedLendingServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedLendingServiceServer struct {
+}
+
+func (*UnimplementedLendingServiceServer) CreateLending(*CreateLendingRequest, LendingService_CreateLendingServer) error {
+	return status.Errorf(codes.Unimplemented, "method CreateLending not implemented")
+}
+func (*UnimplementedLendingServiceServer) FetchLending(context.Context, *FetchLendingRequest) (*FetchLendingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchLending not implemented")
+}
+func (*UnimplementedLendingServiceServer) RenewLending(context.Context, *RenewLendingRequest) (*Lending, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewLending not implemented")
+}
+func (*UnimplementedLendingServiceServer) FinishLending(context.Context, *FinishLendingRequest) (*Lending, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishLending not implemented")
+}
+func (*UnimplementedLendingServiceServer) CountActiveLendings(context.Context, *CountActiveLendingsRequest) (*CountActiveLendingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountActiveLendings not implemented")
+}
+func (*UnimplementedLendingServiceServer) FinishActiveLendings(context.Context, *FinishActiveLendingsRequest) (*FinishActiveLendingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishActiveLendings not implemented")
+}
+func (*UnimplementedLendingServiceServer) LoansPerPeriod(context.Context, *ReportRequest) (*LoansPerPeriodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoansPerPeriod not implemented")
+}
+func (*UnimplementedLendingServiceServer) TopBorrowedBooks(context.Context, *ReportRequest) (*TopBorrowedBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopBorrowedBooks not implemented")
+}
+func (*UnimplementedLendingServiceServer) BorrowedBooks(context.Context, *ReportRequest) (*BorrowedBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BorrowedBooks not implemented")
+}
+func (*UnimplementedLendingServiceServer) CirculationSummary(context.Context, *ReportRequest) (*CirculationSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CirculationSummary not implemented")
+}
+func (*UnimplementedLendingServiceServer) ActiveMembers(context.Context, *ReportRequest) (*ActiveMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActiveMembers not implemented")
+}
+
+func RegisterLendingServiceServer(s *grpc.Server, srv LendingServiceServer) {
+	s.RegisterService(&_LendingService_serviceDesc, srv)
+}
+
+func _LendingService_CreateLending_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CreateLendingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LendingServiceServer).CreateLending(m, &lendingServiceCreateLendingServer{stream})
+}
+
+type LendingService_CreateLendingServer interface {
+	Send(*Lending) error
+	grpc.ServerStream
+}
+
+type lendingServiceCreateLendingServer struct {
+	grpc.ServerStream
+}
+
+func (x *lendingServiceCreateLendingServer) Send(m *Lending) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LendingService_FetchLending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchLendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).FetchLending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/FetchLending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).FetchLending(ctx, req.(*FetchLendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_RenewLending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewLendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).RenewLending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/RenewLending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).RenewLending(ctx, req.(*RenewLendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_FinishLending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishLendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).FinishLending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/FinishLending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).FinishLending(ctx, req.(*FinishLendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_CountActiveLendings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountActiveLendingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).CountActiveLendings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/CountActiveLendings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).CountActiveLendings(ctx, req.(*CountActiveLendingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_FinishActiveLendings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishActiveLendingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).FinishActiveLendings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/FinishActiveLendings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).FinishActiveLendings(ctx, req.(*FinishActiveLendingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_LoansPerPeriod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).LoansPerPeriod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/LoansPerPeriod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).LoansPerPeriod(ctx, req.(*ReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_TopBorrowedBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).TopBorrowedBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/TopBorrowedBooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).TopBorrowedBooks(ctx, req.(*ReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_BorrowedBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).BorrowedBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/BorrowedBooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).BorrowedBooks(ctx, req.(*ReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_CirculationSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).CirculationSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/CirculationSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).CirculationSummary(ctx, req.(*ReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LendingService_ActiveMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LendingServiceServer).ActiveMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lending.LendingService/ActiveMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LendingServiceServer).ActiveMembers(ctx, req.(*ReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _LendingService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "lending.LendingService",
+	HandlerType: (*LendingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FetchLending",
+			Handler:    _LendingService_FetchLending_Handler,
+		},
+		{
+			MethodName: "RenewLending",
+			Handler:    _LendingService_RenewLending_Handler,
+		},
+		{
+			MethodName: "FinishLending",
+			Handler:    _LendingService_FinishLending_Handler,
+		},
+		{
+			MethodName: "CountActiveLendings",
+			Handler:    _LendingService_CountActiveLendings_Handler,
+		},
+		{
+			MethodName: "FinishActiveLendings",
+			Handler:    _LendingService_FinishActiveLendings_Handler,
+		},
+		{
+			MethodName: "LoansPerPeriod",
+			Handler:    _LendingService_LoansPerPeriod_Handler,
+		},
+		{
+			MethodName: "TopBorrowedBooks",
+			Handler:    _LendingService_TopBorrowedBooks_Handler,
+		},
+		{
+			MethodName: "BorrowedBooks",
+			Handler:    _LendingService_BorrowedBooks_Handler,
+		},
+		{
+			MethodName: "CirculationSummary",
+			Handler:    _LendingService_CirculationSummary_Handler,
+		},
+		{
+			MethodName: "ActiveMembers",
+			Handler:    _LendingService_ActiveMembers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CreateLending",
+			Handler:       _LendingService_CreateLending_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "lending.proto",
+}
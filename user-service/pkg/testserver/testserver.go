@@ -0,0 +1,127 @@
+// Package testserver serves the user service backed by in-memory repositories, so the tests spanning the services run
+// in one process without MongoDB.
+package testserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"user-service/internal/domain"
+	"user-service/internal/event"
+	"user-service/internal/repository"
+	"user-service/internal/service"
+	"user-service/pkg/jwt"
+	"user-service/pkg/logger"
+	"user-service/pkg/mongodb"
+	"user-service/pkg/proto"
+	"user-service/pkg/tenant"
+)
+
+// the events are relayed soon after the requests
+const (
+	relayInterval    = 10 * time.Millisecond
+	relayMaxAttempts = 10
+	eventBufferSize  = 100
+)
+
+// Server is the user service, its repositories live as long as it
+type Server struct {
+	*grpc.Server
+	userService *service.UserGRPCService
+	clientConns []*grpc.ClientConn
+	publisher   *event.ChannelPublisher
+	stopRelay   context.CancelFunc
+	relayDone   chan struct{}
+}
+
+// New returns the user service calling the lending and book services through their dialers, the tokens are signed
+// with jwtSecretKey. The default tenant exists like after the migrations, and the connections are not blocking, so
+// the services can be created in any order.
+func New(lendingServiceDialer, bookServiceDialer func(context.Context, string) (net.Conn, error), jwtSecretKey string) (*Server, error) {
+	tenantRepository := repository.NewTenantMemoryRepository()
+	err := tenantRepository.Create(context.Background(), &domain.Tenant{Code: tenant.Default, Name: "Default"})
+	if err != nil {
+		return nil, err
+	}
+
+	lendingGRPCClientConn, err := dial("lending-service", lendingServiceDialer)
+	if err != nil {
+		return nil, err
+	}
+	bookGRPCClientConn, err := dial("book-service", bookServiceDialer)
+	if err != nil {
+		_ = lendingGRPCClientConn.Close()
+		return nil, err
+	}
+
+	outboxRepository := repository.NewOutboxMemoryRepository()
+	userService := service.NewUserGRPCService(
+		repository.NewUserMemoryRepository(),
+		tenantRepository,
+		outboxRepository,
+		mongodb.NewNoTXRepository(),
+		proto.NewLendingServiceClient(lendingGRPCClientConn),
+		proto.NewBookServiceClient(bookGRPCClientConn),
+		jwt.New(jwtSecretKey),
+	)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(tenant.UnaryServerInterceptor(), logger.UnaryServerInterceptor(), logger.RecoveryUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(tenant.StreamServerInterceptor(), logger.StreamServerInterceptor(), logger.RecoveryStreamServerInterceptor()),
+	)
+	proto.RegisterUserServiceServer(server, userService)
+
+	s := &Server{
+		Server:      server,
+		userService: userService,
+		clientConns: []*grpc.ClientConn{lendingGRPCClientConn, bookGRPCClientConn},
+		publisher:   event.NewChannelPublisher(eventBufferSize),
+		relayDone:   make(chan struct{}),
+	}
+
+	var relayCtx context.Context
+	relayCtx, s.stopRelay = context.WithCancel(context.Background())
+	go func() {
+		defer close(s.relayDone)
+		event.NewRelay(outboxRepository, s.publisher, relayInterval, relayMaxAttempts).Run(relayCtx)
+	}()
+
+	return s, nil
+}
+
+// CreateUser creates a user of the default tenant like the seed command, for the roles no one can register through
+// the gateway
+func (s *Server) CreateUser(ctx context.Context, email, password, role string) error {
+	_, err := s.userService.CreateUser(ctx, &proto.CreateUserRequest{Email: email, Password: password, Role: role})
+	return err
+}
+
+// Events receives the events published by the service. The events wait in the outbox while the channel is full,
+// so a test not reading them does not block the service.
+func (s *Server) Events() <-chan *proto.Event {
+	return s.publisher.Events()
+}
+
+// Stop stops serving and relaying the events, and closes the connections to the lending and book services
+func (s *Server) Stop() {
+	s.Server.Stop()
+	s.stopRelay()
+	<-s.relayDone
+	for _, clientConn := range s.clientConns {
+		_ = clientConn.Close()
+	}
+}
+
+func dial(target string, dialer func(context.Context, string) (net.Conn, error)) (*grpc.ClientConn, error) {
+	return grpc.Dial(
+		target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer),
+		grpc.WithChainUnaryInterceptor(tenant.UnaryClientInterceptor(), logger.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tenant.StreamClientInterceptor(), logger.StreamClientInterceptor()),
+	)
+}